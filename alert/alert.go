@@ -22,16 +22,25 @@ var (
 	// Leader 选举器
 	LeaderElector *tools.LeaderElector
 
+	// 规则分片实例, 启用分片时规则按规则 ID 分配到所有存活实例
+	ShardWorker *tools.ShardWorker
+
 	// 消息订阅取消函数
 	subscriberCancels []context.CancelFunc
 
+	// 规则重载消息订阅取消函数, 分片模式下所有实例均订阅
+	ruleSubscriberCancel context.CancelFunc
+
 	// 选举开关
 	leaderElectionEnabled bool
+
+	// 分片开关
+	shardingEnabled bool
 )
 
 func Initialize(ctx *ctx.Context) {
 	// 初始化告警规则评估任务
-	AlertRule = eval.NewAlertRuleEval(ctx)
+	AlertRule = eval.NewAlertRuleEval(ctx, IsRuleOwner)
 	ConsumerWork = consumer.NewConsumerWork(ctx)
 
 	// 初始化拨测任务
@@ -40,6 +49,16 @@ func Initialize(ctx *ctx.Context) {
 	// 检查 Leader 选举是否启用
 	leaderElectionEnabled = config.Application.Server.EnableElection
 
+	// 分片模式下每个实例评估各自分片内的规则, 不依赖 Leader 身份
+	shardingEnabled = config.Application.Server.EnableSharding
+	if shardingEnabled {
+		logc.Infof(ctx.Ctx, "规则分片已启用，注册分片实例...")
+		ShardWorker = tools.NewShardWorker(ctx.Ctx, client.Redis, AlertRule.RebalanceEvals)
+		ShardWorker.Start()
+		AlertRule.RestartAllEvals()
+		ruleSubscriberCancel = subscribeRuleReload()
+	}
+
 	if leaderElectionEnabled {
 		// 启用 Leader 选举模式
 		logc.Infof(ctx.Ctx, "Leader 选举已启用，开始选举流程...")
@@ -60,8 +79,10 @@ func Initialize(ctx *ctx.Context) {
 func loadRules() {
 	logc.Infof(ctx.Ctx, "本节点为 Leader 节点，开始加载规则...")
 
-	// 重启所有告警规则评估器
-	AlertRule.RestartAllEvals()
+	// 重启所有告警规则评估器, 分片模式下由分片实例负责
+	if !shardingEnabled {
+		AlertRule.RestartAllEvals()
+	}
 
 	// 重启所有故障中心消费者
 	ConsumerWork.RestartAllConsumers()
@@ -79,10 +100,10 @@ func loadRules() {
 func startMessageSubscribers() {
 	subscriberCancels = make([]context.CancelFunc, 0)
 
-	// 订阅告警规则重载消息
-	subCtx1, cancel1 := context.WithCancel(ctx.Ctx)
-	subscriberCancels = append(subscriberCancels, cancel1)
-	go tools.SubscribeReloadMessages(subCtx1, client.Redis, tools.ChannelRuleReload, handleRuleReload)
+	// 订阅告警规则重载消息, 分片模式下已在初始化时订阅
+	if !shardingEnabled {
+		subscriberCancels = append(subscriberCancels, subscribeRuleReload())
+	}
 
 	// 订阅故障中心重载消息
 	subCtx2, cancel2 := context.WithCancel(ctx.Ctx)
@@ -95,6 +116,13 @@ func startMessageSubscribers() {
 	go tools.SubscribeReloadMessages(subCtx3, client.Redis, tools.ChannelProbingReload, handleProbingReload)
 }
 
+// subscribeRuleReload 订阅告警规则重载消息
+func subscribeRuleReload() context.CancelFunc {
+	subCtx, cancel := context.WithCancel(ctx.Ctx)
+	go tools.SubscribeReloadMessages(subCtx, client.Redis, tools.ChannelRuleReload, handleRuleReload)
+	return cancel
+}
+
 // stopMessageSubscribers 停止消息订阅器
 func stopMessageSubscribers() {
 	for _, cancel := range subscriberCancels {
@@ -106,6 +134,17 @@ func stopMessageSubscribers() {
 
 // handleRuleReload 处理告警规则重载消息
 func handleRuleReload(msg tools.ReloadMessage) {
+	// 分片模式下所有实例均收到消息, 仅由负责该规则的实例处理
+	if !IsRuleOwner(msg.ID) {
+		return
+	}
+
+	// 删除消息到达时规则已从数据库删除, 直接停止评估
+	if msg.Action == tools.ActionDelete || msg.Action == tools.ActionDisable {
+		AlertRule.Stop(msg.ID)
+		logc.Infof(ctx.Ctx, "[Leader] 已停止规则评估: %s", msg.Name)
+		return
+	}
 
 	// 从数据库获取规则
 	rule := ctx.DB.Rule().GetRuleObject(msg.ID)
@@ -127,10 +166,6 @@ func handleRuleReload(msg tools.ReloadMessage) {
 			AlertRule.Submit(rule)
 			logc.Infof(ctx.Ctx, "[Leader] 已重启规则评估: %s", msg.Name)
 		}
-
-	case tools.ActionDelete, tools.ActionDisable:
-		AlertRule.Stop(msg.ID)
-		logc.Infof(ctx.Ctx, "[Leader] 已停止规则评估: %s", msg.Name)
 	}
 }
 
@@ -203,8 +238,10 @@ func unloadRules() {
 	// 停止消息订阅
 	stopMessageSubscribers()
 
-	// 停止所有告警规则评估器
-	AlertRule.StopAllEvals()
+	// 停止所有告警规则评估器, 分片模式下与 Leader 身份无关
+	if !shardingEnabled {
+		AlertRule.StopAllEvals()
+	}
 
 	// 停止所有故障中心消费者
 	ConsumerWork.StopAllConsumers()
@@ -215,6 +252,40 @@ func unloadRules() {
 	}
 }
 
+// Shutdown 进程退出前停止所有任务, 并等待执行中的规则评估完成
+func Shutdown(c context.Context) error {
+	logc.Infof(ctx.Ctx, "服务停止中, 等待执行中的任务完成...")
+
+	stopMessageSubscribers()
+	if ruleSubscriberCancel != nil {
+		ruleSubscriberCancel()
+	}
+
+	// 先注销分片实例, 其他实例尽快接管当前实例的规则
+	if ShardWorker != nil {
+		ShardWorker.Stop()
+	}
+
+	err := AlertRule.Shutdown(c)
+
+	ConsumerWork.StopAllConsumers()
+
+	if err := Probe.StopAll(); err != nil {
+		logc.Errorf(ctx.Ctx, "停止所有拨测任务失败: %v", err)
+	}
+
+	return err
+}
+
+// IsRuleOwner 判断当前实例是否负责评估规则, 分片模式下按规则 ID 分配, 否则由 Leader 负责
+func IsRuleOwner(ruleId string) bool {
+	if shardingEnabled {
+		return ShardWorker != nil && ShardWorker.Owns(ruleId)
+	}
+
+	return IsLeader()
+}
+
 // IsLeader 判断节点角色
 func IsLeader() bool {
 	if !leaderElectionEnabled {
@@ -3,10 +3,11 @@ package consumer
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"runtime/debug"
+	"slices"
 	"sync"
 	"time"
+	"watchAlert/alert/mute"
 	"watchAlert/alert/process"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
@@ -39,6 +40,10 @@ type (
 	Consume struct {
 		ctx *ctx.Context
 		sync.RWMutex
+
+		// 按标签分组通知的状态, key 为 故障中心 ID + 分组 ID
+		groupStates map[string]*alertGroupState
+		groupLock   sync.Mutex
 	}
 
 	EventsGroup struct {
@@ -63,7 +68,7 @@ func (ag *AlertGroups) AddAlert(stateId string, alert *models.AlertCurEvent, fau
 	defer ag.lock.Unlock()
 
 	// 获取通知对象 ID 列表 用于事件分组
-	noticeObjIds := ag.getNoticeId(alert, faultCenter)
+	noticeObjIds := slices.Concat(ag.getNoticeId(alert, faultCenter), dutyNoticeIds(faultCenter))
 	if len(noticeObjIds) == 0 {
 		return // 如果没有通知对象ID，则跳过
 	}
@@ -98,29 +103,51 @@ func (ag *AlertGroups) AddAlert(stateId string, alert *models.AlertCurEvent, fau
 	}
 }
 
-// getNoticeId 从告警路由中获取该事件匹配的通知对象
+// getNoticeId 从告警路由中获取该事件匹配的通知对象, 未命中任何路由时使用故障中心默认的通知对象
 func (ag *AlertGroups) getNoticeId(alert *models.AlertCurEvent, faultCenter models.FaultCenter) []string {
 	if len(faultCenter.NoticeRoutes) > 0 {
-		labels := alert.Labels
+		if noticeIds, matched := matchNoticeRoutes(alert.Labels, faultCenter.NoticeRoutes); matched {
+			return noticeIds
+		}
+	}
 
-		for _, route := range faultCenter.NoticeRoutes {
-			val, ok := labels[route.Key].(string)
-			if !ok {
-				continue
-			}
+	return faultCenter.NoticeIds
+}
 
-			if regexp.MustCompile(route.Value).MatchString(val) {
-				return route.NoticeIds
+// matchNoticeRoutes 在同级路由中按顺序匹配, 返回命中路由的通知对象(去重)及是否命中
+func matchNoticeRoutes(labels map[string]interface{}, routes []models.NoticeRoute) ([]string, bool) {
+	var (
+		noticeIds []string
+		matched   bool
+	)
+	for _, route := range routes {
+		if !mute.MatchLabels(labels, route.GetMatchers()) {
+			continue
+		}
+		matched = true
+
+		ids, childMatched := matchNoticeRoutes(labels, route.Routes)
+		if !childMatched {
+			ids = route.NoticeIds
+		}
+		for _, id := range ids {
+			if !slices.Contains(noticeIds, id) {
+				noticeIds = append(noticeIds, id)
 			}
 		}
+
+		if !route.Continue {
+			break
+		}
 	}
 
-	return faultCenter.NoticeIds
+	return noticeIds, matched
 }
 
 func NewConsumerWork(ctx *ctx.Context) ConsumeInterface {
 	return &Consume{
-		ctx: ctx,
+		ctx:         ctx,
+		groupStates: make(map[string]*alertGroupState),
 	}
 }
 
@@ -141,6 +168,8 @@ func (c *Consume) Stop(faultCenterId string) {
 		cancel()
 		delete(c.ctx.ContextMap, faultCenterId)
 	}
+
+	c.removeGroupStates(faultCenterId)
 }
 
 func (c *Consume) Restart(faultCenter models.FaultCenter) {
@@ -189,6 +218,12 @@ func (c *Consume) executeTask(faultCenter models.FaultCenter, taskChan chan stru
 		return
 	}
 
+	// 计算抑制状态, 规则删除后同样需要解除已有的抑制
+	applyInhibition(c.ctx, faultCenter, data)
+	// 命中静默或抑制规则的告警转为已抑制状态, 结束后恢复为告警中
+	applySuppression(c.ctx, faultCenter, data)
+	// 状态频繁变化的事件标记为抖动, 抖动期间暂停通知
+	detectFlapping(c.ctx, faultCenter, data)
 	// 事件过滤
 	filterEvents := c.filterAlertEvents(faultCenter, data)
 	// 事件分组
@@ -196,6 +231,10 @@ func (c *Consume) executeTask(faultCenter models.FaultCenter, taskChan chan stru
 		Rules: make(map[string]RulesGroup),
 	}
 	c.alarmGrouping(faultCenter, &alertGroups, filterEvents)
+	// 按标签分组时, 由分组的通知时间控制发送
+	if faultCenter.IsGroupByEnabled() {
+		c.filterAlertGroups(faultCenter, &alertGroups)
+	}
 	// 发送事件
 	c.sendAlerts(faultCenter, &alertGroups)
 	// 处理告警升级
@@ -203,6 +242,8 @@ func (c *Consume) executeTask(faultCenter models.FaultCenter, taskChan chan stru
 	if err != nil {
 		logc.Error(c.ctx.Ctx, fmt.Sprintf("process alarm upgeade fail, err: %s", err.Error()))
 	}
+	// 处理多级升级
+	alarmEscalation(c.ctx, faultCenter, data)
 }
 
 // filterAlertEvents 过滤告警事件
@@ -222,11 +263,17 @@ func (c *Consume) filterAlertEvents(faultCenter models.FaultCenter, alerts map[s
 		// 记录恢复状态的事件
 		if event.IsRecovered {
 			c.removeAlertFromCache(event)
+			c.ctx.Redis.NoticeState().ResetLastNotice(event.TenantId, event.FaultCenterId, event.Fingerprint)
 			if err := process.RecordAlertHisEvent(c.ctx, *event); err != nil {
 				logc.Error(c.ctx.Ctx, fmt.Sprintf("Failed to record alert history: %v", err))
 			}
 		}
 
+		// 同一次告警的恢复通知只发送一次
+		if event.IsRecovered && !c.ctx.Redis.NoticeState().AcquireRecover(event.TenantId, event.Fingerprint, event.FirstTriggerTime) {
+			continue
+		}
+
 		if valid := c.validateEvent(event, faultCenter); valid {
 			newEvents = append(newEvents, event)
 		}
@@ -237,14 +284,26 @@ func (c *Consume) filterAlertEvents(faultCenter models.FaultCenter, alerts map[s
 
 // validateEvent 事件验证
 func (c *Consume) validateEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter) bool {
-	return event.IsRecovered || event.LastSendTime == 0 ||
-		event.LastEvalTime >= event.LastSendTime+faultCenter.RepeatNoticeInterval*60
+	// 按标签分组时, 重复通知间隔由分组控制
+	if faultCenter.IsGroupByEnabled() {
+		return true
+	}
+
+	if event.IsRecovered {
+		return true
+	}
+
+	// 告警中的事件在重复通知间隔到达后再次通知
+	lastSendTime := c.ctx.Redis.NoticeState().GetLastNotice(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	return lastSendTime == 0 ||
+		time.Now().Unix() >= lastSendTime+event.GetRepeatNoticeInterval(faultCenter.RepeatNoticeInterval)*60
 }
 
 // alarmGrouping 告警分组
 // 会进行两次分组
 // 第一次是状态+规则，避免不同状态及不同规则的事件分到一级组。
 // 第二次时告警路由，与告警路由中 KV 匹配的事件分到二级组。
+// 故障中心配置了 GroupBy 时，第一次分组改为状态+分组标签。
 func (c *Consume) alarmGrouping(faultCenter models.FaultCenter, alertGroups *AlertGroups, alerts []*models.AlertCurEvent) {
 	if len(alerts) == 0 {
 		return
@@ -253,7 +312,9 @@ func (c *Consume) alarmGrouping(faultCenter models.FaultCenter, alertGroups *Ale
 	for _, alert := range alerts {
 		// 状态+规则 = 状态 ID
 		var stateId string
-		if alert.IsRecovered {
+		if faultCenter.IsGroupByEnabled() {
+			stateId = buildGroupStateId(faultCenter, alert)
+		} else if alert.IsRecovered {
 			stateId = RecoverStatePrefix + alert.RuleId
 		} else {
 			stateId = FiringStatePrefix + alert.RuleId
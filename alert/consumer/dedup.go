@@ -0,0 +1,52 @@
+package consumer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+)
+
+// buildNoticeDedupHash 生成通知去重的 Hash, 配置了去重标签时按标签值计算, 否则按渲染后的通知内容计算
+// 同一渠道（通知类型 + Hook + 收件人）内的相同通知视为重复
+func buildNoticeDedupHash(dedup models.NoticeDedup, event *models.AlertCurEvent, route models.Route, content string) string {
+	parts := []string{route.NoticeType, route.Hook, strings.Join(route.To, ",")}
+	if len(dedup.Labels) > 0 {
+		labels := make([]string, 0, len(dedup.Labels))
+		for _, key := range dedup.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%v", key, event.Labels[key]))
+		}
+		sort.Strings(labels)
+		parts = append(parts, fmt.Sprintf("recovered=%v", event.IsRecovered))
+		parts = append(parts, labels...)
+	} else {
+		parts = append(parts, content)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// dedupNotice 通知去重, 返回 true 表示窗口内已发送过相同通知, 本次不再发送
+// 未被去重时返回窗口内被去重的次数, 用于在通知中提示; 返回的 release 用于发送失败时释放去重窗口, 避免后续重试被误判为重复
+func dedupNotice(ctx *ctx.Context, faultCenter models.FaultCenter, event *models.AlertCurEvent, route models.Route, content string) (bool, int64, func()) {
+	dedup := faultCenter.NoticeDedup
+	if dedup.Window <= 0 {
+		return false, 0, func() {}
+	}
+
+	hash := buildNoticeDedupHash(dedup, event, route, content)
+	if !ctx.Redis.NoticeDedup().Acquire(event.TenantId, hash, time.Duration(dedup.Window)*time.Second) {
+		ctx.Redis.NoticeDedup().IncrSuppressed(event.TenantId, hash)
+		return true, 0, func() {}
+	}
+
+	repeated := ctx.Redis.NoticeDedup().PopSuppressed(event.TenantId, hash)
+	return false, repeated, func() {
+		ctx.Redis.NoticeDedup().Release(event.TenantId, hash, repeated)
+	}
+}
@@ -0,0 +1,63 @@
+package consumer
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// alarmEscalation 多级升级, 告警中且未认领的事件按升级策略逐级通知
+// 升级进度随事件存储在 Redis 中, 认领后停止升级, 认领过期后继续升级
+func alarmEscalation(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) {
+	policy := faultCenter.EscalationPolicy
+	if !policy.GetEnabled() || len(policy.Steps) == 0 {
+		return
+	}
+
+	currentTime := time.Now().Unix()
+	for _, event := range alerts {
+		if event.Status != models.StateAlerting || event.IsRecovered || event.ConfirmState.IsAcknowledged(currentTime) {
+			continue
+		}
+
+		if !policy.IsMatchSeverity(event.Severity) || isMutedEvent(event, faultCenter) {
+			continue
+		}
+
+		level := getEscalationLevel(policy, event.FirstTriggerTime, currentTime)
+		if level <= event.EscalationState.Level {
+			continue
+		}
+
+		// 先记录升级进度, 避免发送耗时较长时重复升级
+		event.EscalationState = models.EscalationState{
+			Level:            level,
+			LastEscalateTime: currentTime,
+		}
+		ctx.Redis.Alert().PushAlertEvent(event)
+
+		step := policy.Steps[level-1]
+		logc.Alert(ctx.Ctx, fmt.Sprintf("Alarm escalated to level %d, fingerprint: %s, unacknowledged for %d min", level, event.Fingerprint, step.Timeout))
+
+		escalated := *event
+		escalated.Annotations = fmt.Sprintf("%s\n告警未认领已超过 %d 分钟, 升级至第 %d 级通知", escalated.Annotations, step.Timeout, level)
+		if err := handleAlert(ctx, "escalation", faultCenter, step.NoticeId, []*models.AlertCurEvent{&escalated}); err != nil {
+			logc.Error(ctx.Ctx, fmt.Sprintf("send escalation alert failed, err: %s", err.Error()))
+		}
+	}
+}
+
+// getEscalationLevel 根据未认领时长计算应达到的升级级别
+func getEscalationLevel(policy models.EscalationPolicy, firstTriggerTime, currentTime int64) int {
+	level := 0
+	for i, step := range policy.Steps {
+		if currentTime-firstTriggerTime < step.Timeout*60 {
+			break
+		}
+		level = i + 1
+	}
+	return level
+}
@@ -0,0 +1,72 @@
+package consumer
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// detectFlapping 统计事件在窗口内的状态变化次数, 达到阈值时标记为抖动并暂停通知, 回落到阈值以下时解除
+// 每次告警及恢复各计一次变化, 事件恢复后从缓存中移除, 变化记录按指纹单独保存以便跨越多次告警统计
+func detectFlapping(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) {
+	flapping, err := ctx.Redis.Flapping().List(faultCenter.TenantId, faultCenter.ID)
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to list flapping events, faultCenterId: %s, err: %s", faultCenter.ID, err.Error()))
+		return
+	}
+
+	detection := faultCenter.FlappingDetection
+	if !detection.IsEnabled() {
+		// 关闭检测后解除所有抖动状态
+		for fingerprint := range flapping {
+			ctx.Redis.Flapping().Unmark(faultCenter.TenantId, faultCenter.ID, fingerprint)
+		}
+		for _, event := range alerts {
+			event.IsFlapping = false
+		}
+		return
+	}
+
+	window := time.Duration(detection.Window) * time.Minute
+	for fingerprint, event := range alerts {
+		var change string
+		switch {
+		case event.IsRecovered:
+			change = fmt.Sprintf("recovered:%d", event.RecoverTime)
+		case event.Status == models.StateAlerting, event.Status == models.StateSuppressed, event.Status == models.StatePendingRecovery:
+			change = fmt.Sprintf("alerting:%d", event.FirstTriggerTime)
+		default:
+			continue
+		}
+
+		count, err := ctx.Redis.Flapping().RecordChange(faultCenter.TenantId, faultCenter.ID, fingerprint, change, window)
+		if err != nil {
+			logc.Error(ctx.Ctx, fmt.Sprintf("Failed to record state change, fingerprint: %s, err: %s", fingerprint, err.Error()))
+			continue
+		}
+
+		if _, ok := flapping[fingerprint]; !ok && count >= detection.Threshold {
+			flapping[fingerprint] = time.Now().Unix()
+			ctx.Redis.Flapping().Mark(faultCenter.TenantId, faultCenter.ID, fingerprint, flapping[fingerprint])
+			logc.Info(ctx.Ctx, fmt.Sprintf("Alarm entered flapping, fingerprint: %s, rule: %s, %d state changes in %d min", fingerprint, event.RuleName, count, detection.Window))
+		}
+	}
+
+	for fingerprint := range flapping {
+		count, err := ctx.Redis.Flapping().CountChanges(faultCenter.TenantId, faultCenter.ID, fingerprint, window)
+		if err != nil || count >= detection.Threshold {
+			continue
+		}
+
+		ctx.Redis.Flapping().Unmark(faultCenter.TenantId, faultCenter.ID, fingerprint)
+		delete(flapping, fingerprint)
+		logc.Info(ctx.Ctx, fmt.Sprintf("Alarm left flapping, fingerprint: %s, %d state changes in %d min", fingerprint, count, detection.Window))
+	}
+
+	for fingerprint, event := range alerts {
+		_, event.IsFlapping = flapping[fingerprint]
+	}
+}
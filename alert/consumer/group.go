@@ -0,0 +1,184 @@
+package consumer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+)
+
+// 分组通知中最多列出的指纹数量
+const maxGroupFingerprints = 20
+
+type (
+	// alertGroupState 按标签分组的告警通知状态
+	alertGroupState struct {
+		// 分组首次出现时间
+		FirstSeen int64
+		// 上一次发送通知的时间
+		LastSend int64
+		// 上一次通知时分组内的事件指纹
+		Fingerprints map[string]struct{}
+	}
+)
+
+// buildGroupStateId 按 GroupBy 标签生成一级分组 ID, 替代默认的按规则分组
+func buildGroupStateId(faultCenter models.FaultCenter, alert *models.AlertCurEvent) string {
+	groupKey := faultCenter.BuildGroupKey(alert.Labels)
+	if alert.IsRecovered {
+		return RecoverStatePrefix + groupKey
+	}
+	return FiringStatePrefix + groupKey
+}
+
+// buildGroupStateKey 分组状态的存储 Key
+func buildGroupStateKey(faultCenterId, stateId string) string {
+	return faultCenterId + "/" + stateId
+}
+
+// filterAlertGroups 根据 GroupWait / GroupInterval 过滤未到通知时间的分组, 并维护分组状态
+// 恢复事件会从对应的告警分组中移除, 分组内事件全部恢复后关闭该分组
+func (c *Consume) filterAlertGroups(faultCenter models.FaultCenter, alertGroups *AlertGroups) {
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
+
+	curTime := time.Now().Unix()
+
+	// 先处理恢复事件, 更新告警分组的成员
+	for stateId, rule := range alertGroups.Rules {
+		if !strings.HasPrefix(stateId, RecoverStatePrefix) {
+			continue
+		}
+
+		firingKey := buildGroupStateKey(faultCenter.ID, FiringStatePrefix+strings.TrimPrefix(stateId, RecoverStatePrefix))
+		state, ok := c.groupStates[firingKey]
+		if !ok {
+			continue
+		}
+
+		for fingerprint := range groupFingerprints(rule) {
+			delete(state.Fingerprints, fingerprint)
+		}
+		if len(state.Fingerprints) == 0 {
+			delete(c.groupStates, firingKey)
+		}
+	}
+
+	active := make(map[string]struct{})
+	for stateId, rule := range alertGroups.Rules {
+		if !strings.HasPrefix(stateId, FiringStatePrefix) {
+			continue
+		}
+
+		key := buildGroupStateKey(faultCenter.ID, stateId)
+		active[key] = struct{}{}
+
+		state, ok := c.groupStates[key]
+		if !ok {
+			state = &alertGroupState{FirstSeen: curTime}
+			c.groupStates[key] = state
+		}
+
+		fingerprints := groupFingerprints(rule)
+		if !isGroupDue(faultCenter, state, fingerprints, curTime) {
+			delete(alertGroups.Rules, stateId)
+			continue
+		}
+
+		state.LastSend = curTime
+		state.Fingerprints = fingerprints
+	}
+
+	// 清理已不存在的分组
+	prefix := faultCenter.ID + "/"
+	for key := range c.groupStates {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if _, ok := active[key]; !ok {
+			delete(c.groupStates, key)
+		}
+	}
+}
+
+// isGroupDue 判断分组是否需要发送通知
+func isGroupDue(faultCenter models.FaultCenter, state *alertGroupState, fingerprints map[string]struct{}, curTime int64) bool {
+	// 新分组等待 GroupWait 以收集同组的其他事件
+	if state.LastSend == 0 {
+		return curTime-state.FirstSeen >= faultCenter.GroupWait
+	}
+
+	// 分组内出现新事件, 间隔 GroupInterval 后通知
+	for fingerprint := range fingerprints {
+		if _, ok := state.Fingerprints[fingerprint]; !ok {
+			return curTime-state.LastSend >= faultCenter.GroupInterval
+		}
+	}
+
+	// 分组无变化, 按重复通知间隔发送
+	if faultCenter.RepeatNoticeInterval <= 0 {
+		return false
+	}
+	return curTime-state.LastSend >= faultCenter.RepeatNoticeInterval*60
+}
+
+// groupFingerprints 获取分组内所有事件的指纹
+func groupFingerprints(rule RulesGroup) map[string]struct{} {
+	fingerprints := make(map[string]struct{})
+	for _, group := range rule.Groups {
+		for _, event := range group.Events {
+			fingerprints[event.Fingerprint] = struct{}{}
+		}
+	}
+	return fingerprints
+}
+
+// removeGroupStates 清理故障中心的所有分组状态
+func (c *Consume) removeGroupStates(faultCenterId string) {
+	c.groupLock.Lock()
+	defer c.groupLock.Unlock()
+
+	prefix := faultCenterId + "/"
+	for key := range c.groupStates {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.groupStates, key)
+		}
+	}
+}
+
+// mergeGroupedAlerts 将同一分组的事件合并为一条通知, 以最高等级的事件为基础并附加事件数量及指纹列表
+func mergeGroupedAlerts(faultCenter models.FaultCenter, alerts []*models.AlertCurEvent) []*models.AlertCurEvent {
+	var events []*models.AlertCurEvent
+	for _, alert := range alerts {
+		if alert.Fingerprint != "" {
+			events = append(events, alert)
+		}
+	}
+	if len(events) <= 1 {
+		return events
+	}
+
+	base := events[0]
+	fingerprints := make([]string, 0, len(events))
+	for _, event := range events {
+		// P0 > P1 > P2
+		if event.Severity < base.Severity {
+			base = event
+		}
+		fingerprints = append(fingerprints, event.Fingerprint)
+	}
+
+	if len(fingerprints) > maxGroupFingerprints {
+		fingerprints = append(fingerprints[:maxGroupFingerprints], "...")
+	}
+
+	state := "告警中"
+	if base.IsRecovered {
+		state = "已恢复"
+	}
+
+	event := *base
+	event.Annotations += fmt.Sprintf("\n分组: %s, 共 %d 条事件%s\n事件指纹: %s\n",
+		faultCenter.BuildGroupKey(base.Labels), len(events), state, strings.Join(fingerprints, ", "))
+	return []*models.AlertCurEvent{&event}
+}
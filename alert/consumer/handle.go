@@ -29,6 +29,22 @@ func handleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 		return err
 	}
 
+	// 记录发送时间
+	if processType == "alarm" {
+		for _, alert := range alerts {
+			if alert.Fingerprint != "" && !alert.IsRecovered {
+				alert.LastSendTime = curTime
+				ctx.Redis.Alert().PushAlertEvent(alert)
+				ctx.Redis.NoticeState().SetLastNotice(alert.TenantId, alert.FaultCenterId, alert.Fingerprint, curTime)
+			}
+		}
+	}
+
+	// 按标签分组的事件合并为一条通知
+	if processType == "alarm" && faultCenter.IsGroupByEnabled() {
+		alerts = mergeGroupedAlerts(faultCenter, alerts)
+	}
+
 	// 按告警等级分组
 	severityGroups := make(map[string][]*models.AlertCurEvent)
 	for _, alert := range alerts {
@@ -50,46 +66,64 @@ func handleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 			}
 
 			// 获取当前事件等级对应的路由配置
-			routes := getNoticeRoutes(noticeData, severity)
+			routes, routeIndexes := getNoticeRoutes(noticeData, severity, time.Now().In(ctx.TenantLocation(noticeData.TenantId)))
+			recoverNotify := isRecoverNotify(faultCenter, routes)
 			for _, event := range events {
 				if event.Fingerprint == "" {
 					continue
 				}
 
-				if processType == "alarm" && !event.IsRecovered {
-					event.LastSendTime = curTime
-					ctx.Redis.Alert().PushAlertEvent(event)
-				}
-
 				if len(routes) == 0 {
 					logc.Infof(ctx.Ctx, "没有匹配的通知策略, 告警事件名称: %s, 通知对象名称: %s", event.RuleName, noticeData.Name)
 				}
 
 				if mute.IsMuted(mute.MuteParams{
 					IsRecovered:   event.IsRecovered,
+					IsSuppressed:  event.IsSuppressed,
+					IsInhibited:   event.IsInhibited,
+					IsFlapping:    event.IsFlapping,
+					InMaintenance: faultCenter.InMaintenance(time.Now().In(ctx.TenantLocation(event.TenantId))),
 					TenantId:      event.TenantId,
 					Labels:        event.Labels,
 					FaultCenterId: event.FaultCenterId,
-					RecoverNotify: faultCenter.RecoverNotify,
+					Fingerprint:   event.Fingerprint,
+					RecoverNotify: recoverNotify,
 				}) {
 					continue
 				}
 
-				for _, route := range routes {
+				for i, route := range routes {
+					if event.IsRecovered && !route.IsRecoverNotify(faultCenter.GetRecoverNotify()) {
+						continue
+					}
+
 					// 设置值班用户信息
 					event.DutyUser = strings.Join(getDutyUsers(ctx, noticeData, route.NoticeType), " ")
 
 					// 生成告警内容
 					content := generateAlertContent(ctx, event, noticeData, route)
 
+					// 通知去重
+					deduped, repeated, releaseDedup := dedupNotice(ctx, faultCenter, event, route, content)
+					if deduped {
+						logc.Infof(ctx.Ctx, "通知已去重, 告警事件名称: %s, 通知类型: %s", event.RuleName, route.NoticeType)
+						continue
+					}
+					if repeated > 0 {
+						e := *event
+						e.Annotations += fmt.Sprintf("\n该通知在去重窗口内重复 %d 次\n", repeated)
+						content = generateAlertContent(ctx, &e, noticeData, route)
+					}
+
 					// 构建邮件信息
 					email := models.Email{
-						Subject: route.Subject,
+						Subject: templates.RenderSubject(*event, route.Subject),
 						To:      route.To,
 						CC:      route.CC,
 					}
 
 					// 发送告警
+					result := &sender.SendResult{}
 					err := sender.Sender(ctx, sender.SendParams{
 						TenantId:    event.TenantId,
 						EventId:     event.EventId,
@@ -103,9 +137,19 @@ func handleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 						Email:       email,
 						Content:     content,
 						Sign:        route.Sign,
+						ChatId:      route.ChatId,
+						RoutingKey:  route.RoutingKey,
+						RouteIndex:  routeIndexes[i],
+						Result:      result,
 					})
 					if err != nil {
 						logc.Error(ctx.Ctx, fmt.Sprintf("Failed to send alert: %v", err))
+						releaseDedup()
+						continue
+					}
+
+					if result.IncidentKey != "" && !event.IsRecovered {
+						setIncidentKey(ctx, event, result.IncidentKey)
 					}
 				}
 			}
@@ -117,6 +161,22 @@ func handleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 	return g.Wait()
 }
 
+// setIncidentKey 将外部事件平台返回的 incident key 回写到缓存中的事件
+func setIncidentKey(ctx *ctx.Context, event *models.AlertCurEvent, incidentKey string) {
+	if event.IncidentKey == incidentKey {
+		return
+	}
+	event.IncidentKey = incidentKey
+
+	// 重新读取缓存, 避免覆盖评估过程中更新的事件状态
+	cacheEvent, err := ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if err != nil || cacheEvent.Fingerprint == "" {
+		return
+	}
+	cacheEvent.IncidentKey = incidentKey
+	ctx.Redis.Alert().PushAlertEvent(&cacheEvent)
+}
+
 // alarmAggregation 告警聚合
 func alarmAggregation(ctx *ctx.Context, processType string, faultCenter models.FaultCenter, alertGroups map[string][]*models.AlertCurEvent) map[string][]*models.AlertCurEvent {
 	// 仅当 processType 为 "alarm" 时执行聚合
@@ -124,12 +184,11 @@ func alarmAggregation(ctx *ctx.Context, processType string, faultCenter models.F
 		return alertGroups
 	}
 
-	curTime := time.Now().Unix()
 	newAlertGroups := alertGroups
 	switch faultCenter.GetAlarmAggregationType() {
 	case "Rule":
 		for severity, events := range alertGroups {
-			newAlertGroups[severity] = withRuleGroupByAlerts(ctx, curTime, events)
+			newAlertGroups[severity] = withRuleGroupByAlerts(events)
 		}
 	default:
 		return alertGroups
@@ -139,19 +198,11 @@ func alarmAggregation(ctx *ctx.Context, processType string, faultCenter models.F
 }
 
 // withRuleGroupByAlerts 聚合告警
-func withRuleGroupByAlerts(ctx *ctx.Context, timeInt int64, alerts []*models.AlertCurEvent) []*models.AlertCurEvent {
+func withRuleGroupByAlerts(alerts []*models.AlertCurEvent) []*models.AlertCurEvent {
 	if len(alerts) <= 1 {
 		return alerts
 	}
 
-	for i := range alerts {
-		alert := alerts[i]
-		if !alert.IsRecovered {
-			alert.LastSendTime = timeInt
-			ctx.Redis.Alert().PushAlertEvent(alert)
-		}
-	}
-
 	event := *alerts[0]
 	event.Annotations += fmt.Sprintf("\n聚合 %d 条消息，详情请前往 WatchAlert 查看\n", len(alerts))
 	return []*models.AlertCurEvent{&event}
@@ -159,25 +210,33 @@ func withRuleGroupByAlerts(ctx *ctx.Context, timeInt int64, alerts []*models.Ale
 
 // getNoticeData 获取 Notice 数据
 func getNoticeData(ctx *ctx.Context, tenantId, noticeId string) (models.AlertNotice, error) {
+	if strings.HasPrefix(noticeId, DutyNoticePrefix) {
+		return resolveDutyNotice(ctx, tenantId, noticeId)
+	}
+
 	return ctx.DB.Notice().Get(tenantId, noticeId)
 }
 
-// getNoticeRoutes 获取事件等级对应的路由配置
-func getNoticeRoutes(notice models.AlertNotice, severity string) []models.Route {
-	var routes []models.Route
+// getNoticeRoutes 获取事件等级对应的路由配置, 同时返回各路由在通知对象中的序号
+func getNoticeRoutes(notice models.AlertNotice, severity string, now time.Time) ([]models.Route, []int) {
+	var (
+		routes  []models.Route
+		indexes []int
+	)
 	if notice.Routes != nil {
 		for i, route := range notice.Routes {
-			if process.NotInTheEffectiveTime(route.EffectiveTime) {
+			if process.NotInTheEffectiveTime(route.EffectiveTime, now) {
 				logc.Infof(ctx.Ctx, "Notice %v route [%v] is not in effective time", notice.Name, i+1)
 				continue
 			}
 			if slices.Contains(route.Severitys, severity) {
 				routes = append(routes, route)
+				indexes = append(indexes, i)
 			}
 		}
 	}
 
-	return routes
+	return routes, indexes
 }
 
 type WebhookContent struct {
@@ -188,7 +247,7 @@ type WebhookContent struct {
 // generateAlertContent 生成告警内容
 func generateAlertContent(ctx *ctx.Context, alert *models.AlertCurEvent, noticeData models.AlertNotice, route models.Route) string {
 	if route.NoticeType == "WebHook" {
-		users, ok := ctx.DB.DutyCalendar().GetDutyUserInfo(*noticeData.GetDutyId(), time.Now().Format("2006-1-2"))
+		users, ok := getOnCallUsers(ctx, noticeData.TenantId, *noticeData.GetDutyId())
 		if !ok || len(users) == 0 {
 			logc.Error(ctx.Ctx, "Failed to get duty users, noticeName: ", noticeData.Name)
 		}
@@ -220,7 +279,7 @@ func generateAlertContent(ctx *ctx.Context, alert *models.AlertCurEvent, noticeD
 
 func getDutyUsers(ctx *ctx.Context, noticeData models.AlertNotice, noticeType string) []string {
 	var us []string
-	users, ok := ctx.DB.DutyCalendar().GetDutyUserInfo(*noticeData.GetDutyId(), time.Now().Format("2006-1-2"))
+	users, ok := getOnCallUsers(ctx, noticeData.TenantId, *noticeData.GetDutyId())
 	if ok {
 		switch noticeType {
 		case "FeiShu":
@@ -233,7 +292,7 @@ func getDutyUsers(ctx *ctx.Context, noticeData models.AlertNotice, noticeType st
 				us = append(us, fmt.Sprintf("@%s", user.DutyUserId))
 			}
 			return us
-		case "Email", "WeChat", "WebHook":
+		case "Email", "WeChat", "WebHook", "Teams", "PagerDuty":
 			for _, user := range users {
 				us = append(us, fmt.Sprintf("@%s", user.UserName))
 			}
@@ -243,8 +302,22 @@ func getDutyUsers(ctx *ctx.Context, noticeData models.AlertNotice, noticeType st
 				us = append(us, fmt.Sprintf("<@%s>", user.DutyUserId))
 			}
 			return us
+		case "Telegram":
+			for _, user := range users {
+				us = append(us, fmt.Sprintf("@%s", user.DutyUserId))
+			}
+			return us
 		}
 	}
 
 	return []string{"暂无"}
 }
+
+// isRecoverNotify 故障中心或任一渠道开启恢复通知时返回 true, 具体渠道是否发送由渠道配置决定
+func isRecoverNotify(faultCenter models.FaultCenter, routes []models.Route) *bool {
+	enabled := faultCenter.GetRecoverNotify()
+	for _, route := range routes {
+		enabled = enabled || route.IsRecoverNotify(faultCenter.GetRecoverNotify())
+	}
+	return &enabled
+}
@@ -0,0 +1,70 @@
+package consumer
+
+import (
+	"fmt"
+	"watchAlert/alert/mute"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// applyInhibition 按故障中心的抑制规则重新计算事件的抑制状态
+// 被抑制的事件仍保留在 Redis 中, 仅标记 IsInhibited, 源事件恢复后在下一轮消费时自动解除
+func applyInhibition(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) {
+	for _, event := range alerts {
+		// 已恢复的事件保留原有抑制状态, 避免发送未通知过的恢复消息
+		if event.IsRecovered {
+			continue
+		}
+
+		inhibited := isInhibited(faultCenter.InhibitRules, event, alerts)
+		if inhibited == event.IsInhibited {
+			continue
+		}
+
+		event.IsInhibited = inhibited
+		ctx.Redis.Alert().PushAlertEvent(event)
+		if inhibited {
+			logc.Info(ctx.Ctx, fmt.Sprintf("Alarm inhibited, fingerprint: %s", event.Fingerprint))
+		} else {
+			logc.Info(ctx.Ctx, fmt.Sprintf("Alarm inhibition released, fingerprint: %s", event.Fingerprint))
+		}
+	}
+}
+
+// isInhibited 判断事件是否被任一抑制规则抑制
+func isInhibited(rules []models.InhibitRule, target *models.AlertCurEvent, alerts map[string]*models.AlertCurEvent) bool {
+	for _, rule := range rules {
+		if !mute.MatchLabels(target.Labels, rule.TargetMatchers) {
+			continue
+		}
+
+		for _, source := range alerts {
+			if source.Fingerprint == target.Fingerprint || !isActiveSource(source) {
+				continue
+			}
+
+			if mute.MatchLabels(source.Labels, rule.SourceMatchers) && isEqualLabels(rule.Equal, source, target) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isActiveSource 仅告警中(包括已被静默或抑制)且未恢复的事件可作为抑制源
+func isActiveSource(event *models.AlertCurEvent) bool {
+	return (event.Status == models.StateAlerting || event.Status == models.StateSuppressed) && !event.IsRecovered
+}
+
+// isEqualLabels 判断源事件与目标事件指定标签的值是否相同
+func isEqualLabels(keys []string, source, target *models.AlertCurEvent) bool {
+	for _, key := range keys {
+		if fmt.Sprint(source.Labels[key]) != fmt.Sprint(target.Labels[key]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package consumer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// DutyNoticePrefix 故障中心引用值班表时使用的通知对象 ID 前缀
+const DutyNoticePrefix = "duty:"
+
+// 按值班人员偏好渠道通知时匹配所有告警等级
+var allSeveritys = []string{"P0", "P1", "P2"}
+
+// dutyNoticeIds 将故障中心引用的值班表转换为通知对象 ID
+func dutyNoticeIds(faultCenter models.FaultCenter) []string {
+	ids := make([]string, 0, len(faultCenter.DutyIds))
+	for _, dutyId := range faultCenter.DutyIds {
+		ids = append(ids, DutyNoticePrefix+dutyId)
+	}
+	return ids
+}
+
+// getOnCallUsers 获取当前值班人员, 临时换班优先于值班表
+func getOnCallUsers(ctx *ctx.Context, tenantId, dutyId string) ([]models.Member, bool) {
+	if dutyId == "" {
+		return nil, false
+	}
+
+	duty, err := ctx.DB.Duty().Get(tenantId, dutyId)
+	if err == nil {
+		overrides := duty.GetActiveOverrides(time.Now().Unix())
+		if len(overrides) > 0 {
+			var users []models.Member
+			for _, o := range overrides {
+				user, ok, err := ctx.DB.User().Get(o.UserId, "", "")
+				if !ok {
+					logc.Errorf(ctx.Ctx, "获取换班人员信息失败, userId: %s, err: %v", o.UserId, err)
+					continue
+				}
+				users = append(users, user)
+			}
+			if len(users) > 0 {
+				return users, true
+			}
+		}
+	}
+
+	// 值班日期按租户时区计算
+	return ctx.DB.DutyCalendar().GetDutyUserInfo(dutyId, time.Now().In(ctx.TenantLocation(tenantId)).Format("2006-1-2"))
+}
+
+// resolveDutyNotice 根据当前值班人员的偏好渠道生成通知对象
+func resolveDutyNotice(ctx *ctx.Context, tenantId, noticeId string) (models.AlertNotice, error) {
+	dutyId := strings.TrimPrefix(noticeId, DutyNoticePrefix)
+	duty, err := ctx.DB.Duty().Get(tenantId, dutyId)
+	if err != nil {
+		return models.AlertNotice{}, fmt.Errorf("获取值班表失败, dutyId: %s, err: %s", dutyId, err.Error())
+	}
+
+	notice := models.AlertNotice{
+		TenantId: tenantId,
+		Uuid:     noticeId,
+		Name:     duty.Name,
+		DutyId:   &dutyId,
+	}
+
+	users, ok := getOnCallUsers(ctx, tenantId, dutyId)
+	if !ok {
+		logc.Infof(ctx.Ctx, "值班表 %s 当前没有值班人员", duty.Name)
+		return notice, nil
+	}
+
+	for _, user := range users {
+		channel := user.NoticeChannel
+		if channel.NoticeType == "" {
+			logc.Infof(ctx.Ctx, "值班人员 %s 未配置通知渠道", user.UserName)
+			continue
+		}
+
+		route := models.Route{
+			NoticeType:   channel.NoticeType,
+			NoticeTmplId: duty.NoticeTmplId,
+			Severitys:    allSeveritys,
+			Hook:         channel.Hook,
+			Sign:         channel.Sign,
+			ChatId:       channel.ChatId,
+		}
+		if channel.NoticeType == "Email" {
+			route.Subject = duty.Name
+			route.To = []string{user.Email}
+		}
+		notice.Routes = append(notice.Routes, route)
+	}
+
+	return notice, nil
+}
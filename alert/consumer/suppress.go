@@ -0,0 +1,68 @@
+package consumer
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/alert/mute"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// applySuppression 告警中的事件命中静默、被抑制或暂停通知时转为已抑制状态, 结束后回到告警中
+// 手动抑制的事件不改变状态, 仍按原有方式仅屏蔽通知, 以免事件无法恢复
+func applySuppression(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) {
+	snoozes := activeSnoozes(ctx, faultCenter, alerts)
+	for _, event := range alerts {
+		if event.IsRecovered {
+			continue
+		}
+		if event.Status != models.StateAlerting && event.Status != models.StateSuppressed {
+			continue
+		}
+
+		suppressed := event.IsInhibited || snoozes[event.Fingerprint] || mute.IsSilence(mute.MuteParams{
+			TenantId:      event.TenantId,
+			FaultCenterId: faultCenter.ID,
+			Labels:        event.Labels,
+		})
+
+		target := models.StateAlerting
+		if suppressed {
+			target = models.StateSuppressed
+		}
+		if event.Status == target {
+			continue
+		}
+
+		if err := event.TransitionStatus(target); err != nil {
+			logc.Error(ctx.Ctx, fmt.Sprintf("Failed to transition to「%s」state for fingerprint %s: %v", target, event.Fingerprint, err))
+			continue
+		}
+		ctx.Redis.Alert().PushAlertEvent(event)
+		logc.Info(ctx.Ctx, fmt.Sprintf("Alarm state changed to %s, fingerprint: %s", target, event.Fingerprint))
+	}
+}
+
+// activeSnoozes 获取暂停通知中的事件, 同时清理已到期或事件已不存在的暂停记录
+func activeSnoozes(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) map[string]bool {
+	snoozes, err := ctx.Redis.Snooze().List(faultCenter.TenantId, faultCenter.ID)
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to list snoozed events, faultCenterId: %s, err: %s", faultCenter.ID, err.Error()))
+		return nil
+	}
+
+	now := time.Now().Unix()
+	active := make(map[string]bool, len(snoozes))
+	for fingerprint, until := range snoozes {
+		event, ok := alerts[fingerprint]
+		if !ok || event.IsRecovered || until <= now {
+			ctx.Redis.Snooze().Delete(faultCenter.TenantId, faultCenter.ID, fingerprint)
+			continue
+		}
+		active[fingerprint] = true
+	}
+
+	return active
+}
@@ -34,8 +34,8 @@ func alarmUpgrade(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[s
 	confirmAggregated := createAggregatedAlert(models.ConfirmStatus, faultCenter)
 	// 遍历事件并处理升级阶段
 	for _, event := range filterAlerts {
-		// 确认阶段
-		if !event.ConfirmState.IsOk {
+		// 确认阶段, 认领过期的事件视为未认领
+		if !event.ConfirmState.IsAcknowledged(currentTime) {
 			if err := processStage(ctx, faultCenter, event, currentTime, confirmAggregated, models.ConfirmStatus); err != nil {
 				logc.Error(ctx.Ctx, fmt.Errorf("process confirm stage failed: %w", err))
 			}
@@ -55,7 +55,7 @@ func filterAlertEvents(faultCenter models.FaultCenter, alerts map[string]*models
 
 	for _, event := range alerts {
 		switch event.Status {
-		case models.StatePreAlert, models.StatePendingRecovery:
+		case models.StatePreAlert, models.StatePendingRecovery, models.StateSuppressed:
 			continue
 		}
 
@@ -84,9 +84,14 @@ func filterAlertEvents(faultCenter models.FaultCenter, alerts map[string]*models
 func isMutedEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter) bool {
 	return mute.IsMuted(mute.MuteParams{
 		IsRecovered:   event.IsRecovered,
+		IsSuppressed:  event.IsSuppressed,
+		IsInhibited:   event.IsInhibited,
+		IsFlapping:    event.IsFlapping,
+		InMaintenance: faultCenter.InMaintenance(time.Now().In(ctx.DO().TenantLocation(event.TenantId))),
 		TenantId:      event.TenantId,
 		Labels:        event.Labels,
 		FaultCenterId: event.FaultCenterId,
+		Fingerprint:   event.Fingerprint,
 		RecoverNotify: faultCenter.RecoverNotify,
 	})
 }
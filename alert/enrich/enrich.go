@@ -0,0 +1,117 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 查询失败时的缓存时间, CMDB 故障期间避免每次评估都等待超时
+const failureCacheTTL = 30 * time.Second
+
+type cacheEntry struct {
+	labels   map[string]string
+	expireAt time.Time
+}
+
+var cache = struct {
+	sync.RWMutex
+	m map[string]cacheEntry
+}{m: make(map[string]cacheEntry)}
+
+// Enrich 查询事件的归属信息并合并到事件标签中, 不覆盖已有标签; 查询失败时不影响告警
+func Enrich(ctx context.Context, cfg models.EventEnrichment, event *models.AlertCurEvent) {
+	if event == nil || !cfg.GetEnabled() {
+		return
+	}
+
+	label, value := lookupValue(cfg, event.Labels)
+	if value == "" {
+		return
+	}
+
+	for k, v := range lookup(ctx, cfg, label, value) {
+		if _, exists := event.Labels[k]; !exists {
+			event.Labels[k] = v
+		}
+	}
+}
+
+// lookupValue 按顺序获取第一个存在的查询标签
+func lookupValue(cfg models.EventEnrichment, labels map[string]interface{}) (string, string) {
+	for _, label := range cfg.GetLookupLabels() {
+		if v, ok := labels[label]; ok && fmt.Sprint(v) != "" {
+			return label, fmt.Sprint(v)
+		}
+	}
+	return "", ""
+}
+
+func lookup(ctx context.Context, cfg models.EventEnrichment, label, value string) map[string]string {
+	if cfg.Type == models.EnrichmentTypeMapping {
+		return cfg.Mapping[value]
+	}
+
+	key := strings.Join([]string{cfg.Url, label, value}, "|")
+	cache.RLock()
+	entry, ok := cache.m[key]
+	cache.RUnlock()
+	if ok && time.Now().Before(entry.expireAt) {
+		return entry.labels
+	}
+
+	labels, err := query(cfg, label, value)
+	ttl := time.Duration(cfg.GetCacheTTL()) * time.Second
+	if err != nil {
+		logc.Errorf(ctx, "事件富化查询失败, label: %s, value: %s, err: %s", label, value, err.Error())
+		// 保留过期前的结果, 故障期间继续使用
+		labels, ttl = entry.labels, failureCacheTTL
+	}
+
+	cache.Lock()
+	cache.m[key] = cacheEntry{labels: labels, expireAt: time.Now().Add(ttl)}
+	cache.Unlock()
+
+	return labels
+}
+
+// query 请求富化接口, 接口返回 JSON 对象, 值统一转换为字符串
+func query(cfg models.EventEnrichment, label, value string) (map[string]string, error) {
+	u, err := url.Parse(cfg.Url)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("label", label)
+	q.Set("value", value)
+	u.RawQuery = q.Encode()
+
+	resp, err := tools.Get(cfg.Headers, u.String(), cfg.GetTimeout())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body map[string]interface{}
+	if err := tools.ParseReaderBody(resp.Body, &body); err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(body))
+	for k, v := range body {
+		labels[k] = fmt.Sprint(v)
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,47 @@
+package eval
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// annotationTemplateHeader 与 Prometheus 一致, 允许在注解模版中使用 $labels、$value
+const annotationTemplateHeader = `{{ $labels := .Labels }}{{ $value := .Value }}`
+
+// annotationTemplateData 注解模版可用的变量
+type annotationTemplateData struct {
+	Labels map[string]interface{}
+	Value  interface{}
+	Event  *models.AlertCurEvent
+}
+
+// renderAnnotations 使用 Go text/template 渲染事件注解, 渲染失败时记录日志并保留原始内容
+func renderAnnotations(ctx *ctx.Context, event *models.AlertCurEvent) {
+	if !strings.Contains(event.Annotations, "{{") {
+		return
+	}
+
+	tmpl, err := template.New("annotations").Option("missingkey=zero").Parse(annotationTemplateHeader + event.Annotations)
+	if err != nil {
+		logc.Errorf(eventLogContext(ctx.Ctx, event), "告警注解模版解析失败, err: %s", err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, annotationTemplateData{
+		Labels: event.Labels,
+		Value:  event.Labels["value"],
+		Event:  event,
+	})
+	if err != nil {
+		logc.Errorf(eventLogContext(ctx.Ctx, event), "告警注解模版渲染失败, err: %s", err.Error())
+		return
+	}
+
+	event.Annotations = buf.String()
+}
@@ -0,0 +1,129 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"watchAlert/alert/process"
+	"watchAlert/config"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// CardinalityLabel 基数超限告警事件携带的标签
+const CardinalityLabel = "__cardinality__"
+
+// cardinalityEmitter 限制单次评估产生的事件数, 达到上限后不再产生新事件, 已存在的事件仍继续更新,
+// 避免单条规则匹配大量序列时撑大 Redis 及通知渠道
+type cardinalityEmitter struct {
+	ctx   *ctx.Context
+	rule  models.AlertRule
+	limit int64
+	next  emitter
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+	// 超出上限后才加载的规则已有事件的指纹
+	existing map[string]struct{}
+	dropped  int64
+}
+
+func newCardinalityEmitter(ctx *ctx.Context, rule models.AlertRule, next emitter) *cardinalityEmitter {
+	return &cardinalityEmitter{
+		ctx:   ctx,
+		rule:  rule,
+		limit: rule.GetMaxFingerprints(config.Application.Eval.MaxFingerprints),
+		next:  next,
+		seen:  make(map[string]struct{}),
+	}
+}
+
+func (c *cardinalityEmitter) Push(event *models.AlertCurEvent) {
+	if event != nil && !c.admit(event.Fingerprint) {
+		return
+	}
+	c.next.Push(event)
+}
+
+func (c *cardinalityEmitter) Skip(event *models.AlertCurEvent) {
+	c.next.Skip(event)
+}
+
+func (c *cardinalityEmitter) Refresh(event *models.AlertCurEvent) {
+	c.next.Refresh(event)
+}
+
+func (c *cardinalityEmitter) unwrap() emitter { return c.next }
+
+// admit 判断指纹是否允许产生事件, 未达到上限或事件已存在时允许
+func (c *cardinalityEmitter) admit(fingerprint string) bool {
+	if c.limit <= 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.seen[fingerprint]; ok {
+		return true
+	}
+	if int64(len(c.seen)) < c.limit {
+		c.seen[fingerprint] = struct{}{}
+		return true
+	}
+
+	if c.existing == nil {
+		fingerprints := c.ctx.Redis.Alert().GetFingerprintsByRuleId(c.rule.TenantId, c.rule.FaultCenterId, c.rule.RuleId)
+		c.existing = make(map[string]struct{}, len(fingerprints))
+		for _, fp := range fingerprints {
+			c.existing[fp] = struct{}{}
+		}
+	}
+	if _, ok := c.existing[fingerprint]; ok {
+		return true
+	}
+
+	c.dropped++
+	return false
+}
+
+// finish 本次评估有事件被丢弃时记录日志并推送一条基数超限告警, 返回该告警的指纹; 未超限时告警随之恢复
+func (c *cardinalityEmitter) finish(ctx context.Context) []string {
+	c.mu.Lock()
+	dropped := c.dropped
+	c.mu.Unlock()
+	if dropped == 0 {
+		return nil
+	}
+
+	logc.Errorf(ctx, "规则产生的事件数超过上限 %d, 丢弃 %d 个新事件, 请检查查询语句或指纹标签配置", c.limit, dropped)
+
+	fingerprint := provider.Metrics{Metric: map[string]interface{}{
+		"rule_id":        c.rule.RuleId,
+		CardinalityLabel: "true",
+	}}.GetFingerprint()
+
+	event := process.BuildEvent(c.rule, func() map[string]interface{} {
+		metric := map[string]interface{}{
+			"rule_name":      c.rule.RuleName,
+			"severity":       c.rule.Severity,
+			"fingerprint":    fingerprint,
+			CardinalityLabel: "true",
+		}
+		for ek, ev := range c.rule.ExternalLabels {
+			metric[ek] = ev
+		}
+		return metric
+	})
+	if len(c.rule.DatasourceIdList) > 0 {
+		event.DatasourceId = c.rule.DatasourceIdList[0]
+	}
+	event.Fingerprint = fingerprint
+	event.Annotations = fmt.Sprintf("规则: %s 单次评估产生的事件数超过上限 %d, 已丢弃 %d 个新事件, 请检查查询语句或指纹标签配置", c.rule.RuleName, c.limit, dropped)
+
+	c.next.Push(&event)
+	return []string{fingerprint}
+}
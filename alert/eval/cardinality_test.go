@@ -0,0 +1,35 @@
+package eval
+
+import (
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestCardinalityEmitterAdmit(t *testing.T) {
+	rule := models.AlertRule{RuleId: "a-1", MaxFingerprints: 2}
+	sink := &previewEmitter{}
+	c := newCardinalityEmitter(nil, rule, sink)
+	// 预置已有事件, 避免访问 Redis
+	c.existing = map[string]struct{}{"fp-exist": {}}
+
+	for _, fp := range []string{"fp-1", "fp-2", "fp-1", "fp-3", "fp-exist", "fp-4"} {
+		c.Push(&models.AlertCurEvent{Fingerprint: fp})
+	}
+
+	var pushed []string
+	for _, sample := range sink.samples {
+		pushed = append(pushed, sample.Fingerprint)
+	}
+	want := []string{"fp-1", "fp-2", "fp-1", "fp-exist"}
+	if len(pushed) != len(want) {
+		t.Fatalf("pushed = %v, want %v", pushed, want)
+	}
+	for i := range want {
+		if pushed[i] != want[i] {
+			t.Fatalf("pushed = %v, want %v", pushed, want)
+		}
+	}
+	if c.dropped != 2 {
+		t.Fatalf("dropped = %d, want 2", c.dropped)
+	}
+}
@@ -0,0 +1,35 @@
+package eval
+
+import (
+	"context"
+	"watchAlert/internal/models"
+)
+
+// deadlineEmitter 查询超时后丢弃事件, 避免超时后才返回的结果仍被推送到故障中心
+type deadlineEmitter struct {
+	ctx  context.Context
+	next emitter
+}
+
+func (d deadlineEmitter) Push(event *models.AlertCurEvent) {
+	if d.ctx.Err() != nil {
+		return
+	}
+	d.next.Push(event)
+}
+
+func (d deadlineEmitter) Skip(event *models.AlertCurEvent) {
+	if d.ctx.Err() != nil {
+		return
+	}
+	d.next.Skip(event)
+}
+
+func (d deadlineEmitter) Refresh(event *models.AlertCurEvent) {
+	if d.ctx.Err() != nil {
+		return
+	}
+	d.next.Refresh(event)
+}
+
+func (d deadlineEmitter) unwrap() emitter { return d.next }
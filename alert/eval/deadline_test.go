@@ -0,0 +1,23 @@
+package eval
+
+import (
+	"context"
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestDeadlineEmitterDropsLateEvents(t *testing.T) {
+	queryCtx, cancel := context.WithCancel(context.Background())
+	sink := &previewEmitter{}
+	emit := deadlineEmitter{ctx: queryCtx, next: sink}
+
+	emit.Push(&models.AlertCurEvent{Fingerprint: "fp-a"})
+	cancel()
+	// 超时后才返回的结果不再转发
+	emit.Push(&models.AlertCurEvent{Fingerprint: "fp-b"})
+	emit.Skip(&models.AlertCurEvent{Fingerprint: "fp-c"})
+
+	if len(sink.samples) != 1 || sink.samples[0].Fingerprint != "fp-a" {
+		t.Fatalf("samples = %+v, want only fp-a", sink.samples)
+	}
+}
@@ -3,48 +3,65 @@ package eval
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"runtime/debug"
-	"slices"
 	"strings"
 	"sync"
 	"time"
+	"watchAlert/alert/process"
+	"watchAlert/config"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/pkg/provider"
 	"watchAlert/pkg/tools"
+	"watchAlert/pkg/tracing"
 
-	"github.com/go-redis/redis"
 	"github.com/zeromicro/go-zero/core/logc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	// 数据源类型
 	DatasourceTypePrometheus      = "Prometheus"
+	DatasourceTypeInfluxDB        = "InfluxDB"
+	DatasourceTypeDatadog         = "Datadog"
 	DatasourceTypeAliCloudSLS     = "AliCloudSLS"
 	DatasourceTypeLoki            = "Loki"
 	DatasourceTypeElasticSearch   = "ElasticSearch"
 	DatasourceTypeVictoriaLogs    = "VictoriaLogs"
 	DatasourceTypeClickHouse      = "ClickHouse"
 	DatasourceTypeJaeger          = "Jaeger"
+	DatasourceTypeTempo           = "Tempo"
 	DatasourceTypeCloudWatch      = "CloudWatch"
 	DatasourceTypeKubernetesEvent = "KubernetesEvent"
 
 	// 默认恢复等待时间
 	DefaultRecoverWaitTime = 1
 
-	// 任务通道缓冲区大小
+	// 任务通道缓冲区大小, 即单条规则同时执行的评估任务数量
 	TaskChannelBufferSize = 1
+
+	// 默认数据源并发查询数量
+	DefaultDatasourceParallelism = 4
 )
 
+// 评估周期的时间单位, 规则的 EvalInterval 以秒为单位
+var evalIntervalUnit = time.Second
+
 // 数据源处理器映射
-var datasourceHandlers = map[string]func(*ctx.Context, string, string, models.AlertRule) []string{
+var datasourceHandlers = map[string]func(*ctx.Context, string, string, models.AlertRule, emitter) []string{
 	DatasourceTypePrometheus:      metrics,
+	DatasourceTypeInfluxDB:        influx,
+	DatasourceTypeDatadog:         datadog,
 	DatasourceTypeAliCloudSLS:     logs,
 	DatasourceTypeLoki:            logs,
 	DatasourceTypeElasticSearch:   logs,
 	DatasourceTypeVictoriaLogs:    logs,
 	DatasourceTypeClickHouse:      logs,
 	DatasourceTypeJaeger:          traces,
+	DatasourceTypeTempo:           tempo,
 	DatasourceTypeCloudWatch:      cloudWatch,
 	DatasourceTypeKubernetesEvent: kubernetesEvent,
 }
@@ -55,30 +72,62 @@ type (
 		Submit(rule models.AlertRule)
 		Stop(ruleId string)
 		Eval(ctx context.Context, rule models.AlertRule)
-		Recover(tenantId, ruleId string, eventCacheKey models.AlertEventCacheKey, faultCenterInfoKey models.FaultCenterInfoCacheKey, curFingerprints []string)
+		Recover(ctx context.Context, tenantId, ruleId string, eventCacheKey models.AlertEventCacheKey, faultCenterInfoKey models.FaultCenterInfoCacheKey, curFingerprints []string, ruleRecoverWaitTime int64)
 		RestartAllEvals()
+		RebalanceEvals()
 		StopAllEvals()
+		Preview(rule models.AlertRule) (PreviewResult, error)
+		Shutdown(ctx context.Context) error
 	}
 
 	// AlertRule 告警规则
 	AlertRule struct {
 		ctx *ctx.Context
+		// 运行中的评估协程, 用于停机时等待评估完成
+		wg sync.WaitGroup
+		// 停机后不再接收新的评估任务, 由 ctx.Mux 保护
+		closed bool
+		// 评估协程 panic 的次数, 用于限制自动重启
+		panics evalPanics
+		// 判断当前实例是否负责评估该规则, 多副本部署时保证每条规则只由一个实例评估
+		owns func(ruleId string) bool
 	}
 )
 
-func NewAlertRuleEval(ctx *ctx.Context) AlertRuleEval {
+func NewAlertRuleEval(ctx *ctx.Context, owns func(ruleId string) bool) AlertRuleEval {
 	return &AlertRule{
-		ctx: ctx,
+		ctx:  ctx,
+		owns: owns,
 	}
 }
 
+// Submit 启动规则评估, 同时清除规则的 panic 次数, 由用户重新启用或更新规则时重新计数
 func (t *AlertRule) Submit(rule models.AlertRule) {
+	t.panics.reset(rule.RuleId)
+	t.submit(rule)
+}
+
+func (t *AlertRule) submit(rule models.AlertRule) {
 	t.ctx.Mux.Lock()
 	defer t.ctx.Mux.Unlock()
 
+	if t.closed {
+		logc.Info(ruleLogContext(t.ctx.Ctx, rule), "Eval engine is shutting down, skip submit")
+		return
+	}
+
+	if !t.isRuleOwner(rule.RuleId) {
+		logc.Info(ruleLogContext(t.ctx.Ctx, rule), "Rule is not owned by this instance, skip submit")
+		return
+	}
+
 	c, cancel := context.WithCancel(context.Background())
 	t.ctx.ContextMap[rule.RuleId] = cancel
-	go t.Eval(c, rule)
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.Eval(c, rule)
+	}()
 }
 
 func (t *AlertRule) Stop(ruleId string) {
@@ -89,47 +138,106 @@ func (t *AlertRule) Stop(ruleId string) {
 		cancel()
 		delete(t.ctx.ContextMap, ruleId)
 	}
+	t.ctx.Metrics.RemoveRule(ruleId)
+	noDataCounts.removeRule(ruleId)
 }
 
+// Restart 重启规则评估, 保留规则的 panic 次数
 func (t *AlertRule) Restart(rule models.AlertRule) {
 	t.Stop(rule.RuleId)
-	t.Submit(rule)
+	t.submit(rule)
 }
 
 func (t *AlertRule) Eval(ctx context.Context, rule models.AlertRule) {
+	logCtx := ruleLogContext(t.ctx.Ctx, rule)
 	err := rule.Validate()
 	if err != nil {
-		logc.Errorf(t.ctx.Ctx, "Rule validation failed, RuleName: %s, RuleId: %s, Error: %v", rule.RuleName, rule.RuleId, err)
+		logc.Errorf(logCtx, "Rule validation failed, Error: %v", err)
+		return
+	}
+
+	// 随机延迟首次评估, 避免大量规则对齐在同一时刻查询数据源
+	jitter := time.NewTimer(t.getStartupJitter(rule.EvalInterval))
+	select {
+	case <-jitter.C:
+	case <-ctx.Done():
+		jitter.Stop()
 		return
 	}
 
 	taskChan := make(chan struct{}, TaskChannelBufferSize)
+	// 评估任务的 panic 交由评估协程处理, 与评估协程 panic 一样退避重启
+	panicChan := make(chan interface{}, 1)
 	timer := time.NewTicker(t.getEvalTimeDuration(rule.EvalInterval))
+	t.ctx.Metrics.EvalStarted()
 	defer func() {
 		timer.Stop()
+		t.ctx.Metrics.EvalStopped()
 		if r := recover(); r != nil {
 			// 获取调用栈信息
 			stack := debug.Stack()
-			logc.Errorf(t.ctx.Ctx, "Recovered from rule eval goroutine panic: %s, RuleName: %s, RuleId: %s\n%s", r, rule.RuleName, rule.RuleId, stack)
-			t.Restart(rule)
+			logc.Errorf(logCtx, "Recovered from rule eval goroutine panic: %s\n%s", r, stack)
+			t.handleEvalPanic(ctx, logCtx, rule, r)
 		}
 	}()
 
+	// 当前评估任务的开始时间
+	var taskStartAt time.Time
 	for {
 		select {
 		case <-timer.C:
-			// 处理任务信号量
-			taskChan <- struct{}{}
-			logc.Infof(t.ctx.Ctx, fmt.Sprintf("Handle eval task, RuleId: %v, RuleName: %s", rule.RuleId, rule.RuleName))
-			t.executeTask(rule, taskChan)
+			// 处理任务信号量, 上一次评估未完成时按规则配置跳过或等待
+			select {
+			case taskChan <- struct{}{}:
+			default:
+				policy := rule.GetOverrunPolicy()
+				t.ctx.Metrics.IncEvalOverrun(rule.RuleId, rule.RuleName, policy)
+				logc.Errorf(logCtx, "Previous eval still running, running: %s, interval: %s, policy: %s",
+					time.Since(taskStartAt).Round(time.Millisecond), t.getEvalTimeDuration(rule.EvalInterval), policy)
+				if policy == models.OverrunPolicySkip {
+					continue
+				}
+
+				select {
+				case taskChan <- struct{}{}:
+				case <-ctx.Done():
+					taskChan <- struct{}{}
+					logc.Info(logCtx, "Stop eval task")
+					return
+				}
+			}
+
+			taskStartAt = time.Now()
+			logc.Info(logCtx, "Handle eval task")
+			go t.runTask(rule, taskChan, panicChan)
+		case r := <-panicChan:
+			t.handleEvalPanic(ctx, logCtx, rule, r)
+			return
 		case <-ctx.Done():
-			logc.Infof(t.ctx.Ctx, fmt.Sprintf("Stop eval task, RuleId: %v, RuleName: %s", rule.RuleId, rule.RuleName))
+			// 等待执行中的评估完成后再退出
+			taskChan <- struct{}{}
+			logc.Info(logCtx, "Stop eval task")
 			return
 		}
-		timer.Reset(t.getEvalTimeDuration(rule.EvalInterval))
 	}
 }
 
+// runTask 在独立协程中执行评估任务, 评估过程中的 panic 通过 panicChan 通知评估协程
+func (t *AlertRule) runTask(rule models.AlertRule, taskChan chan struct{}, panicChan chan<- interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			logc.Errorf(ruleLogContext(t.ctx.Ctx, rule), "Recovered from rule eval task panic: %s\n%s", r, stack)
+			select {
+			case panicChan <- r:
+			default:
+			}
+		}
+	}()
+
+	t.executeTask(rule, taskChan)
+}
+
 // executeTask 执行评估任务
 func (t *AlertRule) executeTask(rule models.AlertRule, taskChan chan struct{}) {
 	defer func() {
@@ -137,115 +245,267 @@ func (t *AlertRule) executeTask(rule models.AlertRule, taskChan chan struct{}) {
 		<-taskChan
 	}()
 
+	// 失去规则归属后到评估协程被停止前, 跳过评估避免与新的负责实例重复评估
+	if !t.isRuleOwner(rule.RuleId) {
+		return
+	}
+
 	// 在规则评估前检查是否仍然启用
 	if !t.isRuleEnabled(rule.RuleId) {
 		return
 	}
 
+	// 每个评估周期作为一条链路, 数据源查询及恢复处理为子 Span
+	spanCtx, span := tracing.Start(ruleLogContext(context.Background(), rule), "eval.executeTask",
+		tracing.AttrRuleId.String(rule.RuleId),
+		tracing.AttrRuleName.String(rule.RuleName),
+		tracing.AttrTenantId.String(rule.TenantId),
+	)
+	defer span.End()
+
 	// 并发处理数据源
-	curFingerprints := t.processDatasources(rule)
+	startAt := time.Now()
+	emit := newCardinalityEmitter(t.ctx, rule, t.newEmitter(rule))
+	curFingerprints := t.processDatasources(spanCtx, rule, emit)
+	curFingerprints = append(curFingerprints, emit.finish(spanCtx)...)
+	t.ctx.Metrics.ObserveEval(rule.RuleId, rule.RuleName, time.Since(startAt).Seconds(), len(curFingerprints))
+	span.SetAttributes(attribute.Int("eval.fingerprints", len(curFingerprints)))
 
 	// 处理恢复逻辑
-	t.Recover(rule.TenantId, rule.RuleId,
+	t.Recover(spanCtx, rule.TenantId, rule.RuleId,
 		models.BuildAlertEventCacheKey(rule.TenantId, rule.FaultCenterId),
 		models.BuildFaultCenterInfoCacheKey(rule.TenantId, rule.FaultCenterId),
-		curFingerprints)
+		curFingerprints, rule.RecoverWaitTime)
+}
+
+// processDatasources 处理数据源, 按规则的数据源评估方式合并所有数据源的结果、使用第一个可用的数据源或按投票结果产生事件
+func (t *AlertRule) processDatasources(ctx context.Context, rule models.AlertRule, next emitter) []string {
+	switch rule.GetDatasourceStrategy() {
+	case models.DatasourceStrategyFailover:
+		return t.processDatasourcesFailover(ctx, rule, next)
+	case models.DatasourceStrategyQuorum:
+		return t.processDatasourcesQuorum(ctx, rule, next)
+	}
+
+	return t.processDatasourcesUnion(ctx, rule, next)
 }
 
-// processDatasources 处理数据源
-func (t *AlertRule) processDatasources(rule models.AlertRule) []string {
+// processDatasourcesUnion 并发查询所有数据源, 合并各数据源的结果
+func (t *AlertRule) processDatasourcesUnion(ctx context.Context, rule models.AlertRule, next emitter) []string {
 	var (
 		curFingerprints []string
-		fingerprintChan = make(chan []string, len(rule.DatasourceIdList))
-		wg              sync.WaitGroup
+		mu              sync.Mutex
+		g               = new(errgroup.Group)
 	)
 
-	// 启动工作协程
+	// 限制单条规则并发查询数据源的数量
+	g.SetLimit(t.getDatasourceParallelism())
 	for _, dsId := range rule.DatasourceIdList {
-		wg.Add(1)
-		go func(dsId string) {
-			defer wg.Done()
-			fingerprints := t.processSingleDatasource(dsId, rule)
-			if len(fingerprints) > 0 {
-				fingerprintChan <- fingerprints
+		dsId := dsId
+		g.Go(func() error {
+			fingerprints, _ := t.processSingleDatasource(ctx, dsId, rule, next)
+			if len(fingerprints) == 0 {
+				return nil
 			}
-		}(dsId)
+
+			mu.Lock()
+			curFingerprints = append(curFingerprints, fingerprints...)
+			mu.Unlock()
+			return nil
+		})
 	}
 
-	go func() {
-		wg.Wait()
-		close(fingerprintChan)
-	}()
+	_ = g.Wait()
+	return curFingerprints
+}
+
+// processDatasourcesFailover 按顺序查询数据源, 数据源不可用或查询超时时继续查询下一个, 查询成功后不再查询后续数据源,
+// 避免主备数据源保存相同数据时重复计算
+func (t *AlertRule) processDatasourcesFailover(ctx context.Context, rule models.AlertRule, next emitter) []string {
+	for i, dsId := range rule.DatasourceIdList {
+		fingerprints, err := t.processSingleDatasource(ctx, dsId, rule, next)
+		if err == nil {
+			if i > 0 {
+				logc.Info(datasourceLogContext(ctx, dsId), "Rule evaluated on failover datasource")
+			}
+			return fingerprints
+		}
 
-	for fingerprints := range fingerprintChan {
-		curFingerprints = append(curFingerprints, fingerprints...)
+		logc.Errorf(datasourceLogContext(ctx, dsId), "Datasource failed, try next datasource, err: %v", err)
 	}
 
-	return curFingerprints
+	return nil
+}
+
+// getDatasourceParallelism 获取数据源并发查询数量
+func (t *AlertRule) getDatasourceParallelism() int {
+	if config.Application.Eval.DatasourceParallelism <= 0 {
+		return DefaultDatasourceParallelism
+	}
+	return config.Application.Eval.DatasourceParallelism
 }
 
-// processSingleDatasource 处理单个数据源
-func (t *AlertRule) processSingleDatasource(dsId string, rule models.AlertRule) []string {
+// processSingleDatasource 处理单个数据源, 数据源不可用或查询超时时返回错误
+func (t *AlertRule) processSingleDatasource(ctx context.Context, dsId string, rule models.AlertRule, next emitter) ([]string, error) {
+	spanCtx, span := tracing.Start(ctx, "eval.query",
+		tracing.AttrRuleId.String(rule.RuleId),
+		tracing.AttrDatasourceId.String(dsId),
+		tracing.AttrDatasourceType.String(rule.DatasourceType),
+	)
+	defer span.End()
+	spanCtx = datasourceLogContext(spanCtx, dsId)
+
+	startAt := time.Now()
 	instance, err := t.ctx.DB.Datasource().GetInstance(dsId)
 	if err != nil {
-		logc.Errorf(t.ctx.Ctx, "Failed to get datasource instance %s: %v", dsId, err)
-		return nil
+		logc.Errorf(spanCtx, "Failed to get datasource instance: %v", err)
+		t.ctx.Metrics.IncQueryFailure(dsId, rule.DatasourceType)
+		tracing.RecordError(span, err)
+		t.saveEvalRecord(rule, dsId, rule.DatasourceType, startAt, nil, nil, err)
+		return nil, err
 	}
 
 	// 检查数据源健康状态
-	if ok, _ := provider.CheckDatasourceHealth(instance); !ok {
-		logc.Errorf(t.ctx.Ctx, "Datasource %s is unhealthy", dsId)
-		return nil
+	if ok, err := provider.CheckDatasourceHealth(instance); !ok {
+		logc.Error(spanCtx, "Datasource is unhealthy")
+		t.ctx.Metrics.IncQueryFailure(dsId, instance.Type)
+		err = fmt.Errorf("datasource is unhealthy: %v", err)
+		tracing.RecordError(span, err)
+		t.saveEvalRecord(rule, dsId, instance.Type, startAt, nil, nil, err)
+		return nil, err
 	}
 
 	// 检查数据源是否启用
 	if !*instance.Enabled {
-		logc.Errorf(t.ctx.Ctx, "Datasource %s is disabled", dsId)
-		return nil
+		logc.Error(spanCtx, "Datasource is disabled")
+		return nil, fmt.Errorf("datasource is disabled")
 	}
 
 	// 调用处理器
 	handler, exists := datasourceHandlers[rule.DatasourceType]
 	if !exists {
-		logc.Errorf(t.ctx.Ctx, "Unsupported datasource type: %s", rule.DatasourceType)
-		return nil
+		logc.Errorf(spanCtx, "Unsupported datasource type: %s", rule.DatasourceType)
+		return nil, fmt.Errorf("unsupported datasource type: %s", rule.DatasourceType)
+	}
+
+	emit := &historyEmitter{next: withQueryLink(t.ctx, rule, instance, next)}
+	fingerprints, err := t.queryWithTimeout(spanCtx, instance, rule, handler, emit)
+	if err != nil {
+		logc.Errorf(spanCtx, "Datasource query timeout after %s, skip it in this tick", instance.GetQueryTimeout())
+		t.ctx.Metrics.IncQueryFailure(dsId, instance.Type)
+		tracing.RecordError(span, err)
+		t.saveEvalRecord(rule, dsId, instance.Type, startAt, nil, emit.getSamples(), err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("eval.fingerprints", len(fingerprints)))
+	t.saveEvalRecord(rule, dsId, instance.Type, startAt, fingerprints, emit.getSamples(), nil)
+	return fingerprints, nil
+}
+
+// queryWithTimeout 在数据源查询超时内调用处理器, 查询超时独立于评估周期, 超时后取消查询并丢弃超时后才返回的结果
+func (t *AlertRule) queryWithTimeout(ctx context.Context, instance models.AlertDataSource, rule models.AlertRule, handler func(*ctx.Context, string, string, models.AlertRule, emitter) []string, emit emitter) ([]string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, instance.GetQueryTimeout())
+	defer cancel()
+
+	resultChan := make(chan []string, 1)
+	go func() {
+		resultChan <- handler(t.ctx.WithContext(queryCtx), instance.ID, instance.Type, rule, deadlineEmitter{ctx: queryCtx, next: emit})
+	}()
+
+	select {
+	case fingerprints := <-resultChan:
+		return fingerprints, nil
+	case <-queryCtx.Done():
+		return nil, queryCtx.Err()
 	}
+}
 
-	return handler(t.ctx, dsId, instance.Type, rule)
+// newEmitter 创建将事件推送到故障中心的 emitter
+func (t *AlertRule) newEmitter(rule models.AlertRule) emitter {
+	return withSeverityExpr(t.ctx, rule, faultCenterEmitter{ctx: t.ctx, enrichment: t.getEnrichment(rule)})
 }
 
 // getEvalTimeDuration 获取评估时间间隔
 func (t *AlertRule) getEvalTimeDuration(evalInterval int64) time.Duration {
-	return time.Duration(evalInterval) * time.Second
+	return time.Duration(evalInterval) * evalIntervalUnit
 }
 
-func (t *AlertRule) Recover(tenantId, ruleId string, eventCacheKey models.AlertEventCacheKey, faultCenterInfoKey models.FaultCenterInfoCacheKey, curFingerprints []string) {
-	// 过滤空指纹
-	var filteredCurFingerprints []string
-	for _, fp := range curFingerprints {
-		if fp != "" {
-			filteredCurFingerprints = append(filteredCurFingerprints, fp)
-		}
+// getStartupJitter 获取首次评估前的随机延迟, 不超过规则评估周期
+func (t *AlertRule) getStartupJitter(evalInterval int64) time.Duration {
+	maxJitter := t.getEvalTimeDuration(evalInterval)
+	if j := time.Duration(config.Application.Eval.MaxStartupJitter) * time.Second; j > 0 && j < maxJitter {
+		maxJitter = j
 	}
-	curFingerprints = filteredCurFingerprints
+	if maxJitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(maxJitter)))
+}
+
+func (t *AlertRule) Recover(ctx context.Context, tenantId, ruleId string, eventCacheKey models.AlertEventCacheKey, faultCenterInfoKey models.FaultCenterInfoCacheKey, curFingerprints []string, ruleRecoverWaitTime int64) {
+	ctx, span := tracing.Start(ctx, "eval.Recover", tracing.AttrRuleId.String(ruleId), tracing.AttrTenantId.String(tenantId))
+	defer span.End()
 
 	// 校验 key 非空
 	if eventCacheKey == "" || faultCenterInfoKey == "" {
-		logc.Errorf(t.ctx.Ctx, "AlertRule.Recover: eventCacheKey or faultCenterInfoKey is empty")
+		logc.Errorf(ctx, "AlertRule.Recover: eventCacheKey or faultCenterInfoKey is empty")
 		return
 	}
 
 	// 获取所有的故障中心告警事件
+	redisSpan := startRedisSpan(ctx, "GetAllEvents", ruleId)
 	events, err := t.ctx.Redis.Alert().GetAllEvents(eventCacheKey)
+	tracing.RecordError(redisSpan, err)
+	redisSpan.End()
+	if err != nil {
+		logc.Errorf(ctx, "AlertRule.Recover: Failed to get all events: %v", err)
+		return
+	}
+
+	redisSpan = startRedisSpan(ctx, "Pending.List", ruleId)
+	pendings := t.ctx.Redis.Pending().List(tenantId, ruleId)
+	redisSpan.End()
+
+	// 一次读取当前规则所有待恢复指纹的时间戳, 后续在内存中判断, 避免逐个指纹访问 Redis
+	redisSpan = startRedisSpan(ctx, "PendingRecover.GetAll", ruleId)
+	pendingFingerprints, err := t.ctx.Redis.PendingRecover().GetAll(tenantId, ruleId)
+	tracing.RecordError(redisSpan, err)
+	redisSpan.End()
 	if err != nil {
-		logc.Errorf(t.ctx.Ctx, "AlertRule.Recover: Failed to get all events: %v", err)
+		logc.Errorf(ctx, "AlertRule.Recover: Failed to get「pending_recovery」times: %v", err)
 		return
 	}
 
-	// 存储当前规则下所有活动的指纹
-	var activeRuleFingerprints []string
+	plan := planRecover(ctx, ruleId, events, curFingerprints, pendings, pendingFingerprints,
+		time.Now().Unix(), t.getRecoverWaitTime(ruleRecoverWaitTime, faultCenterInfoKey))
+	t.applyRecoverPlan(ctx, tenantId, ruleId, eventCacheKey, plan)
+}
+
+// recoverPlan 一次恢复处理需要写入 Redis 的变更, 计算完成后分批通过 Pipeline 写入
+type recoverPlan struct {
+	push                 []*models.AlertCurEvent
+	remove               []string
+	pendingDelete        []string
+	pendingRecoverSet    map[string]int64
+	pendingRecoverDelete []string
+}
+
+// planRecover 在内存中计算规则下各指纹的状态转换, 各指纹之间互不影响
+func planRecover(ctx context.Context, ruleId string, events map[string]*models.AlertCurEvent, curFingerprints []string, pendings, pendingFingerprints map[string]int64, curTime, recoverWaitTime int64) recoverPlan {
+	plan := recoverPlan{pendingRecoverSet: make(map[string]int64)}
+
+	// 过滤空指纹
+	current := make(map[string]struct{}, len(curFingerprints))
+	for _, fp := range curFingerprints {
+		if fp != "" {
+			current[fp] = struct{}{}
+		}
+	}
 
 	// 筛选当前规则相关的指纹，并处理预告警状态
+	var recoverFingerprints []string
 	for fingerprint, event := range events {
 		if fingerprint == "" {
 			continue
@@ -255,72 +515,72 @@ func (t *AlertRule) Recover(tenantId, ruleId string, eventCacheKey models.AlertE
 			continue
 		}
 
+		if _, ok := current[fingerprint]; ok {
+			continue
+		}
+
 		// 移除状态为预告警且当前告警列表中不存在的事件
-		if event.Status == models.StatePreAlert && !slices.Contains(curFingerprints, fingerprint) {
-			t.ctx.Redis.Alert().RemoveAlertEvent(event.TenantId, event.FaultCenterId, event.Fingerprint)
+		if event.Status == models.StatePreAlert {
+			plan.remove = append(plan.remove, fingerprint)
 			continue
 		}
 
-		activeRuleFingerprints = append(activeRuleFingerprints, fingerprint)
+		// 已抑制的事件保持原状, 抑制结束回到告警中后再判断恢复
+		if event.Status == models.StateSuppressed {
+			continue
+		}
+
+		// 在 Redis 中存在但在当前活动列表中不存在的指纹需要恢复
+		recoverFingerprints = append(recoverFingerprints, fingerprint)
+	}
+
+	// 条件不再满足时清除持续时间的计时, 下次满足条件时重新开始计时
+	for fingerprint := range pendings {
+		if _, ok := current[fingerprint]; !ok {
+			plan.pendingDelete = append(plan.pendingDelete, fingerprint)
+		}
 	}
 
 	/*
 		从待恢复状态转换成告警状态（即在 Redis 中存在待恢复 且在 curFingerprints 存在告警的事件）
 	*/
+	for fingerprint := range current {
+		if _, exists := pendingFingerprints[fingerprint]; !exists {
+			continue
+		}
+		event, ok := events[fingerprint]
+		if !ok {
+			continue
+		}
 
-	// 获取当前待恢复的告警指纹列表
-	pendingFingerprints := t.ctx.Redis.PendingRecover().List(tenantId, ruleId)
-	if len(pendingFingerprints) != 0 {
-		for _, fingerprint := range curFingerprints {
-			if _, exists := pendingFingerprints[fingerprint]; !exists {
-				continue
-			}
-			event, ok := events[fingerprint]
-			if !ok {
-				continue
-			}
-
-			newEvent := event
-			// 转换成告警状态
-			err := newEvent.TransitionStatus(models.StateAlerting)
-			if err != nil {
-				logc.Errorf(t.ctx.Ctx, "Failed to transition to「alerting」state for fingerprint %s: %v", fingerprint, err)
-				continue
-			}
-			t.ctx.Redis.Alert().PushAlertEvent(newEvent)
-			t.ctx.Redis.PendingRecover().Delete(tenantId, ruleId, fingerprint)
+		newEvent := event
+		// 转换成告警状态
+		err := newEvent.TransitionStatus(models.StateAlerting)
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(ctx, tools.LogFieldFingerprint, fingerprint), "Failed to transition to「alerting」state: %v", err)
+			continue
 		}
+		plan.push = append(plan.push, newEvent)
+		plan.pendingRecoverDelete = append(plan.pendingRecoverDelete, fingerprint)
 	}
 
 	/*
 		从待恢复状态转换成已恢复状态
 	*/
-
-	// 计算需要恢复的指纹列表 (即在 Redis 中存在但在当前活动列表中不存在的指纹)
-	recoverFingerprints := tools.GetSliceDifference(activeRuleFingerprints, curFingerprints)
-	curTime := time.Now().Unix()
-	recoverWaitTime := t.getRecoverWaitTime(faultCenterInfoKey)
 	for _, fingerprint := range recoverFingerprints {
-		event, ok := events[fingerprint]
-		if !ok {
-			continue
-		}
+		newEvent := events[fingerprint]
 
-		newEvent := event
 		// 获取待恢复状态的时间戳
-		wTime, err := t.ctx.Redis.PendingRecover().Get(tenantId, ruleId, fingerprint)
-		if err == redis.Nil {
+		wTime, exists := pendingFingerprints[fingerprint]
+		if !exists {
 			// 转换状态, 标记为待恢复
 			if err := newEvent.TransitionStatus(models.StatePendingRecovery); err != nil {
-				logc.Errorf(t.ctx.Ctx, "Failed to transition to「pending_recovery」state for fingerprint %s: %v", fingerprint, err)
+				logc.Errorf(tools.WithLogFields(ctx, tools.LogFieldFingerprint, fingerprint), "Failed to transition to「pending_recovery」state: %v", err)
 				continue
 			}
 			// 记录当前时间
-			t.ctx.Redis.PendingRecover().Set(tenantId, ruleId, fingerprint, curTime)
-			t.ctx.Redis.Alert().PushAlertEvent(newEvent)
-			continue
-		} else if err != nil {
-			logc.Errorf(t.ctx.Ctx, "Failed to get「pending_recovery」time for fingerprint %s: %v", fingerprint, err)
+			plan.pendingRecoverSet[fingerprint] = curTime
+			plan.push = append(plan.push, newEvent)
 			continue
 		}
 
@@ -330,20 +590,82 @@ func (t *AlertRule) Recover(tenantId, ruleId string, eventCacheKey models.AlertE
 		if curTime >= recoverThreshold && newEvent.Status == models.StatePendingRecovery {
 			// 已恢复状态
 			if err := newEvent.TransitionStatus(models.StateRecovered); err != nil {
-				logc.Errorf(t.ctx.Ctx, "Failed to transition to recovered state for fingerprint %s: %v", fingerprint, err)
+				logc.Errorf(tools.WithLogFields(ctx, tools.LogFieldFingerprint, fingerprint), "Failed to transition to recovered state: %v", err)
 				continue
 			}
-			// 更新告警事件
-			t.ctx.Redis.Alert().PushAlertEvent(newEvent)
-			// 恢复后继续处理下一个事件
-			t.ctx.Redis.PendingRecover().Delete(tenantId, ruleId, fingerprint)
-			continue
+			// 更新告警事件, 恢复后继续处理下一个事件
+			plan.push = append(plan.push, newEvent)
+			plan.pendingRecoverDelete = append(plan.pendingRecoverDelete, fingerprint)
 		}
 	}
+
+	return plan
 }
 
-// getRecoverWaitTime 获取恢复等待时间
-func (t *AlertRule) getRecoverWaitTime(faultCenterInfoKey models.FaultCenterInfoCacheKey) int64 {
+// 单个 Pipeline 写入的最大事件数, 避免单次请求过大阻塞 Redis
+const recoverPipelineBatchSize = 500
+
+// applyRecoverPlan 分批通过 Pipeline 写入恢复处理的变更
+func (t *AlertRule) applyRecoverPlan(ctx context.Context, tenantId, ruleId string, eventCacheKey models.AlertEventCacheKey, plan recoverPlan) {
+	if len(plan.pendingDelete) > 0 {
+		t.ctx.Redis.Pending().BatchDelete(tenantId, ruleId, plan.pendingDelete)
+	}
+
+	span := startRedisSpan(ctx, "PendingRecover.PipelineUpdate", ruleId)
+	err := t.ctx.Redis.PendingRecover().PipelineUpdate(tenantId, ruleId, plan.pendingRecoverSet, plan.pendingRecoverDelete)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		logc.Errorf(ctx, "AlertRule.Recover: Failed to update「pending_recovery」times: %v", err)
+	}
+
+	remove := plan.remove
+	for start := 0; start < len(plan.push) || len(remove) > 0; start += recoverPipelineBatchSize {
+		end := min(start+recoverPipelineBatchSize, len(plan.push))
+		push := plan.push[min(start, end):end]
+
+		span := startRedisSpan(ctx, "PipelineUpdateEvents", ruleId)
+		err := t.ctx.Redis.Alert().PipelineUpdateEventsByKey(eventCacheKey, push, remove)
+		tracing.RecordError(span, err)
+		span.End()
+		if err != nil {
+			logc.Errorf(ctx, "AlertRule.Recover: Failed to update events: %v", err)
+		} else {
+			for _, event := range push {
+				action := models.EventChangeUpdate
+				if event.Status == models.StateRecovered {
+					action = models.EventChangeRecover
+				}
+				process.PublishEventChange(t.ctx, action, event)
+			}
+		}
+		remove = nil
+	}
+}
+
+// startRedisSpan 创建 Redis 操作的子 Span
+func startRedisSpan(ctx context.Context, operation, ruleId string) trace.Span {
+	_, span := tracing.Start(ctx, "redis."+operation,
+		tracing.AttrRedisOperation.String(operation),
+		tracing.AttrRuleId.String(ruleId),
+	)
+	return span
+}
+
+// pushAlertEvent 更新故障中心事件并记录 Redis 操作 Span
+func (t *AlertRule) pushAlertEvent(ctx context.Context, ruleId string, event *models.AlertCurEvent) {
+	span := startRedisSpan(ctx, "PushAlertEvent", ruleId)
+	defer span.End()
+
+	t.ctx.Redis.Alert().PushAlertEvent(event)
+}
+
+// getRecoverWaitTime 获取恢复等待时间, 优先使用规则上的配置, 其次使用故障中心的配置
+func (t *AlertRule) getRecoverWaitTime(ruleRecoverWaitTime int64, faultCenterInfoKey models.FaultCenterInfoCacheKey) int64 {
+	if ruleRecoverWaitTime > 0 {
+		return ruleRecoverWaitTime
+	}
+
 	faultCenter := t.ctx.Redis.FaultCenter().GetFaultCenterInfo(faultCenterInfoKey)
 	if faultCenter.RecoverWaitTime == 0 {
 		return DefaultRecoverWaitTime
@@ -351,6 +673,15 @@ func (t *AlertRule) getRecoverWaitTime(faultCenterInfoKey models.FaultCenterInfo
 	return faultCenter.RecoverWaitTime
 }
 
+// getEnrichment 获取事件富化配置, 优先使用规则上的配置, 其次使用故障中心的配置
+func (t *AlertRule) getEnrichment(rule models.AlertRule) models.EventEnrichment {
+	if rule.Enrichment.GetEnabled() {
+		return rule.Enrichment
+	}
+
+	return t.ctx.Redis.FaultCenter().GetFaultCenterInfo(models.BuildFaultCenterInfoCacheKey(rule.TenantId, rule.FaultCenterId)).Enrichment
+}
+
 // RestartAllEvals 重启所有评估器
 func (t *AlertRule) RestartAllEvals() {
 	ruleList, err := t.getRuleList()
@@ -390,15 +721,48 @@ func (t *AlertRule) RestartAllEvals() {
 	logc.Info(t.ctx.Ctx, "所有规则评估器启动成功！")
 }
 
-// isRuleEnabled 检查规则是否启用
+// isRuleOwner 检查当前实例是否负责评估规则, 未设置时负责所有规则
+func (t *AlertRule) isRuleOwner(ruleId string) bool {
+	return t.owns == nil || t.owns(ruleId)
+}
+
+// isRuleEnabled 检查规则是否启用, 处于暂停期的规则跳过评估, 到期后自动恢复
 func (t *AlertRule) isRuleEnabled(ruleId string) bool {
 	// 直接检查数据库或缓存中的当前启用状态
-	e := t.ctx.DB.Rule().GetRuleObject(ruleId).Enabled
-	if e == nil {
+	rule := t.ctx.DB.Rule().GetRuleObject(ruleId)
+	if rule.Enabled == nil || !*rule.Enabled {
 		return false
 	}
 
-	return *e
+	return !rule.IsPaused(time.Now())
+}
+
+// RebalanceEvals 规则归属变化后启动新分配到当前实例的规则, 停止不再归属当前实例的规则, 归属未变化的规则保持运行
+func (t *AlertRule) RebalanceEvals() {
+	ruleList, err := t.getRuleList()
+	if err != nil {
+		logc.Errorf(t.ctx.Ctx, "Failed to get rule list: %v", err)
+		return
+	}
+
+	var started, stopped int
+	for _, rule := range ruleList {
+		t.ctx.Mux.RLock()
+		_, running := t.ctx.ContextMap[rule.RuleId]
+		t.ctx.Mux.RUnlock()
+
+		owned := t.isRuleOwner(rule.RuleId)
+		switch {
+		case owned && !running:
+			t.Submit(rule)
+			started++
+		case !owned && running:
+			t.Stop(rule.RuleId)
+			stopped++
+		}
+	}
+
+	logc.Infof(t.ctx.Ctx, "规则重新分配完成, 启动 %d 个, 停止 %d 个", started, stopped)
 }
 
 // getRuleList 获取规则列表
@@ -410,6 +774,31 @@ func (t *AlertRule) getRuleList() ([]models.AlertRule, error) {
 	return ruleList, nil
 }
 
+// Shutdown 停止接收新的评估任务, 取消所有评估协程并等待执行中的评估完成, 超过 ctx 截止时间后返回
+func (t *AlertRule) Shutdown(ctx context.Context) error {
+	t.ctx.Mux.Lock()
+	t.closed = true
+	for ruleId, cancel := range t.ctx.ContextMap {
+		cancel()
+		delete(t.ctx.ContextMap, ruleId)
+	}
+	t.ctx.Mux.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logc.Infof(t.ctx.Ctx, "所有规则评估任务已完成")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待规则评估任务完成超时: %w", ctx.Err())
+	}
+}
+
 // StopAllEvals 停止所有评估器
 func (t *AlertRule) StopAllEvals() {
 	t.ctx.Mux.Lock()
@@ -0,0 +1,81 @@
+package eval
+
+import (
+	"sync"
+	"time"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 每条评估记录保留的样本数量, 避免高基数查询撑大 Redis 列表
+const evalHistorySampleLimit = 20
+
+// historyEmitter 转发事件的同时采样评估结果, 用于写入规则评估记录
+type historyEmitter struct {
+	next emitter
+
+	mu      sync.Mutex
+	samples []models.RuleEvalSample
+}
+
+func (h *historyEmitter) Push(event *models.AlertCurEvent) {
+	h.next.Push(event)
+	h.record(event, true)
+}
+
+func (h *historyEmitter) Skip(event *models.AlertCurEvent) {
+	h.next.Skip(event)
+	h.record(event, false)
+}
+
+func (h *historyEmitter) Refresh(event *models.AlertCurEvent) {
+	h.next.Refresh(event)
+}
+
+func (h *historyEmitter) unwrap() emitter { return h.next }
+
+func (h *historyEmitter) record(event *models.AlertCurEvent, triggered bool) {
+	if event == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) >= evalHistorySampleLimit {
+		return
+	}
+	h.samples = append(h.samples, models.RuleEvalSample{
+		Fingerprint: event.Fingerprint,
+		Value:       event.Labels["value"],
+		Triggered:   triggered,
+	})
+}
+
+func (h *historyEmitter) getSamples() []models.RuleEvalSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]models.RuleEvalSample(nil), h.samples...)
+}
+
+// saveEvalRecord 写入规则对数据源的本次评估结果
+func (t *AlertRule) saveEvalRecord(rule models.AlertRule, dsId, dsType string, startAt time.Time, fingerprints []string, samples []models.RuleEvalSample, evalErr error) {
+	record := models.RuleEvalRecord{
+		RuleId:         rule.RuleId,
+		DatasourceId:   dsId,
+		DatasourceType: dsType,
+		EvalAt:         startAt.Unix(),
+		Duration:       time.Since(startAt).Milliseconds(),
+		Fingerprints:   fingerprints,
+		Samples:        samples,
+	}
+	if evalErr != nil {
+		record.Error = evalErr.Error()
+	}
+
+	if err := t.ctx.Redis.RuleEvalHistory().Push(rule.TenantId, record); err != nil {
+		logc.Errorf(datasourceLogContext(ruleLogContext(t.ctx.Ctx, rule), dsId), "写入规则评估记录失败, err: %s", err.Error())
+	}
+}
@@ -0,0 +1,79 @@
+package eval
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 查询链接的时间范围, 终点为事件产生时间
+const queryLinkWindow = time.Hour
+
+// linkEmitter 按链接模版为事件生成跳转到 Grafana/Prometheus 的查询链接
+type linkEmitter struct {
+	ctx          *ctx.Context
+	tmpl         *template.Template
+	datasourceId string
+	next         emitter
+}
+
+// withQueryLink 规则或数据源配置了链接模版时为事件生成查询链接, 规则的配置优先
+func withQueryLink(c *ctx.Context, rule models.AlertRule, datasource models.AlertDataSource, next emitter) emitter {
+	text := rule.LinkTemplate
+	if text == "" {
+		text = datasource.LinkTemplate
+	}
+	if text == "" {
+		return next
+	}
+
+	tmpl, err := models.ParseQueryLinkTemplate(text)
+	if err != nil {
+		logc.Errorf(datasourceLogContext(ruleLogContext(c.Ctx, rule), datasource.ID), "%v", err)
+		return next
+	}
+
+	return linkEmitter{ctx: c, tmpl: tmpl, datasourceId: datasource.ID, next: next}
+}
+
+func (l linkEmitter) Push(event *models.AlertCurEvent) {
+	l.render(event)
+	l.next.Push(event)
+}
+
+func (l linkEmitter) Skip(event *models.AlertCurEvent) {
+	l.next.Skip(event)
+}
+
+func (l linkEmitter) Refresh(event *models.AlertCurEvent) {
+	l.next.Refresh(event)
+}
+
+func (l linkEmitter) unwrap() emitter { return l.next }
+
+func (l linkEmitter) render(event *models.AlertCurEvent) {
+	if event == nil {
+		return
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	err := l.tmpl.Execute(&buf, models.QueryLinkData{
+		Labels:       event.Labels,
+		Query:        event.SearchQL,
+		RuleName:     event.RuleName,
+		DatasourceId: l.datasourceId,
+		From:         now.Add(-queryLinkWindow).UnixMilli(),
+		To:           now.UnixMilli(),
+	})
+	if err != nil {
+		logc.Errorf(eventLogContext(l.ctx.Ctx, event), "查询链接模版渲染失败, err: %s", err.Error())
+		return
+	}
+
+	event.QueryLink = buf.String()
+}
@@ -0,0 +1,33 @@
+package eval
+
+import (
+	"context"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// ruleLogContext 附加规则的结构化日志字段
+func ruleLogContext(ctx context.Context, rule models.AlertRule) context.Context {
+	return tools.WithLogFields(ctx,
+		tools.LogFieldTenantId, rule.TenantId,
+		tools.LogFieldRuleId, rule.RuleId,
+		tools.LogFieldRuleName, rule.RuleName,
+	)
+}
+
+// datasourceLogContext 附加数据源的结构化日志字段
+func datasourceLogContext(ctx context.Context, datasourceId string) context.Context {
+	return tools.WithLogFields(ctx, tools.LogFieldDatasourceId, datasourceId)
+}
+
+// eventLogContext 附加告警事件的结构化日志字段
+func eventLogContext(ctx context.Context, event *models.AlertCurEvent) context.Context {
+	return tools.WithLogFields(ctx,
+		tools.LogFieldTenantId, event.TenantId,
+		tools.LogFieldRuleId, event.RuleId,
+		tools.LogFieldRuleName, event.RuleName,
+		tools.LogFieldFaultCenterId, event.FaultCenterId,
+		tools.LogFieldDatasourceId, event.DatasourceId,
+		tools.LogFieldFingerprint, event.Fingerprint,
+	)
+}
@@ -0,0 +1,95 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+)
+
+// NoDataLabel 无数据告警事件携带的标签
+const NoDataLabel = "__nodata__"
+
+// noDataCounter 记录规则在各数据源上连续无数据的评估次数
+type noDataCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+var noDataCounts = &noDataCounter{
+	counts: make(map[string]int64),
+}
+
+func (c *noDataCounter) incr(ruleId, datasourceId string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := ruleId + "/" + datasourceId
+	c.counts[key]++
+	return c.counts[key]
+}
+
+func (c *noDataCounter) reset(ruleId, datasourceId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.counts, ruleId+"/"+datasourceId)
+}
+
+func (c *noDataCounter) removeRule(ruleId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.counts {
+		if strings.HasPrefix(key, ruleId+"/") {
+			delete(c.counts, key)
+		}
+	}
+}
+
+// noData 查询结果为空时计数, 连续次数达到阈值后推送无数据告警事件
+func noData(ctx *ctx.Context, datasourceId string, rule models.AlertRule, searchQL string, emit emitter) []string {
+	if !rule.NoDataAlert.Enabled {
+		return nil
+	}
+
+	var count int64
+	if isPreview(emit) {
+		// 预览不记录状态，直接视为满足条件
+		count = rule.NoDataAlert.GetConsecutive()
+	} else {
+		count = noDataCounts.incr(rule.RuleId, datasourceId)
+	}
+	if count < rule.NoDataAlert.GetConsecutive() {
+		return nil
+	}
+
+	fingerprint := provider.Metrics{Metric: map[string]interface{}{
+		"rule_id":    rule.RuleId,
+		NoDataLabel:  "true",
+		"datasource": datasourceId,
+	}}.GetFingerprint()
+
+	event := process.BuildEvent(rule, func() map[string]interface{} {
+		metric := map[string]interface{}{
+			"rule_name":   rule.RuleName,
+			"severity":    rule.Severity,
+			"fingerprint": fingerprint,
+			NoDataLabel:   "true",
+		}
+		for ek, ev := range rule.ExternalLabels {
+			metric[ek] = ev
+		}
+		return metric
+	})
+	event.DatasourceId = datasourceId
+	event.Fingerprint = fingerprint
+	event.SearchQL = searchQL
+	event.Annotations = fmt.Sprintf("规则: %s 在数据源: %s 上连续 %d 次评估未查询到数据", rule.RuleName, datasourceId, count)
+
+	emit.Push(&event)
+	return []string{fingerprint}
+}
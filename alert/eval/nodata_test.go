@@ -0,0 +1,29 @@
+package eval
+
+import (
+	"context"
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestNoDataPreviewThroughWrappedEmitter(t *testing.T) {
+	rule := models.AlertRule{RuleId: "a-nodata", RuleName: "nodata", NoDataAlert: models.NoDataAlert{Enabled: true, Consecutive: 3}}
+	sink := &previewEmitter{datasourceId: "ds-1"}
+	// 预览时 emitter 被超时及历史记录装饰, 仍应识别为预览
+	emit := deadlineEmitter{ctx: context.Background(), next: &historyEmitter{next: sink}}
+
+	for i := 0; i < 2; i++ {
+		if fingerprints := noData(nil, "ds-1", rule, "up", emit); len(fingerprints) != 1 {
+			t.Fatalf("preview %d fingerprints = %v, want one nodata event", i, fingerprints)
+		}
+	}
+	if len(sink.samples) != 2 {
+		t.Fatalf("samples = %d, want 2", len(sink.samples))
+	}
+
+	noDataCounts.mu.Lock()
+	defer noDataCounts.mu.Unlock()
+	if count, ok := noDataCounts.counts[rule.RuleId+"/ds-1"]; ok {
+		t.Fatalf("preview recorded nodata count %d, want none", count)
+	}
+}
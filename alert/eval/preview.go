@@ -0,0 +1,174 @@
+package eval
+
+import (
+	"fmt"
+	"watchAlert/alert/enrich"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type (
+	// emitter 处理评估过程中产生的告警事件
+	emitter interface {
+		// Push 处理满足告警条件的事件
+		Push(event *models.AlertCurEvent)
+		// Skip 处理未满足告警条件的事件
+		Skip(event *models.AlertCurEvent)
+		// Refresh 更新仍在告警中的事件的最新值, 不会产生新事件
+		Refresh(event *models.AlertCurEvent)
+	}
+
+	// faultCenterEmitter 将事件推送到故障中心，用于正常的规则评估
+	faultCenterEmitter struct {
+		ctx        *ctx.Context
+		enrichment models.EventEnrichment
+	}
+
+	// previewEmitter 仅记录事件，不写入 Redis，用于规则预览
+	previewEmitter struct {
+		datasourceId string
+		samples      []PreviewSample
+	}
+
+	// PreviewResult 规则预览结果
+	PreviewResult struct {
+		Samples      []PreviewSample `json:"samples"`
+		Fingerprints []string        `json:"fingerprints"`
+	}
+
+	// PreviewSample 单个样本的评估结果
+	PreviewSample struct {
+		DatasourceId string                 `json:"datasourceId"`
+		Fingerprint  string                 `json:"fingerprint"`
+		Severity     string                 `json:"severity"`
+		Labels       map[string]interface{} `json:"labels"`
+		Annotations  string                 `json:"annotations"`
+		SearchQL     string                 `json:"searchQL"`
+		Triggered    bool                   `json:"triggered"`
+	}
+)
+
+func (f faultCenterEmitter) Push(event *models.AlertCurEvent) {
+	// 租户活跃事件达到配额后丢弃新事件, 已存在的事件仍继续更新及恢复
+	if isEventQuotaExceeded(f.ctx, event.TenantId) {
+		if _, err := f.ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint); err != nil {
+			logc.Info(eventLogContext(f.ctx.Ctx, event), "租户活跃事件配额不足, 丢弃新事件")
+			return
+		}
+	}
+
+	enrich.Enrich(f.ctx.Ctx, f.enrichment, event)
+	event.MarkTriggered()
+	process.PushEventToFaultCenter(f.ctx, event)
+}
+
+// Skip 未满足条件的事件不推送到故障中心, 由恢复流程处理
+func (f faultCenterEmitter) Skip(event *models.AlertCurEvent) {}
+
+func (f faultCenterEmitter) Refresh(event *models.AlertCurEvent) {
+	// 更新恢复时最新值
+	cache, err := f.ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if err != nil {
+		return
+	}
+
+	if !cache.IsRecovered && cache.Status != models.StateRecovered {
+		// 保持与告警时一致的富化标签
+		enrich.Enrich(f.ctx.Ctx, f.enrichment, event)
+		process.PushEventToFaultCenter(f.ctx, event)
+	}
+}
+
+// emitterWrapper 包装下游 emitter 的装饰器
+type emitterWrapper interface {
+	unwrap() emitter
+}
+
+// isPreview 沿装饰器链查找最终的 emitter, 判断是否为规则预览
+func isPreview(emit emitter) bool {
+	for emit != nil {
+		if _, ok := emit.(*previewEmitter); ok {
+			return true
+		}
+		w, ok := emit.(emitterWrapper)
+		if !ok {
+			return false
+		}
+		emit = w.unwrap()
+	}
+	return false
+}
+
+func (p *previewEmitter) Push(event *models.AlertCurEvent) {
+	p.record(event, true)
+}
+
+func (p *previewEmitter) Skip(event *models.AlertCurEvent) {
+	p.record(event, false)
+}
+
+// Refresh 预览不读写故障中心, 样本已由 Skip 记录
+func (p *previewEmitter) Refresh(event *models.AlertCurEvent) {}
+
+func (p *previewEmitter) record(event *models.AlertCurEvent, triggered bool) {
+	if event == nil {
+		return
+	}
+
+	p.samples = append(p.samples, PreviewSample{
+		DatasourceId: p.datasourceId,
+		Fingerprint:  event.Fingerprint,
+		Severity:     event.Severity,
+		Labels:       event.Labels,
+		Annotations:  event.Annotations,
+		SearchQL:     event.SearchQL,
+		Triggered:    triggered,
+	})
+}
+
+// Preview 使用规则配置对数据源执行一次评估，不写入 Redis 也不会发送通知
+func (t *AlertRule) Preview(rule models.AlertRule) (PreviewResult, error) {
+	handler, exists := datasourceHandlers[rule.DatasourceType]
+	if !exists {
+		return PreviewResult{}, fmt.Errorf("不支持的数据源类型: %s", rule.DatasourceType)
+	}
+
+	if len(rule.DatasourceIdList) == 0 {
+		return PreviewResult{}, fmt.Errorf("数据源不能为空")
+	}
+
+	result := PreviewResult{
+		Samples:      []PreviewSample{},
+		Fingerprints: []string{},
+	}
+	for _, dsId := range rule.DatasourceIdList {
+		instance, err := t.ctx.DB.Datasource().GetInstance(dsId)
+		if err != nil {
+			return PreviewResult{}, fmt.Errorf("获取数据源信息失败, datasourceId: %s, err: %s", dsId, err.Error())
+		}
+
+		// 与规则评估一致, 先检查数据源健康状态及是否启用
+		if ok, err := provider.CheckDatasourceHealth(instance); !ok {
+			return PreviewResult{}, fmt.Errorf("数据源不可用, datasourceId: %s, err: %v", dsId, err)
+		}
+		if !*instance.Enabled {
+			return PreviewResult{}, fmt.Errorf("数据源未启用, datasourceId: %s", dsId)
+		}
+
+		emit := &previewEmitter{datasourceId: dsId}
+		logCtx := datasourceLogContext(ruleLogContext(t.ctx.Ctx, rule), dsId)
+		fingerprints, err := t.queryWithTimeout(logCtx, instance, rule, handler, withSeverityExpr(t.ctx, rule, emit))
+		if err != nil {
+			return PreviewResult{}, fmt.Errorf("数据源查询超时, datasourceId: %s, timeout: %s", dsId, instance.GetQueryTimeout())
+		}
+
+		result.Samples = append(result.Samples, emit.samples...)
+		result.Fingerprints = append(result.Fingerprints, fingerprints...)
+	}
+
+	return result, nil
+}
@@ -3,6 +3,7 @@ package eval
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"watchAlert/alert/process"
@@ -16,50 +17,134 @@ import (
 )
 
 // Metrics Prometheus 数据源
-func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
 	pools := ctx.Redis.ProviderPools()
 	var (
 		resQuery       []provider.Metrics
 		externalLabels map[string]interface{}
-		// 当前活跃告警的指纹列表
-		curFingerprints []string
-		// 按指纹分组存储事件，相同规则只保留最高优先级的事件
-		highestPriorityEvents = make(map[string]struct{})
 	)
 
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return nil
 	}
 
 	switch datasourceType {
 	case provider.PrometheusDsProvider:
-		resQuery, err = cli.(provider.PrometheusProvider).Query(rule.PrometheusConfig.PromQL)
+		resQuery, err = cli.(provider.PrometheusProvider).Query(ctx.Ctx, rule.PrometheusConfig.PromQL)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "Prometheus查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, PromQL: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.PrometheusConfig.PromQL, err)
+			logc.Errorf(ctx.Ctx, "Prometheus查询失败, PromQL: %s, 错误: %v", rule.PrometheusConfig.PromQL, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return nil
 		}
 
 		// 检查查询结果数量，避免过多结果导致系统压力
 		if len(resQuery) > 1000 {
-			logc.Errorf(ctx.Ctx, "Prometheus查询结果过多，可能影响性能，今提取前 1000 个数据点，规则ID: %s, 规则名称: %s, 结果数量: %d", rule.RuleId, rule.RuleName, len(resQuery))
+			logc.Errorf(ctx.Ctx, "Prometheus查询结果过多，可能影响性能，今提取前 1000 个数据点，结果数量: %d", len(resQuery))
 			resQuery = resQuery[:1000]
 		}
 
 		externalLabels = cli.(provider.PrometheusProvider).GetExternalLabels()
 	default:
-		logc.Errorf(ctx.Ctx, "不支持的指标类型, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 类型: %s", rule.RuleId, rule.RuleName, datasourceId, datasourceType)
+		logc.Errorf(ctx.Ctx, "不支持的指标类型, 类型: %s", datasourceType)
 		return nil
 	}
 
 	if len(resQuery) == 0 {
+		return noData(ctx, datasourceId, rule, rule.PrometheusConfig.PromQL, emit)
+	}
+	noDataCounts.reset(rule.RuleId, datasourceId)
+
+	return evalMetrics(ctx, datasourceId, rule, resQuery, externalLabels, rule.PrometheusConfig.PromQL, rule.PrometheusConfig.Rules, rule.PrometheusConfig.Annotations, emit)
+}
+
+// influx InfluxDB 数据源
+func influx(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
+	pools := ctx.Redis.ProviderPools()
+	cli, err := pools.GetClient(datasourceId)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+		return nil
+	}
+
+	influxCli, ok := cli.(provider.InfluxDBProvider)
+	if !ok {
+		logc.Errorf(ctx.Ctx, "数据源客户端类型错误, 类型: %s", datasourceType)
+		return nil
+	}
+
+	resQuery, err := influxCli.Query(ctx.Ctx, rule.InfluxDBConfig.Flux)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "InfluxDB查询失败, Flux: %s, 错误: %v", rule.InfluxDBConfig.Flux, err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return nil
 	}
 
+	if len(resQuery) > 1000 {
+		logc.Errorf(ctx.Ctx, "InfluxDB查询结果过多，可能影响性能，今提取前 1000 个数据点，结果数量: %d", len(resQuery))
+		resQuery = resQuery[:1000]
+	}
+
+	if len(resQuery) == 0 {
+		return noData(ctx, datasourceId, rule, rule.InfluxDBConfig.Flux, emit)
+	}
+	noDataCounts.reset(rule.RuleId, datasourceId)
+
+	return evalMetrics(ctx, datasourceId, rule, resQuery, influxCli.GetExternalLabels(), rule.InfluxDBConfig.Flux, rule.InfluxDBConfig.Rules, rule.InfluxDBConfig.Annotations, emit)
+}
+
+// datadog Datadog 数据源, 查询语句及分级阈值复用 Prometheus 规则配置
+func datadog(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
+	pools := ctx.Redis.ProviderPools()
+	cli, err := pools.GetClient(datasourceId)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+		return nil
+	}
+
+	datadogCli, ok := cli.(provider.DatadogProvider)
+	if !ok {
+		logc.Errorf(ctx.Ctx, "数据源客户端类型错误, 类型: %s", datasourceType)
+		return nil
+	}
+
+	resQuery, err := datadogCli.Query(ctx.Ctx, rule.PrometheusConfig.PromQL)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "Datadog查询失败, Query: %s, 错误: %v", rule.PrometheusConfig.PromQL, err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+		return nil
+	}
+
+	if len(resQuery) > 1000 {
+		logc.Errorf(ctx.Ctx, "Datadog查询结果过多，可能影响性能，今提取前 1000 个数据点，结果数量: %d", len(resQuery))
+		resQuery = resQuery[:1000]
+	}
+
+	if len(resQuery) == 0 {
+		return noData(ctx, datasourceId, rule, rule.PrometheusConfig.PromQL, emit)
+	}
+	noDataCounts.reset(rule.RuleId, datasourceId)
+
+	return evalMetrics(ctx, datasourceId, rule, resQuery, datadogCli.GetExternalLabels(), rule.PrometheusConfig.PromQL, rule.PrometheusConfig.Rules, rule.PrometheusConfig.Annotations, emit)
+}
+
+// evalMetrics 按告警等级评估指标类数据源的查询结果, 返回满足条件的指纹列表
+// 每个序列只产生一个事件, 取满足条件的最高等级作为事件等级, 低于所有等级的阈值时事件恢复
+func evalMetrics(ctx *ctx.Context, datasourceId string, rule models.AlertRule, resQuery []provider.Metrics, externalLabels map[string]interface{}, query string, ruleExprs []models.Rules, annotations string, emit emitter) []string {
+	// 当前活跃告警的指纹列表
+	var curFingerprints []string
+
 	// 按优先级排序规则（P0 > P1 > P2）
-	rules := sortRulesByPriority(rule.PrometheusConfig.Rules)
+	rules := sortRulesByPriority(ruleExprs)
+	if len(rules) == 0 {
+		return nil
+	}
 
+	seen := make(map[string]struct{}, len(resQuery))
 	for _, v := range resQuery {
 		// 避免共享引用导致的指纹不一致问题
 		metricLabels := make(map[string]interface{})
@@ -67,98 +152,145 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 			metricLabels[k] = val
 		}
 
-		// 使用独立的标签副本来生成指纹，避免修改原始数据
-		fingerprintLabels := make(map[string]interface{})
-		for k, val := range metricLabels {
-			fingerprintLabels[k] = val
+		// 使用独立的标签副本来生成指纹，避免修改原始数据; 指纹不包含等级, 等级变化时仍为同一事件
+		fingerprintLabels := rule.BuildFingerprintLabels(metricLabels)
+		fingerprint := provider.Metrics{Metric: fingerprintLabels}.GetFingerprint()
+		// 指纹标签不足以区分序列时, 同一指纹只取第一个序列, 避免事件被反复覆盖
+		if _, ok := seen[fingerprint]; ok {
+			logc.Errorf(tools.WithLogFields(ctx.Ctx, tools.LogFieldFingerprint, fingerprint), "多个序列生成了相同的指纹, 请检查指纹标签配置, 标签: %v", metricLabels)
+			continue
 		}
-		fingerprintLabels["rule_id"] = rule.RuleId
-		fingerprintLabels["rule_name"] = rule.RuleName
-
-		// 遍历按优先级排序后的规则
-		for _, ruleExpr := range rules {
-			fingerprintLabels["severity"] = ruleExpr.Severity
+		seen[fingerprint] = struct{}{}
+
+		// 遍历按优先级排序后的规则, 取第一个满足条件的等级
+		var (
+			matched  *models.Rules
+			searchQL string
+		)
+		for i, ruleExpr := range rules {
 			operator, value, err := process.ProcessRuleExpr(ruleExpr.Expr)
 			if err != nil {
-				logc.Errorf(ctx.Ctx, "处理规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, ruleExpr.Expr, err)
+				logc.Errorf(ctx.Ctx, "处理规则表达式失败, 表达式: %s, 错误: %v", ruleExpr.Expr, err)
 				continue
 			}
 
-			fingerprintMetric := provider.Metrics{
-				Metric: fingerprintLabels,
-			}
-			fingerprint := fingerprintMetric.GetFingerprint()
-
-			event := process.BuildEvent(rule, func() map[string]interface{} {
-				newMetric := make(map[string]interface{})
-				for k, val := range metricLabels {
-					newMetric[k] = val
-				}
-				newMetric["rule_name"] = rule.RuleName
-				newMetric["fingerprint"] = fingerprint
-				newMetric["severity"] = ruleExpr.Severity
-				newMetric["value"] = v.Value
-				for ek, ev := range externalLabels {
-					newMetric[ek] = ev
-				}
-				for ek, ev := range rule.ExternalLabels {
-					newMetric[ek] = ev
-				}
-
-				// 获取初次触发值
-				data, err := ctx.Redis.Alert().GetEventFromCache(rule.TenantId, rule.FaultCenterId, fingerprint)
-				if err == nil && data.Labels["first_value"] != nil {
-					newMetric["first_value"] = data.Labels["first_value"]
-				} else {
-					newMetric["first_value"] = v.Value
-				}
-
-				return newMetric
-			})
-			event.DatasourceId = datasourceId
-			event.Fingerprint = fingerprint
-			event.Severity = ruleExpr.Severity
-			event.SearchQL = fmt.Sprintf("%s %s %v", rule.PrometheusConfig.PromQL, operator, value)
-			event.ForDuration = rule.GetForDuration(ruleExpr.Severity)
-			event.Annotations = tools.ParserVariables(rule.PrometheusConfig.Annotations, tools.ConvertStructToMap(event))
-			event.Status = models.StatePreAlert
-
-			// 告警评估
+			// 未满足任何等级时, 查询语句展示最低等级的阈值
+			searchQL = fmt.Sprintf("%s %s %v", query, operator, value)
 			if process.EvalCondition(models.EvalCondition{
 				Operator:      operator,
 				QueryValue:    v.Value,
 				ExpectedValue: value,
 			}) {
-				if len(highestPriorityEvents) > 0 {
-					// 如果有高优先级告警，则抑制掉低级告警
-					event.LastSendTime = time.Now().Unix()
-				}
-				highestPriorityEvents[fingerprint] = struct{}{}
-				event.Status = models.StatePreAlert
-				process.PushEventToFaultCenter(ctx, &event)
-				curFingerprints = append(curFingerprints, fingerprint)
+				matched = &rules[i]
+				break
+			}
+		}
+
+		cache, cacheErr := ctx.Redis.Alert().GetEventFromCache(rule.TenantId, rule.FaultCenterId, fingerprint)
+		if cacheErr != nil && !isPreview(emit) {
+			cache, cacheErr = migrateLegacyFingerprint(ctx, rule, fingerprintLabels, fingerprint, rules)
+		}
+		severity := rules[len(rules)-1].Severity
+		if matched != nil {
+			severity = matched.Severity
+		} else if cacheErr == nil && cache.Severity != "" {
+			// 恢复时保持告警时的等级
+			severity = cache.Severity
+		}
+
+		event := process.BuildEvent(rule, func() map[string]interface{} {
+			newMetric := make(map[string]interface{})
+			for k, val := range metricLabels {
+				newMetric[k] = val
+			}
+			newMetric["rule_name"] = rule.RuleName
+			newMetric["fingerprint"] = fingerprint
+			newMetric["severity"] = severity
+			newMetric["value"] = v.Value
+			for ek, ev := range externalLabels {
+				newMetric[ek] = ev
+			}
+			for ek, ev := range rule.ExternalLabels {
+				newMetric[ek] = ev
+			}
+
+			// 获取初次触发值
+			if cacheErr == nil && cache.Labels["first_value"] != nil {
+				newMetric["first_value"] = cache.Labels["first_value"]
 			} else {
-				// 更新恢复时最新值
-				cache, err := ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
-				if err == nil {
-					if !cache.IsRecovered && cache.Status != models.StateRecovered {
-						event.Labels["value"] = v.GetValue()
-						process.PushEventToFaultCenter(ctx, &event)
-					}
-				}
+				newMetric["first_value"] = v.Value
 			}
+
+			return newMetric
+		})
+		event.DatasourceId = datasourceId
+		event.Fingerprint = fingerprint
+		event.Severity = severity
+		event.SearchQL = searchQL
+		event.ForDuration = rule.GetForDuration(severity)
+		event.Annotations = tools.ParserVariables(annotations, tools.ConvertStructToMap(event))
+		renderAnnotations(ctx, &event)
+		event.Status = models.StatePreAlert
+
+		// 告警评估
+		if matched != nil {
+			emit.Push(&event)
+			curFingerprints = append(curFingerprints, fingerprint)
+		} else {
+			event.Labels["value"] = v.GetValue()
+			emit.Skip(&event)
+			emit.Refresh(&event)
 		}
 	}
 
 	return curFingerprints
 }
 
+// migrateLegacyFingerprint 旧版本的指标类事件指纹包含告警等级, 升级后首次评估到该序列时将缓存中的旧事件迁移到新指纹下,
+// 保留首次触发时间及通知状态, 避免旧事件被判定为恢复后又以新指纹重新告警
+func migrateLegacyFingerprint(ctx *ctx.Context, rule models.AlertRule, fingerprintLabels map[string]interface{}, fingerprint string, rules []models.Rules) (models.AlertCurEvent, error) {
+	legacyLabels := make(map[string]interface{}, len(fingerprintLabels)+1)
+	for k, v := range fingerprintLabels {
+		legacyLabels[k] = v
+	}
+
+	ctx.Mux.Lock()
+	defer ctx.Mux.Unlock()
+	for _, r := range rules {
+		legacyLabels["severity"] = r.Severity
+		legacyFingerprint := provider.Metrics{Metric: legacyLabels}.GetFingerprint()
+		event, err := ctx.Redis.Alert().GetEventFromCache(rule.TenantId, rule.FaultCenterId, legacyFingerprint)
+		if err != nil {
+			continue
+		}
+
+		event.Fingerprint = fingerprint
+		if event.Labels != nil {
+			event.Labels["fingerprint"] = fingerprint
+		}
+		if err := ctx.Redis.Alert().PipelineUpdateEvents(rule.TenantId, rule.FaultCenterId, []*models.AlertCurEvent{&event}, []string{legacyFingerprint}); err != nil {
+			logc.Errorf(ctx.Ctx, "迁移旧版本事件指纹失败, 旧指纹: %s, 新指纹: %s, 错误: %v", legacyFingerprint, fingerprint, err)
+			return models.AlertCurEvent{}, err
+		}
+		// 待恢复计时同样按指纹记录, 一并迁移
+		if pendingAt, err := ctx.Redis.PendingRecover().Get(rule.TenantId, rule.RuleId, legacyFingerprint); err == nil && pendingAt > 0 {
+			ctx.Redis.PendingRecover().Set(rule.TenantId, rule.RuleId, fingerprint, pendingAt)
+			ctx.Redis.PendingRecover().Delete(rule.TenantId, rule.RuleId, legacyFingerprint)
+		}
+		logc.Infof(ctx.Ctx, "已迁移旧版本事件指纹, 旧指纹: %s, 新指纹: %s", legacyFingerprint, fingerprint)
+		return event, nil
+	}
+
+	return models.AlertCurEvent{}, fmt.Errorf("缓存中不存在旧版本指纹的事件")
+}
+
 // sortRulesByPriority 按优先级排序规则
 func sortRulesByPriority(rules []models.Rules) []models.Rules {
 	sortedRules := make([]models.Rules, len(rules))
 	copy(sortedRules, rules)
 
-	sort.Slice(sortedRules, func(i, j int) bool {
+	// 相同优先级保持配置的顺序
+	sort.SliceStable(sortedRules, func(i, j int) bool {
 		return getPriorityValue(sortedRules[i].Severity) > getPriorityValue(sortedRules[j].Severity)
 	})
 
@@ -166,17 +298,19 @@ func sortRulesByPriority(rules []models.Rules) []models.Rules {
 }
 
 // getPriorityValue 获取优先级的数值表示，用于排序
-// p0 优先级最高
-// p1 次之
-// p2 最低
+// p0 优先级最高, 依次递减到 p4
 // 其他情况排在后面
 func getPriorityValue(severity string) int {
 	switch severity {
 	case "P0":
-		return 3
+		return 5
 	case "P1":
-		return 2
+		return 4
 	case "P2":
+		return 3
+	case "P3":
+		return 2
+	case "P4":
 		return 1
 	default:
 		return 0
@@ -184,7 +318,7 @@ func getPriorityValue(severity string) int {
 }
 
 // Logs 包含 AliSLS、Loki、ElasticSearch 数据源
-func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
 	var (
 		// 日志信息
 		log provider.Logs
@@ -194,6 +328,12 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		evalOptions models.EvalCondition
 		// 额外的标签
 		externalLabels map[string]interface{}
+		// 计算评估值, 为空时使用日志总数
+		evalValue func(count int, messages []map[string]interface{}) (float64, error)
+		// 分组字段
+		groupBy = rule.LogGroupBy
+		// 查询失败的数据源目标已产生的事件指纹, 本次评估保持不变
+		held []string
 		// 当前时间
 		curAt = time.Now()
 	)
@@ -201,7 +341,8 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 	pools := ctx.Redis.ProviderPools()
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+		logc.Errorf(ctx.Ctx, "获取数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return []string{}
 	}
 
@@ -215,16 +356,17 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			StartAt: startsAt.Unix(),
 			EndAt:   curAt.Unix(),
 		}
-		log, count, err = cli.(provider.LokiProvider).Query(queryOptions)
+		log, count, err = cli.(provider.LokiProvider).Query(ctx.Ctx, queryOptions)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "Loki查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, LogQL: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.LokiConfig.LogQL, err)
+			logc.Errorf(ctx.Ctx, "Loki查询失败, LogQL: %s, 错误: %v", rule.LokiConfig.LogQL, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
 		externalLabels = cli.(provider.LokiProvider).GetExternalLabels()
 		operator, value, err := process.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, rule.LogEvalCondition, err)
+			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 表达式: %s, 错误: %v", rule.LogEvalCondition, err)
 			return []string{}
 		}
 
@@ -233,6 +375,12 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			QueryValue:    float64(count),
 			ExpectedValue: value,
 		}
+		if rule.LokiConfig.GetEvalMode() == models.LogEvalModeRate {
+			window := curAt.Sub(startsAt)
+			evalValue = func(count int, _ []map[string]interface{}) (float64, error) {
+				return logRate(count, window), nil
+			}
+		}
 	case provider.AliCloudSLSDsProviderName:
 		startsAt := tools.ParserDuration(curAt, rule.AliCloudSLSConfig.LogScope, "m")
 		queryOptions := provider.LogQueryOptions{
@@ -244,16 +392,27 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			StartAt: int32(startsAt.Unix()),
 			EndAt:   int32(curAt.Unix()),
 		}
-		log, count, err = cli.(provider.AliCloudSlsDsProvider).Query(queryOptions)
-		if err != nil {
-			logc.Errorf(ctx.Ctx, "AliCloudSLS查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, LogQL: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.AliCloudSLSConfig.LogQL, err)
-			return []string{}
+		if targets := rule.AliCloudSLSConfig.Targets; len(targets) > 0 {
+			queryOptions.AliCloudSLS.Targets = targets
+			var failed []models.AliCloudSLSTarget
+			log, failed = querySlsTargets(ctx, datasourceId, datasourceType, cli.(provider.AliCloudSlsDsProvider), queryOptions)
+			count = len(log.Message)
+			held = heldSlsFingerprints(ctx, rule, datasourceId, failed)
+			// 按查询目标分别产生事件
+			groupBy = append([]string{slsProjectLabel, slsLogstoreLabel}, rule.LogGroupBy...)
+		} else {
+			log, count, err = cli.(provider.AliCloudSlsDsProvider).Query(ctx.Ctx, queryOptions)
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "AliCloudSLS查询失败, LogQL: %s, 错误: %v", rule.AliCloudSLSConfig.LogQL, err)
+				ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+				return []string{}
+			}
 		}
 
 		externalLabels = cli.(provider.AliCloudSlsDsProvider).GetExternalLabels()
 		operator, value, err := process.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, rule.LogEvalCondition, err)
+			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 表达式: %s, 错误: %v", rule.LogEvalCondition, err)
 			return []string{}
 		}
 
@@ -271,18 +430,22 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 				QueryType:            rule.ElasticSearchConfig.EsQueryType,
 				QueryWildcard:        rule.ElasticSearchConfig.QueryWildcard,
 				RawJson:              rule.ElasticSearchConfig.RawJson,
+				QueryLanguage:        rule.ElasticSearchConfig.GetQueryLanguage(),
+				EsQL:                 rule.ElasticSearchConfig.EsQL,
+				Scope:                rule.ElasticSearchConfig.Scope,
 			},
 		}
-		log, count, err = cli.(provider.ElasticSearchDsProvider).Query(queryOptions)
+		log, count, err = cli.(provider.ElasticSearchDsProvider).Query(ctx.Ctx, queryOptions)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "ElasticSearch查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 索引: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.ElasticSearchConfig.Index, err)
+			logc.Errorf(ctx.Ctx, "ElasticSearch查询失败, 索引: %s, 错误: %v", rule.ElasticSearchConfig.Index, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
 		externalLabels = cli.(provider.ElasticSearchDsProvider).GetExternalLabels()
 		operator, value, err := process.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, rule.LogEvalCondition, err)
+			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 表达式: %s, 错误: %v", rule.LogEvalCondition, err)
 			return []string{}
 		}
 
@@ -291,6 +454,12 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			QueryValue:    float64(count),
 			ExpectedValue: value,
 		}
+		if field := rule.ElasticSearchConfig.ValueField; field != "" {
+			// 聚合查询按第一行结果中的数值列评估
+			evalValue = func(_ int, messages []map[string]interface{}) (float64, error) {
+				return firstNumericField(messages, field)
+			}
+		}
 	case provider.VictoriaLogsDsProviderName:
 		startsAt := tools.ParserDuration(curAt, rule.VictoriaLogsConfig.LogScope, "m")
 		queryOptions := provider.LogQueryOptions{
@@ -301,16 +470,17 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			StartAt: int32(startsAt.Unix()),
 			EndAt:   int32(curAt.Unix()),
 		}
-		log, count, err = cli.(provider.VictoriaLogsProvider).Query(queryOptions)
+		log, count, err = cli.(provider.VictoriaLogsProvider).Query(ctx.Ctx, queryOptions)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "VictoriaLogs查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, LogQL: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.VictoriaLogsConfig.LogQL, err)
+			logc.Errorf(ctx.Ctx, "VictoriaLogs查询失败, LogQL: %s, 错误: %v", rule.VictoriaLogsConfig.LogQL, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
 		externalLabels = cli.(provider.VictoriaLogsProvider).GetExternalLabels()
 		operator, value, err := process.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, rule.LogEvalCondition, err)
+			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 表达式: %s, 错误: %v", rule.LogEvalCondition, err)
 			return []string{}
 		}
 
@@ -319,22 +489,35 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 			QueryValue:    float64(count),
 			ExpectedValue: value,
 		}
+		if field := rule.VictoriaLogsConfig.ValueField; field != "" {
+			stats, err := models.ParseLogsQLStats(rule.VictoriaLogsConfig.LogQL)
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "解析 LogsQL stats 管道失败, LogQL: %s, 错误: %v", rule.VictoriaLogsConfig.LogQL, err)
+				return []string{}
+			}
+			// stats 管道每个分组返回一行, 按分组字段生成指纹并以聚合结果评估
+			groupBy = stats.By
+			evalValue = func(_ int, messages []map[string]interface{}) (float64, error) {
+				return firstNumericField(messages, field)
+			}
+		}
 	case provider.ClickHouseDsProviderName:
 		queryOptions := provider.LogQueryOptions{
 			ClickHouse: provider.ClickHouse{
 				Query: rule.ClickHouseConfig.LogQL,
 			},
 		}
-		log, count, err = cli.(provider.ClickHouseProvider).Query(queryOptions)
+		log, count, err = cli.(provider.ClickHouseProvider).Query(ctx.Ctx, queryOptions)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "ClickHouse查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, LogQL: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.ClickHouseConfig.LogQL, err)
+			logc.Errorf(ctx.Ctx, "ClickHouse查询失败, LogQL: %s, 错误: %v", rule.ClickHouseConfig.LogQL, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
 		externalLabels = cli.(provider.ClickHouseProvider).GetExternalLabels()
 		operator, value, err := process.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 规则ID: %s, 规则名称: %s, 表达式: %s, 错误: %v", rule.RuleId, rule.RuleName, rule.LogEvalCondition, err)
+			logc.Errorf(ctx.Ctx, "处理日志规则表达式失败, 表达式: %s, 错误: %v", rule.LogEvalCondition, err)
 			return []string{}
 		}
 
@@ -346,64 +529,192 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 	}
 
 	if count <= 0 {
-		return []string{}
+		return append([]string{}, held...)
 	}
 
-	// 唯一指纹基于 RuleId
-	fingerprint := log.GenerateFingerprint(rule.RuleId)
-	var curFingerprints []string
-	event := func() *models.AlertCurEvent {
-		event := process.BuildEvent(rule, func() map[string]interface{} {
-			labels := map[string]interface{}{
-				"value":       count,
-				"severity":    rule.Severity,
-				"fingerprint": fingerprint,
-				"rule_name":   rule.RuleName,
-			}
-			for ek, ev := range externalLabels {
-				labels[ek] = ev
-			}
-			for ek, ev := range rule.ExternalLabels {
-				labels[ek] = ev
+	curFingerprints := held
+	for _, group := range groupLogs(log, count, groupBy) {
+		groupEval := evalOptions
+		groupEval.QueryValue = float64(group.count)
+
+		// 事件的 value 标签, 未配置评估值时为日志总数
+		var eventValue interface{} = group.count
+		if evalValue != nil {
+			v, err := evalValue(group.count, group.log.Message)
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "计算日志评估值失败, 错误: %v", err)
+				continue
 			}
-			for logKey, logValue := range log.GetAnnotations() {
-				labels[logKey] = logValue
+			groupEval.QueryValue = v
+			eventValue = v
+		}
+
+		// 唯一指纹基于 RuleId 及分组字段的值
+		fingerprint := group.log.GenerateFingerprintWithLabels(rule.RuleId, group.labels)
+		event := func() *models.AlertCurEvent {
+			event := process.BuildEvent(rule, func() map[string]interface{} {
+				labels := map[string]interface{}{
+					"value":       eventValue,
+					"severity":    rule.Severity,
+					"fingerprint": fingerprint,
+					"rule_name":   rule.RuleName,
+				}
+				if evalValue != nil {
+					labels["count"] = group.count
+				}
+				for ek, ev := range externalLabels {
+					labels[ek] = ev
+				}
+				for ek, ev := range rule.ExternalLabels {
+					labels[ek] = ev
+				}
+				for logKey, logValue := range group.log.GetAnnotations() {
+					labels[logKey] = logValue
+				}
+				for gk, gv := range group.labels {
+					labels[gk] = gv
+				}
+				return labels
+			})
+			event.DatasourceId = datasourceId
+			event.Fingerprint = fingerprint
+
+			switch datasourceType {
+			case provider.LokiDsProviderName:
+				event.SearchQL = rule.LokiConfig.LogQL
+			case provider.AliCloudSLSDsProviderName:
+				event.SearchQL = rule.AliCloudSLSConfig.LogQL
+			case provider.ElasticSearchDsProviderName:
+				if rule.ElasticSearchConfig.GetQueryLanguage() == models.EsQueryLanguageEsql {
+					event.SearchQL = rule.ElasticSearchConfig.EsQL
+				} else if rule.ElasticSearchConfig.RawJson != "" {
+					event.SearchQL = rule.ElasticSearchConfig.RawJson
+				} else {
+					event.SearchQL = tools.JsonMarshalToString(rule.ElasticSearchConfig.Filter)
+				}
+			case provider.VictoriaLogsDsProviderName:
+				event.SearchQL = rule.VictoriaLogsConfig.LogQL
 			}
-			return labels
-		})
-		event.DatasourceId = datasourceId
-		event.Fingerprint = fingerprint
 
-		switch datasourceType {
-		case provider.LokiDsProviderName:
-			event.SearchQL = rule.LokiConfig.LogQL
-		case provider.AliCloudSLSDsProviderName:
-			event.SearchQL = rule.AliCloudSLSConfig.LogQL
-		case provider.ElasticSearchDsProviderName:
-			if rule.ElasticSearchConfig.RawJson != "" {
-				event.SearchQL = rule.ElasticSearchConfig.RawJson
-			} else {
-				event.SearchQL = tools.JsonMarshalToString(rule.ElasticSearchConfig.Filter)
+			return &event
+		}
+
+		// 评估告警条件
+		if process.EvalCondition(groupEval) {
+			e := event()
+			curFingerprints = append(curFingerprints, e.Fingerprint)
+			emit.Push(e)
+		} else {
+			emit.Skip(event())
+		}
+	}
+
+	return curFingerprints
+}
+
+type logGroup struct {
+	labels map[string]interface{}
+	log    provider.Logs
+	count  int
+}
+
+// groupLogs 按字段的值对日志分组, 未配置分组字段时整体作为一组; 缺少分组字段的日志归入空值分组
+func groupLogs(log provider.Logs, count int, groupBy []string) []logGroup {
+	if len(groupBy) == 0 {
+		return []logGroup{{log: log, count: count}}
+	}
+
+	var (
+		groups []logGroup
+		index  = make(map[string]int)
+	)
+	for _, message := range log.Message {
+		labels := make(map[string]interface{}, len(groupBy))
+		keys := make([]string, 0, len(groupBy))
+		for _, field := range groupBy {
+			value := ""
+			if v, ok := lookupLogField(message, field); ok {
+				value = fmt.Sprintf("%v", v)
 			}
-		case provider.VictoriaLogsDsProviderName:
-			event.SearchQL = rule.VictoriaLogsConfig.LogQL
+			labels[field] = value
+			keys = append(keys, value)
 		}
 
-		curFingerprints = append(curFingerprints, event.Fingerprint)
+		key := strings.Join(keys, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, logGroup{
+				labels: labels,
+				log:    provider.Logs{ProviderName: log.ProviderName},
+			})
+		}
+		groups[i].log.Message = append(groups[i].log.Message, message)
+		groups[i].count++
+	}
 
-		return &event
+	return groups
+}
+
+// lookupLogField 获取日志字段, 支持以 . 分隔的嵌套字段, 如 req.method
+func lookupLogField(message map[string]interface{}, field string) (interface{}, bool) {
+	if v, ok := message[field]; ok {
+		return v, v != nil
 	}
 
-	// 评估告警条件
-	if process.EvalCondition(evalOptions) {
-		process.PushEventToFaultCenter(ctx, event())
+	var cur interface{} = message
+	for _, key := range strings.Split(field, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok || cur == nil {
+			return nil, false
+		}
 	}
+	return cur, true
+}
 
-	return curFingerprints
+// logRate 计算窗口内每秒匹配的日志条数
+func logRate(count int, window time.Duration) float64 {
+	if window <= 0 {
+		return float64(count)
+	}
+	return float64(count) / window.Seconds()
+}
+
+// firstNumericField 获取第一行结果中数值类型的字段, 日志总数与返回的行数不一致时可能没有结果行
+func firstNumericField(messages []map[string]interface{}, field string) (float64, error) {
+	if len(messages) == 0 {
+		return 0, fmt.Errorf("查询结果中没有数据行, 无法获取字段 %s", field)
+	}
+	return numericField(messages[0], field)
+}
+
+// numericField 获取日志中数值类型的字段
+func numericField(message map[string]interface{}, field string) (float64, error) {
+	v, ok := message[field]
+	if !ok || v == nil {
+		return 0, fmt.Errorf("字段 %s 不存在", field)
+	}
+
+	switch value := v.(type) {
+	case float64:
+		return value, nil
+	case int64:
+		return float64(value), nil
+	case int:
+		return float64(value), nil
+	case string:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return 0, fmt.Errorf("字段 %s 不是数值类型: %v", field, v)
+	}
 }
 
 // Traces 包含 Jaeger 数据源
-func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
 	var (
 		queryRes       []provider.Traces
 		externalLabels map[string]interface{}
@@ -417,7 +728,8 @@ func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.A
 
 		cli, err := pools.GetClient(datasourceId)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "获取Jaeger数据源客户端失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+			logc.Errorf(ctx.Ctx, "获取Jaeger数据源客户端失败, 错误: %v", err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
@@ -427,9 +739,10 @@ func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.A
 			StartAt: startsAt.UnixMicro(),
 			EndAt:   curAt.UnixMicro(),
 		}
-		queryRes, err = cli.(provider.JaegerDsProvider).Query(queryOptions)
+		queryRes, err = cli.(provider.JaegerDsProvider).Query(ctx.Ctx, queryOptions)
 		if err != nil {
-			logc.Errorf(ctx.Ctx, "Jaeger查询失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 服务: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.JaegerConfig.Service, err)
+			logc.Errorf(ctx.Ctx, "Jaeger查询失败, 服务: %s, 错误: %v", rule.JaegerConfig.Service, err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 			return []string{}
 		}
 
@@ -460,87 +773,217 @@ func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.A
 		event.Annotations = fmt.Sprintf("服务: %s 链路中存在异常, TraceId: %s", rule.JaegerConfig.Service, v.TraceId)
 
 		curFingerprints = append(curFingerprints, event.Fingerprint)
-		process.PushEventToFaultCenter(ctx, &event)
+		emit.Push(&event)
 	}
 
 	return curFingerprints
 }
 
-func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
-	var externalLabels map[string]interface{}
+// tempo Grafana Tempo 数据源, 按服务/接口统计窗口内匹配 TraceQL 的链路数量
+func tempo(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
 	pools := ctx.Redis.ProviderPools()
-	cfg, err := pools.GetClient(datasourceId)
+	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取CloudWatch数据源客户端失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+		logc.Errorf(ctx.Ctx, "获取Tempo数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return []string{}
 	}
 
-	externalLabels = cfg.(provider.AwsConfig).GetExternalLabels()
-
-	cli := cfg.(provider.AwsConfig).CloudWatchCli()
+	scope := rule.JaegerConfig.Scope
+	if scope <= 0 {
+		scope = 5
+	}
 	curAt := time.Now().UTC()
-	startsAt := tools.ParserDuration(curAt, rule.CloudWatchConfig.Period, "m")
+	startsAt := tools.ParserDuration(curAt, scope, "m")
+
+	tempoCli := cli.(provider.TempoDsProvider)
+	queryRes, err := tempoCli.Query(ctx.Ctx, provider.TraceQueryOptions{
+		Tags:    rule.JaegerConfig.Tags,
+		Service: rule.JaegerConfig.Service,
+		StartAt: startsAt.UnixMicro(),
+		EndAt:   curAt.UnixMicro(),
+	})
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "Tempo查询失败, TraceQL: %s, 错误: %v", rule.JaegerConfig.Tags, err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+		return []string{}
+	}
 
-	var curFingerprints []string
-	for _, endpoint := range rule.CloudWatchConfig.Endpoints {
-		query := cloudwatch.CloudWatchQuery{
-			Endpoint:   endpoint,
-			Dimension:  rule.CloudWatchConfig.Dimension,
-			Period:     int32(rule.CloudWatchConfig.Period * 60),
-			Namespace:  rule.CloudWatchConfig.Namespace,
-			MetricName: rule.CloudWatchConfig.MetricName,
-			Statistic:  rule.CloudWatchConfig.Statistic,
-			Form:       startsAt,
-			To:         curAt,
-		}
-		_, values := cloudwatch.MetricDataQuery(cli, query)
-		if len(values) == 0 {
-			return []string{}
+	// 未配置条件时, 存在匹配链路即告警
+	evalCondition := rule.LogEvalCondition
+	if evalCondition == "" {
+		evalCondition = "> 0"
+	}
+	operator, value, err := process.ProcessRuleExpr(evalCondition)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "处理链路规则表达式失败, 表达式: %s, 错误: %v", evalCondition, err)
+		return []string{}
+	}
+
+	type traceGroup struct {
+		service   string
+		operation string
+		traceId   string
+		count     int
+	}
+	var (
+		groups []*traceGroup
+		index  = make(map[string]*traceGroup)
+	)
+	for _, v := range queryRes {
+		key := v.Service + "/" + v.Operation
+		g, ok := index[key]
+		if !ok {
+			g = &traceGroup{service: v.Service, operation: v.Operation}
+			index[key] = g
+			groups = append(groups, g)
 		}
+		g.count++
+		g.traceId = v.TraceId
+	}
+
+	externalLabels := tempoCli.GetExternalLabels()
+	traceQL := provider.BuildTraceQL(rule.JaegerConfig.Service, rule.JaegerConfig.Tags)
+
+	var curFingerprints []string
+	for _, g := range groups {
+		fingerprint := provider.Metrics{Metric: map[string]interface{}{
+			"rule_id":   rule.RuleId,
+			"service":   g.service,
+			"operation": g.operation,
+		}}.GetFingerprint()
 
 		event := process.BuildEvent(rule, func() map[string]interface{} {
-			metric := query.GetMetrics()
-			metric["severity"] = rule.Severity
+			metric := map[string]interface{}{
+				"rule_name":   rule.RuleName,
+				"severity":    rule.Severity,
+				"fingerprint": fingerprint,
+				"service":     g.service,
+				"operation":   g.operation,
+				"traceId":     g.traceId,
+				"value":       g.count,
+			}
 			for ek, ev := range externalLabels {
 				metric[ek] = ev
 			}
 			for ek, ev := range rule.ExternalLabels {
 				metric[ek] = ev
 			}
-			metric["rule_name"] = rule.RuleName
 			return metric
 		})
 		event.DatasourceId = datasourceId
-		event.Fingerprint = query.GetFingerprint()
-		event.Annotations = fmt.Sprintf("%s %s %s %s %d", query.Namespace, query.MetricName, query.Statistic, rule.CloudWatchConfig.Expr, rule.CloudWatchConfig.Threshold)
+		event.Fingerprint = fingerprint
+		event.SearchQL = traceQL
+		event.Annotations = fmt.Sprintf("服务: %s 接口: %s 最近 %d 分钟内匹配链路数: %d, 示例 TraceId: %s", g.service, g.operation, scope, g.count, g.traceId)
 
-		options := models.EvalCondition{
-			Operator:      rule.CloudWatchConfig.Expr,
-			QueryValue:    values[0],
-			ExpectedValue: float64(rule.CloudWatchConfig.Threshold),
+		if process.EvalCondition(models.EvalCondition{
+			Operator:      operator,
+			QueryValue:    float64(g.count),
+			ExpectedValue: value,
+		}) {
+			curFingerprints = append(curFingerprints, fingerprint)
+			emit.Push(&event)
+		} else {
+			emit.Skip(&event)
 		}
+	}
 
-		curFingerprints = append(curFingerprints, event.Fingerprint)
-		if process.EvalCondition(options) {
-			process.PushEventToFaultCenter(ctx, &event)
+	return curFingerprints
+}
+
+func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
+	var externalLabels map[string]interface{}
+	pools := ctx.Redis.ProviderPools()
+	cfg, err := pools.GetClient(datasourceId)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取CloudWatch数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+		return []string{}
+	}
+
+	awsCfg := cfg.(provider.AwsConfig)
+	externalLabels = awsCfg.GetExternalLabels()
+
+	curAt := time.Now().UTC()
+	startsAt := tools.ParserDuration(curAt, rule.CloudWatchConfig.Period, "m")
+
+	// 未配置区域时使用默认凭证链中的区域
+	regions := awsCfg.GetRegions()
+	if len(regions) == 0 {
+		regions = []string{""}
+	}
+
+	var curFingerprints []string
+	for _, region := range regions {
+		cli := awsCfg.CloudWatchCli()
+		if region != "" {
+			cli = awsCfg.CloudWatchRegionCli(region)
+		}
+
+		for _, endpoint := range rule.CloudWatchConfig.Endpoints {
+			query := cloudwatch.CloudWatchQuery{
+				Region:     region,
+				Endpoint:   endpoint,
+				Dimension:  rule.CloudWatchConfig.Dimension,
+				Period:     int32(rule.CloudWatchConfig.Period * 60),
+				Namespace:  rule.CloudWatchConfig.Namespace,
+				MetricName: rule.CloudWatchConfig.MetricName,
+				Statistic:  rule.CloudWatchConfig.Statistic,
+				Form:       startsAt,
+				To:         curAt,
+			}
+			_, values := cloudwatch.MetricDataQuery(ctx.Ctx, cli, query)
+			if len(values) == 0 {
+				continue
+			}
+
+			event := process.BuildEvent(rule, func() map[string]interface{} {
+				metric := query.GetMetrics()
+				metric["severity"] = rule.Severity
+				for ek, ev := range externalLabels {
+					metric[ek] = ev
+				}
+				for ek, ev := range rule.ExternalLabels {
+					metric[ek] = ev
+				}
+				metric["rule_name"] = rule.RuleName
+				return metric
+			})
+			event.DatasourceId = datasourceId
+			event.Fingerprint = query.GetFingerprint()
+			event.Annotations = fmt.Sprintf("%s %s %s %s %d", query.Namespace, query.MetricName, query.Statistic, rule.CloudWatchConfig.Expr, rule.CloudWatchConfig.Threshold)
+
+			options := models.EvalCondition{
+				Operator:      rule.CloudWatchConfig.Expr,
+				QueryValue:    values[0],
+				ExpectedValue: float64(rule.CloudWatchConfig.Threshold),
+			}
+
+			curFingerprints = append(curFingerprints, event.Fingerprint)
+			if process.EvalCondition(options) {
+				emit.Push(&event)
+			} else {
+				emit.Skip(&event)
+			}
 		}
 	}
 
 	return curFingerprints
 }
 
-func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule, emit emitter) []string {
 	// 获取数据源实例信息
 	datasourceObj, err := ctx.DB.Datasource().GetInstance(datasourceId)
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取数据源实例失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+		logc.Errorf(ctx.Ctx, "获取数据源实例失败, 错误: %v", err)
 		return []string{}
 	}
 
 	pools := ctx.Redis.ProviderPools()
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取Kubernetes数据源客户端失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, err)
+		logc.Errorf(ctx.Ctx, "获取Kubernetes数据源客户端失败, 错误: %v", err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return []string{}
 	}
 
@@ -548,9 +991,17 @@ func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule
 	externalLabels := k8sClient.GetExternalLabels()
 
 	// 查询 Kubernetes 事件
-	k8sEventMap, err := k8sClient.GetWarningEvent(rule.KubernetesConfig.Reason, rule.KubernetesConfig.Scope, rule.KubernetesConfig.Filter)
+	k8sConfig := rule.KubernetesConfig
+	k8sEventMap, err := k8sClient.GetWarningEvent(provider.KubernetesEventQuery{
+		Reasons:    k8sConfig.GetReasons(),
+		Namespaces: k8sConfig.Namespaces,
+		Kinds:      k8sConfig.Kinds,
+		Exclude:    k8sConfig.Filter,
+		Scope:      k8sConfig.Scope,
+	})
 	if err != nil {
-		logc.Errorf(ctx.Ctx, "获取Kubernetes警告事件失败, 规则ID: %s, 规则名称: %s, 数据源ID: %s, 原因: %s, 错误: %v", rule.RuleId, rule.RuleName, datasourceId, rule.KubernetesConfig.Reason, err)
+		logc.Errorf(ctx.Ctx, "获取Kubernetes警告事件失败, 原因: %v, 错误: %v", k8sConfig.GetReasons(), err)
+		ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
 		return []string{}
 	}
 
@@ -559,54 +1010,67 @@ func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule
 		return []string{}
 	}
 
-	// 遍历事件组，评估并生成告警
+	// 遍历事件组，同一资源的事件合并为一个告警
 	curFingerprints := make([]string, 0, len(k8sEventMap))
 	for _, eventItems := range k8sEventMap {
-		for _, k8sEvent := range eventItems {
-			fingerprint := k8sEvent.GetFingerprint()
+		var (
+			count   int64
+			msgList []string
+			latest  = eventItems[0]
+		)
+		for _, e := range eventItems {
+			count += e.GetCount()
+			msgList = append(msgList, strings.ReplaceAll(e.Message, "\"", "'"))
+			if e.LastTimestamp.After(latest.LastTimestamp.Time) {
+				latest = e
+			}
+		}
 
-			// 构建告警事件
-			event := process.BuildEvent(rule, func() map[string]interface{} {
-				metric := k8sEvent.GetMetrics()
-				metric["rule_name"] = rule.RuleName
-				metric["severity"] = rule.Severity
-				metric["fingerprint"] = fingerprint
-				for k, v := range externalLabels {
-					metric[k] = v
-				}
-				for k, v := range rule.ExternalLabels {
-					metric[k] = v
-				}
-				return metric
-			})
+		// 未达到最少事件次数
+		if count < k8sConfig.MinCount {
+			continue
+		}
 
-			// 设置事件基本信息
-			event.DatasourceId = datasourceId
-			event.Fingerprint = fingerprint
-			event.SearchQL = rule.KubernetesConfig.Resource
+		// 同一规则可跨多个集群, 指纹及标签中包含集群标识
+		fingerprint := latest.GetFingerprint(datasourceId)
 
-			// 构建注释信息
-			var msgList []string
-			for _, e := range eventItems {
-				msg := strings.ReplaceAll(e.Message, "\"", "'")
-				msgList = append(msgList, msg)
+		// 构建告警事件
+		event := process.BuildEvent(rule, func() map[string]interface{} {
+			metric := latest.GetMetrics()
+			metric["rule_name"] = rule.RuleName
+			metric["severity"] = rule.Severity
+			metric["fingerprint"] = fingerprint
+			metric["value"] = count
+			metric["datasource_id"] = datasourceId
+			metric["cluster"] = datasourceObj.Name
+			for k, v := range externalLabels {
+				metric[k] = v
 			}
+			for k, v := range rule.ExternalLabels {
+				metric[k] = v
+			}
+			return metric
+		})
 
-			event.Annotations = fmt.Sprintf(
-				"- 数据源: %s\n- 命名空间: %s\n- 资源类型: %s\n- 资源名称: %s\n- 事件类型: %s\n- 事件详情:\n%s",
-				datasourceObj.Name,
-				k8sEvent.Namespace,
-				k8sEvent.InvolvedObject.Kind,
-				k8sEvent.InvolvedObject.Name,
-				k8sEvent.Reason,
-				strings.Join(msgList, "\n"),
-			)
-
-			// 推送到故障中心
-			process.PushEventToFaultCenter(ctx, &event)
-			curFingerprints = append(curFingerprints, fingerprint)
-		}
-
+		// 设置事件基本信息
+		event.DatasourceId = datasourceId
+		event.Fingerprint = fingerprint
+		event.SearchQL = k8sConfig.Resource
+
+		event.Annotations = fmt.Sprintf(
+			"- 集群: %s\n- 命名空间: %s\n- 资源类型: %s\n- 资源名称: %s\n- 事件类型: %s\n- 事件次数: %d\n- 事件详情:\n%s",
+			datasourceObj.Name,
+			latest.Namespace,
+			latest.InvolvedObject.Kind,
+			latest.InvolvedObject.Name,
+			latest.Reason,
+			count,
+			strings.Join(msgList, "\n"),
+		)
+
+		// 推送到故障中心
+		emit.Push(&event)
+		curFingerprints = append(curFingerprints, fingerprint)
 	}
 
 	return curFingerprints
@@ -0,0 +1,107 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"watchAlert/internal/models"
+)
+
+// quorumEmitter 暂存各数据源的评估结果, 每个数据源对每个指纹投一票, 所有数据源评估完成后按票数转发
+type quorumEmitter struct {
+	mu      sync.Mutex
+	votes   map[string]map[string]*models.AlertCurEvent // 指纹 -> 满足条件的数据源 -> 事件
+	skipped map[string]*models.AlertCurEvent            // 指纹 -> 未满足条件的事件
+	refresh map[string]*models.AlertCurEvent            // 指纹 -> 需要更新最新值的事件
+}
+
+func newQuorumEmitter() *quorumEmitter {
+	return &quorumEmitter{
+		votes:   make(map[string]map[string]*models.AlertCurEvent),
+		skipped: make(map[string]*models.AlertCurEvent),
+		refresh: make(map[string]*models.AlertCurEvent),
+	}
+}
+
+func (q *quorumEmitter) Push(event *models.AlertCurEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.votes[event.Fingerprint] == nil {
+		q.votes[event.Fingerprint] = make(map[string]*models.AlertCurEvent)
+	}
+	q.votes[event.Fingerprint][event.DatasourceId] = event
+}
+
+func (q *quorumEmitter) Skip(event *models.AlertCurEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.skipped[event.Fingerprint]; !ok {
+		q.skipped[event.Fingerprint] = event
+	}
+}
+
+func (q *quorumEmitter) Refresh(event *models.AlertCurEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.refresh[event.Fingerprint]; !ok {
+		q.refresh[event.Fingerprint] = event
+	}
+}
+
+// flush 票数达到 quorum 的指纹按数据源列表中靠前的数据源的事件推送, 其余指纹按未满足条件处理并更新最新值, 返回产生事件的指纹
+func (q *quorumEmitter) flush(rule models.AlertRule, quorum int, next emitter) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var (
+		fingerprints []string
+		pushed       = make(map[string]struct{})
+	)
+	for fingerprint, votes := range q.votes {
+		var event *models.AlertCurEvent
+		for _, dsId := range rule.DatasourceIdList {
+			if e, ok := votes[dsId]; ok {
+				event = e
+				break
+			}
+		}
+		if event == nil {
+			continue
+		}
+
+		if len(votes) < quorum {
+			next.Skip(event)
+			continue
+		}
+		next.Push(event)
+		fingerprints = append(fingerprints, fingerprint)
+		pushed[fingerprint] = struct{}{}
+	}
+
+	for fingerprint, event := range q.skipped {
+		if _, ok := q.votes[fingerprint]; ok {
+			continue
+		}
+		next.Skip(event)
+	}
+
+	for fingerprint, event := range q.refresh {
+		if _, ok := pushed[fingerprint]; ok {
+			continue
+		}
+		next.Refresh(event)
+	}
+
+	return fingerprints
+}
+
+// processDatasourcesQuorum 查询所有数据源并按指纹统计满足条件的数据源数量, 达到 quorum 时才产生事件;
+// 查询失败的数据源不参与投票, 可用的数据源不足 quorum 时不会产生新事件
+func (t *AlertRule) processDatasourcesQuorum(ctx context.Context, rule models.AlertRule, next emitter) []string {
+	votes := newQuorumEmitter()
+	t.processDatasourcesUnion(ctx, rule, votes)
+
+	return votes.flush(rule, rule.GetDatasourceQuorum(), next)
+}
@@ -0,0 +1,40 @@
+package eval
+
+import (
+	"slices"
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestQuorumEmitterFlush(t *testing.T) {
+	rule := models.AlertRule{DatasourceIdList: []string{"ds-1", "ds-2", "ds-3"}}
+	votes := newQuorumEmitter()
+
+	// fp-a 三个数据源均满足条件, fp-b 仅一个数据源满足条件, fp-c 均未满足条件
+	for _, ds := range rule.DatasourceIdList {
+		votes.Push(&models.AlertCurEvent{Fingerprint: "fp-a", DatasourceId: ds})
+		votes.Skip(&models.AlertCurEvent{Fingerprint: "fp-c", DatasourceId: ds})
+	}
+	votes.Push(&models.AlertCurEvent{Fingerprint: "fp-b", DatasourceId: "ds-3"})
+	votes.Skip(&models.AlertCurEvent{Fingerprint: "fp-b", DatasourceId: "ds-1"})
+
+	sink := &previewEmitter{}
+	fingerprints := votes.flush(rule, rule.GetDatasourceQuorum(), sink)
+	if !slices.Equal(fingerprints, []string{"fp-a"}) {
+		t.Fatalf("fingerprints = %v, want [fp-a]", fingerprints)
+	}
+
+	triggered := make(map[string]bool)
+	for _, sample := range sink.samples {
+		if _, ok := triggered[sample.Fingerprint]; ok {
+			t.Fatalf("fingerprint %s emitted more than once", sample.Fingerprint)
+		}
+		triggered[sample.Fingerprint] = sample.Triggered
+	}
+	if !triggered["fp-a"] || triggered["fp-b"] || triggered["fp-c"] {
+		t.Fatalf("unexpected triggered samples: %v", triggered)
+	}
+	if len(triggered) != 3 {
+		t.Fatalf("emitted %d fingerprints, want 3", len(triggered))
+	}
+}
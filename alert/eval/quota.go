@@ -0,0 +1,55 @@
+package eval
+
+import (
+	"sync"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 租户活跃事件配额的检查结果缓存时间, 避免每个事件都统计故障中心
+const eventQuotaCacheTTL = 10 * time.Second
+
+type eventQuotaState struct {
+	exceeded  bool
+	used      int64
+	quota     int64
+	checkedAt time.Time
+}
+
+var eventQuotas = struct {
+	sync.Mutex
+	m map[string]eventQuotaState
+}{m: make(map[string]eventQuotaState)}
+
+// isEventQuotaExceeded 判断租户的活跃事件数量是否已达到配额, 未配置配额时不限制
+func isEventQuotaExceeded(c *ctx.Context, tenantId string) bool {
+	eventQuotas.Lock()
+	defer eventQuotas.Unlock()
+
+	if state, ok := eventQuotas.m[tenantId]; ok && time.Since(state.checkedAt) < eventQuotaCacheTTL {
+		return state.exceeded
+	}
+
+	state := eventQuotaState{checkedAt: time.Now()}
+	tenant, err := c.DB.Tenant().Get(tenantId)
+	if err == nil && tenant.EventNumber > 0 {
+		used, err := process.GetTenantEventCount(c, tenantId)
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(c.Ctx, tools.LogFieldTenantId, tenantId), "统计租户活跃事件数量失败, err: %s", err.Error())
+		} else {
+			state.used, state.quota = used, tenant.EventNumber
+			state.exceeded = used >= tenant.EventNumber
+		}
+	}
+	eventQuotas.m[tenantId] = state
+
+	if state.exceeded {
+		logc.Errorf(tools.WithLogFields(c.Ctx, tools.LogFieldTenantId, tenantId), "租户活跃事件数量已达到配额上限 (%d/%d), 不再产生新的告警事件", state.used, state.quota)
+	}
+
+	return state.exceeded
+}
@@ -0,0 +1,159 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"watchAlert/alert/process"
+	"watchAlert/internal/cache"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/go-redis/redis"
+)
+
+const benchFingerprints = 10000
+
+// buildRecoverFixture 构造 10k 指纹: 1/4 仍满足条件, 1/4 预告警待移除, 1/4 告警中待恢复, 1/4 等待时间已到待恢复
+func buildRecoverFixture(n int, curTime int64) (map[string]*models.AlertCurEvent, []string, map[string]int64) {
+	events := make(map[string]*models.AlertCurEvent, n)
+	pendingRecover := make(map[string]int64)
+	var cur []string
+	for i := 0; i < n; i++ {
+		fp := fmt.Sprintf("fp-%d", i)
+		event := &models.AlertCurEvent{RuleId: "a-1", Fingerprint: fp, Status: models.StateAlerting}
+		switch i % 4 {
+		case 0:
+			cur = append(cur, fp)
+		case 1:
+			event.Status = models.StatePreAlert
+		case 3:
+			event.Status = models.StatePendingRecovery
+			pendingRecover[fp] = curTime - 600
+		}
+		events[fp] = event
+	}
+
+	return events, cur, pendingRecover
+}
+
+func TestPlanRecover(t *testing.T) {
+	curTime := int64(1700000000)
+	events, cur, pendingRecover := buildRecoverFixture(8, curTime)
+
+	plan := planRecover(context.Background(), "a-1", events, cur, map[string]int64{"fp-1": curTime}, pendingRecover, curTime, 60)
+
+	if len(plan.remove) != 2 {
+		t.Fatalf("expected 2 pre-alert events removed, got: %v", plan.remove)
+	}
+	if len(plan.pendingDelete) != 1 || plan.pendingDelete[0] != "fp-1" {
+		t.Fatalf("expected pending timer of fp-1 deleted, got: %v", plan.pendingDelete)
+	}
+	if len(plan.pendingRecoverSet) != 2 {
+		t.Fatalf("expected 2 events marked pending recovery, got: %v", plan.pendingRecoverSet)
+	}
+	if len(plan.pendingRecoverDelete) != 2 {
+		t.Fatalf("expected 2 events recovered, got: %v", plan.pendingRecoverDelete)
+	}
+	if len(plan.push) != 4 {
+		t.Fatalf("expected 4 events updated, got: %d", len(plan.push))
+	}
+	if events["fp-3"].Status != models.StateRecovered || events["fp-2"].Status != models.StatePendingRecovery {
+		t.Fatalf("unexpected transitions: fp-2 %s, fp-3 %s", events["fp-2"].Status, events["fp-3"].Status)
+	}
+}
+
+// newCountingRedis 创建统计网络往返次数的 Redis 客户端, 命令不会真正发出, 单条命令及每次 Pipeline 执行各计一次往返
+func newCountingRedis() (*redis.Client, *int64) {
+	var roundtrips int64
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	client.WrapProcess(func(func(redis.Cmder) error) func(redis.Cmder) error {
+		return func(redis.Cmder) error {
+			atomic.AddInt64(&roundtrips, 1)
+			return nil
+		}
+	})
+	client.WrapProcessPipeline(func(func([]redis.Cmder) error) func([]redis.Cmder) error {
+		return func([]redis.Cmder) error {
+			atomic.AddInt64(&roundtrips, 1)
+			return nil
+		}
+	})
+	return client, &roundtrips
+}
+
+// applyRecoverPlanPerFingerprint 按原实现逐个指纹写入恢复处理的变更, 作为对照
+func (t *AlertRule) applyRecoverPlanPerFingerprint(tenantId, ruleId string, plan recoverPlan) {
+	for _, fingerprint := range plan.pendingDelete {
+		t.ctx.Redis.Pending().Delete(tenantId, ruleId, fingerprint)
+	}
+	for _, fingerprint := range plan.remove {
+		t.ctx.Redis.Alert().RemoveAlertEvent(tenantId, "", fingerprint)
+	}
+	for fingerprint, ts := range plan.pendingRecoverSet {
+		t.ctx.Redis.PendingRecover().Set(tenantId, ruleId, fingerprint, ts)
+	}
+	for _, fingerprint := range plan.pendingRecoverDelete {
+		t.ctx.Redis.PendingRecover().Delete(tenantId, ruleId, fingerprint)
+	}
+	for _, event := range plan.push {
+		t.ctx.Redis.Alert().PushAlertEvent(event)
+		action := models.EventChangeUpdate
+		if event.Status == models.StateRecovered {
+			action = models.EventChangeRecover
+		}
+		process.PublishEventChange(t.ctx, action, event)
+	}
+}
+
+// BenchmarkPlanRecover 基于集合在内存中计算状态转换, 写入按批次合并为 Pipeline, 统计写入阶段实际发往 Redis 的往返次数
+func BenchmarkPlanRecover(b *testing.B) {
+	curTime := int64(1700000000)
+	client, roundtrips := newCountingRedis()
+	t := &AlertRule{ctx: &ctx.Context{Ctx: context.Background(), Redis: cache.NewEntryCacheWithClient(client)}}
+	eventCacheKey := models.BuildAlertEventCacheKey("t-1", "")
+
+	var before, after int64
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		events, cur, pendingRecover := buildRecoverFixture(benchFingerprints, curTime)
+		b.StartTimer()
+
+		plan := planRecover(context.Background(), "a-1", events, cur, nil, pendingRecover, curTime, 60)
+
+		b.StopTimer()
+		atomic.StoreInt64(roundtrips, 0)
+		t.applyRecoverPlanPerFingerprint("t-1", "a-1", plan)
+		before = atomic.LoadInt64(roundtrips)
+		b.StartTimer()
+
+		atomic.StoreInt64(roundtrips, 0)
+		t.applyRecoverPlan(context.Background(), "t-1", "a-1", eventCacheKey, plan)
+		after = atomic.LoadInt64(roundtrips)
+	}
+
+	b.ReportMetric(float64(before), "roundtrips-before/op")
+	b.ReportMetric(float64(after), "roundtrips-after/op")
+}
+
+// BenchmarkPlanRecoverSliceLookup 原实现按切片查找指纹, 作为对照
+func BenchmarkPlanRecoverSliceLookup(b *testing.B) {
+	curTime := int64(1700000000)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		events, cur, _ := buildRecoverFixture(benchFingerprints, curTime)
+		b.StartTimer()
+
+		var active []string
+		for fingerprint, event := range events {
+			if event.Status == models.StatePreAlert && !slices.Contains(cur, fingerprint) {
+				continue
+			}
+			active = append(active, fingerprint)
+		}
+		_ = tools.GetSliceDifference(active, cur)
+	}
+}
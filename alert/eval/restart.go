@@ -0,0 +1,99 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	// 统计窗口内评估协程 panic 后允许自动重启的最大次数, 超过后停用规则并记录异常
+	evalPanicMaxRestarts = 5
+	// panic 次数的统计窗口
+	evalPanicWindow = 10 * time.Minute
+)
+
+var (
+	// 首次自动重启前的等待时间, 之后每次翻倍
+	evalPanicBaseBackoff = time.Second
+	// 自动重启前的最长等待时间
+	evalPanicMaxBackoff = time.Minute
+)
+
+// evalPanics 记录各规则在统计窗口内的 panic 次数
+type evalPanics struct {
+	sync.Mutex
+	m map[string]*evalPanicState
+}
+
+type evalPanicState struct {
+	count       int
+	windowStart time.Time
+}
+
+// record 记录一次 panic, 返回统计窗口内的 panic 次数, 超出窗口后重新计数
+func (p *evalPanics) record(ruleId string, now time.Time) int {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.m == nil {
+		p.m = make(map[string]*evalPanicState)
+	}
+	state, ok := p.m[ruleId]
+	if !ok || now.Sub(state.windowStart) > evalPanicWindow {
+		state = &evalPanicState{windowStart: now}
+		p.m[ruleId] = state
+	}
+	state.count++
+
+	return state.count
+}
+
+func (p *evalPanics) reset(ruleId string) {
+	p.Lock()
+	defer p.Unlock()
+
+	delete(p.m, ruleId)
+}
+
+// evalPanicBackoff 获取第 count 次 panic 后自动重启前的等待时间
+func evalPanicBackoff(count int) time.Duration {
+	backoff := evalPanicBaseBackoff
+	for i := 1; i < count && backoff < evalPanicMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, evalPanicMaxBackoff)
+}
+
+// handleEvalPanic 评估协程 panic 后退避一段时间再重启, 统计窗口内超过最大重启次数时停用规则并记录最后一次 panic 的信息,
+// 由用户排查后重新启用
+func (t *AlertRule) handleEvalPanic(ctx, logCtx context.Context, rule models.AlertRule, r interface{}) {
+	count := t.panics.record(rule.RuleId, time.Now())
+	if count > evalPanicMaxRestarts {
+		logc.Errorf(logCtx, "Rule eval panicked %d times within %s, stop eval and mark rule errored", count, evalPanicWindow)
+		if err := t.ctx.DB.Rule().MarkEvalError(rule.TenantId, rule.RuleId, fmt.Sprintf("%v", r), time.Now().Unix()); err != nil {
+			logc.Errorf(logCtx, "Failed to mark rule errored: %v", err)
+		}
+		t.Stop(rule.RuleId)
+		return
+	}
+
+	backoff := evalPanicBackoff(count)
+	logc.Infof(logCtx, "Restart rule eval after %s, restarts: %d/%d", backoff, count, evalPanicMaxRestarts)
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		// 等待期间规则被停止或更新, 不再重启
+		return
+	}
+
+	t.Restart(rule)
+}
@@ -0,0 +1,123 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/repo"
+)
+
+func TestEvalPanicsRecord(t *testing.T) {
+	var p evalPanics
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		if count := p.record("a-1", now.Add(time.Duration(i)*time.Second)); count != i {
+			t.Fatalf("count = %d, want %d", count, i)
+		}
+	}
+
+	// 超出统计窗口后重新计数
+	if count := p.record("a-1", now.Add(evalPanicWindow+time.Minute)); count != 1 {
+		t.Fatalf("count after window = %d, want 1", count)
+	}
+
+	p.reset("a-1")
+	if count := p.record("a-1", now); count != 1 {
+		t.Fatalf("count after reset = %d, want 1", count)
+	}
+}
+
+func TestEvalPanicBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		10: evalPanicMaxBackoff,
+	}
+	for count, want := range cases {
+		if got := evalPanicBackoff(count); got != want {
+			t.Fatalf("evalPanicBackoff(%d) = %s, want %s", count, got, want)
+		}
+	}
+}
+
+// panicRuleRepo 读取规则状态时 panic, 模拟评估任务中的 panic, 并记录规则被标记为异常的信息
+type panicRuleRepo struct {
+	repo.InterRuleRepo
+
+	mu        sync.Mutex
+	panics    []time.Time
+	evalError string
+	marked    chan struct{}
+}
+
+func (r *panicRuleRepo) GetRuleObject(ruleId string) models.AlertRule {
+	r.mu.Lock()
+	r.panics = append(r.panics, time.Now())
+	r.mu.Unlock()
+
+	panic("boom")
+}
+
+func (r *panicRuleRepo) MarkEvalError(tenantId, ruleId, message string, at int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evalError = message
+	close(r.marked)
+	return nil
+}
+
+type panicEntryRepo struct {
+	repo.InterEntryRepo
+	rules *panicRuleRepo
+}
+
+func (e panicEntryRepo) Rule() repo.InterRuleRepo { return e.rules }
+
+func TestEvalTaskPanicRestartsAndMarksRuleErrored(t *testing.T) {
+	unit, base, maxBackoff := evalIntervalUnit, evalPanicBaseBackoff, evalPanicMaxBackoff
+	evalIntervalUnit, evalPanicBaseBackoff, evalPanicMaxBackoff = 2*time.Millisecond, 10*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() {
+		evalIntervalUnit, evalPanicBaseBackoff, evalPanicMaxBackoff = unit, base, maxBackoff
+	})
+
+	rules := &panicRuleRepo{marked: make(chan struct{})}
+	c := ctx.NewContext(context.Background(), panicEntryRepo{rules: rules}, nil)
+	eval := NewAlertRuleEval(c, nil).(*AlertRule)
+
+	rule := models.AlertRule{TenantId: "t-1", RuleId: "a-1", RuleName: "panic", EvalInterval: 5}
+	eval.Submit(rule)
+
+	select {
+	case <-rules.marked:
+	case <-time.After(10 * time.Second):
+		t.Fatal("rule was not marked errored after repeated eval task panics")
+	}
+	if err := eval.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rules.mu.Lock()
+	defer rules.mu.Unlock()
+
+	if rules.evalError != "boom" {
+		t.Fatalf("eval error = %q, want boom", rules.evalError)
+	}
+	// 每次 panic 后退避重启, 超过最大重启次数后停用规则, 不再重启
+	if len(rules.panics) != evalPanicMaxRestarts+1 {
+		t.Fatalf("eval task panicked %d times, want %d", len(rules.panics), evalPanicMaxRestarts+1)
+	}
+	for i := 1; i < len(rules.panics); i++ {
+		if gap, backoff := rules.panics[i].Sub(rules.panics[i-1]), evalPanicBackoff(i); gap < backoff {
+			t.Fatalf("restart %d after %s, want backoff of at least %s", i, gap, backoff)
+		}
+	}
+	if _, running := c.ContextMap[rule.RuleId]; running {
+		t.Fatal("rule eval still running after reaching the restart limit")
+	}
+}
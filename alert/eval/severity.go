@@ -0,0 +1,58 @@
+package eval
+
+import (
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// severityEmitter 按规则的等级表达式计算事件等级后转发, 计算失败时保留默认等级
+type severityEmitter struct {
+	ctx  *ctx.Context
+	rule models.AlertRule
+	next emitter
+}
+
+// withSeverityExpr 规则配置了等级表达式时包装 emitter
+func withSeverityExpr(ctx *ctx.Context, rule models.AlertRule, next emitter) emitter {
+	if rule.SeverityExpr == "" {
+		return next
+	}
+
+	return severityEmitter{ctx: ctx, rule: rule, next: next}
+}
+
+func (s severityEmitter) Push(event *models.AlertCurEvent) {
+	s.apply(event)
+	s.next.Push(event)
+}
+
+func (s severityEmitter) Skip(event *models.AlertCurEvent) {
+	s.apply(event)
+	s.next.Skip(event)
+}
+
+func (s severityEmitter) Refresh(event *models.AlertCurEvent) {
+	s.apply(event)
+	s.next.Refresh(event)
+}
+
+func (s severityEmitter) unwrap() emitter { return s.next }
+
+func (s severityEmitter) apply(event *models.AlertCurEvent) {
+	if event == nil {
+		return
+	}
+
+	severity, err := process.EvalSeverityExpr(s.rule.SeverityExpr, event)
+	if err != nil {
+		logc.Errorf(eventLogContext(s.ctx.Ctx, event), "等级表达式计算失败, 使用默认等级 %s, 错误: %v", event.Severity, err)
+		return
+	}
+
+	event.Severity = severity
+	event.Labels["severity"] = severity
+	event.ForDuration = s.rule.GetForDuration(severity)
+}
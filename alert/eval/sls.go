@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"fmt"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 多个 SLS 查询目标时标记事件来源的标签, 参与指纹计算
+const (
+	slsProjectLabel  = "sls_project"
+	slsLogstoreLabel = "sls_logstore"
+)
+
+// querySlsTargets 查询所有 SLS 查询目标并为日志标记来源, 返回合并后的日志及查询失败的目标
+func querySlsTargets(ctx *ctx.Context, datasourceId, datasourceType string, cli provider.AliCloudSlsDsProvider, options provider.LogQueryOptions) (provider.Logs, []models.AliCloudSLSTarget) {
+	var (
+		log    = provider.Logs{ProviderName: provider.AliCloudSLSDsProviderName}
+		failed []models.AliCloudSLSTarget
+	)
+	for _, result := range cli.QueryTargets(ctx.Ctx, options) {
+		if result.Err != nil {
+			logc.Errorf(ctx.Ctx, "AliCloudSLS查询失败, project: %s, logstore: %s, 错误: %v", result.Target.Project, result.Target.Logstore, result.Err)
+			ctx.Metrics.IncQueryFailure(datasourceId, datasourceType)
+			failed = append(failed, result.Target)
+			continue
+		}
+
+		for _, message := range result.Logs.Message {
+			message[slsProjectLabel] = result.Target.Project
+			message[slsLogstoreLabel] = result.Target.Logstore
+			log.Message = append(log.Message, message)
+		}
+	}
+
+	return log, failed
+}
+
+// heldSlsFingerprints 获取查询失败的目标已产生的活跃事件指纹, 避免单个目标查询失败时其事件被误恢复
+func heldSlsFingerprints(ctx *ctx.Context, rule models.AlertRule, datasourceId string, failed []models.AliCloudSLSTarget) []string {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	fingerprints := ctx.Redis.Alert().GetFingerprintsByRuleId(rule.TenantId, rule.FaultCenterId, rule.RuleId)
+	if len(fingerprints) == 0 {
+		return nil
+	}
+	events, err := ctx.Redis.Alert().GetEventsFromCache(rule.TenantId, rule.FaultCenterId, fingerprints)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取规则的活跃事件失败, 错误: %v", err)
+		return nil
+	}
+
+	var held []string
+	for fingerprint, event := range events {
+		if event.DatasourceId != datasourceId {
+			continue
+		}
+		for _, target := range failed {
+			if fmt.Sprint(event.Labels[slsProjectLabel]) == target.Project && fmt.Sprint(event.Labels[slsLogstoreLabel]) == target.Logstore {
+				held = append(held, fingerprint)
+				break
+			}
+		}
+	}
+
+	return held
+}
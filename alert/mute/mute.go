@@ -2,6 +2,7 @@ package mute
 
 import (
 	"regexp"
+	"time"
 	"watchAlert/internal/ctx"
 	models "watchAlert/internal/models"
 
@@ -11,12 +12,42 @@ import (
 type MuteParams struct {
 	RecoverNotify *bool
 	IsRecovered   bool
+	IsSuppressed  bool
+	IsInhibited   bool
+	InMaintenance bool
 	TenantId      string
 	Labels        map[string]interface{}
 	FaultCenterId string
+	Fingerprint   string
+	IsFlapping    bool
 }
 
 func IsMuted(mute MuteParams) bool {
+	// 手动抑制的事件不发送告警通知
+	if mute.IsSuppressed && !mute.IsRecovered {
+		return true
+	}
+
+	// 暂停通知的事件在截止时间前不发送告警通知
+	if !mute.IsRecovered && IsSnoozed(mute) {
+		return true
+	}
+
+	// 抖动中的事件不发送通知, 包括其恢复通知
+	if mute.IsFlapping {
+		return true
+	}
+
+	// 被抑制规则抑制的事件不发送通知, 包括其恢复通知
+	if mute.IsInhibited {
+		return true
+	}
+
+	// 维护窗口内不发送通知
+	if mute.InMaintenance {
+		return true
+	}
+
 	if IsSilence(mute) {
 		return true
 	}
@@ -30,17 +61,31 @@ func IsMuted(mute MuteParams) bool {
 
 // RecoverNotify 判断是否推送恢复通知
 func RecoverNotify(mp MuteParams) bool {
-	return mp.IsRecovered && !*mp.RecoverNotify
+	return mp.IsRecovered && (mp.RecoverNotify == nil || !*mp.RecoverNotify)
+}
+
+// IsSnoozed 判断事件是否处于暂停通知期间
+func IsSnoozed(mute MuteParams) bool {
+	if mute.Fingerprint == "" {
+		return false
+	}
+	return ctx.Redis.Snooze().Get(mute.TenantId, mute.FaultCenterId, mute.Fingerprint) > time.Now().Unix()
 }
 
 // IsSilence 判断是否静默
 func IsSilence(mute MuteParams) bool {
+	_, ok := MatchSilence(mute)
+	return ok
+}
+
+// MatchSilence 获取匹配事件标签的生效中的静默规则 ID
+func MatchSilence(mute MuteParams) (string, bool) {
 	silenceCtx := ctx.Redis.Silence()
 	// 获取静默列表中所有的id
 	ids, err := silenceCtx.GetAlertMutes(mute.TenantId, mute.FaultCenterId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, err.Error())
-		return false
+		return "", false
 	}
 
 	// 根据ID获取到详细的静默规则
@@ -48,7 +93,7 @@ func IsSilence(mute MuteParams) bool {
 		muteRule, err := silenceCtx.WithIdGetMuteFromCache(mute.TenantId, mute.FaultCenterId, id)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return false
+			return "", false
 		}
 
 		if muteRule.Status != 1 {
@@ -56,11 +101,16 @@ func IsSilence(mute MuteParams) bool {
 		}
 
 		if evalCondition(mute.Labels, muteRule.Labels) {
-			return true
+			return muteRule.ID, true
 		}
 	}
 
-	return false
+	return "", false
+}
+
+// MatchLabels 判断标签是否满足所有匹配条件
+func MatchLabels(labels map[string]interface{}, matchers []models.SilenceLabel) bool {
+	return evalCondition(labels, matchers)
 }
 
 func evalCondition(metrics map[string]interface{}, muteLabels []models.SilenceLabel) bool {
@@ -81,10 +131,13 @@ func evalCondition(metrics map[string]interface{}, muteLabels []models.SilenceLa
 			matched = (val == muteLabel.Value)
 		case "!=":
 			matched = (val != muteLabel.Value)
-		case "=~":
-			matched = regexp.MustCompile(muteLabel.Value).MatchString(val)
-		case "!~":
-			matched = !regexp.MustCompile(muteLabel.Value).MatchString(val)
+		case "=~", "!~":
+			re, err := regexp.Compile(muteLabel.Value)
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "静默规则正则表达式无效, label: %s, value: %s, err: %v", muteLabel.Key, muteLabel.Value, err)
+				return false
+			}
+			matched = re.MatchString(val) == (muteLabel.Operator == "=~")
 		default:
 			matched = false
 		}
@@ -9,6 +9,7 @@ import (
 	"watchAlert/internal/models"
 	"watchAlert/pkg/tools"
 
+	"github.com/go-redis/redis"
 	"github.com/zeromicro/go-zero/core/logc"
 )
 
@@ -24,6 +25,7 @@ func BuildEvent(rule models.AlertRule, labels func() map[string]interface{}) mod
 		IsRecovered:          false,
 		RepeatNoticeInterval: rule.RepeatNoticeInterval,
 		Severity:             rule.Severity,
+		ForDuration:          rule.GetForDuration(rule.Severity),
 		EffectiveTime:        rule.EffectiveTime,
 		FaultCenterId:        rule.FaultCenterId,
 	}
@@ -34,7 +36,8 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 		return
 	}
 
-	if NotInTheEffectiveTime(event.EffectiveTime) {
+	now := time.Now().In(ctx.TenantLocation(event.TenantId))
+	if NotInTheEffectiveTime(event.EffectiveTime, now) {
 		return
 	}
 
@@ -52,8 +55,23 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 	event.LastEvalTime = cacheEvent.GetLastEvalTime()
 	event.LastSendTime = cacheEvent.GetLastSendTime()
 	event.ConfirmState = cacheEvent.GetLastConfirmState()
+	event.IsSuppressed = cacheEvent.IsSuppressed
+	event.EscalationState = cacheEvent.EscalationState
+	event.ExtraAnnotations = cacheEvent.ExtraAnnotations
+	event.IncidentKey = cacheEvent.IncidentKey
+	// 查询链接在事件产生时生成, 之后保持不变
+	if cacheEvent.QueryLink != "" {
+		event.QueryLink = cacheEvent.QueryLink
+	}
+	event.IsInhibited = cacheEvent.IsInhibited
 	event.EventId = cacheEvent.GetEventId()
+	// 本次推送满足告警条件时累加次数并刷新最近触发时间, 否则沿用缓存中的值
+	event.Count += cacheEvent.Count
+	if event.LastTriggerTime == 0 {
+		event.LastTriggerTime = cacheEvent.LastTriggerTime
+	}
 	event.FaultCenter = cache.FaultCenter().GetFaultCenterInfo(models.BuildFaultCenterInfoCacheKey(event.TenantId, event.FaultCenterId))
+	event.Maintenance = event.FaultCenter.InMaintenance(now)
 
 	// 获取当前缓存中的状态
 	currentStatus := cacheEvent.GetEventStatus()
@@ -68,9 +86,23 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 	// 根据不同情况处理状态转换
 	switch event.Status {
 	case models.StatePreAlert:
-		if event.IsArriveForDuration() {
+		// 获取首次满足条件的时间, 存储在 Redis 中以便进程重启后继续计时
+		pendingAt, err := cache.Pending().Get(event.TenantId, event.RuleId, event.Fingerprint)
+		switch {
+		case err == redis.Nil:
+			// 首次进入预告警, 以本次评估时间作为起始时间
+			pendingAt = event.LastEvalTime
+			cache.Pending().Set(event.TenantId, event.RuleId, event.Fingerprint, pendingAt)
+		case err != nil:
+			// Redis 异常时不能重置计时, 否则持续时间会被反复刷新
+			logc.Errorf(ctx.Ctx, "获取 Pending 起始时间失败, tenant=%s, rule=%s(%s), fingerprint=%s, err: %v", event.TenantId, event.RuleName, event.RuleId, event.Fingerprint, err)
+			return
+		}
+
+		if event.IsArriveForDuration(pendingAt) {
 			// 如果达到持续时间，转为告警状态
 			event.TransitionStatus(models.StateAlerting)
+			cache.Pending().Delete(event.TenantId, event.RuleId, event.Fingerprint)
 		}
 	}
 
@@ -82,17 +114,32 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 
 	// 更新缓存
 	cache.Alert().PushAlertEvent(event)
+
+	// 新产生或状态变化的事件推送给事件流的订阅者
+	switch {
+	case currentStatus == "":
+		PublishEventChange(ctx, models.EventChangeCreate, event)
+	case event.Status != currentStatus:
+		PublishEventChange(ctx, models.EventChangeUpdate, event)
+	}
+}
+
+// PublishEventChange 发布活跃告警事件的变更, 发布失败不影响告警评估
+func PublishEventChange(ctx *ctx.Context, action string, event *models.AlertCurEvent) {
+	err := ctx.Redis.EventStream().Publish(models.EventChange{Action: action, Event: *event})
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "发布事件变更失败, fingerprint: %s, err: %s", event.Fingerprint, err.Error())
+	}
 }
 
-// NotInTheEffectiveTime 判断是否不在生效时间内
-func NotInTheEffectiveTime(et models.EffectiveTime) bool {
+// NotInTheEffectiveTime 判断 currentTime 是否不在生效时间内
+func NotInTheEffectiveTime(et models.EffectiveTime, currentTime time.Time) bool {
 	// 如果没有配置有效星期，则认为始终有效
 	if len(et.Week) == 0 {
 		return false
 	}
 
-	// 当前日期
-	currentTime := time.Now()
+	// 当前日期, 按 currentTime 所在时区计算星期及时间段
 	currentWeekday := tools.TimeTransformToWeek(currentTime)
 
 	// 检查当前星期是否在有效范围内
@@ -122,6 +169,25 @@ func NotInTheEffectiveTime(et models.EffectiveTime) bool {
 	return !isInValidTimeRange
 }
 
+// GetTenantEventCount 统计租户下所有故障中心的活跃事件数量
+func GetTenantEventCount(ctx *ctx.Context, tenantId string) (int64, error) {
+	list, err := ctx.DB.FaultCenter().List(tenantId, "")
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, fc := range list {
+		count, err := ctx.Redis.Alert().CountEvents(tenantId, fc.ID)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
 // RecordAlertHisEvent 记录历史告警
 func RecordAlertHisEvent(ctx *ctx.Context, alert models.AlertCurEvent) error {
 	hisData := models.AlertHisEvent{
@@ -145,6 +211,8 @@ func RecordAlertHisEvent(ctx *ctx.Context, alert models.AlertCurEvent) error {
 		ConfirmState:     alert.ConfirmState,
 		AlarmDuration:    alert.RecoverTime - alert.FirstTriggerTime,
 		SearchQL:         alert.SearchQL,
+		ExtraAnnotations: alert.ExtraAnnotations,
+		IncidentKey:      alert.IncidentKey,
 	}
 
 	err := ctx.DB.Event().CreateHistoryEvent(hisData)
@@ -0,0 +1,60 @@
+package process
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"watchAlert/internal/models"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// severityExprEnv 等级表达式可使用的变量, 如 labels.env == "prod" ? "P0" : "P2"
+type severityExprEnv struct {
+	Labels   map[string]interface{} `expr:"labels"`
+	Value    interface{}            `expr:"value"`
+	Severity string                 `expr:"severity"`
+}
+
+// 已编译的等级表达式, 避免每次评估重复编译
+var severityPrograms sync.Map
+
+// CompileSeverityExpr 编译等级表达式, 表达式须返回字符串
+func CompileSeverityExpr(severityExpr string) (*vm.Program, error) {
+	if program, ok := severityPrograms.Load(severityExpr); ok {
+		return program.(*vm.Program), nil
+	}
+
+	program, err := expr.Compile(severityExpr, expr.Env(severityExprEnv{}), expr.AsKind(reflect.String))
+	if err != nil {
+		return nil, fmt.Errorf("等级表达式无效: %s", err.Error())
+	}
+	severityPrograms.Store(severityExpr, program)
+
+	return program, nil
+}
+
+// EvalSeverityExpr 根据事件的标签及当前值计算告警等级, event.Severity 作为表达式中的默认等级
+func EvalSeverityExpr(severityExpr string, event *models.AlertCurEvent) (string, error) {
+	program, err := CompileSeverityExpr(severityExpr)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := expr.Run(program, severityExprEnv{
+		Labels:   event.Labels,
+		Value:    event.Labels["value"],
+		Severity: event.Severity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	severity, ok := out.(string)
+	if !ok || severity == "" {
+		return "", fmt.Errorf("等级表达式的返回值无效: %v", out)
+	}
+
+	return severity, nil
+}
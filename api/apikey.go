@@ -4,7 +4,6 @@ import (
 	middleware "watchAlert/internal/middleware"
 	"watchAlert/internal/services"
 	"watchAlert/internal/types"
-	"watchAlert/pkg/tools"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,17 +21,20 @@ func (apiKeyController apiKeyController) API(gin *gin.RouterGroup) {
 	a := gin.Group("apikey")
 	a.Use(
 		middleware.Auth(),
+		middleware.RejectScopedApiKey(),
 		middleware.ParseTenant(),
 	)
 	{
 		a.POST("create", apiKeyController.Create)
 		a.POST("update", apiKeyController.Update)
 		a.POST("delete", apiKeyController.Delete)
+		a.POST("revoke", apiKeyController.Revoke)
 	}
 
 	b := gin.Group("apikey")
 	b.Use(
 		middleware.Auth(),
+		middleware.RejectScopedApiKey(),
 		middleware.ParseTenant(),
 	)
 	{
@@ -44,13 +46,11 @@ func (apiKeyController apiKeyController) API(gin *gin.RouterGroup) {
 func (apiKeyController apiKeyController) Create(ctx *gin.Context) {
 	r := new(types.RequestApiKeyCreate)
 
-	// 从JWT中获取当前用户ID
-	token := ctx.Request.Header.Get("Authorization")
-	userId := tools.GetUserID(token)
-	r.UserId = userId
-
 	BindJson(ctx, r)
 
+	// 绑定请求后再从认证信息中获取当前用户ID
+	r.UserId = ctx.GetString("UserId")
+
 	Service(ctx, func() (interface{}, interface{}) {
 		return services.ApiKeyService.Create(r)
 	})
@@ -59,13 +59,11 @@ func (apiKeyController apiKeyController) Create(ctx *gin.Context) {
 func (apiKeyController apiKeyController) List(ctx *gin.Context) {
 	r := new(types.RequestApiKeyQuery)
 
-	// 从JWT中获取当前用户ID，确保用户只能看到自己的API密钥
-	token := ctx.Request.Header.Get("Authorization")
-	userId := tools.GetUserID(token)
-	r.UserId = userId
-
 	BindQuery(ctx, r)
 
+	// 绑定请求后再从认证信息中获取当前用户ID，确保用户只能看到自己的API密钥
+	r.UserId = ctx.GetString("UserId")
+
 	Service(ctx, func() (interface{}, interface{}) {
 		return services.ApiKeyService.List(r)
 	})
@@ -74,13 +72,11 @@ func (apiKeyController apiKeyController) List(ctx *gin.Context) {
 func (apiKeyController apiKeyController) Get(ctx *gin.Context) {
 	r := new(types.RequestApiKeyQuery)
 
-	// 从JWT中获取当前用户ID，确保用户只能获取自己的API密钥
-	token := ctx.Request.Header.Get("Authorization")
-	userId := tools.GetUserID(token)
-	r.UserId = userId
-
 	BindQuery(ctx, r)
 
+	// 绑定请求后再从认证信息中获取当前用户ID，确保用户只能获取自己的API密钥
+	r.UserId = ctx.GetString("UserId")
+
 	Service(ctx, func() (interface{}, interface{}) {
 		return services.ApiKeyService.Get(r)
 	})
@@ -89,13 +85,11 @@ func (apiKeyController apiKeyController) Get(ctx *gin.Context) {
 func (apiKeyController apiKeyController) Update(ctx *gin.Context) {
 	r := new(types.RequestApiKeyUpdate)
 
-	// 从JWT中获取当前用户ID，确保用户只能更新自己的API密钥
-	token := ctx.Request.Header.Get("Authorization")
-	userId := tools.GetUserID(token)
-	r.UserId = userId
-
 	BindJson(ctx, r)
 
+	// 绑定请求后再从认证信息中获取当前用户ID，确保用户只能更新自己的API密钥
+	r.UserId = ctx.GetString("UserId")
+
 	Service(ctx, func() (interface{}, interface{}) {
 		return services.ApiKeyService.Update(r)
 	})
@@ -104,14 +98,25 @@ func (apiKeyController apiKeyController) Update(ctx *gin.Context) {
 func (apiKeyController apiKeyController) Delete(ctx *gin.Context) {
 	r := new(types.RequestApiKeyQuery)
 
-	// 从JWT中获取当前用户ID，确保用户只能删除自己的API密钥
-	token := ctx.Request.Header.Get("Authorization")
-	userId := tools.GetUserID(token)
-	r.UserId = userId
-
 	BindJson(ctx, r)
 
+	// 绑定请求后再从认证信息中获取当前用户ID，确保用户只能删除自己的API密钥
+	r.UserId = ctx.GetString("UserId")
+
 	Service(ctx, func() (interface{}, interface{}) {
 		return services.ApiKeyService.Delete(r)
 	})
 }
+
+func (apiKeyController apiKeyController) Revoke(ctx *gin.Context) {
+	r := new(types.RequestApiKeyQuery)
+
+	BindJson(ctx, r)
+
+	// 绑定请求后再从认证信息中获取当前用户ID，确保用户只能吊销自己的API密钥
+	r.UserId = ctx.GetString("UserId")
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ApiKeyService.Revoke(r)
+	})
+}
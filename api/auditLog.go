@@ -22,6 +22,16 @@ func (auditLogController auditLogController) API(gin *gin.RouterGroup) {
 		a.GET("listAuditLog", auditLogController.List)
 		a.GET("searchAuditLog", auditLogController.Search)
 	}
+
+	b := gin.Group("audit")
+	b.Use(
+		middleware.Cors(),
+		middleware.Auth(),
+		middleware.ParseTenant(),
+	)
+	{
+		b.GET("search", auditLogController.Filter)
+	}
 }
 
 func (auditLogController auditLogController) List(ctx *gin.Context) {
@@ -45,3 +55,15 @@ func (auditLogController auditLogController) Search(ctx *gin.Context) {
 		return services.AuditLogService.Search(r)
 	})
 }
+
+func (auditLogController auditLogController) Filter(ctx *gin.Context) {
+	r := new(types.RequestAuditLogFilter)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.AuditLogService.Filter(r)
+	})
+}
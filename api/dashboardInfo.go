@@ -1,6 +1,9 @@
 package api
 
 import (
+	"strconv"
+	"strings"
+	"watchAlert/alert/process"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/middleware"
 	"watchAlert/internal/models"
@@ -38,16 +41,45 @@ func (dashboardInfoController dashboardInfoController) GetDashboardInfo(context
 		return
 	}
 
+	startAt, _ := strconv.ParseInt(context.Query("startAt"), 10, 64)
+	endAt, _ := strconv.ParseInt(context.Query("endAt"), 10, 64)
+	if startAt > 0 && endAt > 0 {
+		if startAt >= endAt {
+			response.Fail(context, "开始时间必须早于结束时间", "failed")
+			return
+		}
+
+		// 指定时间范围时, 告警分布统计范围内触发的事件
+		events := getRangeEvents(c, faultCenter, startAt, endAt)
+		distribution := make(map[string]types.AlarmDistribution)
+		if list, err := c.DB.FaultCenter().List(tidString, ""); err == nil {
+			for _, fc := range list {
+				distribution[fc.ID] = countAlarmDistribution(getRangeEvents(c, fc, startAt, endAt))
+			}
+		}
+
+		response.Success(context, types.ResponseDashboardInfo{
+			CountAlertRules:         getRuleNumber(c, tidString),
+			FaultCenterNumber:       getFaultCenterNumber(c, tidString),
+			UserNumber:              getUserNumber(c),
+			CurAlertList:            getAlertList(c, faultCenter),
+			AlarmDistribution:       countAlarmDistribution(events),
+			FaultCenterDistribution: distribution,
+			AlertCount:              int64(len(events)),
+			AlertTrend:              getAlertTrend(events, startAt, endAt),
+			QuotaUsage:              getQuotaUsage(c, tidString),
+		}, "success")
+		return
+	}
+
 	response.Success(context, types.ResponseDashboardInfo{
-		CountAlertRules:   getRuleNumber(c, tidString),
-		FaultCenterNumber: getFaultCenterNumber(c, tidString),
-		UserNumber:        getUserNumber(c),
-		CurAlertList:      getAlertList(c, faultCenter),
-		AlarmDistribution: types.AlarmDistribution{
-			P0: getAlarmDistribution(c, faultCenter, "P0"),
-			P1: getAlarmDistribution(c, faultCenter, "P1"),
-			P2: getAlarmDistribution(c, faultCenter, "P2"),
-		},
+		CountAlertRules:         getRuleNumber(c, tidString),
+		FaultCenterNumber:       getFaultCenterNumber(c, tidString),
+		UserNumber:              getUserNumber(c),
+		CurAlertList:            getAlertList(c, faultCenter),
+		AlarmDistribution:       getAlarmDistribution(c, faultCenter),
+		FaultCenterDistribution: getFaultCenterDistribution(c, tidString),
+		QuotaUsage:              getQuotaUsage(c, tidString),
 	}, "success")
 }
 
@@ -62,6 +94,33 @@ func getRuleNumber(ctx *ctx.Context, tenantId string) int64 {
 	return int64(len(list))
 }
 
+// getQuotaUsage 获取租户资源的已用数量及配额
+func getQuotaUsage(ctx *ctx.Context, tenantId string) types.QuotaUsage {
+	tenant, err := ctx.DB.Tenant().Get(tenantId)
+	if err != nil {
+		logc.Error(ctx.Ctx, err.Error())
+		return types.QuotaUsage{}
+	}
+
+	usage := types.QuotaUsage{
+		Rules:   types.QuotaItem{Used: getRuleNumber(ctx, tenantId), Quota: tenant.RuleNumber},
+		Notices: types.QuotaItem{Quota: tenant.NoticeNumber},
+		Duties:  types.QuotaItem{Quota: tenant.DutyNumber},
+		Events:  types.QuotaItem{Quota: tenant.EventNumber},
+	}
+	if notices, err := ctx.DB.Notice().List(tenantId, "", ""); err == nil {
+		usage.Notices.Used = int64(len(notices))
+	}
+	if duties, err := ctx.DB.Duty().List(tenantId); err == nil {
+		usage.Duties.Used = int64(len(duties))
+	}
+	if events, err := process.GetTenantEventCount(ctx, tenantId); err == nil {
+		usage.Events.Used = events
+	}
+
+	return usage
+}
+
 // getFaultCenterNumber 获取故障中心总数
 func getFaultCenterNumber(ctx *ctx.Context, tenantId string) int64 {
 	list, err := ctx.DB.FaultCenter().List(tenantId, "")
@@ -104,17 +163,108 @@ func getAlertList(ctx *ctx.Context, faultCenter models.FaultCenter) []types.Aler
 }
 
 // getAlarmDistribution 获取告警分布
-func getAlarmDistribution(ctx *ctx.Context, faultCenter models.FaultCenter, severity string) int64 {
+func getAlarmDistribution(ctx *ctx.Context, faultCenter models.FaultCenter) types.AlarmDistribution {
+	var distribution types.AlarmDistribution
 	events, err := ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(faultCenter.TenantId, faultCenter.ID))
 	if err != nil {
-		return 0
+		return distribution
+	}
+
+	for _, event := range events {
+		distribution.Add(getEventSeverity(event))
+	}
+	return distribution
+}
+
+// getFaultCenterDistribution 获取租户下各故障中心的告警分布
+func getFaultCenterDistribution(ctx *ctx.Context, tenantId string) map[string]types.AlarmDistribution {
+	list, err := ctx.DB.FaultCenter().List(tenantId, "")
+	if err != nil {
+		logc.Error(ctx.Ctx, err.Error())
+		return nil
+	}
+
+	distribution := make(map[string]types.AlarmDistribution, len(list))
+	for _, faultCenter := range list {
+		distribution[faultCenter.ID] = getAlarmDistribution(ctx, faultCenter)
+	}
+	return distribution
+}
+
+// getEventSeverity 优先使用事件 severity 标签中的等级, 缺失时使用规则等级
+func getEventSeverity(event *models.AlertCurEvent) string {
+	if severity, ok := event.Labels["severity"].(string); ok && severity != "" {
+		return strings.ToUpper(severity)
+	}
+	return event.Severity
+}
+
+// rangeEvent 时间范围内触发的告警事件
+type rangeEvent struct {
+	severity    string
+	triggerTime int64
+}
+
+// getRangeEvents 获取时间范围内触发的告警, 包含已恢复的历史事件及仍活跃的事件
+func getRangeEvents(ctx *ctx.Context, faultCenter models.FaultCenter, startAt, endAt int64) []rangeEvent {
+	var events []rangeEvent
+	err := ctx.DB.Event().StreamHistoryEvent(types.RequestAlertHisEventQuery{
+		TenantId:      faultCenter.TenantId,
+		FaultCenterId: faultCenter.ID,
+		StartAt:       startAt,
+		EndAt:         endAt,
+	}, func(event models.AlertHisEvent) error {
+		severity := event.Severity
+		if s, ok := event.Labels["severity"].(string); ok && s != "" {
+			severity = strings.ToUpper(s)
+		}
+		events = append(events, rangeEvent{severity: severity, triggerTime: event.FirstTriggerTime})
+		return nil
+	})
+	if err != nil {
+		logc.Error(ctx.Ctx, err.Error())
+	}
+
+	curEvents, err := ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(faultCenter.TenantId, faultCenter.ID))
+	if err != nil {
+		return events
+	}
+	for _, event := range curEvents {
+		if event.FirstTriggerTime > startAt && event.FirstTriggerTime < endAt {
+			events = append(events, rangeEvent{severity: getEventSeverity(event), triggerTime: event.FirstTriggerTime})
+		}
 	}
 
-	var number int64
+	return events
+}
+
+// countAlarmDistribution 统计告警等级分布
+func countAlarmDistribution(events []rangeEvent) types.AlarmDistribution {
+	var distribution types.AlarmDistribution
 	for _, event := range events {
-		if event.Severity == severity {
-			number++
+		distribution.Add(event.severity)
+	}
+	return distribution
+}
+
+// getAlertTrend 按时间分桶统计告警触发数量, 范围不超过 2 天时按小时分桶, 否则按天分桶
+func getAlertTrend(events []rangeEvent, startAt, endAt int64) []types.AlertTrendPoint {
+	step := int64(86400)
+	if endAt-startAt <= 2*86400 {
+		step = 3600
+	}
+
+	var trend []types.AlertTrendPoint
+	for t := startAt; t < endAt; t += step {
+		trend = append(trend, types.AlertTrendPoint{Time: t})
+	}
+
+	for _, event := range events {
+		i := (event.triggerTime - startAt) / step
+		if i >= 0 && i < int64(len(trend)) {
+			trend[i].Count++
 		}
 	}
-	return number
+
+	return trend
 }
@@ -312,7 +312,7 @@ func (datasourceController datasourceController) SearchViewLogsContent(ctx *gin.
 			}
 		}
 
-		query, _, err := client.Query(options)
+		query, _, err := client.Query(ctx, options)
 		if err != nil {
 			return nil, err
 		}
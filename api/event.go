@@ -1,18 +1,28 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
 	"time"
 	"watchAlert/internal/middleware"
+	"watchAlert/internal/models"
 	"watchAlert/internal/services"
 	"watchAlert/internal/types"
 	"watchAlert/pkg/response"
 	utils "watchAlert/pkg/tools"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zeromicro/go-zero/core/logc"
 )
 
 type alertEventController struct{}
 
+// 事件流的心跳间隔
+const eventStreamHeartbeat = 15 * time.Second
+
 var AlertEventController = new(alertEventController)
 
 /*
@@ -27,11 +37,29 @@ func (alertEventController alertEventController) API(gin *gin.RouterGroup) {
 		middleware.ParseTenant(),
 	)
 	{
-		a.POST("process", alertEventController.ProcessAlertEvent)
-		a.POST("delete", alertEventController.DeleteAlertEvent)
-		a.POST("addComment", alertEventController.AddComment)
-		a.GET("listComments", alertEventController.ListComment)
-		a.POST("deleteComment", alertEventController.DeleteComment)
+		a.POST("process", middleware.RequirePermission(models.PermEventProcess), alertEventController.ProcessAlertEvent)
+		a.POST("bulkProcess", middleware.RequirePermission(models.PermEventProcess), alertEventController.BulkProcessAlertEvent)
+		a.POST("snooze", middleware.RequirePermission(models.PermEventProcess), alertEventController.SnoozeAlertEvent)
+		a.POST("cancelSnooze", middleware.RequirePermission(models.PermEventProcess), alertEventController.CancelSnoozeAlertEvent)
+		a.POST("delete", middleware.RequirePermission(models.PermEventDelete), alertEventController.DeleteAlertEvent)
+		a.POST("addComment", middleware.RequirePermission(models.PermCommentWrite), alertEventController.AddComment)
+		a.GET("listComments", middleware.RequirePermission(models.PermEventRead), alertEventController.ListComment)
+		a.GET("export", middleware.RequirePermission(models.PermEventRead), alertEventController.ExportAlertEvent)
+		a.GET("stream", middleware.RequirePermission(models.PermEventRead), alertEventController.StreamCurrentEvent)
+		a.POST("deleteComment", middleware.RequirePermission(models.PermCommentDelete), alertEventController.DeleteComment)
+	}
+
+	c := gin.Group("event")
+	c.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+		middleware.AuditingLog(),
+	)
+	{
+		c.POST("updateEventAnnotations", middleware.RequirePermission(models.PermEventProcess), alertEventController.UpdateEventAnnotations)
+		c.POST("editComment", middleware.RequirePermission(models.PermCommentWrite), alertEventController.EditComment)
+		c.POST("bulkDelete", middleware.RequirePermission(models.PermEventDelete), alertEventController.BulkDeleteAlertEvent)
 	}
 
 	b := gin.Group("event")
@@ -40,8 +68,17 @@ func (alertEventController alertEventController) API(gin *gin.RouterGroup) {
 		middleware.ParseTenant(),
 	)
 	{
-		b.GET("curEvent", alertEventController.ListCurrentEvent)
-		b.GET("hisEvent", alertEventController.ListHistoryEvent)
+		b.GET("curEvent", middleware.RequirePermission(models.PermEventRead), alertEventController.ListCurrentEvent)
+		b.GET("hisEvent", middleware.RequirePermission(models.PermEventRead), alertEventController.ListHistoryEvent)
+	}
+
+	// 外部告警接入, 租户及故障中心由路径指定, 支持 API Key 认证
+	d := gin.Group("event")
+	d.Use(
+		middleware.Auth(),
+	)
+	{
+		d.POST("ingest/alertmanager/:tenantId/:faultCenterId", alertEventController.IngestAlertmanager)
 	}
 }
 
@@ -66,6 +103,96 @@ func (alertEventController alertEventController) ProcessAlertEvent(ctx *gin.Cont
 	})
 }
 
+func (alertEventController alertEventController) BulkProcessAlertEvent(ctx *gin.Context) {
+	r := new(types.RequestBulkProcessAlertEvent)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+	r.Time = time.Now().Unix()
+
+	tokenStr := ctx.Request.Header.Get("Authorization")
+	if tokenStr == "" {
+		response.Fail(ctx, "未知的用户", "failed")
+		return
+	}
+
+	r.Username = utils.GetUser(tokenStr)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.BulkProcessAlertEvent(r)
+	})
+}
+
+func (alertEventController alertEventController) BulkDeleteAlertEvent(ctx *gin.Context) {
+	r := new(types.RequestBulkDeleteAlertEvent)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+	r.Time = time.Now().Unix()
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.BulkDeleteAlertEvent(r)
+	})
+}
+
+func (alertEventController alertEventController) SnoozeAlertEvent(ctx *gin.Context) {
+	r := new(types.RequestSnoozeAlertEvent)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+	r.Time = time.Now().Unix()
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.SnoozeAlertEvent(r)
+	})
+}
+
+func (alertEventController alertEventController) CancelSnoozeAlertEvent(ctx *gin.Context) {
+	r := new(types.RequestSnoozeAlertEvent)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.CancelSnoozeAlertEvent(r)
+	})
+}
+
+func (alertEventController alertEventController) UpdateEventAnnotations(ctx *gin.Context) {
+	r := new(types.RequestUpdateEventAnnotations)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.UpdateEventAnnotations(r)
+	})
+}
+
+func (alertEventController alertEventController) IngestAlertmanager(ctx *gin.Context) {
+	r := new(types.RequestAlertmanagerWebhook)
+	BindJson(ctx, r)
+
+	r.TenantId = ctx.Param("tenantId")
+	r.FaultCenterId = ctx.Param("faultCenterId")
+	r.UserId = ctx.GetString("UserId")
+
+	// 限定租户的API密钥只能推送到该租户
+	if key, ok := middleware.GetApiKey(ctx); ok && key.TenantId != "" && key.TenantId != r.TenantId {
+		response.PermissionFail(ctx)
+		return
+	}
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.IngestAlertmanager(r)
+	})
+}
+
 func (alertEventController alertEventController) DeleteAlertEvent(ctx *gin.Context) {
 	r := new(types.RequestProcessAlertEvent)
 	BindJson(ctx, r)
@@ -103,6 +230,110 @@ func (alertEventController alertEventController) ListHistoryEvent(ctx *gin.Conte
 	})
 }
 
+// StreamCurrentEvent 以 Server-Sent Events 推送活跃告警事件, 连接建立后先推送 snapshot, 之后推送 change
+func (alertEventController alertEventController) StreamCurrentEvent(ctx *gin.Context) {
+	r := new(types.RequestAlertEventStream)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	if r.FaultCenterId == "" {
+		response.Fail(ctx, "故障中心 ID 不能为空", "failed")
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	// 定时发送心跳, 避免空闲连接被代理断开
+	var mu sync.Mutex
+	go func() {
+		ticker := time.NewTicker(eventStreamHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mu.Lock()
+				_, err := ctx.Writer.WriteString(": ping\n\n")
+				if err == nil {
+					ctx.Writer.Flush()
+				}
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			case <-streamCtx.Done():
+				return
+			}
+		}
+	}()
+
+	send := func(name string, data interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ctx.SSEvent(name, data)
+		ctx.Writer.Flush()
+		return ctx.Request.Context().Err()
+	}
+
+	// 响应已开始写出, 推送中途失败时只能记录日志
+	if err := services.EventService.StreamCurrentEvent(streamCtx, r, send); err != nil && !errors.Is(err, context.Canceled) {
+		logc.Errorf(ctx.Request.Context(), "推送事件流失败, err: %s", err.Error())
+	}
+}
+
+func (alertEventController alertEventController) ExportAlertEvent(ctx *gin.Context) {
+	r := new(types.RequestAlertEventExport)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	tenantId := tid.(string)
+
+	contentType, ext := "text/csv; charset=utf-8", "csv"
+	if r.Format == types.EventExportFormatJSONL {
+		contentType, ext = "application/x-ndjson; charset=utf-8", "jsonl"
+	}
+
+	var export func() error
+	switch r.Type {
+	case types.EventExportTypeCurrent:
+		q := new(types.RequestAlertCurEventQuery)
+		BindQuery(ctx, q)
+		q.TenantId = tenantId
+		export = func() error { return services.EventService.ExportCurrentEvent(q, r.Format, ctx.Writer) }
+	case types.EventExportTypeHistory:
+		q := new(types.RequestAlertHisEventQuery)
+		BindQuery(ctx, q)
+		q.TenantId = tenantId
+		export = func() error { return services.EventService.ExportHistoryEvent(q, r.Format, ctx.Writer) }
+	default:
+		response.Fail(ctx, "不支持的导出类型: "+r.Type, "failed")
+		return
+	}
+
+	if r.Format != "" && r.Format != types.EventExportFormatCSV && r.Format != types.EventExportFormatJSONL {
+		response.Fail(ctx, "不支持的导出格式: "+r.Format, "failed")
+		return
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=events-%s-%s.%s", r.Type, time.Now().Format("20060102150405"), ext))
+	ctx.Status(http.StatusOK)
+
+	// 响应已开始写出, 导出中途失败时只能记录日志
+	if err := export(); err != nil {
+		logc.Errorf(ctx.Request.Context(), "导出告警事件失败, err: %s", err.Error())
+	}
+}
+
 func (alertEventController alertEventController) ListComment(ctx *gin.Context) {
 	r := new(types.RequestListEventComments)
 	BindQuery(ctx, r)
@@ -142,3 +373,18 @@ func (alertEventController alertEventController) DeleteComment(ctx *gin.Context)
 		return services.EventService.DeleteComment(r)
 	})
 }
+
+func (alertEventController alertEventController) EditComment(ctx *gin.Context) {
+	r := new(types.RequestEditEventComment)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	token := ctx.Request.Header.Get("Authorization")
+	r.UserId = utils.GetUserID(token)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.EditComment(r)
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"watchAlert/internal/middleware"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/response"
+	jwtUtils "watchAlert/pkg/tools"
+
+	"github.com/gin-gonic/gin"
+)
+
+type heartbeatController struct{}
+
+var HeartbeatController = new(heartbeatController)
+
+/*
+心跳监控 API
+/api/w8t/heartbeat
+*/
+func (heartbeatController heartbeatController) API(gin *gin.RouterGroup) {
+	a := gin.Group("heartbeat")
+	a.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+		middleware.AuditingLog(),
+	)
+	{
+		a.POST("heartbeatCreate", heartbeatController.Create)
+		a.POST("heartbeatUpdate", heartbeatController.Update)
+		a.POST("heartbeatDelete", heartbeatController.Delete)
+	}
+
+	b := gin.Group("heartbeat")
+	b.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+	)
+	{
+		b.GET("heartbeatList", heartbeatController.List)
+	}
+
+	// 外部系统上报心跳, 租户及心跳名称由路径指定, 支持 API Key 认证
+	c := gin.Group("heartbeat")
+	c.Use(
+		middleware.Auth(),
+	)
+	{
+		c.GET("ping/:tenantId/:name", heartbeatController.Ping)
+		c.POST("ping/:tenantId/:name", heartbeatController.Ping)
+	}
+}
+
+func (heartbeatController heartbeatController) Create(ctx *gin.Context) {
+	r := new(types.RequestHeartbeatCreate)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+	r.UpdateBy = jwtUtils.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.HeartbeatService.Create(r)
+	})
+}
+
+func (heartbeatController heartbeatController) Update(ctx *gin.Context) {
+	r := new(types.RequestHeartbeatUpdate)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+	r.UpdateBy = jwtUtils.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.HeartbeatService.Update(r)
+	})
+}
+
+func (heartbeatController heartbeatController) Delete(ctx *gin.Context) {
+	r := new(types.RequestHeartbeatQuery)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.HeartbeatService.Delete(r)
+	})
+}
+
+func (heartbeatController heartbeatController) List(ctx *gin.Context) {
+	r := new(types.RequestHeartbeatQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.HeartbeatService.List(r)
+	})
+}
+
+func (heartbeatController heartbeatController) Ping(ctx *gin.Context) {
+	r := &types.RequestHeartbeatPing{
+		TenantId: ctx.Param("tenantId"),
+		Name:     ctx.Param("name"),
+		UserId:   ctx.GetString("UserId"),
+	}
+
+	// 限定租户的API密钥只能上报该租户的心跳
+	if key, ok := middleware.GetApiKey(ctx); ok && key.TenantId != "" && key.TenantId != r.TenantId {
+		response.PermissionFail(ctx)
+		return
+	}
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.HeartbeatService.Ping(r)
+	})
+}
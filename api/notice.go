@@ -30,6 +30,7 @@ func (noticeController noticeController) API(gin *gin.RouterGroup) {
 		a.POST("noticeCreate", noticeController.Create)
 		a.POST("noticeUpdate", noticeController.Update)
 		a.POST("noticeDelete", noticeController.Delete)
+		a.POST("deadLetterRequeue", noticeController.RequeueDeadLetter)
 	}
 
 	b := gin.Group("notice")
@@ -41,6 +42,7 @@ func (noticeController noticeController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("noticeList", noticeController.List)
 		b.GET("noticeRecordList", noticeController.ListRecord)
+		b.GET("deadLetterList", noticeController.ListDeadLetter)
 	}
 
 	c := gin.Group("notice")
@@ -159,3 +161,27 @@ func (noticeController noticeController) Test(ctx *gin.Context) {
 		return services.NoticeService.Test(r)
 	})
 }
+
+func (noticeController noticeController) ListDeadLetter(ctx *gin.Context) {
+	r := new(types.RequestNoticeDeadLetterQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeService.ListDeadLetter(r)
+	})
+}
+
+func (noticeController noticeController) RequeueDeadLetter(ctx *gin.Context) {
+	r := new(types.RequestNoticeDeadLetterRequeue)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeService.RequeueDeadLetter(r)
+	})
+}
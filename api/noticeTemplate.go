@@ -40,6 +40,7 @@ func (noticeTemplateController noticeTemplateController) API(gin *gin.RouterGrou
 	{
 		b.GET("noticeTemplateList", noticeTemplateController.List)
 		b.GET("noticeTemplateGet", noticeTemplateController.Get)
+		b.POST("noticeTemplateTest", noticeTemplateController.Test)
 	}
 }
 
@@ -99,3 +100,12 @@ func (noticeTemplateController noticeTemplateController) Get(ctx *gin.Context) {
 		return services.NoticeTmplService.Get(r)
 	})
 }
+
+func (noticeTemplateController noticeTemplateController) Test(ctx *gin.Context) {
+	r := new(types.RequestNoticeTemplateTest)
+	BindJson(ctx, r)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeTmplService.Test(r)
+	})
+}
@@ -40,6 +40,10 @@ func (ruleController ruleController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("ruleList", ruleController.List)
 		b.GET("ruleSearch", ruleController.Search)
+		b.POST("preview", ruleController.Preview)
+		b.GET("evalHistory", ruleController.EvalHistory)
+		b.GET("exportPrometheus", ruleController.ExportPrometheus)
+		b.GET("shard", ruleController.Shard)
 	}
 	c := gin.Group("rule")
 	c.Use(
@@ -49,6 +53,7 @@ func (ruleController ruleController) API(gin *gin.RouterGroup) {
 	{
 		c.POST("import", ruleController.Import)
 		c.POST("ruleChangeStatus", ruleController.ChangeStatus)
+		c.POST("rulePause", ruleController.Pause)
 		c.POST("change", ruleController.Change)
 	}
 }
@@ -137,6 +142,18 @@ func (ruleController ruleController) ChangeStatus(ctx *gin.Context) {
 	})
 }
 
+func (ruleController ruleController) Pause(ctx *gin.Context) {
+	r := new(types.RequestRulePause)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.Pause(r)
+	})
+}
+
 func (ruleController ruleController) Import(ctx *gin.Context) {
 	r := new(types.RequestRuleImport)
 	BindJson(ctx, r)
@@ -166,3 +183,51 @@ func (ruleController ruleController) Change(ctx *gin.Context) {
 		return services.RuleService.Change(r)
 	})
 }
+
+func (ruleController ruleController) Preview(ctx *gin.Context) {
+	r := new(types.RequestRuleCreate)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.Preview(r)
+	})
+}
+
+func (ruleController ruleController) EvalHistory(ctx *gin.Context) {
+	r := new(types.RequestRuleEvalHistory)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.EvalHistory(r)
+	})
+}
+
+func (ruleController ruleController) ExportPrometheus(ctx *gin.Context) {
+	r := new(types.RequestRuleExportPrometheus)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.ExportPrometheus(r)
+	})
+}
+
+func (ruleController ruleController) Shard(ctx *gin.Context) {
+	r := new(types.RequestRuleShard)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.Shard(r)
+	})
+}
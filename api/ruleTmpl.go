@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	middleware "watchAlert/internal/middleware"
 	"watchAlert/internal/services"
 	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
 )
 
 type ruleTmplController struct{}
@@ -27,6 +29,7 @@ func (ruleTmplController ruleTmplController) API(gin *gin.RouterGroup) {
 		a.POST("ruleTmplCreate", ruleTmplController.Create)
 		a.POST("ruleTmplUpdate", ruleTmplController.Update)
 		a.POST("ruleTmplDelete", ruleTmplController.Delete)
+		a.POST("ruleTmplInstantiate", ruleTmplController.Instantiate)
 	}
 
 	b := gin.Group("ruleTmpl")
@@ -67,6 +70,24 @@ func (ruleTmplController ruleTmplController) Delete(ctx *gin.Context) {
 	})
 }
 
+func (ruleTmplController ruleTmplController) Instantiate(ctx *gin.Context) {
+	r := new(types.RequestRuleTemplateInstantiate)
+	BindJson(ctx, r)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		tokenStr := ctx.Request.Header.Get("Authorization")
+		if len(tokenStr) <= 0 {
+			return nil, errors.New("用户未登录")
+		}
+		r.UpdateBy = tools.GetUser(tokenStr)
+
+		tid, _ := ctx.Get("TenantID")
+		r.TenantId = tid.(string)
+
+		return services.RuleTmplService.Instantiate(r)
+	})
+}
+
 func (ruleTmplController ruleTmplController) List(ctx *gin.Context) {
 	r := new(types.RequestRuleTemplateQuery)
 	BindQuery(ctx, r)
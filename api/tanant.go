@@ -4,6 +4,7 @@ import (
 	middleware "watchAlert/internal/middleware"
 	"watchAlert/internal/services"
 	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,6 +31,7 @@ func (tenantController tenantController) API(gin *gin.RouterGroup) {
 		a.POST("addUsersToTenant", tenantController.AddUsersToTenant)
 		a.POST("delUsersOfTenant", tenantController.DelUsersOfTenant)
 		a.POST("changeTenantUserRole", tenantController.ChangeTenantUserRole)
+		a.POST("importTenantConfig", tenantController.ImportConfig)
 	}
 
 	b := gin.Group("tenant")
@@ -41,6 +43,7 @@ func (tenantController tenantController) API(gin *gin.RouterGroup) {
 		b.GET("getTenantList", tenantController.List)
 		b.GET("getTenant", tenantController.Get)
 		b.GET("getUsersForTenant", tenantController.GetUsersForTenant)
+		b.GET("exportTenantConfig", tenantController.ExportConfig)
 	}
 }
 
@@ -128,3 +131,22 @@ func (tenantController tenantController) ChangeTenantUserRole(ctx *gin.Context)
 		return services.TenantService.ChangeTenantUserRole(r)
 	})
 }
+
+func (tenantController tenantController) ExportConfig(ctx *gin.Context) {
+	r := new(types.RequestTenantQuery)
+	BindQuery(ctx, r)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.TenantService.ExportConfig(r)
+	})
+}
+
+func (tenantController tenantController) ImportConfig(ctx *gin.Context) {
+	r := new(types.RequestTenantConfigImport)
+	BindJson(ctx, r)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		r.UpdateBy = tools.GetUser(ctx.Request.Header.Get("Authorization"))
+		return services.TenantService.ImportConfig(r)
+	})
+}
@@ -27,6 +27,8 @@ func (userRoleController userRoleController) API(gin *gin.RouterGroup) {
 		a.POST("roleCreate", userRoleController.Create)
 		a.POST("roleUpdate", userRoleController.Update)
 		a.POST("roleDelete", userRoleController.Delete)
+		a.POST("tenantPermissionUpdate", userRoleController.UpdateTenantPermission)
+		a.POST("tenantPermissionDelete", userRoleController.DeleteTenantPermission)
 	}
 
 	b := gin.Group("role")
@@ -37,6 +39,7 @@ func (userRoleController userRoleController) API(gin *gin.RouterGroup) {
 	)
 	{
 		b.GET("roleList", userRoleController.List)
+		b.GET("tenantPermissionList", userRoleController.ListTenantPermissions)
 	}
 }
 
@@ -75,3 +78,39 @@ func (userRoleController userRoleController) List(ctx *gin.Context) {
 		return services.UserRoleService.List(r)
 	})
 }
+
+func (userRoleController userRoleController) ListTenantPermissions(ctx *gin.Context) {
+	r := new(types.RequestTenantRolePermission)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.UserRoleService.ListTenantPermissions(r)
+	})
+}
+
+func (userRoleController userRoleController) UpdateTenantPermission(ctx *gin.Context) {
+	r := new(types.RequestTenantRolePermission)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.UserRoleService.UpdateTenantPermission(r)
+	})
+}
+
+func (userRoleController userRoleController) DeleteTenantPermission(ctx *gin.Context) {
+	r := new(types.RequestTenantRolePermission)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.UserRoleService.DeleteTenantPermission(r)
+	})
+}
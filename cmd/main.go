@@ -2,10 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
+	"os/signal"
 	"sync"
+	"syscall"
+	"time"
 	"watchAlert/alert"
 	"watchAlert/config"
 	"watchAlert/internal/cache"
@@ -17,19 +22,30 @@ import (
 	v1 "watchAlert/internal/routers/v1"
 	"watchAlert/internal/services"
 	"watchAlert/pkg/ai"
+	"watchAlert/pkg/audit"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zeromicro/go-zero/core/logc"
+	"github.com/zeromicro/go-zero/core/logx"
 	"golang.org/x/sync/errgroup"
 )
 
 var Version string
 
+// 停机时等待执行中任务完成的最长时间
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// 初始化配置
 	config.InitConfig(Version)
+	initLogger(config.Application.Log)
 	logc.Info(context.Background(), "服务启动")
 
+	// 启用评估引擎链路追踪
+	tracing.Initialize(context.Background(), config.Application.Tracing)
+
 	initBasic()
 
 	mode := config.Application.Server.Mode
@@ -53,17 +69,58 @@ func main() {
 		panic(http.ListenAndServe("localhost:9999", nil))
 	}()
 
-	err := ginEngine.Run(":" + config.Application.Server.Port)
-	if err != nil {
-		panic(fmt.Sprintf("服务启动失败: %s", err.Error()))
+	srv := &http.Server{
+		Addr:    ":" + config.Application.Server.Port,
+		Handler: ginEngine,
 	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(fmt.Sprintf("服务启动失败: %s", err.Error()))
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	shutdown(srv)
+}
+
+// shutdown 优雅停机, 先停止接收请求, 再等待执行中的规则评估完成
+func shutdown(srv *http.Server) {
+	c, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(c); err != nil {
+		logc.Errorf(context.Background(), "HTTP 服务停止失败: %s", err.Error())
+	}
+
+	if err := alert.Shutdown(c); err != nil {
+		logc.Errorf(context.Background(), "告警引擎停止失败: %s", err.Error())
+	}
+
+	if err := tracing.Shutdown(c); err != nil {
+		logc.Errorf(context.Background(), "链路追踪停止失败: %s", err.Error())
+	}
+
+	logc.Info(context.Background(), "服务已停止")
 }
 
 func initRouter(engine *gin.Engine) {
 	routers.HealthCheck(engine)
+	routers.Metrics(engine)
 	v1.Router(engine)
 }
 
+// initLogger 按配置设置日志输出格式及级别, 未配置时输出 json 格式
+func initLogger(c config.Log) {
+	logx.MustSetup(logx.LogConf{
+		Mode:     "console",
+		Encoding: c.Encoding,
+		Level:    c.Level,
+	})
+}
+
 func initBasic() {
 	// 初始化数据库和缓存
 	dbRepo := repo.NewRepoEntry()
@@ -72,6 +129,9 @@ func initBasic() {
 	// 创建上下文
 	ctx := ctx.NewContext(context.Background(), dbRepo, rCache)
 
+	// 数据源查询缓存命中情况上报到评估引擎指标
+	provider.SetQueryCacheMetrics(ctx.Metrics)
+
 	// 初始化服务
 	services.NewServices(ctx)
 
@@ -84,6 +144,24 @@ func initBasic() {
 	// 加载静默规则
 	go pushMuteRuleToRedis()
 
+	// 启用审计日志外发
+	audit.Initialize(ctx.Ctx, config.Application.AuditLog.Sink)
+
+	// 定期清理过期审计日志
+	pruneCtx, pruneCancel := context.WithCancel(context.Background())
+	ctx.ContextMap["PruneAuditLogJob"] = pruneCancel
+	go services.AuditLogService.PruneCronjob(pruneCtx)
+
+	// 定期清理过期历史事件及失效的当前事件
+	eventPruneCtx, eventPruneCancel := context.WithCancel(context.Background())
+	ctx.ContextMap["PruneEventJob"] = eventPruneCancel
+	go services.EventService.PruneCronjob(eventPruneCtx)
+
+	// 定期检查心跳是否超时
+	heartbeatCtx, heartbeatCancel := context.WithCancel(context.Background())
+	ctx.ContextMap["HeartbeatCheckJob"] = heartbeatCancel
+	go services.HeartbeatService.CheckCronjob(heartbeatCtx)
+
 	r, err := ctx.DB.Setting().Get()
 	if err != nil {
 		logc.Error(ctx.Ctx, fmt.Sprintf("加载系统设置失败: %s", err.Error()))
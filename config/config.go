@@ -12,12 +12,24 @@ type App struct {
 	Redis    Redis    `json:"Redis"`
 	Jwt      Jwt      `json:"Jwt"`
 	Jaeger   Jaeger   `json:"Jaeger"`
+	Eval     Eval     `json:"Eval"`
+	Notice   Notice   `json:"Notice"`
+	Provider Provider `json:"Provider"`
+	AuditLog AuditLog `json:"AuditLog"`
+	Event    Event    `json:"Event"`
+	Tracing  Tracing  `json:"Tracing"`
+	Oidc     Oidc     `json:"Oidc"`
+	Log      Log      `json:"Log"`
 }
 
 type Server struct {
 	Mode           string `json:"mode"`
 	Port           string `json:"port"`
 	EnableElection bool   `json:"enableElection"`
+	// 启用后规则按规则 ID 分配到所有存活实例评估, 故障中心消费及拨测仍由 Leader 负责
+	EnableSharding bool `json:"enableSharding"`
+	// 控制台外部访问地址, 用于在通知中生成跳转链接
+	ExternalUrl string `json:"externalUrl"`
 }
 
 type Database struct {
@@ -32,10 +44,17 @@ type Database struct {
 }
 
 type Redis struct {
+	// 部署模式: standalone(默认) / sentinel / cluster
+	Mode     string `json:"mode"`
 	Host     string `json:"host"`
 	Port     string `json:"port"`
 	Pass     string `json:"pass"`
 	Database int    `json:"database"`
+	// Sentinel 模式的主节点名称及哨兵地址(host:port)
+	MasterName    string   `json:"masterName"`
+	SentinelAddrs []string `json:"sentinelAddrs"`
+	// Cluster 模式的节点地址(host:port), 集群模式下不支持 database
+	ClusterAddrs []string `json:"clusterAddrs"`
 }
 
 type Jwt struct {
@@ -46,6 +65,109 @@ type Jaeger struct {
 	URL string `json:"url"`
 }
 
+type Eval struct {
+	// 单条规则并发查询数据源的最大数量
+	DatasourceParallelism int `json:"datasourceParallelism"`
+	// 规则首次评估的最大随机延迟（秒），为 0 时以规则评估周期为上限
+	MaxStartupJitter int64 `json:"maxStartupJitter"`
+	// 数据源健康检查结果缓存时间（秒），为 0 时使用默认值 5 秒，小于 0 时不缓存
+	HealthCheckCacheTTL int64 `json:"healthCheckCacheTTL"`
+	// 单条规则单次评估允许产生的最大事件数, 规则未单独配置时使用, 为 0 时不限制
+	MaxFingerprints int64 `json:"maxFingerprints"`
+}
+
+type Provider struct {
+	// 数据源 HTTP 连接池的最大空闲连接数
+	MaxIdleConns int `json:"maxIdleConns"`
+	// 每个数据源地址的最大空闲连接数
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost"`
+	// 空闲连接的保持时间（秒）
+	IdleConnTimeout int64 `json:"idleConnTimeout"`
+}
+
+type Notice struct {
+	// 单个通知渠道每秒允许发送的消息数，为 0 时不限速
+	RateLimit float64 `json:"rateLimit"`
+	// 单个通知渠道允许的突发消息数
+	Burst int `json:"burst"`
+	// 发送失败时的最大尝试次数, 超过后进入死信队列
+	MaxAttempts int `json:"maxAttempts"`
+	// 首次重试的退避时间（秒），之后每次翻倍
+	RetryBackoff int `json:"retryBackoff"`
+}
+
+type Event struct {
+	// 历史事件默认保留天数, 租户未单独配置时使用, 为 0 时不按时间清理
+	HistoryRetention int64 `json:"historyRetention"`
+	// 每个租户最多保留的历史事件数量, 租户未单独配置时使用, 为 0 时不限制
+	HistoryMaxCount int64 `json:"historyMaxCount"`
+	// 当前事件超过该时间（秒）未被评估时视为已失效并移除, 为 0 时不清理
+	CurrentEventTTL int64 `json:"currentEventTTL"`
+	// 清理任务的 Cron 表达式
+	PruneCronjob string `json:"pruneCronjob"`
+	// 单批删除的最大行数, 避免长时间锁表
+	PruneBatchSize int `json:"pruneBatchSize"`
+	// 事件状态存储后端
+	Store EventStore `json:"store"`
+}
+
+type EventStore struct {
+	// 存储后端: redis(默认) 或 postgres
+	Backend string `json:"backend"`
+	// Postgres 连接串, 如 host=127.0.0.1 port=5432 user=w8t password=w8t dbname=watchalert sslmode=disable
+	DSN string `json:"dsn"`
+}
+
+type AuditLog struct {
+	// 审计日志默认保留天数, 租户未单独配置时使用, 为 0 时不清理
+	Retention int64 `json:"retention"`
+	// 清理任务的 Cron 表达式
+	PruneCronjob string `json:"pruneCronjob"`
+	// 单批删除的最大行数, 避免长时间锁表
+	PruneBatchSize int `json:"pruneBatchSize"`
+	// 审计日志外发配置
+	Sink AuditSink `json:"sink"`
+}
+
+type AuditSink struct {
+	// 外发类型: syslog / webhook, 为空时不外发
+	Type string `json:"type"`
+	// syslog 为 TCP 地址 host:port, webhook 为 URL
+	Address string `json:"address"`
+	// 日志格式: json / string
+	Format string `json:"format"`
+	// 发送失败时内存中缓冲的最大日志数
+	BufferSize int `json:"bufferSize"`
+}
+
+type Tracing struct {
+	// 是否启用链路追踪, 默认关闭
+	Enabled bool `json:"enabled"`
+	// OTLP HTTP 上报地址, 如 http://otel-collector:4318/v1/traces
+	Endpoint string `json:"endpoint"`
+	// 上报的服务名称
+	ServiceName string `json:"serviceName"`
+	// 采样比例, 取值 0~1, 为 0 时全部采样
+	SampleRatio float64 `json:"sampleRatio"`
+}
+
+type Oidc struct {
+	// 身份提供方地址, 配置后优先于系统设置中的 OIDC 连接配置
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"clientID"`
+	ClientSecret string `json:"clientSecret"`
+	RedirectURI  string `json:"redirectURI"`
+	// 登录 Cookie 的域名
+	Domain string `json:"domain"`
+}
+
+type Log struct {
+	// 日志输出格式: json(默认) / plain, json 格式下关联字段作为独立的键输出, 便于日志系统按字段过滤
+	Encoding string `json:"encoding"`
+	// 日志级别: debug / info(默认) / error / severe
+	Level string `json:"level"`
+}
+
 var (
 	Application App
 	Version     string
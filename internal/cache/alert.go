@@ -15,7 +15,7 @@ import (
 type (
 	// AlertCache 用于管理告警事件缓存操作
 	AlertCache struct {
-		rc *redis.Client
+		rc redis.UniversalClient
 		sync.RWMutex
 	}
 
@@ -26,11 +26,15 @@ type (
 		GetFingerprintsByRuleId(tenantId, faultCenterId, ruleId string) []string
 		GetAllEvents(key models.AlertEventCacheKey) (map[string]*models.AlertCurEvent, error)
 		GetEventFromCache(tenantId, faultCenterId, fingerprint string) (models.AlertCurEvent, error)
+		GetEventsFromCache(tenantId, faultCenterId string, fingerprints []string) (map[string]models.AlertCurEvent, error)
+		PipelineUpdateEvents(tenantId, faultCenterId string, push []*models.AlertCurEvent, remove []string) error
+		PipelineUpdateEventsByKey(key models.AlertEventCacheKey, push []*models.AlertCurEvent, remove []string) error
+		CountEvents(tenantId, faultCenterId string) (int64, error)
 	}
 )
 
 // newAlertCacheInterface 创建一个新的 AlertCache 实例
-func newAlertCacheInterface(r *redis.Client) AlertCacheInterface {
+func newAlertCacheInterface(r redis.UniversalClient) AlertCacheInterface {
 	return &AlertCache{
 		rc: r,
 	}
@@ -105,6 +109,66 @@ func (a *AlertCache) GetEventFromCache(tenantId, faultCenterId, fingerprint stri
 	return event, nil
 }
 
+// GetEventsFromCache 批量获取事件数据, 不存在的指纹不会出现在结果中
+func (a *AlertCache) GetEventsFromCache(tenantId, faultCenterId string, fingerprints []string) (map[string]models.AlertCurEvent, error) {
+	events := make(map[string]models.AlertCurEvent, len(fingerprints))
+	if len(fingerprints) == 0 {
+		return events, nil
+	}
+
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	values, err := a.rc.HMGet(string(key), fingerprints...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var event models.AlertCurEvent
+		if err := sonic.Unmarshal([]byte(data), &event); err != nil {
+			logc.Errorf(context.Background(), "Failed to unmarshal event, fingerprint: %s, err: %v", fingerprints[i], err)
+			continue
+		}
+		events[fingerprints[i]] = event
+	}
+
+	return events, nil
+}
+
+// PipelineUpdateEvents 在同一个 Pipeline 中写入和删除事件
+func (a *AlertCache) PipelineUpdateEvents(tenantId, faultCenterId string, push []*models.AlertCurEvent, remove []string) error {
+	return a.PipelineUpdateEventsByKey(models.BuildAlertEventCacheKey(tenantId, faultCenterId), push, remove)
+}
+
+// PipelineUpdateEventsByKey 在同一个 Pipeline 中写入和删除指定故障中心缓存的事件
+func (a *AlertCache) PipelineUpdateEventsByKey(cacheKey models.AlertEventCacheKey, push []*models.AlertCurEvent, remove []string) error {
+	if len(push) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	key := string(cacheKey)
+	pipe := a.rc.Pipeline()
+	for _, event := range push {
+		pipe.HSet(key, event.Fingerprint, tools.JsonMarshalToString(event))
+	}
+	if len(remove) > 0 {
+		pipe.HDel(key, remove...)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+// CountEvents 获取故障中心的事件数量
+func (a *AlertCache) CountEvents(tenantId, faultCenterId string) (int64, error) {
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	return a.rc.HLen(string(key)).Result()
+}
+
 // 封装 Redis 操作
 func (a *AlertCache) setEventCacheHash(key models.AlertEventCacheKey, field, value string) {
 	a.rc.HSet(string(key), field, value)
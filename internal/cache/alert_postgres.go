@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/zeromicro/go-zero/core/logc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// AlertPostgresStore 基于 Postgres 的事件存储, 与 AlertCache 的语义一致
+	AlertPostgresStore struct {
+		db *gorm.DB
+	}
+)
+
+// newAlertPostgresStore 创建一个新的 AlertPostgresStore 实例
+func newAlertPostgresStore(db *gorm.DB) AlertCacheInterface {
+	return &AlertPostgresStore{
+		db: db,
+	}
+}
+
+// PushAlertEvent 将事件写入故障中心, 已存在时覆盖
+func (a *AlertPostgresStore) PushAlertEvent(event *models.AlertCurEvent) {
+	key := models.BuildAlertEventCacheKey(event.TenantId, event.FaultCenterId)
+	if err := a.upsert(a.db, key, []*models.AlertCurEvent{event}); err != nil {
+		logc.Errorf(context.Background(), "写入事件失败, fingerprint: %s, err: %s", event.Fingerprint, err.Error())
+	}
+}
+
+// RemoveAlertEvent 从故障中心移除事件
+func (a *AlertPostgresStore) RemoveAlertEvent(tenantId, faultCenterId, fingerprint string) {
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	a.db.Where("cache_key = ? AND fingerprint = ?", string(key), fingerprint).Delete(&models.StoreAlertEvent{})
+}
+
+// GetFingerprintsByRuleId 获取与指定规则 ID 相关的指纹列表
+func (a *AlertPostgresStore) GetFingerprintsByRuleId(tenantId, faultCenterId, ruleId string) []string {
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	var fingerprints []string
+	err := a.db.Model(&models.StoreAlertEvent{}).
+		Where("cache_key = ? AND rule_id = ?", string(key), ruleId).
+		Pluck("fingerprint", &fingerprints).Error
+	if err != nil {
+		logc.Error(context.Background(), err.Error())
+		return nil
+	}
+	return fingerprints
+}
+
+// GetAllEvents 获取故障中心的所有事件
+func (a *AlertPostgresStore) GetAllEvents(key models.AlertEventCacheKey) (map[string]*models.AlertCurEvent, error) {
+	var rows []models.StoreAlertEvent
+	if err := a.db.Where("cache_key = ?", string(key)).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	events := make(map[string]*models.AlertCurEvent, len(rows))
+	for _, row := range rows {
+		var event models.AlertCurEvent
+		if err := sonic.Unmarshal([]byte(row.Event), &event); err != nil {
+			logc.Error(context.Background(), fmt.Sprintf("unmarshal event json error: %s, event json: %s", err.Error(), row.Event))
+			continue
+		}
+		events[row.Fingerprint] = &event
+	}
+
+	return events, nil
+}
+
+// GetEventFromCache 获取事件数据, 不存在时返回 gorm.ErrRecordNotFound
+func (a *AlertPostgresStore) GetEventFromCache(tenantId, faultCenterId, fingerprint string) (models.AlertCurEvent, error) {
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	var row models.StoreAlertEvent
+	if err := a.db.Where("cache_key = ? AND fingerprint = ?", string(key), fingerprint).First(&row).Error; err != nil {
+		return models.AlertCurEvent{}, err
+	}
+
+	var event models.AlertCurEvent
+	if err := sonic.Unmarshal([]byte(row.Event), &event); err != nil {
+		return models.AlertCurEvent{}, err
+	}
+
+	return event, nil
+}
+
+// GetEventsFromCache 批量获取事件数据, 不存在的指纹不会出现在结果中
+func (a *AlertPostgresStore) GetEventsFromCache(tenantId, faultCenterId string, fingerprints []string) (map[string]models.AlertCurEvent, error) {
+	events := make(map[string]models.AlertCurEvent, len(fingerprints))
+	if len(fingerprints) == 0 {
+		return events, nil
+	}
+
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	var rows []models.StoreAlertEvent
+	if err := a.db.Where("cache_key = ? AND fingerprint IN ?", string(key), fingerprints).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		var event models.AlertCurEvent
+		if err := sonic.Unmarshal([]byte(row.Event), &event); err != nil {
+			logc.Errorf(context.Background(), "Failed to unmarshal event, fingerprint: %s, err: %v", row.Fingerprint, err)
+			continue
+		}
+		events[row.Fingerprint] = event
+	}
+
+	return events, nil
+}
+
+// PipelineUpdateEvents 在同一个事务中写入和删除事件
+func (a *AlertPostgresStore) PipelineUpdateEvents(tenantId, faultCenterId string, push []*models.AlertCurEvent, remove []string) error {
+	return a.PipelineUpdateEventsByKey(models.BuildAlertEventCacheKey(tenantId, faultCenterId), push, remove)
+}
+
+// PipelineUpdateEventsByKey 在同一个事务中写入和删除指定故障中心的事件
+func (a *AlertPostgresStore) PipelineUpdateEventsByKey(key models.AlertEventCacheKey, push []*models.AlertCurEvent, remove []string) error {
+	if len(push) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if err := a.upsert(tx, key, push); err != nil {
+			return err
+		}
+		if len(remove) > 0 {
+			return tx.Where("cache_key = ? AND fingerprint IN ?", string(key), remove).Delete(&models.StoreAlertEvent{}).Error
+		}
+		return nil
+	})
+}
+
+// CountEvents 获取故障中心的事件数量
+func (a *AlertPostgresStore) CountEvents(tenantId, faultCenterId string) (int64, error) {
+	key := models.BuildAlertEventCacheKey(tenantId, faultCenterId)
+	var count int64
+	err := a.db.Model(&models.StoreAlertEvent{}).Where("cache_key = ?", string(key)).Count(&count).Error
+	return count, err
+}
+
+func (a *AlertPostgresStore) upsert(tx *gorm.DB, key models.AlertEventCacheKey, events []*models.AlertCurEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]models.StoreAlertEvent, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, models.StoreAlertEvent{
+			CacheKey:    string(key),
+			Fingerprint: event.Fingerprint,
+			RuleId:      event.RuleId,
+			Event:       tools.JsonMarshalToString(event),
+		})
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}, {Name: "fingerprint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rule_id", "event"}),
+	}).CreateInBatches(rows, 500).Error
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"sort"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+)
+
+type (
+	// DeadLetterCache 通知死信队列, 按租户存储重试后仍发送失败的通知
+	DeadLetterCache struct {
+		rc redis.UniversalClient
+	}
+
+	DeadLetterCacheInterface interface {
+		Push(letter models.NoticeDeadLetter) error
+		List(tenantId string) ([]models.NoticeDeadLetter, error)
+		Get(tenantId, id string) (models.NoticeDeadLetter, error)
+		Remove(tenantId, id string) error
+	}
+)
+
+func newDeadLetterCacheInterface(r redis.UniversalClient) DeadLetterCacheInterface {
+	return &DeadLetterCache{
+		rc: r,
+	}
+}
+
+func (d *DeadLetterCache) Push(letter models.NoticeDeadLetter) error {
+	key := string(models.BuildNoticeDeadLetterCacheKey(letter.TenantId))
+	return d.rc.HSet(key, letter.Id, tools.JsonMarshalToString(letter)).Err()
+}
+
+// List 获取租户下所有死信通知, 按进入死信队列的时间倒序
+func (d *DeadLetterCache) List(tenantId string) ([]models.NoticeDeadLetter, error) {
+	result, err := d.rc.HGetAll(string(models.BuildNoticeDeadLetterCacheKey(tenantId))).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]models.NoticeDeadLetter, 0, len(result))
+	for _, v := range result {
+		var letter models.NoticeDeadLetter
+		if err := sonic.Unmarshal([]byte(v), &letter); err != nil {
+			continue
+		}
+		letters = append(letters, letter)
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].CreateAt > letters[j].CreateAt
+	})
+
+	return letters, nil
+}
+
+func (d *DeadLetterCache) Get(tenantId, id string) (models.NoticeDeadLetter, error) {
+	var letter models.NoticeDeadLetter
+	data, err := d.rc.HGet(string(models.BuildNoticeDeadLetterCacheKey(tenantId)), id).Bytes()
+	if err != nil {
+		return letter, err
+	}
+
+	err = sonic.Unmarshal(data, &letter)
+	return letter, err
+}
+
+func (d *DeadLetterCache) Remove(tenantId, id string) error {
+	return d.rc.HDel(string(models.BuildNoticeDeadLetterCacheKey(tenantId)), id).Err()
+}
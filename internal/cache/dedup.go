@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// 被去重的通知计数的保留时间
+const dedupCountExpiration = 24 * time.Hour
+
+type (
+	// NoticeDedupCache 通知去重, 记录窗口内已发送的通知及被去重的次数
+	NoticeDedupCache struct {
+		rc redis.UniversalClient
+	}
+
+	NoticeDedupCacheInterface interface {
+		// Acquire 窗口内首次发送时返回 true, 否则返回 false
+		Acquire(tenantId, hash string, window time.Duration) bool
+		// IncrSuppressed 记录一次被去重的通知
+		IncrSuppressed(tenantId, hash string)
+		// PopSuppressed 获取并清空被去重的次数
+		PopSuppressed(tenantId, hash string) int64
+		// Release 发送失败时释放去重窗口, 并归还取出的被去重次数
+		Release(tenantId, hash string, suppressed int64)
+	}
+)
+
+func newNoticeDedupCacheInterface(r redis.UniversalClient) NoticeDedupCacheInterface {
+	return &NoticeDedupCache{
+		rc: r,
+	}
+}
+
+func (n *NoticeDedupCache) Acquire(tenantId, hash string, window time.Duration) bool {
+	ok, err := n.rc.SetNX(buildNoticeDedupKey(tenantId, hash), time.Now().Unix(), window).Result()
+	if err != nil {
+		// Redis 异常时不做去重, 避免丢失通知
+		return true
+	}
+	return ok
+}
+
+func (n *NoticeDedupCache) IncrSuppressed(tenantId, hash string) {
+	key := buildNoticeDedupCountKey(tenantId, hash)
+	pipe := n.rc.TxPipeline()
+	pipe.Incr(key)
+	pipe.Expire(key, dedupCountExpiration)
+	_, _ = pipe.Exec()
+}
+
+func (n *NoticeDedupCache) PopSuppressed(tenantId, hash string) int64 {
+	key := buildNoticeDedupCountKey(tenantId, hash)
+	pipe := n.rc.TxPipeline()
+	get := pipe.Get(key)
+	pipe.Del(key)
+	_, _ = pipe.Exec()
+
+	count, _ := get.Int64()
+	return count
+}
+
+func (n *NoticeDedupCache) Release(tenantId, hash string, suppressed int64) {
+	pipe := n.rc.TxPipeline()
+	pipe.Del(buildNoticeDedupKey(tenantId, hash))
+	if suppressed > 0 {
+		key := buildNoticeDedupCountKey(tenantId, hash)
+		pipe.IncrBy(key, suppressed)
+		pipe.Expire(key, dedupCountExpiration)
+	}
+	_, _ = pipe.Exec()
+}
+
+func buildNoticeDedupKey(tenantId, hash string) string {
+	return fmt.Sprintf("w8t:%s:notice:dedup:%s", tenantId, hash)
+}
+
+func buildNoticeDedupCountKey(tenantId, hash string) string {
+	return fmt.Sprintf("w8t:%s:notice:dedup:%s.count", tenantId, hash)
+}
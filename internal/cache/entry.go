@@ -1,48 +1,109 @@
 package cache
 
 import (
+	"watchAlert/config"
 	"watchAlert/pkg/client"
 
 	"github.com/go-redis/redis"
+	"gorm.io/gorm"
 )
 
 type (
 	entryCache struct {
-		redis    *redis.Client
+		redis    redis.UniversalClient
 		provider *ProviderPoolStore
+		// 事件状态的数据库存储后端, 为空时使用 Redis
+		store *gorm.DB
 	}
 
 	InterEntryCache interface {
-		Redis() *redis.Client
+		Redis() redis.UniversalClient
 		Silence() SilenceCacheInterface
 		Alert() AlertCacheInterface
 		ProviderPools() *ProviderPoolStore
 		FaultCenter() FaultCenterCacheInterface
 		PendingRecover() PendingRecoverCacheInterface
+		Pending() PendingCacheInterface
 		Topology() TopologyCacheInterface
+		DeadLetter() DeadLetterCacheInterface
+		NoticeDedup() NoticeDedupCacheInterface
+		NoticeState() NoticeStateCacheInterface
+		RuleEvalHistory() RuleEvalHistoryCacheInterface
+		Heartbeat() HeartbeatCacheInterface
+		Snooze() SnoozeCacheInterface
+		Flapping() FlappingCacheInterface
+		EventStream() EventStreamCacheInterface
 	}
 )
 
 func NewEntryCache() InterEntryCache {
 	r := client.InitRedis()
 	p := NewClientPoolStore()
+	s := client.InitEventStore(config.Application.Event.Store)
 
 	return &entryCache{
 		redis:    r,
 		provider: p,
+		store:    s,
 	}
 }
 
-func (e entryCache) Redis() *redis.Client              { return e.redis }
+// NewEntryCacheWithClient 使用已有的 Redis 客户端创建缓存, 事件状态存储在 Redis 中
+func NewEntryCacheWithClient(r redis.UniversalClient) InterEntryCache {
+	return &entryCache{
+		redis:    r,
+		provider: NewClientPoolStore(),
+	}
+}
+
+func (e entryCache) Redis() redis.UniversalClient      { return e.redis }
 func (e entryCache) Silence() SilenceCacheInterface    { return newSilenceCacheInterface(e.redis) }
-func (e entryCache) Alert() AlertCacheInterface        { return newAlertCacheInterface(e.redis) }
 func (e entryCache) ProviderPools() *ProviderPoolStore { return e.provider }
+func (e entryCache) Alert() AlertCacheInterface {
+	if e.store != nil {
+		return newAlertPostgresStore(e.store)
+	}
+	return newAlertCacheInterface(e.redis)
+}
 func (e entryCache) FaultCenter() FaultCenterCacheInterface {
+	if e.store != nil {
+		return newFaultCenterPostgresStore(e.store)
+	}
 	return newFaultCenterCacheInterface(e.redis)
 }
 func (e entryCache) PendingRecover() PendingRecoverCacheInterface {
+	if e.store != nil {
+		return newPendingRecoverPostgresStore(e.store)
+	}
 	return newPendingRecoverCacheInterface(e.redis)
 }
+func (e entryCache) Pending() PendingCacheInterface {
+	return newPendingCacheInterface(e.redis)
+}
 func (e entryCache) Topology() TopologyCacheInterface {
 	return newTopologyCacheInterface(e.redis)
 }
+func (e entryCache) DeadLetter() DeadLetterCacheInterface {
+	return newDeadLetterCacheInterface(e.redis)
+}
+func (e entryCache) NoticeDedup() NoticeDedupCacheInterface {
+	return newNoticeDedupCacheInterface(e.redis)
+}
+func (e entryCache) NoticeState() NoticeStateCacheInterface {
+	return newNoticeStateCacheInterface(e.redis)
+}
+func (e entryCache) RuleEvalHistory() RuleEvalHistoryCacheInterface {
+	return newRuleEvalHistoryCacheInterface(e.redis)
+}
+func (e entryCache) Heartbeat() HeartbeatCacheInterface {
+	return newHeartbeatCacheInterface(e.redis)
+}
+func (e entryCache) Snooze() SnoozeCacheInterface {
+	return newSnoozeCacheInterface(e.redis)
+}
+func (e entryCache) Flapping() FlappingCacheInterface {
+	return newFlappingCacheInterface(e.redis)
+}
+func (e entryCache) EventStream() EventStreamCacheInterface {
+	return newEventStreamCacheInterface(e.redis)
+}
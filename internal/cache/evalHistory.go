@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+)
+
+const (
+	// 每个规则保留的评估记录数量
+	ruleEvalHistoryLimit = 100
+	// 规则删除后评估记录的保留时间
+	ruleEvalHistoryTTL = 7 * 24 * time.Hour
+)
+
+type (
+	// RuleEvalHistoryCache 规则评估记录, 按规则存储在定长的 Redis 列表中, 最新的记录在前
+	RuleEvalHistoryCache struct {
+		rc redis.UniversalClient
+	}
+
+	RuleEvalHistoryCacheInterface interface {
+		Push(tenantId string, record models.RuleEvalRecord) error
+		List(tenantId, ruleId string, limit int64) ([]models.RuleEvalRecord, error)
+	}
+)
+
+func newRuleEvalHistoryCacheInterface(r redis.UniversalClient) RuleEvalHistoryCacheInterface {
+	return &RuleEvalHistoryCache{
+		rc: r,
+	}
+}
+
+func (h *RuleEvalHistoryCache) Push(tenantId string, record models.RuleEvalRecord) error {
+	key := string(models.BuildRuleEvalHistoryCacheKey(tenantId, record.RuleId))
+
+	pipe := h.rc.TxPipeline()
+	pipe.LPush(key, tools.JsonMarshalToString(record))
+	pipe.LTrim(key, 0, ruleEvalHistoryLimit-1)
+	pipe.Expire(key, ruleEvalHistoryTTL)
+	_, err := pipe.Exec()
+	return err
+}
+
+// List 获取规则最近的评估记录, limit 不大于 0 时返回全部
+func (h *RuleEvalHistoryCache) List(tenantId, ruleId string, limit int64) ([]models.RuleEvalRecord, error) {
+	if limit <= 0 || limit > ruleEvalHistoryLimit {
+		limit = ruleEvalHistoryLimit
+	}
+
+	result, err := h.rc.LRange(string(models.BuildRuleEvalHistoryCacheKey(tenantId, ruleId)), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.RuleEvalRecord, 0, len(result))
+	for _, v := range result {
+		var record models.RuleEvalRecord
+		if err := sonic.Unmarshal([]byte(v), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
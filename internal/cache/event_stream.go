@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"watchAlert/internal/models"
+
+	"github.com/go-redis/redis"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type (
+	// EventStreamCache 活跃告警事件变更的消息通道, 按租户及故障中心划分
+	EventStreamCache struct {
+		rc redis.UniversalClient
+	}
+
+	EventStreamCacheInterface interface {
+		// Publish 发布事件变更
+		Publish(change models.EventChange) error
+		// Subscribe 订阅故障中心的事件变更, ctx 结束后关闭订阅及返回的通道
+		Subscribe(ctx context.Context, tenantId, faultCenterId string) (<-chan models.EventChange, error)
+	}
+)
+
+func newEventStreamCacheInterface(r redis.UniversalClient) EventStreamCacheInterface {
+	return &EventStreamCache{
+		rc: r,
+	}
+}
+
+func (e *EventStreamCache) Publish(change models.EventChange) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	return e.rc.Publish(buildEventStreamChannel(change.Event.TenantId, change.Event.FaultCenterId), string(data)).Err()
+}
+
+func (e *EventStreamCache) Subscribe(ctx context.Context, tenantId, faultCenterId string) (<-chan models.EventChange, error) {
+	pubsub := e.rc.Subscribe(buildEventStreamChannel(tenantId, faultCenterId))
+	// 等待订阅确认, 确保之后发布的变更不会丢失
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan models.EventChange)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var change models.EventChange
+				if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+					logc.Errorf(ctx, "解析事件变更消息失败, err: %s", err.Error())
+					continue
+				}
+				select {
+				case out <- change:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func buildEventStreamChannel(tenantId, faultCenterId string) string {
+	return fmt.Sprintf("w8t:%s:event:stream:%s", tenantId, faultCenterId)
+}
@@ -12,7 +12,7 @@ import (
 
 type (
 	FaultCenterCache struct {
-		rc *redis.Client
+		rc redis.UniversalClient
 		sync.RWMutex
 	}
 
@@ -24,7 +24,7 @@ type (
 )
 
 // newFaultCenterCacheInterface 创建一个新的 FaultCenterCache 实例
-func newFaultCenterCacheInterface(r *redis.Client) FaultCenterCacheInterface {
+func newFaultCenterCacheInterface(r redis.UniversalClient) FaultCenterCacheInterface {
 	return &FaultCenterCache{
 		rc: r,
 	}
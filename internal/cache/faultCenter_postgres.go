@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/zeromicro/go-zero/core/logc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// FaultCenterPostgresStore 基于 Postgres 的故障中心信息存储, 与 FaultCenterCache 的语义一致
+	FaultCenterPostgresStore struct {
+		db *gorm.DB
+	}
+)
+
+// newFaultCenterPostgresStore 创建一个新的 FaultCenterPostgresStore 实例
+func newFaultCenterPostgresStore(db *gorm.DB) FaultCenterCacheInterface {
+	return &FaultCenterPostgresStore{
+		db: db,
+	}
+}
+
+// PushFaultCenterInfo 添加 Info 数据
+func (f *FaultCenterPostgresStore) PushFaultCenterInfo(center models.FaultCenter) {
+	err := f.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"info"}),
+	}).Create(&models.StoreFaultCenterInfo{
+		CacheKey: string(models.BuildFaultCenterInfoCacheKey(center.TenantId, center.ID)),
+		Info:     tools.JsonMarshalToString(center),
+	}).Error
+	if err != nil {
+		logc.Errorf(context.Background(), "写入故障中心信息失败, err: %s", err.Error())
+	}
+}
+
+// GetFaultCenterInfo 获取 Info 数据
+func (f *FaultCenterPostgresStore) GetFaultCenterInfo(faultCenterInfoKey models.FaultCenterInfoCacheKey) models.FaultCenter {
+	var row models.StoreFaultCenterInfo
+	if err := f.db.Where("cache_key = ?", string(faultCenterInfoKey)).First(&row).Error; err != nil {
+		return models.FaultCenter{}
+	}
+
+	var fc models.FaultCenter
+	_ = sonic.Unmarshal([]byte(row.Info), &fc)
+	return fc
+}
+
+// RemoveFaultCenterInfo 删除 Info 数据
+func (f *FaultCenterPostgresStore) RemoveFaultCenterInfo(faultCenterInfoKey models.FaultCenterInfoCacheKey) {
+	f.db.Where("cache_key = ?", string(faultCenterInfoKey)).Delete(&models.StoreFaultCenterInfo{})
+}
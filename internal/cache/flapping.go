@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+type (
+	// FlappingCache 抖动检测, 按事件记录窗口内的状态变化及处于抖动状态的事件
+	FlappingCache struct {
+		rc redis.UniversalClient
+	}
+
+	FlappingCacheInterface interface {
+		// RecordChange 记录事件的一次状态变化, 相同的变化只记录一次, 返回窗口内的状态变化次数
+		RecordChange(tenantId, faultCenterId, fingerprint, change string, window time.Duration) (int64, error)
+		// CountChanges 获取事件窗口内的状态变化次数
+		CountChanges(tenantId, faultCenterId, fingerprint string, window time.Duration) (int64, error)
+		// List 获取故障中心下处于抖动状态的事件及开始抖动的时间
+		List(tenantId, faultCenterId string) (map[string]int64, error)
+		// Mark 标记事件进入抖动状态
+		Mark(tenantId, faultCenterId, fingerprint string, since int64)
+		// Unmark 解除事件的抖动状态
+		Unmark(tenantId, faultCenterId, fingerprint string)
+	}
+)
+
+func newFlappingCacheInterface(r redis.UniversalClient) FlappingCacheInterface {
+	return &FlappingCache{
+		rc: r,
+	}
+}
+
+func (f *FlappingCache) RecordChange(tenantId, faultCenterId, fingerprint, change string, window time.Duration) (int64, error) {
+	key := buildFlappingChangesKey(tenantId, faultCenterId, fingerprint)
+	now := time.Now()
+
+	pipe := f.rc.TxPipeline()
+	pipe.ZAddNX(key, redis.Z{Score: float64(now.Unix()), Member: change})
+	pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(now.Add(-window).Unix(), 10))
+	count := pipe.ZCard(key)
+	pipe.Expire(key, window)
+	if _, err := pipe.Exec(); err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+func (f *FlappingCache) CountChanges(tenantId, faultCenterId, fingerprint string, window time.Duration) (int64, error) {
+	from := strconv.FormatInt(time.Now().Add(-window).Unix(), 10)
+	return f.rc.ZCount(buildFlappingChangesKey(tenantId, faultCenterId, fingerprint), "("+from, "+inf").Result()
+}
+
+func (f *FlappingCache) List(tenantId, faultCenterId string) (map[string]int64, error) {
+	result, err := f.rc.HGetAll(buildFlappingKey(tenantId, faultCenterId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	flapping := make(map[string]int64, len(result))
+	for fingerprint, v := range result {
+		since, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		flapping[fingerprint] = since
+	}
+
+	return flapping, nil
+}
+
+func (f *FlappingCache) Mark(tenantId, faultCenterId, fingerprint string, since int64) {
+	f.rc.HSet(buildFlappingKey(tenantId, faultCenterId), fingerprint, since)
+}
+
+func (f *FlappingCache) Unmark(tenantId, faultCenterId, fingerprint string) {
+	f.rc.HDel(buildFlappingKey(tenantId, faultCenterId), fingerprint)
+}
+
+func buildFlappingKey(tenantId, faultCenterId string) string {
+	return fmt.Sprintf("w8t:%s:flapping:%s", tenantId, faultCenterId)
+}
+
+func buildFlappingChangesKey(tenantId, faultCenterId, fingerprint string) string {
+	return fmt.Sprintf("w8t:%s:flapping:%s:%s", tenantId, faultCenterId, fingerprint)
+}
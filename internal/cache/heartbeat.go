@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"strconv"
+	"watchAlert/internal/models"
+
+	"github.com/go-redis/redis"
+)
+
+type (
+	// HeartbeatCache 记录各心跳最近一次的上报时间, 按租户存储在 Redis Hash 中
+	HeartbeatCache struct {
+		rc redis.UniversalClient
+	}
+
+	HeartbeatCacheInterface interface {
+		Ping(tenantId, id string, time int64) error
+		GetAll(tenantId string) (map[string]int64, error)
+		Delete(tenantId, id string)
+	}
+)
+
+func newHeartbeatCacheInterface(r redis.UniversalClient) HeartbeatCacheInterface {
+	return &HeartbeatCache{
+		rc: r,
+	}
+}
+
+func (h *HeartbeatCache) Ping(tenantId, id string, time int64) error {
+	return h.rc.HSet(string(models.BuildHeartbeatCacheKey(tenantId)), id, time).Err()
+}
+
+func (h *HeartbeatCache) GetAll(tenantId string) (map[string]int64, error) {
+	result, err := h.rc.HGetAll(string(models.BuildHeartbeatCacheKey(tenantId))).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	lastSeen := make(map[string]int64, len(result))
+	for id, v := range result {
+		t, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		lastSeen[id] = t
+	}
+
+	return lastSeen, nil
+}
+
+func (h *HeartbeatCache) Delete(tenantId, id string) {
+	h.rc.HDel(string(models.BuildHeartbeatCacheKey(tenantId)), id)
+}
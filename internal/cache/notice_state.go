@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// 恢复通知发送标记的保留时间
+const recoverNoticeExpiration = 24 * time.Hour
+
+type (
+	// NoticeStateCache 记录事件的通知状态
+	NoticeStateCache struct {
+		rc redis.UniversalClient
+	}
+
+	NoticeStateCacheInterface interface {
+		// AcquireRecover 事件的恢复通知首次发送时返回 true, 同一次告警的恢复通知只发送一次
+		AcquireRecover(tenantId, fingerprint string, firstTriggerTime int64) bool
+		// GetLastNotice 获取事件最近一次发送通知的时间, 未发送过时返回 0
+		GetLastNotice(tenantId, faultCenterId, fingerprint string) int64
+		// SetLastNotice 记录事件发送通知的时间
+		SetLastNotice(tenantId, faultCenterId, fingerprint string, sendAt int64)
+		// ResetLastNotice 清除事件的通知时间, 事件恢复后重新计时
+		ResetLastNotice(tenantId, faultCenterId, fingerprint string)
+	}
+)
+
+func newNoticeStateCacheInterface(r redis.UniversalClient) NoticeStateCacheInterface {
+	return &NoticeStateCache{
+		rc: r,
+	}
+}
+
+func (n *NoticeStateCache) AcquireRecover(tenantId, fingerprint string, firstTriggerTime int64) bool {
+	ok, err := n.rc.SetNX(buildRecoverNoticeKey(tenantId, fingerprint, firstTriggerTime), time.Now().Unix(), recoverNoticeExpiration).Result()
+	if err != nil {
+		// Redis 异常时仍发送, 避免丢失恢复通知
+		return true
+	}
+	return ok
+}
+
+func (n *NoticeStateCache) GetLastNotice(tenantId, faultCenterId, fingerprint string) int64 {
+	sendAt, err := n.rc.HGet(buildLastNoticeKey(tenantId, faultCenterId), fingerprint).Int64()
+	if err != nil {
+		return 0
+	}
+	return sendAt
+}
+
+func (n *NoticeStateCache) SetLastNotice(tenantId, faultCenterId, fingerprint string, sendAt int64) {
+	n.rc.HSet(buildLastNoticeKey(tenantId, faultCenterId), fingerprint, sendAt)
+}
+
+func (n *NoticeStateCache) ResetLastNotice(tenantId, faultCenterId, fingerprint string) {
+	n.rc.HDel(buildLastNoticeKey(tenantId, faultCenterId), fingerprint)
+}
+
+func buildLastNoticeKey(tenantId, faultCenterId string) string {
+	return fmt.Sprintf("w8t:%s:notice:lastSent:%s", tenantId, faultCenterId)
+}
+
+func buildRecoverNoticeKey(tenantId, fingerprint string, firstTriggerTime int64) string {
+	return fmt.Sprintf("w8t:%s:notice:recovered:%s:%d", tenantId, fingerprint, firstTriggerTime)
+}
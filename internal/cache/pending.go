@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/go-redis/redis"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// PendingCache 用于记录告警事件首次满足条件的时间（持续时间 ForDuration 判断）
+	PendingCache struct {
+		rc redis.UniversalClient
+	}
+
+	// PendingCacheInterface 定义了预告警事件缓存的操作接口
+	PendingCacheInterface interface {
+		Set(tenantId, ruleId, fingerprint string, time int64)
+		Get(tenantId, ruleId, fingerprint string) (int64, error)
+		Delete(tenantId, ruleId, fingerprint string)
+		BatchDelete(tenantId, ruleId string, fingerprints []string)
+		List(tenantId, ruleId string) map[string]int64
+	}
+
+	PendingCacheKey string
+)
+
+// newPendingCacheInterface 创建一个新的 PendingCache 实例
+func newPendingCacheInterface(r redis.UniversalClient) PendingCacheInterface {
+	return &PendingCache{
+		rc: r,
+	}
+}
+
+func (p *PendingCache) Set(tenantId, ruleId, fingerprint string, time int64) {
+	p.rc.HSet(string(BuildPendingCacheKey(tenantId, ruleId)), fingerprint, time)
+}
+
+func (p *PendingCache) Get(tenantId, ruleId, fingerprint string) (int64, error) {
+	return p.rc.HGet(string(BuildPendingCacheKey(tenantId, ruleId)), fingerprint).Int64()
+}
+
+func (p *PendingCache) Delete(tenantId, ruleId, fingerprint string) {
+	p.rc.HDel(string(BuildPendingCacheKey(tenantId, ruleId)), fingerprint)
+}
+
+// BatchDelete 一次删除多个指纹的计时
+func (p *PendingCache) BatchDelete(tenantId, ruleId string, fingerprints []string) {
+	if len(fingerprints) == 0 {
+		return
+	}
+
+	p.rc.HDel(string(BuildPendingCacheKey(tenantId, ruleId)), fingerprints...)
+}
+
+func (p *PendingCache) List(tenantId, ruleId string) map[string]int64 {
+	result, err := p.rc.HGetAll(string(BuildPendingCacheKey(tenantId, ruleId))).Result()
+	if err != nil {
+		return map[string]int64{}
+	}
+
+	var newMap = make(map[string]int64)
+	for k, v := range result {
+		newMap[k] = tools.ConvertStringToInt64(v)
+	}
+
+	return newMap
+}
+
+func BuildPendingCacheKey(tenantId, ruleId string) PendingCacheKey {
+	return PendingCacheKey(fmt.Sprintf("w8t:%s:pending:%s.fingerprints", tenantId, ruleId))
+}
@@ -10,7 +10,7 @@ import (
 type (
 	// PendingRecoverCache 用于管理待恢复的告警事件
 	PendingRecoverCache struct {
-		rc    *redis.Client
+		rc    redis.UniversalClient
 		mutex sync.RWMutex
 	}
 
@@ -20,6 +20,8 @@ type (
 		Get(tenantId, ruleId, fingerprint string) (int64, error)
 		Delete(tenantId, ruleId, fingerprint string)
 		List(tenantId, ruleId string) map[string]int64
+		GetAll(tenantId, ruleId string) (map[string]int64, error)
+		PipelineUpdate(tenantId, ruleId string, set map[string]int64, remove []string) error
 	}
 
 	PendingRecoverCacheKey string
@@ -31,7 +33,7 @@ type (
 )
 
 // newPendingRecoverCacheInterface 创建一个新的 PendingRecoverCache 实例
-func newPendingRecoverCacheInterface(r *redis.Client) PendingRecoverCacheInterface {
+func newPendingRecoverCacheInterface(r redis.UniversalClient) PendingRecoverCacheInterface {
 	return &PendingRecoverCache{
 		rc: r,
 	}
@@ -59,20 +61,52 @@ func (p *PendingRecoverCache) Delete(tenantId, ruleId, fingerprint string) {
 }
 
 func (p *PendingRecoverCache) List(tenantId, ruleId string) map[string]int64 {
+	newMap, err := p.GetAll(tenantId, ruleId)
+	if err != nil {
+		return map[string]int64{}
+	}
+
+	return newMap
+}
+
+// GetAll 一次读取规则下所有待恢复指纹的时间戳, 读取失败时返回错误而不是空结果
+func (p *PendingRecoverCache) GetAll(tenantId, ruleId string) (map[string]int64, error) {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
 	result, err := p.rc.HGetAll(string(BuildPendingRecoverCacheKey(tenantId, ruleId))).Result()
 	if err != nil {
-		return map[string]int64{}
+		return nil, err
 	}
 
-	var newMap = make(map[string]int64)
+	var newMap = make(map[string]int64, len(result))
 	for k, v := range result {
 		newMap[k] = tools.ConvertStringToInt64(v)
 	}
 
-	return newMap
+	return newMap, nil
+}
+
+// PipelineUpdate 在同一个 Pipeline 中写入和删除待恢复指纹的时间戳
+func (p *PendingRecoverCache) PipelineUpdate(tenantId, ruleId string, set map[string]int64, remove []string) error {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	key := string(BuildPendingRecoverCacheKey(tenantId, ruleId))
+	pipe := p.rc.Pipeline()
+	for fingerprint, t := range set {
+		pipe.HSet(key, fingerprint, t)
+	}
+	if len(remove) > 0 {
+		pipe.HDel(key, remove...)
+	}
+
+	_, err := pipe.Exec()
+	return err
 }
 
 func BuildPendingRecoverCacheKey(tenantId, ruleId string) PendingRecoverCacheKey {
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	// PendingRecoverPostgresStore 基于 Postgres 的待恢复指纹存储, 与 PendingRecoverCache 的语义一致
+	PendingRecoverPostgresStore struct {
+		db *gorm.DB
+	}
+)
+
+// newPendingRecoverPostgresStore 创建一个新的 PendingRecoverPostgresStore 实例
+func newPendingRecoverPostgresStore(db *gorm.DB) PendingRecoverCacheInterface {
+	return &PendingRecoverPostgresStore{
+		db: db,
+	}
+}
+
+func (p *PendingRecoverPostgresStore) Set(tenantId, ruleId, fingerprint string, time int64) {
+	err := p.upsert(p.db, BuildPendingRecoverCacheKey(tenantId, ruleId), map[string]int64{fingerprint: time})
+	if err != nil {
+		logc.Errorf(context.Background(), "写入待恢复指纹失败, fingerprint: %s, err: %s", fingerprint, err.Error())
+	}
+}
+
+func (p *PendingRecoverPostgresStore) Get(tenantId, ruleId, fingerprint string) (int64, error) {
+	var row models.StorePendingRecover
+	err := p.db.Where("cache_key = ? AND fingerprint = ?", string(BuildPendingRecoverCacheKey(tenantId, ruleId)), fingerprint).First(&row).Error
+	if err != nil {
+		return 0, err
+	}
+	return row.Time, nil
+}
+
+func (p *PendingRecoverPostgresStore) Delete(tenantId, ruleId, fingerprint string) {
+	p.db.Where("cache_key = ? AND fingerprint = ?", string(BuildPendingRecoverCacheKey(tenantId, ruleId)), fingerprint).Delete(&models.StorePendingRecover{})
+}
+
+func (p *PendingRecoverPostgresStore) List(tenantId, ruleId string) map[string]int64 {
+	newMap, err := p.GetAll(tenantId, ruleId)
+	if err != nil {
+		return map[string]int64{}
+	}
+
+	return newMap
+}
+
+// GetAll 一次读取规则下所有待恢复指纹的时间戳, 读取失败时返回错误而不是空结果
+func (p *PendingRecoverPostgresStore) GetAll(tenantId, ruleId string) (map[string]int64, error) {
+	var rows []models.StorePendingRecover
+	if err := p.db.Where("cache_key = ?", string(BuildPendingRecoverCacheKey(tenantId, ruleId))).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	newMap := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		newMap[row.Fingerprint] = row.Time
+	}
+
+	return newMap, nil
+}
+
+// PipelineUpdate 在同一个事务中写入和删除待恢复指纹的时间戳
+func (p *PendingRecoverPostgresStore) PipelineUpdate(tenantId, ruleId string, set map[string]int64, remove []string) error {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	key := BuildPendingRecoverCacheKey(tenantId, ruleId)
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := p.upsert(tx, key, set); err != nil {
+			return err
+		}
+		if len(remove) > 0 {
+			return tx.Where("cache_key = ? AND fingerprint IN ?", string(key), remove).Delete(&models.StorePendingRecover{}).Error
+		}
+		return nil
+	})
+}
+
+func (p *PendingRecoverPostgresStore) upsert(tx *gorm.DB, key PendingRecoverCacheKey, set map[string]int64) error {
+	if len(set) == 0 {
+		return nil
+	}
+
+	rows := make([]models.StorePendingRecover, 0, len(set))
+	for fingerprint, t := range set {
+		rows = append(rows, models.StorePendingRecover{
+			CacheKey:    string(key),
+			Fingerprint: fingerprint,
+			Time:        t,
+		})
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cache_key"}, {Name: "fingerprint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"time"}),
+	}).CreateInBatches(rows, 500).Error
+}
@@ -12,7 +12,7 @@ import (
 type (
 	// SilenceCache 用于管理告警静默的缓存操作
 	SilenceCache struct {
-		rc *redis.Client
+		rc redis.UniversalClient
 		sync.RWMutex
 	}
 
@@ -26,7 +26,7 @@ type (
 )
 
 // newSilenceCacheInterface 创建一个新的 SilenceCache 实例
-func newSilenceCacheInterface(r *redis.Client) SilenceCacheInterface {
+func newSilenceCacheInterface(r redis.UniversalClient) SilenceCacheInterface {
 	return &SilenceCache{
 		rc: r,
 	}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis"
+)
+
+type (
+	// SnoozeCache 记录单个事件的暂停通知截止时间, 按故障中心存储在 Redis Hash 中, 重启后仍然生效
+	SnoozeCache struct {
+		rc redis.UniversalClient
+	}
+
+	SnoozeCacheInterface interface {
+		// Set 设置事件暂停通知的截止时间
+		Set(tenantId, faultCenterId, fingerprint string, until int64) error
+		// Get 获取事件暂停通知的截止时间, 未暂停时返回 0
+		Get(tenantId, faultCenterId, fingerprint string) int64
+		// List 获取故障中心下所有事件的暂停通知截止时间
+		List(tenantId, faultCenterId string) (map[string]int64, error)
+		// Delete 取消事件的暂停通知
+		Delete(tenantId, faultCenterId, fingerprint string)
+	}
+)
+
+func newSnoozeCacheInterface(r redis.UniversalClient) SnoozeCacheInterface {
+	return &SnoozeCache{
+		rc: r,
+	}
+}
+
+func (s *SnoozeCache) Set(tenantId, faultCenterId, fingerprint string, until int64) error {
+	return s.rc.HSet(buildSnoozeKey(tenantId, faultCenterId), fingerprint, until).Err()
+}
+
+func (s *SnoozeCache) Get(tenantId, faultCenterId, fingerprint string) int64 {
+	until, err := s.rc.HGet(buildSnoozeKey(tenantId, faultCenterId), fingerprint).Int64()
+	if err != nil {
+		return 0
+	}
+	return until
+}
+
+func (s *SnoozeCache) List(tenantId, faultCenterId string) (map[string]int64, error) {
+	result, err := s.rc.HGetAll(buildSnoozeKey(tenantId, faultCenterId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	snoozes := make(map[string]int64, len(result))
+	for fingerprint, v := range result {
+		until, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		snoozes[fingerprint] = until
+	}
+
+	return snoozes, nil
+}
+
+func (s *SnoozeCache) Delete(tenantId, faultCenterId, fingerprint string) {
+	s.rc.HDel(buildSnoozeKey(tenantId, faultCenterId), fingerprint)
+}
+
+func buildSnoozeKey(tenantId, faultCenterId string) string {
+	return fmt.Sprintf("w8t:%s:snooze:%s", tenantId, faultCenterId)
+}
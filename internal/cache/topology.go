@@ -12,7 +12,7 @@ import (
 
 type (
 	TopologyCache struct {
-		rc *redis.Client
+		rc redis.UniversalClient
 		sync.RWMutex
 	}
 
@@ -24,7 +24,7 @@ type (
 )
 
 // newTopologyCacheInterface 创建一个新的 TopologyCache 实例
-func newTopologyCacheInterface(r *redis.Client) TopologyCacheInterface {
+func newTopologyCacheInterface(r redis.UniversalClient) TopologyCacheInterface {
 	return &TopologyCache{
 		rc: r,
 	}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"watchAlert/internal/cache"
 	"watchAlert/internal/repo"
+	"watchAlert/pkg/monitor"
 )
 
 type Context struct {
@@ -13,30 +14,45 @@ type Context struct {
 	Ctx        context.Context
 	Mux        sync.RWMutex
 	ContextMap map[string]context.CancelFunc
+	Metrics    *monitor.EvalMetrics
 }
 
 var (
-	DB    repo.InterEntryRepo
-	Redis cache.InterEntryCache
-	Ctx   context.Context
+	DB      repo.InterEntryRepo
+	Redis   cache.InterEntryCache
+	Ctx     context.Context
+	Metrics *monitor.EvalMetrics
 )
 
 func NewContext(ctx context.Context, db repo.InterEntryRepo, redis cache.InterEntryCache) *Context {
 	DB = db
 	Redis = redis
 	Ctx = ctx
+	Metrics = monitor.NewEvalMetrics()
 	return &Context{
 		DB:         db,
 		Redis:      redis,
 		Ctx:        ctx,
 		ContextMap: make(map[string]context.CancelFunc),
+		Metrics:    Metrics,
 	}
 }
 
 func DO() *Context {
 	return &Context{
-		DB:    DB,
-		Redis: Redis,
-		Ctx:   Ctx,
+		DB:      DB,
+		Redis:   Redis,
+		Ctx:     Ctx,
+		Metrics: Metrics,
+	}
+}
+
+// WithContext 基于当前上下文派生携带 ctx 的新上下文, 用于传递链路信息, 与 DO() 一样不共享 Mux 及 ContextMap
+func (c *Context) WithContext(ctx context.Context) *Context {
+	return &Context{
+		DB:      c.DB,
+		Redis:   c.Redis,
+		Ctx:     ctx,
+		Metrics: c.Metrics,
 	}
 }
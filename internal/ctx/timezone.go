@@ -0,0 +1,41 @@
+package ctx
+
+import (
+	"sync"
+	"time"
+)
+
+// 租户时区的缓存时间, 修改租户时区后最多延迟该时间生效
+const tenantLocationTTL = time.Minute
+
+type tenantLocation struct {
+	loc      *time.Location
+	loadedAt time.Time
+}
+
+var tenantLocations = struct {
+	sync.Mutex
+	m map[string]tenantLocation
+}{m: make(map[string]tenantLocation)}
+
+// TenantLocation 获取租户配置的时区, 用于周期性时间窗口的判断及通知中的时间格式化, 未配置或无效时使用服务所在时区
+func (c *Context) TenantLocation(tenantId string) *time.Location {
+	if c.DB == nil || tenantId == "" {
+		return time.Local
+	}
+
+	tenantLocations.Lock()
+	defer tenantLocations.Unlock()
+
+	if cached, ok := tenantLocations.m[tenantId]; ok && time.Since(cached.loadedAt) < tenantLocationTTL {
+		return cached.loc
+	}
+
+	loc := time.Local
+	if tenant, err := c.DB.Tenant().Get(tenantId); err == nil {
+		loc = tenant.Location()
+	}
+	tenantLocations.m[tenantId] = tenantLocation{loc: loc, loadedAt: time.Now()}
+
+	return loc
+}
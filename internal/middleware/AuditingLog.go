@@ -10,6 +10,7 @@ import (
 	"time"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
+	"watchAlert/pkg/audit"
 	"watchAlert/pkg/response"
 	"watchAlert/pkg/tools"
 )
@@ -74,5 +75,8 @@ func AuditingLog() gin.HandlerFunc {
 			context.Abort()
 			return
 		}
+
+		// 写入数据库后外发到 SIEM
+		audit.Forward(auditLog)
 	}
 }
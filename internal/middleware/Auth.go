@@ -12,7 +12,11 @@ import (
 	"github.com/zeromicro/go-zero/core/logc"
 )
 
-const ApiKeyHeader = "X-API-Key"
+const (
+	ApiKeyHeader = "X-API-Key"
+	// ApiKeyContextKey 通过 API 密钥认证时, 上下文中存储的密钥信息
+	ApiKeyContextKey = "ApiKey"
+)
 
 func Auth() gin.HandlerFunc {
 
@@ -35,14 +39,37 @@ func Auth() gin.HandlerFunc {
 			context.Set("UserId", userId)
 		} else if apiKey != "" {
 			// 如果没有Token，则尝试API Key认证
-			userId, ok := IsApiKeyValid(ctx.DO(), apiKey)
+			key, ok := IsApiKeyValid(ctx.DO(), apiKey)
 			if !ok {
 				response.TokenFail(context)
 				context.Abort()
 				return
 			}
-			// 将用户ID存储到上下文中，供后续处理使用
-			context.Set("UserId", userId)
+
+			// 配置了权限的密钥仅允许访问指定的接口, 在认证阶段校验, 未挂载 Permission 的路由同样生效
+			if len(key.Permissions) > 0 && !key.HasPermission(context.Request.URL.Path) {
+				response.PermissionFail(context)
+				context.Abort()
+				return
+			}
+
+			// 限定租户的密钥只能访问该租户, 未指定租户时默认使用密钥的租户
+			if key.TenantId != "" {
+				tid := context.Request.Header.Get(TenantIDHeaderKey)
+				if tid == "" {
+					context.Request.Header.Set(TenantIDHeaderKey, key.TenantId)
+				} else if tid != key.TenantId {
+					response.PermissionFail(context)
+					context.Abort()
+					return
+				}
+			}
+
+			// 将所属用户ID存储到上下文中, 未配置权限的密钥继承该用户的角色权限
+			context.Set("UserId", key.UserId)
+			context.Set(ApiKeyContextKey, key)
+			// 写入密钥身份, GetUser / GetUserID 及审计日志以密钥身份记录操作
+			context.Request.Header.Set("Authorization", tools.BuildApiKeyIdentity(key.ID, key.Name))
 		} else {
 			// 如果两者都没有提供
 			response.TokenFail(context)
@@ -92,14 +119,40 @@ func IsTokenValid(ctx *ctx.Context, tokenStr string) bool {
 
 }
 
-// IsApiKeyValid 验证API密钥的有效性
-func IsApiKeyValid(ctx *ctx.Context, apiKey string) (string, bool) {
+// IsApiKeyValid 验证API密钥的有效性, 已过期或已吊销的密钥无效
+func IsApiKeyValid(ctx *ctx.Context, apiKey string) (models.ApiKey, bool) {
 	// 查询数据库中是否存在该API密钥
-	apiKeyModel, exists, err := ctx.DB.ApiKey().GetByKey(apiKey)
+	key, exists, err := ctx.DB.ApiKey().GetByKey(apiKey)
 	if err != nil || !exists {
-		return "无效的API密钥", false
+		return key, false
+	}
+
+	if !key.IsAvailable(time.Now()) {
+		logc.Infof(ctx.Ctx, "API密钥已过期或已吊销, id: %d, name: %s", key.ID, key.Name)
+		return key, false
+	}
+
+	return key, true
+}
+
+// RejectScopedApiKey 拒绝配置了接口权限的API密钥, 避免受限密钥通过密钥管理接口创建权限更大的密钥
+func RejectScopedApiKey() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if key, ok := GetApiKey(context); ok && len(key.Permissions) > 0 {
+			response.PermissionFail(context)
+			context.Abort()
+			return
+		}
+	}
+}
+
+// GetApiKey 获取当前请求认证使用的API密钥
+func GetApiKey(context *gin.Context) (models.ApiKey, bool) {
+	v, exists := context.Get(ApiKeyContextKey)
+	if !exists {
+		return models.ApiKey{}, false
 	}
 
-	// 返回用户ID和成功标志
-	return apiKeyModel.UserId, true
+	key, ok := v.(models.ApiKey)
+	return key, ok
 }
@@ -64,12 +64,19 @@ func Permission() gin.HandlerFunc {
 			return
 		}
 
+		// 用户未加入该租户
+		if tenantUserInfo.UserID == "" {
+			response.PermissionFail(context)
+			context.Abort()
+			return
+		}
+
 		// 获取角色权限
 		var role models.UserRole
 		err = c.DB.DB().Model(&models.UserRole{}).Where("id = ?", tenantUserInfo.UserRole).First(&role).Error
 		if err != nil {
 			errMsg := fmt.Sprintf("获取用户 %s 的角色失败: %s", user.UserName, err.Error())
-			logc.Errorf(c.Ctx, errMsg)
+			logc.Error(c.Ctx, errMsg)
 			response.Fail(context, errMsg, "failed")
 			context.Abort()
 			return
@@ -96,3 +103,43 @@ func hasPermission(permissions []models.UserPermissions, currentPath string) boo
 	}
 	return false
 }
+
+// RequirePermission 校验租户成员身份及细粒度权限, 非租户成员直接拒绝, 租户未给角色配置细粒度权限时不做限制
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		tid := context.Request.Header.Get(TenantIDHeaderKey)
+		userId := context.GetString("UserId")
+		if tid == "" || tid == "null" || userId == "admin" {
+			return
+		}
+
+		// 未挂载 Permission 的路由同样要求用户已加入该租户
+		c := ctx.DO()
+		tenantUserInfo, err := c.DB.Tenant().GetTenantLinkedUserInfo(tid, userId)
+		if err != nil {
+			logc.Errorf(c.Ctx, "获取租户用户角色失败: %s", err.Error())
+			response.PermissionFail(context)
+			context.Abort()
+			return
+		}
+		if userId == "" || tenantUserInfo.UserRole == "" {
+			response.PermissionFail(context)
+			context.Abort()
+			return
+		}
+
+		rp, ok, err := c.DB.UserRole().GetTenantPermission(tid, tenantUserInfo.UserRole)
+		if err != nil {
+			logc.Errorf(c.Ctx, "获取角色细粒度权限失败: %s", err.Error())
+			response.PermissionDenied(context, permission)
+			context.Abort()
+			return
+		}
+
+		if ok && !rp.HasPermission(permission) {
+			response.PermissionDenied(context, permission)
+			context.Abort()
+			return
+		}
+	}
+}
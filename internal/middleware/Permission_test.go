@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	rulePath         = "/api/w8t/rule/ruleList"
+	userCreatePath   = "/api/w8t/user/userCreate"
+	apiKeyCreatePath = "/api/w8t/apikey/create"
+)
+
+// permissionEntryRepo 使用内存数据库存储用户及角色, 租户成员关系由 tenants 提供
+type permissionEntryRepo struct {
+	repo.InterEntryRepo
+	db      *gorm.DB
+	tenants permissionTenantRepo
+	keys    permissionApiKeyRepo
+}
+
+func (r permissionEntryRepo) DB() *gorm.DB                 { return r.db }
+func (r permissionEntryRepo) Tenant() repo.InterTenantRepo { return r.tenants }
+func (r permissionEntryRepo) ApiKey() repo.InterApiKeyRepo { return r.keys }
+
+type permissionApiKeyRepo struct {
+	repo.InterApiKeyRepo
+	keys map[string]models.ApiKey
+}
+
+func (r permissionApiKeyRepo) GetByKey(key string) (models.ApiKey, bool, error) {
+	k, ok := r.keys[key]
+	return k, ok, nil
+}
+
+type permissionTenantRepo struct {
+	repo.InterTenantRepo
+	users map[string][]models.TenantUser
+}
+
+func (r permissionTenantRepo) GetTenantLinkedUserInfo(tenantId, userId string) (models.TenantUser, error) {
+	for _, u := range r.users[tenantId] {
+		if u.UserID == userId {
+			return u, nil
+		}
+	}
+	return models.TenantUser{}, nil
+}
+
+func setupPermission(t *testing.T) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.Member{}, &models.UserRole{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&models.Member{UserId: "u-1", UserName: "viewer", Tenants: []string{"t-1"}})
+	db.Create(&models.UserRole{ID: "viewer", Permissions: []models.UserPermissions{{API: rulePath}}})
+
+	ctx.NewContext(context.Background(), permissionEntryRepo{
+		db: db,
+		tenants: permissionTenantRepo{users: map[string][]models.TenantUser{
+			"t-1": {{UserID: "u-1", UserName: "viewer", UserRole: "viewer"}},
+		}},
+		keys: permissionApiKeyRepo{keys: map[string]models.ApiKey{
+			"scoped": {ID: 1, UserId: "u-1", Name: "scoped", Permissions: []string{rulePath, apiKeyCreatePath}},
+		}},
+	}, nil)
+}
+
+func servePermission(key models.ApiKey, tenantId, path string) int {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("UserId", key.UserId)
+		c.Set(ApiKeyContextKey, key)
+	}, Permission())
+	r.Any(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set(TenantIDHeaderKey, tenantId)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestPermissionApiKeyLimitedByOwnerRole(t *testing.T) {
+	setupPermission(t)
+	key := models.ApiKey{UserId: "u-1", Permissions: []string{rulePath, userCreatePath}}
+
+	if code := servePermission(key, "t-1", rulePath); code != http.StatusOK {
+		t.Fatalf("rule path code = %d, want %d", code, http.StatusOK)
+	}
+	// 密钥配置了仅管理员可访问的接口, 但所属用户的角色没有该权限
+	if code := servePermission(key, "t-1", userCreatePath); code != http.StatusForbidden {
+		t.Fatalf("admin path code = %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestPermissionApiKeyOwnerNotInTenant(t *testing.T) {
+	setupPermission(t)
+	key := models.ApiKey{UserId: "u-1", Permissions: []string{rulePath}}
+
+	if code := servePermission(key, "t-2", rulePath); code != http.StatusForbidden {
+		t.Fatalf("code = %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestAuthApiKeyPermissionsWithoutTenant(t *testing.T) {
+	setupPermission(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Auth())
+	r.Any(rulePath, func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.Any(userCreatePath, func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.Any(apiKeyCreatePath, RejectScopedApiKey(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(ApiKeyHeader, "scoped")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := serve(rulePath); code != http.StatusOK {
+		t.Fatalf("rule path code = %d, want %d", code, http.StatusOK)
+	}
+	// 未携带租户的请求同样只能访问密钥配置的接口
+	if code := serve(userCreatePath); code != http.StatusForbidden {
+		t.Fatalf("user create code = %d, want %d", code, http.StatusForbidden)
+	}
+	// 受限密钥即使配置了密钥管理接口, 也不能创建新的密钥
+	if code := serve(apiKeyCreatePath); code != http.StatusForbidden {
+		t.Fatalf("apikey create code = %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestRequirePermissionDeniesNonMember(t *testing.T) {
+	setupPermission(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("UserId", "u-1") })
+	r.GET("/api/w8t/event/curEvent", RequirePermission(models.PermEventRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// 用户未加入租户 t-2, 不能读取该租户的事件
+	req := httptest.NewRequest(http.MethodGet, "/api/w8t/event/curEvent", nil)
+	req.Header.Set(TenantIDHeaderKey, "t-2")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("code = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
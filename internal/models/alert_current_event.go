@@ -10,9 +10,17 @@ import (
 type AlertStatus string
 
 // 所有可能的状态
+//
+//	pre_alert ──> alerting <──> pending_recovery ──> recovered ──> pre_alert
+//	                 ↑ ↓
+//	             suppressed
+//
+// 预告警持续满足条件达到持续时间后转为告警中; 告警中的事件命中静默或抑制规则时转为已抑制,
+// 已抑制的事件不发送通知且不参与恢复判断, 静默或抑制结束后回到告警中再按正常流程恢复
 const (
 	StatePreAlert        AlertStatus = "pre_alert"        // 预告警
 	StateAlerting        AlertStatus = "alerting"         // 告警中
+	StateSuppressed      AlertStatus = "suppressed"       // 已抑制
 	StatePendingRecovery AlertStatus = "pending_recovery" // 待恢复
 	StateRecovered       AlertStatus = "recovered"        // 已恢复
 )
@@ -33,24 +41,50 @@ type AlertCurEvent struct {
 	ForDuration          int64                  `json:"for_duration"`
 	Annotations          string                 `json:"annotations" gorm:"-"`
 	IsRecovered          bool                   `json:"is_recovered" gorm:"-"`
-	FirstTriggerTime     int64                  `json:"first_trigger_time"`      // 第一次触发时间
-	RepeatNoticeInterval int64                  `json:"repeat_notice_interval"`  // 重复通知间隔时间
-	LastEvalTime         int64                  `json:"last_eval_time" gorm:"-"` // 上一次评估时间
-	LastSendTime         int64                  `json:"last_send_time" gorm:"-"` // 上一次发送时间
-	RecoverTime          int64                  `json:"recover_time" gorm:"-"`   // 恢复时间
+	FirstTriggerTime     int64                  `json:"first_trigger_time"`         // 第一次触发时间
+	LastTriggerTime      int64                  `json:"last_trigger_time" gorm:"-"` // 最近一次满足告警条件的时间
+	Count                int64                  `json:"count" gorm:"-"`             // 满足告警条件的评估次数
+	RepeatNoticeInterval int64                  `json:"repeat_notice_interval"`     // 重复通知间隔时间
+	LastEvalTime         int64                  `json:"last_eval_time" gorm:"-"`    // 上一次评估时间
+	LastSendTime         int64                  `json:"last_send_time" gorm:"-"`    // 上一次发送时间
+	RecoverTime          int64                  `json:"recover_time" gorm:"-"`      // 恢复时间
 	DutyUser             string                 `json:"duty_user" gorm:"-"`
 	EffectiveTime        EffectiveTime          `json:"effectiveTime" gorm:"effectiveTime;serializer:json"`
 	FaultCenterId        string                 `json:"faultCenterId"`
 	FaultCenter          FaultCenter            `json:"faultCenter" gorm:"-"`
 	ConfirmState         ConfirmState           `json:"confirmState" gorm:"-"`
-	Status               AlertStatus            `json:"status" gorm:"-"` // 事件状态
+	IsSuppressed         bool                   `json:"isSuppressed" gorm:"-"` // 是否已手动抑制, 抑制后不再发送通知但仍正常恢复, 与会自动结束的 StateSuppressed 状态相互独立
+	IsSilenced           bool                   `json:"isSilenced" gorm:"-"`   // 是否命中静默规则, 仅用于列表展示
+	SilenceId            string                 `json:"silenceId,omitempty" gorm:"-"`
+	Maintenance          bool                   `json:"maintenance" gorm:"-"` // 是否处于故障中心维护窗口内
+	EscalationState      EscalationState        `json:"escalationState" gorm:"-"`
+	IsInhibited          bool                   `json:"isInhibited" gorm:"-"`                // 是否被抑制规则抑制, 源事件恢复后自动解除
+	ExtraAnnotations     map[string]string      `json:"extraAnnotations,omitempty" gorm:"-"` // 人工补充的注解, 不参与指纹计算
+	IncidentKey          string                 `json:"incidentKey,omitempty" gorm:"-"`      // 外部事件平台(PagerDuty)返回的 incident key
+	AckOwner             string                 `json:"ackOwner,omitempty" gorm:"-"`         // 认领人, 认领未过期时返回, 仅用于列表展示
+	SnoozeUntil          int64                  `json:"snoozeUntil,omitempty" gorm:"-"`      // 暂停通知截止时间, 仅用于列表展示
+	IsFlapping           bool                   `json:"flapping" gorm:"-"`                   // 是否处于抖动状态, 由消费者每轮计算, 抖动期间暂停通知
+	QueryLink            string                 `json:"queryLink,omitempty" gorm:"-"`        // 跳转到 Grafana/Prometheus 的查询链接, 事件产生时按链接模版生成
+	Status               AlertStatus            `json:"status" gorm:"-"`                     // 事件状态
+}
+
+// EscalationState 多级升级进度, 随事件存储在 Redis 中, 重启后继续升级
+type EscalationState struct {
+	Level            int   `json:"level"`            // 已升级到的级别, 0 表示未升级
+	LastEscalateTime int64 `json:"lastEscalateTime"` // 最近一次升级时间
 }
 
 type ConfirmState struct {
 	IsOk                   bool   `json:"isOk"`                   // 是否已认领
 	ConfirmActionTime      int64  `json:"confirmActionTime"`      // 点击认领时间
 	ConfirmTimeoutSendTime int64  `json:"confirmTimeoutSendTime"` // 认领超时通知时间
-	ConfirmUsername        string `json:"confirmUsername"`
+	ConfirmUsername        string `json:"confirmUsername"`        // 认领人
+	ConfirmExpireTime      int64  `json:"confirmExpireTime"`      // 认领过期时间, 为 0 时不过期
+}
+
+// IsAcknowledged 是否处于认领状态, 认领过期后视为未认领, 重新参与认领超时通知及多级升级
+func (c ConfirmState) IsAcknowledged(now int64) bool {
+	return c.IsOk && (c.ConfirmExpireTime == 0 || now < c.ConfirmExpireTime)
 }
 
 const (
@@ -58,6 +92,13 @@ const (
 	SortOrderDesc string = "descend"
 )
 
+// 活跃告警列表的排序字段, 为空时按持续时间排序
+const (
+	SortBySeverity string = "severity" // 告警等级, 升序时 P0 在前
+	SortByLastSeen string = "lastSeen" // 最近一次评估时间
+	SortByStatus   string = "status"   // 事件状态, 升序时告警中在前
+)
+
 func (alert *AlertCurEvent) TransitionStatus(newStatus AlertStatus) error {
 	// 相同状态不需要转换
 	if alert.Status == newStatus {
@@ -84,7 +125,8 @@ func (alert *AlertCurEvent) validateTransition(newState AlertStatus) error {
 	// 定义允许的状态转换规则
 	allowedTransitions := map[AlertStatus][]AlertStatus{
 		StatePreAlert:        {StateAlerting},
-		StateAlerting:        {StatePendingRecovery},
+		StateAlerting:        {StatePendingRecovery, StateSuppressed},
+		StateSuppressed:      {StateAlerting},
 		StatePendingRecovery: {StateAlerting, StateRecovered},
 		StateRecovered:       {StatePreAlert},
 	}
@@ -142,9 +184,17 @@ func (e StateTransitionError) Error() string {
 	return fmt.Sprintf("invalid transition from %s to %s: %s", e.FromState, e.ToState, e.Reason)
 }
 
-// IsArriveForDuration 比对持续时间
-func (alert *AlertCurEvent) IsArriveForDuration() bool {
-	return alert.LastEvalTime-alert.FirstTriggerTime > alert.ForDuration
+// IsArriveForDuration 比对持续时间, pendingAt 为条件首次满足的时间
+func (alert *AlertCurEvent) IsArriveForDuration(pendingAt int64) bool {
+	return alert.LastEvalTime-pendingAt > alert.ForDuration
+}
+
+// GetRepeatNoticeInterval 获取重复通知间隔（分钟）, 优先使用规则上的配置, 未配置时使用故障中心的配置
+func (alert *AlertCurEvent) GetRepeatNoticeInterval(faultCenterInterval int64) int64 {
+	if alert.RepeatNoticeInterval > 0 {
+		return alert.RepeatNoticeInterval
+	}
+	return faultCenterInterval
 }
 
 // GetLastSendTime 获取故障中心事件的最后发送时间
@@ -165,6 +215,12 @@ func (alert *AlertCurEvent) GetFirstTime() int64 {
 	return alert.FirstTriggerTime
 }
 
+// MarkTriggered 标记本次评估满足告警条件, 推送至故障中心时与缓存中的次数累加
+func (alert *AlertCurEvent) MarkTriggered() {
+	alert.Count = 1
+	alert.LastTriggerTime = time.Now().Unix()
+}
+
 // GetLastConfirmState 获取最新告警升级认领状态
 func (alert *AlertCurEvent) GetLastConfirmState() ConfirmState {
 	return alert.ConfirmState
@@ -1,14 +1,14 @@
 package models
 
 type AlertHisEvent struct {
-	TenantId         string                 `json:"tenantId"`
+	TenantId         string                 `json:"tenantId" gorm:"size:64;index:idx_his_event_tenant_center"`
 	EventId          string                 `json:"eventId"`
 	DatasourceId     string                 `json:"datasource_id" gorm:"datasource_id"`
 	DatasourceType   string                 `json:"datasource_type"`
 	Fingerprint      string                 `json:"fingerprint"`
 	RuleGroupId      string                 `json:"rule_group_id"`
 	RuleId           string                 `json:"rule_id"`
-	RuleName         string                 `json:"rule_name"`
+	RuleName         string                 `json:"rule_name" gorm:"size:255;index"`
 	Severity         string                 `json:"severity"`
 	Labels           map[string]interface{} `json:"labels" gorm:"labels;serializer:json"`
 	EvalInterval     int64                  `json:"eval_interval"`
@@ -17,8 +17,10 @@ type AlertHisEvent struct {
 	LastEvalTime     int64                  `json:"last_eval_time"`     // 最近评估时间
 	LastSendTime     int64                  `json:"last_send_time"`     // 最近发送时间
 	RecoverTime      int64                  `json:"recover_time"`       // 恢复时间
-	FaultCenterId    string                 `json:"faultCenterId"`
+	FaultCenterId    string                 `json:"faultCenterId" gorm:"size:64;index:idx_his_event_tenant_center"`
 	ConfirmState     ConfirmState           `json:"confirmState" gorm:"metric;serializer:json"`
 	AlarmDuration    int64                  `json:"alarmDuration"` // 告警持续时长
 	SearchQL         string                 `json:"searchQL"`
+	ExtraAnnotations map[string]string      `json:"extraAnnotations" gorm:"extraAnnotations;serializer:json"` // 人工补充的注解
+	IncidentKey      string                 `json:"incidentKey"`                                              // 外部事件平台(PagerDuty)返回的 incident key
 }
@@ -8,9 +8,31 @@ type ApiKey struct {
 	Name        string    `json:"name" gorm:"column:name;size:255;not null"`
 	Description string    `json:"description" gorm:"column:description;size:500"`
 	Key         string    `json:"key" gorm:"column:key;size:255;not null;uniqueIndex"`
+	TenantId    string    `json:"tenantId" gorm:"column:tenant_id"`                      // 限定访问的租户, 为空时不限制
+	Permissions []string  `json:"permissions" gorm:"column:permissions;serializer:json"` // 允许访问的接口, 为空时继承所属用户的角色权限
+	ExpiresAt   int64     `json:"expiresAt" gorm:"column:expires_at"`                    // 过期时间, 为 0 时永不过期
+	RevokedAt   int64     `json:"revokedAt" gorm:"column:revoked_at"`                    // 吊销时间, 吊销后立即失效
 	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
 }
 
 func (ApiKey) TableName() string {
 	return "w8t_api_keys"
 }
+
+// IsAvailable 是否未过期且未吊销
+func (a ApiKey) IsAvailable(now time.Time) bool {
+	if a.RevokedAt > 0 {
+		return false
+	}
+	return a.ExpiresAt == 0 || now.Unix() < a.ExpiresAt
+}
+
+// HasPermission 是否允许访问接口
+func (a ApiKey) HasPermission(api string) bool {
+	for _, p := range a.Permissions {
+		if p == api {
+			return true
+		}
+	}
+	return false
+}
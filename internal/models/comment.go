@@ -18,4 +18,14 @@ type Comment struct {
 	Time int64 `json:"time"`
 	// 内容
 	Content string `json:"content"`
+	// 最近编辑时间, 未编辑时为 0
+	UpdatedAt int64 `json:"updatedAt"`
+	// 评论中 @ 提及的用户
+	Mentions []CommentMention `json:"mentions" gorm:"mentions;serializer:json"`
+}
+
+// CommentMention 评论中 @ 提及并成功解析的用户
+type CommentMention struct {
+	UserId   string `json:"userId"`
+	Username string `json:"username"`
 }
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type AlertDataSource struct {
 	TenantId         string                 `json:"tenantId"`
 	ID               string                 `json:"id"`
@@ -12,8 +17,13 @@ type AlertDataSource struct {
 	DsAliCloudConfig DsAliCloudConfig       `json:"dsAliCloudConfig" gorm:"dsAliCloudConfig;serializer:json"`
 	AWSCloudWatch    AWSCloudWatch          `json:"awsCloudwatch" gorm:"awsCloudwatch;serializer:json"`
 	ClickHouseConfig DsClickHouseConfig     `json:"clickhouseConfig" gorm:"clickhouseConfig;serializer:json"`
+	InfluxDBConfig   DsInfluxDBConfig       `json:"influxdbConfig" gorm:"influxdbConfig;serializer:json"`
+	DatadogConfig    DsDatadogConfig        `json:"datadogConfig" gorm:"datadogConfig;serializer:json"`
 	Description      string                 `json:"description"`
 	KubeConfig       string                 `json:"kubeConfig"`
+	QueryTimeout     int64                  `json:"queryTimeout"`  // 告警评估时查询数据源的超时时间（秒）
+	QueryCacheTTL    int64                  `json:"queryCacheTTL"` // 查询结果缓存时间（秒），多个规则在缓存时间内的相同查询复用结果，为 0 时不缓存
+	LinkTemplate     string                 `json:"linkTemplate"`  // 查询链接模版, 用于生成从事件跳转到 Grafana/Prometheus 的链接, 规则未配置时使用
 	UpdateBy         string                 `json:"updateBy"`
 	UpdateAt         int64                  `json:"updateAt"`
 	Enabled          *bool                  `json:"enabled" `
@@ -28,6 +38,21 @@ type HTTP struct {
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
 	Timeout int64             `json:"timeout"`
+	TLS     TLS               `json:"tls"`
+	Proxy   string            `json:"proxy"` // 代理地址, 为空时使用 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量
+}
+
+// TLS 数据源 HTTPS 连接配置, 证书及私钥均为 PEM 格式内容
+type TLS struct {
+	CACert             string `json:"caCert"`
+	ClientCert         string `json:"clientCert"`
+	ClientKey          string `json:"clientKey"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+}
+
+// IsEnabled 是否配置了自定义 TLS
+func (t TLS) IsEnabled() bool {
+	return t.CACert != "" || t.ClientCert != "" || t.ClientKey != "" || t.InsecureSkipVerify
 }
 
 type Auth struct {
@@ -36,8 +61,28 @@ type Auth struct {
 }
 
 type DsClickHouseConfig struct {
-	Addr    string
-	Timeout int64
+	Addr        string
+	Timeout     int64
+	Protocol    string `json:"protocol"`    // 连接协议: native / http, 为空时使用 native 保持兼容
+	Compression string `json:"compression"` // 压缩算法: none / lz4 / zstd, 为空时不压缩
+	ReadTimeout int64  `json:"readTimeout"` // 读取超时时间(秒), 为空时使用驱动默认值
+}
+
+const (
+	ClickHouseProtocolNative = "native"
+	ClickHouseProtocolHTTP   = "http"
+)
+
+type DsInfluxDBConfig struct {
+	Org    string `json:"org"`
+	Bucket string `json:"bucket"`
+	Token  string `json:"token"`
+}
+
+// DsDatadogConfig Datadog 数据源认证, 站点地址使用 HTTP.URL, 为空时使用 https://api.datadoghq.com
+type DsDatadogConfig struct {
+	ApiKey string `json:"apiKey"`
+	AppKey string `json:"appKey"`
 }
 
 type DsAliCloudConfig struct {
@@ -48,9 +93,26 @@ type DsAliCloudConfig struct {
 
 type AWSCloudWatch struct {
 	//Endpoint  string `json:"endpoint"`
-	Region    string `json:"region"`
-	AccessKey string `json:"accessKey"`
-	SecretKey string `json:"secretKey"`
+	Region     string   `json:"region"`
+	Regions    []string `json:"regions"` // 多个区域, 评估时依次查询, 为空时使用 Region
+	AccessKey  string   `json:"accessKey"`
+	SecretKey  string   `json:"secretKey"`
+	RoleArn    string   `json:"roleArn"`    // 跨账号角色, 配置后通过 STS 扮演该角色查询
+	ExternalId string   `json:"externalId"` // 扮演角色时的 External ID
+}
+
+// GetRegions 获取需要查询的区域, Region 排在首位
+func (a AWSCloudWatch) GetRegions() []string {
+	var regions []string
+	if a.Region != "" {
+		regions = append(regions, a.Region)
+	}
+	for _, region := range a.Regions {
+		if region != "" && !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
 }
 
 //type PromQueryRes struct {
@@ -67,6 +129,17 @@ type AWSCloudWatch struct {
 //	Value  []interface{}          `json:"value"`
 //}
 
+// DefaultQueryTimeout 默认查询超时时间（秒）
+const DefaultQueryTimeout = 30
+
+// GetQueryTimeout 获取告警评估时查询数据源的超时时间
+func (d *AlertDataSource) GetQueryTimeout() time.Duration {
+	if d.QueryTimeout <= 0 {
+		return DefaultQueryTimeout * time.Second
+	}
+	return time.Duration(d.QueryTimeout) * time.Second
+}
+
 func (d *AlertDataSource) GetEnabled() bool {
 	if d.Enabled == nil {
 		isOk := false
@@ -1,5 +1,7 @@
 package models
 
+import "fmt"
+
 type DutyManagement struct {
 	TenantId    string     `json:"tenantId"`
 	ID          string     `json:"id"`
@@ -7,8 +9,45 @@ type DutyManagement struct {
 	Manager     DutyUser   `json:"manager" gorm:"manager;serializer:json"`
 	Description string     `json:"description"`
 	CurDutyUser []DutyUser `json:"curDutyUser" gorm:"curDutyUser;serializer:json"`
-	UpdateBy    string     `json:"updateBy"`
-	UpdateAt    int64      `json:"updateAt"`
+	// 按值班人员偏好渠道通知时使用的通知模版
+	NoticeTmplId string `json:"noticeTmplId"`
+	// 临时换班, 生效期间替代值班表中的值班人员
+	Overrides []DutyOverride `json:"overrides" gorm:"overrides;serializer:json"`
+	UpdateBy  string         `json:"updateBy"`
+	UpdateAt  int64          `json:"updateAt"`
+}
+
+// DutyOverride 临时换班, 精确到秒, 适用于按小时调整值班人员的场景
+type DutyOverride struct {
+	UserId   string `json:"userid"`
+	Username string `json:"username"`
+	StartsAt int64  `json:"startsAt"`
+	EndsAt   int64  `json:"endsAt"`
+	Reason   string `json:"reason"`
+}
+
+// Validate 校验临时换班配置
+func (d DutyManagement) Validate() error {
+	for _, o := range d.Overrides {
+		if o.UserId == "" {
+			return fmt.Errorf("换班人员不能为空")
+		}
+		if o.EndsAt <= o.StartsAt {
+			return fmt.Errorf("换班人员 %s 的结束时间必须晚于开始时间", o.Username)
+		}
+	}
+	return nil
+}
+
+// GetActiveOverrides 获取当前生效的临时换班
+func (d DutyManagement) GetActiveOverrides(now int64) []DutyOverride {
+	var overrides []DutyOverride
+	for _, o := range d.Overrides {
+		if now >= o.StartsAt && now < o.EndsAt {
+			overrides = append(overrides, o)
+		}
+	}
+	return overrides
 }
 
 type CalendarStatus string
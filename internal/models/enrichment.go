@@ -0,0 +1,71 @@
+package models
+
+import "fmt"
+
+const (
+	EnrichmentTypeHTTP    = "http"
+	EnrichmentTypeMapping = "mapping"
+)
+
+// EventEnrichment 事件富化, 按 instance/service 等标签查询 CMDB 或本地映射表, 将返回的归属信息合并到事件标签中
+type EventEnrichment struct {
+	Enabled      *bool                        `json:"enabled"`
+	Type         string                       `json:"type"`         // http 或 mapping
+	Url          string                       `json:"url"`          // HTTP 查询地址, 以 GET 请求携带 label 及 value 参数, 返回 JSON 对象
+	Headers      map[string]string            `json:"headers"`      // HTTP 请求头
+	LookupLabels []string                     `json:"lookupLabels"` // 用于查询的标签, 按顺序取第一个存在的标签, 为空时使用 instance、service
+	Mapping      map[string]map[string]string `json:"mapping"`      // 本地映射表, key 为查询标签的值
+	Timeout      int64                        `json:"timeout"`      // HTTP 查询超时，单位（秒），默认 3
+	CacheTTL     int64                        `json:"cacheTTL"`     // 查询结果缓存时间，单位（秒），默认 300
+}
+
+func (e EventEnrichment) GetEnabled() bool {
+	if e.Enabled == nil {
+		return false
+	}
+	return *e.Enabled
+}
+
+// GetLookupLabels 获取用于查询的标签
+func (e EventEnrichment) GetLookupLabels() []string {
+	if len(e.LookupLabels) == 0 {
+		return []string{"instance", "service"}
+	}
+	return e.LookupLabels
+}
+
+func (e EventEnrichment) GetTimeout() int {
+	if e.Timeout <= 0 {
+		return 3
+	}
+	return int(e.Timeout)
+}
+
+func (e EventEnrichment) GetCacheTTL() int64 {
+	if e.CacheTTL <= 0 {
+		return 300
+	}
+	return e.CacheTTL
+}
+
+// Validate 校验事件富化配置
+func (e EventEnrichment) Validate() error {
+	if !e.GetEnabled() {
+		return nil
+	}
+
+	switch e.Type {
+	case EnrichmentTypeHTTP:
+		if e.Url == "" {
+			return fmt.Errorf("事件富化的查询地址不能为空")
+		}
+	case EnrichmentTypeMapping:
+		if len(e.Mapping) == 0 {
+			return fmt.Errorf("事件富化的映射表不能为空")
+		}
+	default:
+		return fmt.Errorf("不支持的事件富化类型: %s", e.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+// StoreAlertEvent 数据库存储后端中的当前事件, 按缓存 Key + 指纹存储, 与 Redis Hash 的语义一致
+type StoreAlertEvent struct {
+	CacheKey    string `gorm:"column:cache_key;primaryKey;size:255"`
+	Fingerprint string `gorm:"column:fingerprint;primaryKey;size:255"`
+	RuleId      string `gorm:"column:rule_id;size:64;index"`
+	Event       string `gorm:"column:event;type:text"`
+}
+
+func (StoreAlertEvent) TableName() string { return "w8t_store_alert_events" }
+
+// StorePendingRecover 数据库存储后端中待恢复指纹的时间戳
+type StorePendingRecover struct {
+	CacheKey    string `gorm:"column:cache_key;primaryKey;size:255"`
+	Fingerprint string `gorm:"column:fingerprint;primaryKey;size:255"`
+	Time        int64  `gorm:"column:time"`
+}
+
+func (StorePendingRecover) TableName() string { return "w8t_store_pending_recovers" }
+
+// StoreFaultCenterInfo 数据库存储后端中的故障中心信息
+type StoreFaultCenterInfo struct {
+	CacheKey string `gorm:"column:cache_key;primaryKey;size:255"`
+	Info     string `gorm:"column:info;type:text"`
+}
+
+func (StoreFaultCenterInfo) TableName() string { return "w8t_store_fault_center_infos" }
@@ -0,0 +1,14 @@
+package models
+
+// 活跃告警事件的变更类型
+const (
+	EventChangeCreate  = "create"  // 新产生的事件
+	EventChangeUpdate  = "update"  // 事件状态变化
+	EventChangeRecover = "recover" // 事件已恢复
+)
+
+// EventChange 活跃告警事件的变更, 由告警评估写入后通过 Redis 消息推送给事件流的订阅者
+type EventChange struct {
+	Action string        `json:"action"`
+	Event  AlertCurEvent `json:"event"`
+}
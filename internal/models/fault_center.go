@@ -3,6 +3,10 @@ package models
 import (
 	"fmt"
 	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 // 常量定义
@@ -12,23 +16,196 @@ const (
 )
 
 type FaultCenter struct {
-	TenantId              string          `json:"tenantId"`
-	ID                    string          `json:"id"`
-	Name                  string          `json:"name"`
-	Description           string          `json:"description"`
-	NoticeIds             []string        `json:"noticeIds" gorm:"column:noticeIds;serializer:json"`
-	NoticeRoutes          []NoticeRoute   `json:"noticeRoutes" gorm:"noticeRoutes;serializer:json"`
-	RepeatNoticeInterval  int64           `json:"repeatNoticeInterval"`
-	RecoverNotify         *bool           `json:"recoverNotify"`
-	AggregationType       string          `json:"aggregationType"`
-	CreateAt              int64           `json:"createAt"`
-	RecoverWaitTime       int64           `json:"recoverWaitTime"` // 告警恢复等待时间，单位（秒）
-	CurrentPreAlertNumber int64           `json:"currentPreAlertNumber" gorm:"-"`
-	CurrentAlertNumber    int64           `json:"currentAlertNumber" gorm:"-"`
-	CurrentRecoverNumber  int64           `json:"currentRecoverNumber" gorm:"-"`
-	IsUpgradeEnabled      *bool           `json:"isUpgradeEnabled" gorm:"column:isUpgradeEnabled"`
-	UpgradableSeverity    []string        `json:"upgradableSeverity" gorm:"column:upgradableSeverity;serializer:json"`
-	UpgradeStrategy       UpgradeStrategy `json:"upgradeStrategy" gorm:"column:upgradeStrategy;serializer:json"`
+	TenantId              string              `json:"tenantId"`
+	ID                    string              `json:"id"`
+	Name                  string              `json:"name"`
+	Description           string              `json:"description"`
+	NoticeIds             []string            `json:"noticeIds" gorm:"column:noticeIds;serializer:json"`
+	NoticeRoutes          []NoticeRoute       `json:"noticeRoutes" gorm:"noticeRoutes;serializer:json"`
+	RepeatNoticeInterval  int64               `json:"repeatNoticeInterval"` // 告警中事件的重复通知间隔，单位（分钟），规则配置时以规则为准
+	RecoverNotify         *bool               `json:"recoverNotify"`
+	AggregationType       string              `json:"aggregationType"`
+	CreateAt              int64               `json:"createAt"`
+	RecoverWaitTime       int64               `json:"recoverWaitTime"` // 告警恢复等待时间，单位（秒）
+	CurrentPreAlertNumber int64               `json:"currentPreAlertNumber" gorm:"-"`
+	CurrentAlertNumber    int64               `json:"currentAlertNumber" gorm:"-"`
+	CurrentRecoverNumber  int64               `json:"currentRecoverNumber" gorm:"-"`
+	IsUpgradeEnabled      *bool               `json:"isUpgradeEnabled" gorm:"column:isUpgradeEnabled"`
+	UpgradableSeverity    []string            `json:"upgradableSeverity" gorm:"column:upgradableSeverity;serializer:json"`
+	UpgradeStrategy       UpgradeStrategy     `json:"upgradeStrategy" gorm:"column:upgradeStrategy;serializer:json"`
+	MaintenanceWindows    []MaintenanceWindow `json:"maintenanceWindows" gorm:"column:maintenanceWindows;serializer:json"`
+	GroupBy               []string            `json:"groupBy" gorm:"column:groupBy;serializer:json"` // 按标签分组, 相同分组的事件合并为一条通知
+	GroupWait             int64               `json:"groupWait"`                                     // 新分组首次通知前的等待时间，单位（秒）
+	GroupInterval         int64               `json:"groupInterval"`                                 // 分组内新增事件后再次通知的最小间隔，单位（秒）
+	EscalationPolicy      EscalationPolicy    `json:"escalationPolicy" gorm:"column:escalationPolicy;serializer:json"`
+	DutyIds               []string            `json:"dutyIds" gorm:"column:dutyIds;serializer:json"` // 按值班表通知, 发送至当前值班人员的偏好渠道
+	NoticeDedup           NoticeDedup         `json:"noticeDedup" gorm:"column:noticeDedup;serializer:json"`
+	InhibitRules          []InhibitRule       `json:"inhibitRules" gorm:"column:inhibitRules;serializer:json"`
+	Enrichment            EventEnrichment     `json:"enrichment" gorm:"column:enrichment;serializer:json"`
+	FlappingDetection     FlappingDetection   `json:"flappingDetection" gorm:"column:flappingDetection;serializer:json"`
+}
+
+// InhibitRule 抑制规则, 存在匹配 SourceMatchers 的告警中事件时, 抑制匹配 TargetMatchers 且 Equal 标签值相同的事件通知
+type InhibitRule struct {
+	SourceMatchers []SilenceLabel `json:"sourceMatchers"`
+	TargetMatchers []SilenceLabel `json:"targetMatchers"`
+	Equal          []string       `json:"equal"` // 源事件与目标事件需要相同的标签
+}
+
+// Validate 校验抑制规则的匹配条件
+func (i InhibitRule) Validate() error {
+	if err := validateLabelMatchers("抑制规则源事件", i.SourceMatchers); err != nil {
+		return err
+	}
+	return validateLabelMatchers("抑制规则目标事件", i.TargetMatchers)
+}
+
+// NoticeDedup 通知去重, 窗口内同一渠道的相同通知只发送一次
+type NoticeDedup struct {
+	Window int64    `json:"window"` // 去重窗口，单位（秒），为 0 时不去重
+	Labels []string `json:"labels"` // 参与去重的标签, 为空时按渲染后的通知内容去重
+}
+
+// FlappingDetection 抖动检测, 窗口内事件状态变化次数达到阈值时标记为抖动并暂停通知, 次数回落到阈值以下后恢复通知
+type FlappingDetection struct {
+	Window    int64 `json:"window"`    // 统计窗口，单位（分钟），为 0 时不检测
+	Threshold int64 `json:"threshold"` // 窗口内状态变化次数的阈值, 告警及恢复各计一次
+}
+
+func (f FlappingDetection) IsEnabled() bool {
+	return f.Window > 0 && f.Threshold > 0
+}
+
+// Validate 校验抖动检测配置
+func (f FlappingDetection) Validate() error {
+	if f.Window < 0 || f.Threshold < 0 {
+		return fmt.Errorf("抖动检测的窗口及阈值不能小于 0")
+	}
+	if f.Window > 0 && f.Threshold < 2 {
+		return fmt.Errorf("抖动检测的阈值不能小于 2")
+	}
+	return nil
+}
+
+// MaintenanceWindow 周期性维护窗口, 窗口内事件状态正常流转但不发送通知
+type MaintenanceWindow struct {
+	Name     string `json:"name"`
+	Cron     string `json:"cron"`     // 窗口开始时间, 标准 cron 表达式, 例如 "0 2 * * 0" 表示每周日 02:00
+	Duration int64  `json:"duration"` // 窗口持续时间, 单位（分钟）
+	Timezone string `json:"timezone"` // IANA 时区名称, 例如 Asia/Shanghai, 为空时使用租户的时区
+}
+
+func (m MaintenanceWindow) schedule() (cron.Schedule, error) {
+	spec := m.Cron
+	if m.Timezone != "" {
+		if _, err := time.LoadLocation(m.Timezone); err != nil {
+			return nil, fmt.Errorf("无效的时区: %s", m.Timezone)
+		}
+		spec = "CRON_TZ=" + m.Timezone + " " + spec
+	}
+
+	return cron.ParseStandard(spec)
+}
+
+// Validate 校验维护窗口配置
+func (m MaintenanceWindow) Validate() error {
+	if m.Duration <= 0 {
+		return fmt.Errorf("维护窗口 %s 的持续时间必须大于 0", m.Name)
+	}
+
+	if _, err := m.schedule(); err != nil {
+		return fmt.Errorf("维护窗口 %s 的 cron 表达式无效: %s", m.Name, err.Error())
+	}
+
+	return nil
+}
+
+// IsActive 判断指定时间是否处于维护窗口内, 未配置时区的窗口按 now 所在时区计算
+func (m MaintenanceWindow) IsActive(now time.Time) bool {
+	if m.Duration <= 0 {
+		return false
+	}
+
+	sched, err := m.schedule()
+	if err != nil {
+		return false
+	}
+
+	// 在 [now-duration, now] 区间内存在窗口开始时间, 即处于窗口内
+	duration := time.Duration(m.Duration) * time.Minute
+	return !sched.Next(now.Add(-duration)).After(now)
+}
+
+// InMaintenance 判断故障中心当前是否处于任一维护窗口内
+func (f FaultCenter) InMaintenance(now time.Time) bool {
+	for _, window := range f.MaintenanceWindows {
+		if window.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGroupByEnabled 是否启用按标签分组通知
+func (f FaultCenter) IsGroupByEnabled() bool {
+	return len(f.GroupBy) > 0
+}
+
+// BuildGroupKey 按 GroupBy 标签生成事件的分组 Key, 缺失的标签视为空值
+func (f FaultCenter) BuildGroupKey(labels map[string]interface{}) string {
+	pairs := make([]string, 0, len(f.GroupBy))
+	for _, key := range f.GroupBy {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, labels[key]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// EscalationPolicy 多级升级策略, 告警事件在指定时间内未被认领时逐级通知
+type EscalationPolicy struct {
+	Enabled   *bool            `json:"enabled"`
+	Severitys []string         `json:"severitys"` // 参与升级的告警等级, 为空时所有等级均参与
+	Steps     []EscalationStep `json:"steps"`
+}
+
+type EscalationStep struct {
+	Timeout  int64  `json:"timeout"`  // 自首次触发起未认领的时长，单位（分钟）
+	NoticeId string `json:"noticeId"` // 本级通知对象ID
+}
+
+func (e EscalationPolicy) GetEnabled() bool {
+	if e.Enabled == nil {
+		return false
+	}
+	return *e.Enabled
+}
+
+// IsMatchSeverity 判断告警等级是否参与升级
+func (e EscalationPolicy) IsMatchSeverity(severity string) bool {
+	return len(e.Severitys) == 0 || slices.Contains(e.Severitys, severity)
+}
+
+// Validate 校验升级策略, 各级超时时间需大于 0 且递增
+func (e EscalationPolicy) Validate() error {
+	if !e.GetEnabled() {
+		return nil
+	}
+
+	if len(e.Steps) == 0 {
+		return fmt.Errorf("升级策略至少需要配置一个升级步骤")
+	}
+
+	var lastTimeout int64
+	for i, step := range e.Steps {
+		if step.NoticeId == "" {
+			return fmt.Errorf("第 %d 级升级的通知对象不能为空", i+1)
+		}
+		if step.Timeout <= lastTimeout {
+			return fmt.Errorf("第 %d 级升级的超时时间必须大于 0 且大于上一级", i+1)
+		}
+		lastTimeout = step.Timeout
+	}
+
+	return nil
 }
 
 type UpgradeStrategy struct {
@@ -38,10 +215,43 @@ type UpgradeStrategy struct {
 	NoticeId       string `json:"noticeId"`       // 通知对象ID
 }
 
+// NoticeRoute 通知路由, 同级路由按顺序匹配, 命中后使用该路由的通知对象, 未设置 Continue 时停止匹配后续同级路由;
+// 命中的路由存在子路由时继续在子路由中匹配, 子路由均未命中时使用当前路由的通知对象
 type NoticeRoute struct {
-	Key       string   `json:"key"`
-	Value     string   `json:"value"`
-	NoticeIds []string `json:"noticeIds" gorm:"column:noticeIds;serializer:json"`
+	// Key/Value 为旧版配置, 等价于 Key =~ Value 的匹配条件
+	Key       string         `json:"key"`
+	Value     string         `json:"value"`
+	Matchers  []SilenceLabel `json:"matchers"` // 匹配条件, 需全部满足
+	NoticeIds []string       `json:"noticeIds" gorm:"column:noticeIds;serializer:json"`
+	Continue  bool           `json:"continue"` // 命中后是否继续匹配后续同级路由
+	Routes    []NoticeRoute  `json:"routes"`   // 子路由
+}
+
+// GetMatchers 获取路由的匹配条件, 包含旧版 Key/Value 配置
+func (r NoticeRoute) GetMatchers() []SilenceLabel {
+	if r.Key == "" {
+		return r.Matchers
+	}
+	return append([]SilenceLabel{{Key: r.Key, Value: r.Value, Operator: "=~"}}, r.Matchers...)
+}
+
+// Validate 校验路由及其子路由
+func (r NoticeRoute) Validate() error {
+	if err := validateLabelMatchers("通知路由", r.GetMatchers()); err != nil {
+		return err
+	}
+
+	if len(r.NoticeIds) == 0 && len(r.Routes) == 0 {
+		return fmt.Errorf("通知路由需要配置通知对象或子路由")
+	}
+
+	for _, route := range r.Routes {
+		if err := route.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (u *UpgradeStrategy) GetEnabled() bool {
@@ -0,0 +1,54 @@
+package models
+
+import "fmt"
+
+const (
+	// 心跳告警的数据源类型及规则ID, 不与评估引擎的规则关联
+	HeartbeatDatasourceType = "Heartbeat"
+	HeartbeatRuleId         = "heartbeat"
+)
+
+// Heartbeat 心跳监控, 外部系统定期上报心跳, 超过间隔未上报时向故障中心推送告警
+type Heartbeat struct {
+	TenantId      string            `json:"tenantId"`
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	FaultCenterId string            `json:"faultCenterId"`
+	Interval      int64             `json:"interval"` // 心跳上报间隔，单位（秒）
+	Severity      string            `json:"severity"`
+	Labels        map[string]string `json:"labels" gorm:"labels;serializer:json"` // 附加到告警事件的标签
+	Description   string            `json:"description"`
+	Enabled       *bool             `json:"enabled"`
+	UpdateAt      int64             `json:"updateAt"`
+	UpdateBy      string            `json:"updateBy"`
+	// 最近一次上报时间, 仅用于列表展示
+	LastSeen int64 `json:"lastSeen" gorm:"-"`
+}
+
+func (h Heartbeat) GetEnabled() bool {
+	if h.Enabled == nil {
+		return true
+	}
+	return *h.Enabled
+}
+
+// Validate 校验心跳配置
+func (h Heartbeat) Validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("心跳名称不能为空")
+	}
+	if h.FaultCenterId == "" {
+		return fmt.Errorf("故障中心不能为空")
+	}
+	if h.Interval <= 0 {
+		return fmt.Errorf("心跳上报间隔必须大于 0")
+	}
+
+	return nil
+}
+
+type HeartbeatCacheKey string
+
+func BuildHeartbeatCacheKey(tenantId string) HeartbeatCacheKey {
+	return HeartbeatCacheKey(fmt.Sprintf("w8t:%s:heartbeat.lastSeen", tenantId))
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// labelNamePattern Prometheus 标签名规则, 标签名会拼接到 JSON 路径中, 需要限制字符
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// labelMatcherOperators 按长度优先匹配运算符, 避免 =~ 被识别为 =
+var labelMatcherOperators = []string{"=~", "!~", "!=", "="}
+
+// ParseLabelMatcher 解析 Prometheus 风格的标签匹配条件, 如 severity=P0、team=~"infra.*"
+func ParseLabelMatcher(expr string) (SilenceLabel, error) {
+	idx := strings.IndexAny(expr, "=!")
+	if idx <= 0 {
+		return SilenceLabel{}, fmt.Errorf("标签匹配条件格式错误: %s", expr)
+	}
+
+	key := strings.TrimSpace(expr[:idx])
+	rest := expr[idx:]
+
+	var operator string
+	for _, op := range labelMatcherOperators {
+		if strings.HasPrefix(rest, op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" || !labelNamePattern.MatchString(key) {
+		return SilenceLabel{}, fmt.Errorf("标签匹配条件格式错误: %s", expr)
+	}
+
+	value := strings.TrimSpace(strings.TrimPrefix(rest, operator))
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return SilenceLabel{}, fmt.Errorf("标签匹配条件 %s 的值格式错误: %s", key, value)
+		}
+		value = unquoted
+	}
+
+	return SilenceLabel{Key: key, Value: value, Operator: operator}, nil
+}
+
+// ParseLabelMatchers 解析多个标签匹配条件, 需全部满足
+func ParseLabelMatchers(exprs []string) ([]SilenceLabel, error) {
+	var matchers []SilenceLabel
+	for _, expr := range exprs {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		matcher, err := ParseLabelMatcher(expr)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	if len(matchers) == 0 {
+		return nil, nil
+	}
+
+	if err := validateLabelMatchers("标签", matchers); err != nil {
+		return nil, err
+	}
+
+	return matchers, nil
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogsQLStats LogsQL 查询中 stats 管道的分组字段及聚合结果字段
+type LogsQLStats struct {
+	By      []string // 分组字段, 每组返回一行
+	Results []string // 聚合结果字段
+}
+
+// HasResult 判断 stats 管道是否产生指定的聚合结果字段
+func (s LogsQLStats) HasResult(field string) bool {
+	for _, result := range s.Results {
+		if result == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLogsQLStats 解析 LogsQL 查询中最后一个 stats 管道, 例如
+// `_time:5m error | stats by (host, _time:1m) sum(bytes) as total, count() if (level:error) errors`
+func ParseLogsQLStats(query string) (LogsQLStats, error) {
+	var stats string
+	for _, pipe := range splitLogsQLTopLevel(query, '|') {
+		pipe = strings.TrimSpace(pipe)
+		if word, rest := cutLogsQLWord(pipe); strings.EqualFold(word, "stats") {
+			stats = rest
+		}
+	}
+	if stats == "" {
+		return LogsQLStats{}, fmt.Errorf("LogsQL 查询中缺少 stats 管道")
+	}
+
+	var result LogsQLStats
+	if word, rest := cutLogsQLWord(stats); strings.EqualFold(word, "by") || strings.HasPrefix(strings.ToLower(stats), "by(") {
+		if strings.EqualFold(word, "by") {
+			stats = rest
+		} else {
+			stats = strings.TrimSpace(stats[2:])
+		}
+		if !strings.HasPrefix(stats, "(") {
+			return LogsQLStats{}, fmt.Errorf("stats 管道的 by 子句格式错误")
+		}
+		end := matchLogsQLParen(stats)
+		if end < 0 {
+			return LogsQLStats{}, fmt.Errorf("stats 管道的 by 子句缺少右括号")
+		}
+		for _, field := range splitLogsQLTopLevel(stats[1:end], ',') {
+			// 时间分桶字段如 _time:1m 的结果字段为 _time
+			field, _, _ = strings.Cut(strings.TrimSpace(field), ":")
+			if field = unquoteLogsQL(field); field != "" {
+				result.By = append(result.By, field)
+			}
+		}
+		stats = stats[end+1:]
+	}
+
+	for _, expr := range splitLogsQLTopLevel(stats, ',') {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+		open := strings.Index(expr, "(")
+		end := -1
+		if open > 0 {
+			end = matchLogsQLParen(expr[open:])
+		}
+		if end < 0 {
+			return LogsQLStats{}, fmt.Errorf("stats 管道的聚合函数格式错误: %s", expr)
+		}
+		name := strings.TrimSpace(expr[open+end+1:])
+
+		// 跳过 if (...) 过滤条件
+		if word, rest := cutLogsQLWord(name); strings.EqualFold(word, "if") || strings.HasPrefix(strings.ToLower(name), "if(") {
+			if strings.EqualFold(word, "if") {
+				name = rest
+			} else {
+				name = strings.TrimSpace(name[2:])
+			}
+			filterEnd := matchLogsQLParen(name)
+			if !strings.HasPrefix(name, "(") || filterEnd < 0 {
+				return LogsQLStats{}, fmt.Errorf("stats 管道的 if 条件格式错误: %s", expr)
+			}
+			name = strings.TrimSpace(name[filterEnd+1:])
+		}
+
+		if word, rest := cutLogsQLWord(name); strings.EqualFold(word, "as") {
+			name = rest
+		}
+		// 未指定结果名称时, 结果字段为聚合函数本身
+		if name = unquoteLogsQL(name); name == "" {
+			name = strings.TrimSpace(expr[:open+end+1])
+		}
+		result.Results = append(result.Results, name)
+	}
+	if len(result.Results) == 0 {
+		return LogsQLStats{}, fmt.Errorf("stats 管道缺少聚合函数")
+	}
+
+	return result, nil
+}
+
+// splitLogsQLTopLevel 按分隔符切分, 忽略引号及括号内的分隔符
+func splitLogsQLTopLevel(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// matchLogsQLParen 返回以 ( 开头的字符串中与之匹配的 ) 的位置, 忽略引号内的括号, 不匹配时返回 -1
+func matchLogsQLParen(s string) int {
+	if !strings.HasPrefix(s, "(") {
+		return -1
+	}
+
+	var (
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cutLogsQLWord 切分出第一个单词及剩余部分
+func cutLogsQLWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexAny(s, " \t\r\n")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+func unquoteLogsQL(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
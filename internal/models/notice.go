@@ -22,13 +22,23 @@ type Route struct {
 	NoticeType string `json:"noticeType"`
 	// 通知模版 ID
 	NoticeTmplId string `json:"noticeTmplId"`
+	// 渠道自定义模版(Go template), 配置后优先于通知模版 ID, 均未配置时使用内置的默认模版
+	Template string `json:"template"`
+	// 渠道自定义恢复通知模版, 为空时使用渠道模版
+	RecoverTemplate string `json:"recoverTemplate"`
+	// 是否发送恢复通知, 为空时使用故障中心的配置
+	RecoverNotify *bool `json:"recoverNotify"`
 	// 告警等级
 	Severitys []string `json:"severitys"`
 	// WebHook
 	Hook string `json:"hook"`
-	// 签名
+	// 签名密钥, 钉钉/飞书机器人签名; WebHook 配置后使用 HMAC-SHA256 对请求体签名
 	Sign string `json:"sign"`
-	// 邮件主题
+	// Telegram 会话 ID
+	ChatId string `json:"chatId"`
+	// PagerDuty 服务集成的 Routing Key
+	RoutingKey string `json:"routingKey"`
+	// 邮件主题, 支持模版语法
 	Subject string `json:"subject"`
 	// 收件人
 	To []string `json:"to" gorm:"column:to;serializer:json"`
@@ -38,6 +48,14 @@ type Route struct {
 	EffectiveTime EffectiveTime `json:"effectiveTime"`
 }
 
+// IsRecoverNotify 是否发送恢复通知, 渠道未配置时使用故障中心的配置
+func (r Route) IsRecoverNotify(faultCenterRecoverNotify bool) bool {
+	if r.RecoverNotify == nil {
+		return faultCenterRecoverNotify
+	}
+	return *r.RecoverNotify
+}
+
 type Email struct {
 	Subject string   `json:"subject"`
 	To      []string `json:"to" gorm:"column:to;serializer:json"`
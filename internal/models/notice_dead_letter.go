@@ -0,0 +1,31 @@
+package models
+
+import "fmt"
+
+// NoticeDeadLetter 重试后仍发送失败的通知, 存放于 Redis 死信队列中等待排查或重新投递
+type NoticeDeadLetter struct {
+	Id          string `json:"id"`
+	TenantId    string `json:"tenantId"`
+	EventId     string `json:"eventId"`
+	RuleName    string `json:"ruleName"`
+	Severity    string `json:"severity"`
+	NoticeType  string `json:"noticeType"`
+	NoticeId    string `json:"noticeId"`
+	NoticeName  string `json:"noticeName"`
+	IsRecovered bool   `json:"isRecovered"`
+	// 路由在通知对象中的序号, 重新投递时按当前的通知配置读取地址及密钥, 不保存凭据
+	RouteIndex int `json:"routeIndex"`
+	// 通过用户个人通知渠道发送, NoticeId 为用户 ID
+	UserChannel bool   `json:"userChannel"`
+	Email       Email  `json:"email"`
+	Content     string `json:"content"`
+	Attempts    int    `json:"attempts"`
+	Error       string `json:"error"`
+	CreateAt    int64  `json:"createAt"`
+}
+
+type NoticeDeadLetterCacheKey string
+
+func BuildNoticeDeadLetterCacheKey(tenantId string) NoticeDeadLetterCacheKey {
+	return NoticeDeadLetterCacheKey(fmt.Sprintf("w8t:%s:notice:deadLetter", tenantId))
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// QueryLinkData 查询链接模版可用的变量, 如:
+// https://grafana.example.com/explore?left={"queries":[{"expr":"{{ .Query | urlquery }}"}],"range":{"from":"{{ .From }}","to":"{{ .To }}"}}
+type QueryLinkData struct {
+	Labels       map[string]interface{}
+	Query        string // 事件的查询语句
+	RuleName     string
+	DatasourceId string
+	From         int64 // 时间范围起点, 毫秒时间戳
+	To           int64 // 时间范围终点, 毫秒时间戳
+}
+
+// ParseQueryLinkTemplate 解析查询链接模版, 缺失的标签渲染为空
+func ParseQueryLinkTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("queryLink").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("查询链接模版解析失败: %s", err.Error())
+	}
+	return tmpl, nil
+}
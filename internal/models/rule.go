@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"slices"
+	"time"
 )
 
 type AlertRule struct {
@@ -14,14 +16,27 @@ type AlertRule struct {
 	DatasourceIdList     []string          `json:"datasourceId" gorm:"datasourceId;serializer:json"`
 	RuleName             string            `json:"ruleName"`
 	EvalInterval         int64             `json:"evalInterval"`
-	RepeatNoticeInterval int64             `json:"repeatNoticeInterval"`
+	RepeatNoticeInterval int64             `json:"repeatNoticeInterval"` // 重复通知间隔（分钟），为 0 时使用故障中心的配置
 	Description          string            `json:"description"`
 	EffectiveTime        EffectiveTime     `json:"effectiveTime" gorm:"effectiveTime;serializer:json"`
 	Severity             string            `json:"severity"`
+	SeverityExpr         string            `json:"severityExpr"` // 等级表达式, 根据事件标签及当前值返回告警等级, 计算失败时使用默认等级
+	ForDuration          int64             `json:"forDuration"`  // 持续时间（秒），条件持续满足该时长后才转为告警状态
+	NoDataAlert          NoDataAlert       `json:"noDataAlert" gorm:"noDataAlert;serializer:json"`
+	RecoverWaitTime      int64             `json:"recoverWaitTime"`                              // 恢复等待时间（秒），为 0 时使用故障中心的配置
+	OverrunPolicy        string            `json:"overrunPolicy"`                                // 上一次评估未完成时的处理方式: skip 跳过本次, queue 等待后执行
+	DatasourceStrategy   string            `json:"datasourceStrategy"`                           // 多个数据源的评估方式: union 合并所有数据源的结果, failover 按顺序使用第一个可用的数据源, quorum 按投票结果产生事件
+	DatasourceQuorum     int64             `json:"datasourceQuorum"`                             // quorum 方式下产生事件需要满足条件的最少数据源数量, 为 0 时取多数
+	MaxFingerprints      int64             `json:"maxFingerprints"`                              // 单次评估允许产生的最大事件数, 超出后不再产生新事件, 为 0 时使用全局配置
+	LinkTemplate         string            `json:"linkTemplate"`                                 // 查询链接模版, 优先于数据源的配置, 见 QueryLinkData
+	Enrichment           EventEnrichment   `json:"enrichment" gorm:"enrichment;serializer:json"` // 事件富化, 启用时优先于故障中心的配置
 
 	// Prometheus
 	PrometheusConfig PrometheusConfig `json:"prometheusConfig" gorm:"prometheusConfig;serializer:json"`
 
+	// InfluxDB
+	InfluxDBConfig InfluxDBConfig `json:"influxdbConfig" gorm:"influxdbConfig;serializer:json"`
+
 	// 阿里云SLS
 	AliCloudSLSConfig AliCloudSLSConfig `json:"alicloudSLSConfig" gorm:"alicloudSLSConfig;serializer:json"`
 
@@ -43,11 +58,27 @@ type AlertRule struct {
 	ElasticSearchConfig ElasticSearchConfig `json:"elasticSearchConfig" gorm:"elasticSearchConfig;serializer:json"`
 
 	LogEvalCondition string `json:"logEvalCondition" gorm:"logEvalCondition;serializer:json"`
+	// 日志分组字段, 按字段的值分别评估并生成事件, 支持以 . 分隔的嵌套字段
+	LogGroupBy []string `json:"logGroupBy" gorm:"logGroupBy;serializer:json"`
+	// 参与指纹计算的标签, 仅对指标类规则生效, 为空时使用序列的全部标签;
+	// 修改后已有事件的指纹随之变化, 相当于重置规则的事件, 保存时会清除旧事件并按新的指纹重新生成
+	FingerprintLabels []string `json:"fingerprintLabels" gorm:"fingerprintLabels;serializer:json"`
 
 	FaultCenterId string `json:"faultCenterId"`
 	UpdateAt      int64  `json:"updateAt"`
 	UpdateBy      string `json:"updateBy"`
 	Enabled       *bool  `json:"enabled" gorm:"enabled"`
+	// 规则由模版实例化时绑定的模版组、模版名称及变量, 模版更新时可同步到实例
+	TemplateGroup     string            `json:"templateGroup"`
+	TemplateName      string            `json:"templateName"`
+	TemplateVariables map[string]string `json:"templateVariables" gorm:"templateVariables;serializer:json"`
+	// 暂停评估的截止时间, 到期后自动恢复评估, 为 0 时未暂停
+	PausedUntil int64 `json:"pausedUntil"`
+	// 是否处于暂停期, 仅用于列表展示
+	Paused bool `json:"paused" gorm:"-"`
+	// 评估协程短时间内反复 panic 时规则被停用, 记录最后一次 panic 的信息及时间, 重新启用后清除
+	EvalError   string `json:"evalError"`
+	EvalErrorAt int64  `json:"evalErrorAt"`
 }
 
 type ElasticSearchConfig struct {
@@ -58,6 +89,25 @@ type ElasticSearchConfig struct {
 	EsQueryType     EsQueryType       `json:"queryType"`
 	QueryWildcard   int64             `json:"queryWildcard"` // 0 精准匹配，1 模糊匹配
 	RawJson         string            `json:"rawJson"`
+	// 查询语言, lucene(默认) 使用 queryType 构造查询, esql 使用 ES|QL 语句
+	QueryLanguage EsQueryLanguage `json:"queryLanguage"`
+	EsQL          string          `json:"esql"`
+	// 聚合查询时用于阈值评估的数值列, 为空时按返回的行数评估, 仅 esql 支持
+	ValueField string `json:"valueField"`
+}
+
+type EsQueryLanguage string
+
+const (
+	EsQueryLanguageLucene EsQueryLanguage = "lucene"
+	EsQueryLanguageEsql   EsQueryLanguage = "esql"
+)
+
+func (e ElasticSearchConfig) GetQueryLanguage() EsQueryLanguage {
+	if e.QueryLanguage == "" {
+		return EsQueryLanguageLucene
+	}
+	return e.QueryLanguage
 }
 
 type EsQueryType string
@@ -81,10 +131,23 @@ type EsQueryFilter struct {
 }
 
 type KubernetesConfig struct {
-	Resource string   `json:"resource"`
-	Reason   string   `json:"reason"`
-	Filter   []string `json:"filter"`
-	Scope    int      `json:"scope"`
+	Resource   string   `json:"resource"`
+	Reason     string   `json:"reason"`
+	Reasons    []string `json:"reasons"`    // 事件原因, 如 OOMKilling、FailedScheduling, 与 Reason 合并生效
+	Namespaces []string `json:"namespaces"` // 命名空间, 为空时查询所有命名空间
+	Kinds      []string `json:"kinds"`      // 关联资源类型, 如 Pod、Node, 为空时不过滤
+	Filter     []string `json:"filter"`     // 资源名称包含任一关键字时排除
+	Scope      int      `json:"scope"`
+	MinCount   int64    `json:"minCount"` // 同一资源在时间范围内的事件次数达到该值时告警, 为 0 时不限制
+}
+
+// GetReasons 获取需要查询的事件原因
+func (k KubernetesConfig) GetReasons() []string {
+	reasons := slices.Clone(k.Reasons)
+	if k.Reason != "" && !slices.Contains(reasons, k.Reason) {
+		reasons = append(reasons, k.Reason)
+	}
+	return reasons
 }
 
 type JaegerConfig struct {
@@ -100,12 +163,84 @@ type PrometheusConfig struct {
 	Rules []Rules `json:"rules"`
 }
 
+// InfluxDBConfig 使用 Flux 查询, 阈值规则与 Prometheus 保持一致
+type InfluxDBConfig struct {
+	Flux        string  `json:"flux"`
+	Annotations string  `json:"annotations"`
+	Rules       []Rules `json:"rules"`
+}
+
+// Rules 分级阈值, 同一序列满足多个等级时取最高等级, 如 >80 为 P2、>90 为 P1、>95 为 P0
 type Rules struct {
 	ForDuration int64  `json:"forDuration"`
 	Severity    string `json:"severity"`
 	Expr        string `json:"expr"`
 }
 
+const (
+	// OverrunPolicySkip 上一次评估未完成时跳过本次评估
+	OverrunPolicySkip = "skip"
+	// OverrunPolicyQueue 上一次评估未完成时等待其完成后执行本次评估
+	OverrunPolicyQueue = "queue"
+)
+
+// GetOverrunPolicy 获取评估超时的处理方式, 默认跳过
+func (a *AlertRule) GetOverrunPolicy() string {
+	if a.OverrunPolicy == OverrunPolicyQueue {
+		return OverrunPolicyQueue
+	}
+	return OverrunPolicySkip
+}
+
+const (
+	// DatasourceStrategyUnion 并发查询所有数据源并合并结果
+	DatasourceStrategyUnion = "union"
+	// DatasourceStrategyFailover 按顺序查询数据源, 查询成功后不再查询后续数据源, 适用于主备数据源保存相同数据的场景
+	DatasourceStrategyFailover = "failover"
+	// DatasourceStrategyQuorum 查询所有数据源, 同一指纹至少在 DatasourceQuorum 个数据源中满足条件时才产生事件, 减少单个副本异常导致的误报
+	DatasourceStrategyQuorum = "quorum"
+)
+
+// GetDatasourceStrategy 获取多个数据源的评估方式, 默认合并所有数据源的结果
+func (a *AlertRule) GetDatasourceStrategy() string {
+	switch a.DatasourceStrategy {
+	case DatasourceStrategyFailover, DatasourceStrategyQuorum:
+		return a.DatasourceStrategy
+	}
+	return DatasourceStrategyUnion
+}
+
+// GetDatasourceQuorum 获取 quorum 方式下需要满足条件的最少数据源数量, 未配置时取多数
+func (a *AlertRule) GetDatasourceQuorum() int {
+	if a.DatasourceQuorum > 0 {
+		return int(a.DatasourceQuorum)
+	}
+	return len(a.DatasourceIdList)/2 + 1
+}
+
+// GetMaxFingerprints 获取单次评估允许产生的最大事件数, 规则未配置时使用全局配置, 为 0 时不限制
+func (a *AlertRule) GetMaxFingerprints(defaultMax int64) int64 {
+	if a.MaxFingerprints > 0 {
+		return a.MaxFingerprints
+	}
+	return max(defaultMax, 0)
+}
+
+// NoDataAlert 无数据告警, 指标查询连续多次无结果时产生告警
+type NoDataAlert struct {
+	Enabled bool `json:"enabled"`
+	// 连续无数据的评估次数
+	Consecutive int64 `json:"consecutive"`
+}
+
+// GetConsecutive 获取触发无数据告警所需的连续评估次数, 默认 1 次
+func (n NoDataAlert) GetConsecutive() int64 {
+	if n.Consecutive <= 0 {
+		return 1
+	}
+	return n.Consecutive
+}
+
 type EffectiveTime struct {
 	Week      []string `json:"week"`
 	StartTime int      `json:"startTime"`
@@ -117,17 +252,73 @@ type AliCloudSLSConfig struct {
 	Logstore []string `json:"logstore"`
 	LogQL    string   `json:"logQL"`    // 查询语句
 	LogScope int      `json:"logScope"` // 相对查询的日志范围（单位分钟）,1(min) 5(min)...
+	// 多个 project/logstore 查询目标, 配置后逐个查询并按查询目标分别产生事件, 与 project/logstore 不能同时配置
+	Targets []AliCloudSLSTarget `json:"targets"`
+}
+
+// AliCloudSLSTarget SLS 查询目标, 服务地址及 AccessKey 为空时使用数据源的配置
+type AliCloudSLSTarget struct {
+	Project         string `json:"project"`
+	Logstore        string `json:"logstore"`
+	Endpoint        string `json:"endpoint"` // 查询目标所在地域的服务地址, 例如 cn-hangzhou.log.aliyuncs.com
+	AccessKeyId     string `json:"accessKeyId"`
+	AccessKeySecret string `json:"accessKeySecret"`
+}
+
+// Validate 校验 SLS 查询目标
+func (c AliCloudSLSConfig) Validate() error {
+	if len(c.Targets) == 0 {
+		return nil
+	}
+	if c.Project != "" || len(c.Logstore) > 0 {
+		return fmt.Errorf("配置 SLS 查询目标后不能同时配置 project/logstore")
+	}
+
+	seen := make(map[string]struct{}, len(c.Targets))
+	for _, target := range c.Targets {
+		if target.Project == "" || target.Logstore == "" {
+			return fmt.Errorf("SLS 查询目标的 project 及 logstore 不能为空")
+		}
+		if (target.AccessKeyId == "") != (target.AccessKeySecret == "") {
+			return fmt.Errorf("SLS 查询目标 %s/%s 的 AccessKey ID 与 Secret 需同时配置", target.Project, target.Logstore)
+		}
+		key := target.Endpoint + "/" + target.Project + "/" + target.Logstore
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("SLS 查询目标 %s/%s 重复配置", target.Project, target.Logstore)
+		}
+		seen[key] = struct{}{}
+	}
+
+	return nil
 }
 
+const (
+	// LogEvalModeCount 按匹配日志条数评估
+	LogEvalModeCount = "count"
+	// LogEvalModeRate 按每秒匹配日志条数评估, 即条数除以查询窗口秒数
+	LogEvalModeRate = "rate"
+)
+
 type LokiConfig struct {
 	LogQL    string `json:"logQL"`
 	LogScope int    `json:"logScope"`
+	// 评估模式, count(默认) 或 rate
+	EvalMode string `json:"evalMode"`
+}
+
+func (l LokiConfig) GetEvalMode() string {
+	if l.EvalMode == "" {
+		return LogEvalModeCount
+	}
+	return l.EvalMode
 }
 
 type VictoriaLogsConfig struct {
 	LogQL    string `json:"logQL"`
 	LogScope int    `json:"logScope"`
 	Limit    int    `json:"limit"`
+	// 按 stats 管道的聚合结果评估时使用的结果字段, 每个 stats 分组产生一个事件, 为空时按返回的日志条数评估
+	ValueField string `json:"valueField"`
 }
 
 type ClickHouseConfig struct {
@@ -167,13 +358,47 @@ func (a *AlertRule) GetEnabled() *bool {
 	return a.Enabled
 }
 
+// IsPaused 规则是否处于暂停期
+func (a *AlertRule) IsPaused(now time.Time) bool {
+	return a.PausedUntil > now.Unix()
+}
+
+// GetThresholdRules 获取指标类规则的分级阈值
+func (a *AlertRule) GetThresholdRules() []Rules {
+	if a.DatasourceType == "InfluxDB" {
+		return a.InfluxDBConfig.Rules
+	}
+	return a.PrometheusConfig.Rules
+}
+
+// BuildFingerprintLabels 生成指标类事件参与指纹计算的标签, 配置了 FingerprintLabels 时只保留指定的标签,
+// 始终包含规则 ID 及名称, 不同规则的事件不会合并
+func (a *AlertRule) BuildFingerprintLabels(metric map[string]interface{}) map[string]interface{} {
+	labels := make(map[string]interface{}, len(metric)+2)
+	if len(a.FingerprintLabels) == 0 {
+		for k, v := range metric {
+			labels[k] = v
+		}
+	} else {
+		for _, k := range a.FingerprintLabels {
+			if v, ok := metric[k]; ok {
+				labels[k] = v
+			}
+		}
+	}
+	labels["rule_id"] = a.RuleId
+	labels["rule_name"] = a.RuleName
+	return labels
+}
+
+// GetForDuration 获取持续时间，优先使用告警等级上的配置，未配置时使用规则级别的配置
 func (a *AlertRule) GetForDuration(severity string) int64 {
-	for _, rule := range a.PrometheusConfig.Rules {
-		if rule.Severity == severity {
+	for _, rule := range a.GetThresholdRules() {
+		if rule.Severity == severity && rule.ForDuration > 0 {
 			return rule.ForDuration
 		}
 	}
-	return 0
+	return a.ForDuration
 }
 
 func (t *AlertRule) Validate() error {
@@ -0,0 +1,28 @@
+package models
+
+import "fmt"
+
+// RuleEvalRecord 规则对单个数据源的一次评估结果, 用于排查规则在某一时刻是否触发及触发时的值
+type RuleEvalRecord struct {
+	RuleId         string           `json:"ruleId"`
+	DatasourceId   string           `json:"datasourceId"`
+	DatasourceType string           `json:"datasourceType"`
+	EvalAt         int64            `json:"evalAt"`
+	Duration       int64            `json:"duration"` // 评估耗时, 单位（毫秒）
+	Fingerprints   []string         `json:"fingerprints"`
+	Samples        []RuleEvalSample `json:"samples"`
+	Error          string           `json:"error,omitempty"`
+}
+
+// RuleEvalSample 评估产生的样本, Triggered 为 false 时表示未满足告警条件
+type RuleEvalSample struct {
+	Fingerprint string      `json:"fingerprint"`
+	Value       interface{} `json:"value"`
+	Triggered   bool        `json:"triggered"`
+}
+
+type RuleEvalHistoryCacheKey string
+
+func BuildRuleEvalHistoryCacheKey(tenantId, ruleId string) RuleEvalHistoryCacheKey {
+	return RuleEvalHistoryCacheKey(fmt.Sprintf("w8t:%s:rule:%s.evalHistory", tenantId, ruleId))
+}
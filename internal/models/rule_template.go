@@ -1,5 +1,15 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
 type RuleTemplateGroup struct {
 	Name        string `json:"name" gorm:"type:varchar(255);not null"`
 	Number      int    `json:"number"`
@@ -24,4 +34,102 @@ type RuleTemplate struct {
 	ElasticSearchConfig  ElasticSearchConfig `json:"elasticSearchConfig" gorm:"elasticSearchConfig;serializer:json"`
 	VictoriaLogsConfig   VictoriaLogsConfig  `json:"victoriaLogsConfig" gorm:"victoriaConfig;serializer:json"`
 	ClickHouseConfig     ClickHouseConfig    `json:"clickhouseConfig" gorm:"clickhouseConfig;serializer:json"`
+	// 模版变量, 在规则名称、描述及查询配置中以 {{name}} 引用, 实例化时绑定具体的值
+	Variables []string `json:"variables" gorm:"variables;serializer:json"`
+}
+
+var ruleTemplatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var ruleTemplateVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateVariables 校验模版变量的命名, 且模版中引用的变量均已声明
+func (t RuleTemplate) ValidateVariables() error {
+	sample := make(map[string]string, len(t.Variables))
+	for _, name := range t.Variables {
+		if !ruleTemplateVariableName.MatchString(name) {
+			return fmt.Errorf("模版变量名称无效: %s", name)
+		}
+		if _, ok := sample[name]; ok {
+			return fmt.Errorf("模版变量 %s 重复声明", name)
+		}
+		sample[name] = name
+	}
+
+	_, err := t.Render(sample)
+	return err
+}
+
+// Render 使用变量替换模版中的占位符, 变量需与模版声明的变量一致
+func (t RuleTemplate) Render(vars map[string]string) (RuleTemplate, error) {
+	var missing []string
+	for _, name := range t.Variables {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return RuleTemplate{}, fmt.Errorf("缺少模版变量: %s", strings.Join(missing, ", "))
+	}
+
+	declared := make(map[string]struct{}, len(t.Variables))
+	for _, name := range t.Variables {
+		declared[name] = struct{}{}
+	}
+	var unknown []string
+	for name := range vars {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return RuleTemplate{}, fmt.Errorf("模版未声明变量: %s", strings.Join(unknown, ", "))
+	}
+
+	raw, err := sonic.MarshalString(t)
+	if err != nil {
+		return RuleTemplate{}, err
+	}
+
+	var undeclared []string
+	rendered := ruleTemplatePlaceholder.ReplaceAllStringFunc(raw, func(placeholder string) string {
+		name := ruleTemplatePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := vars[name]
+		if !ok {
+			if !slices.Contains(undeclared, name) {
+				undeclared = append(undeclared, name)
+			}
+			return placeholder
+		}
+		// 变量值写入 JSON 字符串中, 需要转义
+		escaped, _ := sonic.MarshalString(value)
+		return escaped[1 : len(escaped)-1]
+	})
+	if len(undeclared) > 0 {
+		return RuleTemplate{}, fmt.Errorf("模版引用了未声明的变量: %s", strings.Join(undeclared, ", "))
+	}
+
+	var result RuleTemplate
+	if err := sonic.UnmarshalString(rendered, &result); err != nil {
+		return RuleTemplate{}, fmt.Errorf("渲染规则模版失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// ApplyTo 将模版的查询及阈值配置写入规则
+func (t RuleTemplate) ApplyTo(rule *AlertRule) {
+	rule.DatasourceType = t.DatasourceType
+	rule.EvalInterval = t.EvalInterval
+	rule.ForDuration = t.ForDuration
+	rule.RepeatNoticeInterval = t.RepeatNoticeInterval
+	rule.Description = t.Description
+	rule.PrometheusConfig = t.PrometheusConfig
+	rule.AliCloudSLSConfig = t.AliCloudSLSConfig
+	rule.LokiConfig = t.LokiConfig
+	rule.JaegerConfig = t.JaegerConfig
+	rule.KubernetesConfig = t.KubernetesConfig
+	rule.ElasticSearchConfig = t.ElasticSearchConfig
+	rule.VictoriaLogsConfig = t.VictoriaLogsConfig
+	rule.ClickHouseConfig = t.ClickHouseConfig
 }
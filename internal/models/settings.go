@@ -61,6 +61,32 @@ type OidcConfig struct {
 	UpperURI     string `json:"upperURI"`
 	RedirectURI  string `json:"redirectURI"`
 	Domain       string `json:"domain"`
+	// 用户组声明名称, 为空时使用 groups
+	GroupsClaim string `json:"groupsClaim"`
+	// 首次登录时是否自动创建用户, 未配置时自动创建
+	AutoCreateUser *bool `json:"autoCreateUser"`
+	// 用户组与租户角色的映射, 登录时将用户加入租户, 角色只在首次加入时设置
+	GroupRoles []OidcGroupRole `json:"groupRoles"`
+}
+
+type OidcGroupRole struct {
+	Group    string `json:"group"`
+	TenantId string `json:"tenantId"`
+	RoleId   string `json:"roleId"`
+}
+
+func (o OidcConfig) GetGroupsClaim() string {
+	if o.GroupsClaim == "" {
+		return "groups"
+	}
+	return o.GroupsClaim
+}
+
+func (o OidcConfig) GetAutoCreateUser() bool {
+	if o.AutoCreateUser == nil {
+		return true
+	}
+	return *o.AutoCreateUser
 }
 
 func (a AiConfig) GetEnable() bool {
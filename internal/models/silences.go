@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"regexp"
+)
+
 type AlertSilences struct {
 	TenantId      string         `json:"tenantId"`
 	Name          string         `json:"name"`
@@ -19,3 +24,37 @@ type SilenceLabel struct {
 	Value    string `json:"value"`
 	Operator string `json:"operator"`
 }
+
+// Validate 校验静默规则的时间窗口及匹配条件
+func (s AlertSilences) Validate() error {
+	if s.EndsAt <= s.StartsAt {
+		return fmt.Errorf("静默结束时间必须晚于开始时间")
+	}
+
+	return validateLabelMatchers("静默", s.Labels)
+}
+
+// validateLabelMatchers 校验标签匹配条件, name 用于错误提示
+func validateLabelMatchers(name string, labels []SilenceLabel) error {
+	if len(labels) == 0 {
+		return fmt.Errorf("%s匹配条件不能为空", name)
+	}
+
+	for _, label := range labels {
+		if label.Key == "" {
+			return fmt.Errorf("%s匹配条件的标签名不能为空", name)
+		}
+
+		switch label.Operator {
+		case "==", "=", "!=":
+		case "=~", "!~":
+			if _, err := regexp.Compile(label.Value); err != nil {
+				return fmt.Errorf("%s匹配条件 %s 的正则表达式无效: %s", name, label.Key, err.Error())
+			}
+		default:
+			return fmt.Errorf("不支持的匹配运算符: %s", label.Operator)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+const (
+	// PagerDutyEventsApi PagerDuty Events API v2 地址
+	PagerDutyEventsApi = "https://events.pagerduty.com/v2/enqueue"
+
+	PagerDutyActionTrigger = "trigger"
+	PagerDutyActionResolve = "resolve"
+)
+
+// PagerDutyEvent PagerDuty Events API v2 事件, routing_key 在发送时填充, 不写入通知记录
+type PagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *PagerDutyPayload `json:"payload,omitempty"`
+	Client      string            `json:"client,omitempty"`
+	ClientUrl   string            `json:"client_url,omitempty"`
+	Links       []PagerDutyLink   `json:"links,omitempty"`
+}
+
+type PagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+type PagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// PagerDutyResponse Events API v2 响应, dedup_key 即 PagerDuty 的 incident key
+type PagerDutyResponse struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	DedupKey string   `json:"dedup_key"`
+	Errors   []string `json:"errors"`
+}
+
+// PagerDutySeverity 将告警等级映射为 PagerDuty 的 severity
+func PagerDutySeverity(severity string) string {
+	switch severity {
+	case "P0":
+		return "critical"
+	case "P1":
+		return "error"
+	case "P2":
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+const (
+	TeamsAdaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	TeamsAdaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	TeamsAdaptiveCardVersion     = "1.4"
+)
+
+// TeamsMsgTemplate Teams Incoming Webhook 消息
+type TeamsMsgTemplate struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	ContentUrl  *string           `json:"contentUrl"`
+	Content     TeamsAdaptiveCard `json:"content"`
+}
+
+type TeamsAdaptiveCard struct {
+	Schema  string             `json:"$schema"`
+	Type    string             `json:"type"`
+	Version string             `json:"version"`
+	Body    []TeamsCardElement `json:"body"`
+	Actions []TeamsCardAction  `json:"actions,omitempty"`
+	MsTeams map[string]string  `json:"msteams,omitempty"`
+}
+
+// TeamsCardElement 卡片元素, 仅包含 TextBlock 与 FactSet 用到的字段
+type TeamsCardElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Color  string      `json:"color,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []TeamsFact `json:"facts,omitempty"`
+}
+
+type TeamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type TeamsCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type TelegramMsgTemplate struct {
+	ChatId string `json:"chat_id,omitempty"`
+	Text   string `json:"text"`
+}
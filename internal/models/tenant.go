@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 type Tenant struct {
 	ID               string `json:"id"`
 	Name             string `json:"name"`
@@ -10,8 +15,28 @@ type Tenant struct {
 	DutyNumber       int64  `json:"dutyNumber"`
 	NoticeNumber     int64  `json:"noticeNumber"`
 	RemoveProtection *bool  `json:"removeProtection" gorm:"type:BOOL"`
-	UserId           string `json:"userId" gorm:"-"`
-	UpdateAt         int64  `json:"updateAt"`
+	// 审计日志保留天数, 为 0 时使用全局配置
+	AuditLogRetention int64 `json:"auditLogRetention"`
+	// 活跃告警事件配额, 达到后不再产生新的告警事件, 为 0 时不限制
+	EventNumber int64 `json:"eventNumber"`
+	// 历史事件保留天数及最大保留数量, 为 0 时使用全局配置
+	HistoryEventRetention int64 `json:"historyEventRetention"`
+	HistoryEventMaxCount  int64 `json:"historyEventMaxCount"`
+	// IANA 时区名称, 例如 Asia/Shanghai, 用于维护窗口、生效时间等周期性时间的判断及通知中的时间显示, 为空时使用服务所在时区
+	Timezone string `json:"timezone"`
+	UserId   string `json:"userId" gorm:"-"`
+	UpdateAt int64  `json:"updateAt"`
+}
+
+// QuotaExceededError 租户资源配额不足
+type QuotaExceededError struct {
+	Resource string
+	Used     int64
+	Quota    int64
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("创建失败, %s数量已达到租户配额上限 (%d/%d)", e.Resource, e.Used, e.Quota)
 }
 
 func (t *Tenant) GetRemoveProtection() *bool {
@@ -22,6 +47,29 @@ func (t *Tenant) GetRemoveProtection() *bool {
 	return t.RemoveProtection
 }
 
+// Location 获取租户的时区, 未配置或无效时使用服务所在时区
+func (t Tenant) Location() *time.Location {
+	if t.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// ValidateTimezone 校验 IANA 时区名称, 为空时表示使用服务所在时区
+func ValidateTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("无效的时区: %s", name)
+	}
+	return nil
+}
+
 type TenantLinkedUsers struct {
 	ID    string       `json:"id"`
 	Users []TenantUser `json:"users" gorm:"users;serializer:json"`
@@ -12,6 +12,16 @@ type Member struct {
 	JoinDuty   string   `json:"joinDuty" `
 	DutyUserId string   `json:"dutyUserId"`
 	Tenants    []string `json:"tenants" gorm:"tenants;serializer:json"`
+	// 值班期间接收告警的偏好渠道
+	NoticeChannel NoticeChannel `json:"noticeChannel" gorm:"noticeChannel;serializer:json"`
+}
+
+// NoticeChannel 个人通知渠道, Email 类型使用用户邮箱作为收件人
+type NoticeChannel struct {
+	NoticeType string `json:"noticeType"`
+	Hook       string `json:"hook"`
+	Sign       string `json:"sign"`
+	ChatId     string `json:"chatId"`
 }
 
 type ResponseLoginInfo struct {
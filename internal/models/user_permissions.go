@@ -95,6 +95,10 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "查看通知模版",
 			API: "/api/w8t/noticeTemplate/noticeTemplateList",
 		},
+		"noticeTemplateTest": {
+			Key: "测试通知模版",
+			API: "/api/w8t/noticeTemplate/noticeTemplateTest",
+		},
 		"noticeTemplateGet": {
 			Key: "获取通知模版",
 			API: "/api/w8t/noticeTemplate/noticeTemplateGet",
@@ -139,6 +143,22 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "删除告警规则",
 			API: "/api/w8t/rule/ruleDelete",
 		},
+		"rulePreview": {
+			Key: "预览告警规则",
+			API: "/api/w8t/rule/preview",
+		},
+		"ruleEvalHistory": {
+			Key: "查看规则评估记录",
+			API: "/api/w8t/rule/evalHistory",
+		},
+		"ruleExportPrometheus": {
+			Key: "导出 Prometheus 告警规则",
+			API: "/api/w8t/rule/exportPrometheus",
+		},
+		"ruleShard": {
+			Key: "查看规则分片归属",
+			API: "/api/w8t/rule/shard",
+		},
 		"ruleGroupCreate": {
 			Key: "创建告警规则组",
 			API: "/api/w8t/ruleGroup/ruleGroupCreate",
@@ -171,6 +191,10 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "删除规则模版",
 			API: "/api/w8t/ruleTmpl/ruleTmplDelete",
 		},
+		"ruleTmplInstantiate": {
+			Key: "实例化规则模版",
+			API: "/api/w8t/ruleTmpl/ruleTmplInstantiate",
+		},
 		"ruleTmplGroupCreate": {
 			Key: "创建规则模版组",
 			API: "/api/w8t/ruleTmplGroup/ruleTmplGroupCreate",
@@ -195,6 +219,22 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "更新告警规则",
 			API: "/api/w8t/rule/ruleUpdate",
 		},
+		"heartbeatCreate": {
+			Key: "创建心跳",
+			API: "/api/w8t/heartbeat/heartbeatCreate",
+		},
+		"heartbeatDelete": {
+			Key: "删除心跳",
+			API: "/api/w8t/heartbeat/heartbeatDelete",
+		},
+		"heartbeatList": {
+			Key: "查看心跳",
+			API: "/api/w8t/heartbeat/heartbeatList",
+		},
+		"heartbeatUpdate": {
+			Key: "更新心跳",
+			API: "/api/w8t/heartbeat/heartbeatUpdate",
+		},
 		"silenceCreate": {
 			Key: "创建静默规则",
 			API: "/api/w8t/silence/silenceCreate",
@@ -259,6 +299,14 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "修改租户成员角色",
 			API: "/api/w8t/tenant/changeTenantUserRole",
 		},
+		"exportTenantConfig": {
+			Key: "导出租户配置",
+			API: "/api/w8t/tenant/exportTenantConfig",
+		},
+		"importTenantConfig": {
+			Key: "导入租户配置",
+			API: "/api/w8t/tenant/importTenantConfig",
+		},
 		"createProbing": {
 			Key: "创建拨测规则",
 			API: "/api/w8t/probing/createProbing",
@@ -323,6 +371,14 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "查看通知记录",
 			API: "/api/w8t/notice/noticeRecordList",
 		},
+		"noticeDeadLetterList": {
+			Key: "查看死信通知",
+			API: "/api/w8t/notice/deadLetterList",
+		},
+		"noticeDeadLetterRequeue": {
+			Key: "重新投递死信通知",
+			API: "/api/w8t/notice/deadLetterRequeue",
+		},
 		"faultCenterList": {
 			Key: "查看故障中心列表",
 			API: "/api/w8t/faultCenter/faultCenterList",
@@ -351,6 +407,34 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "认领/处理告警",
 			API: "/api/w8t/event/processAlertEvent",
 		},
+		"updateEventAnnotations": {
+			Key: "修改告警事件注解",
+			API: "/api/w8t/event/updateEventAnnotations",
+		},
+		"exportAlertEvent": {
+			Key: "导出告警事件",
+			API: "/api/w8t/event/export",
+		},
+		"streamAlertEvent": {
+			Key: "订阅实时告警事件",
+			API: "/api/w8t/event/stream",
+		},
+		"snoozeAlertEvent": {
+			Key: "暂停告警事件通知",
+			API: "/api/w8t/event/snooze",
+		},
+		"cancelSnoozeAlertEvent": {
+			Key: "取消暂停告警事件通知",
+			API: "/api/w8t/event/cancelSnooze",
+		},
+		"bulkProcessAlertEvent": {
+			Key: "批量认领/关闭/抑制告警",
+			API: "/api/w8t/event/bulkProcess",
+		},
+		"bulkDelete": {
+			Key: "按条件批量删除告警",
+			API: "/api/w8t/event/bulkDelete",
+		},
 		"listComments": {
 			Key: "查看评论",
 			API: "/api/w8t/event/listComments",
@@ -359,6 +443,22 @@ func PermissionsInfo() map[string]UserPermissions {
 			Key: "添加评论",
 			API: "/api/w8t/event/addComment",
 		},
+		"tenantPermissionList": {
+			Key: "查看角色细粒度权限",
+			API: "/api/w8t/role/tenantPermissionList",
+		},
+		"tenantPermissionUpdate": {
+			Key: "修改角色细粒度权限",
+			API: "/api/w8t/role/tenantPermissionUpdate",
+		},
+		"tenantPermissionDelete": {
+			Key: "重置角色细粒度权限",
+			API: "/api/w8t/role/tenantPermissionDelete",
+		},
+		"editComment": {
+			Key: "编辑评论",
+			API: "/api/w8t/event/editComment",
+		},
 		"deleteComment": {
 			Key: "删除评论",
 			API: "/api/w8t/event/deleteComment",
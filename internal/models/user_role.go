@@ -7,3 +7,41 @@ type UserRole struct {
 	Permissions []UserPermissions `json:"permissions" gorm:"permissions;serializer:json"`
 	UpdateAt    int64             `json:"updateAt"`
 }
+
+// 告警事件接口的细粒度权限
+const (
+	PermEventRead     = "event:read"
+	PermEventProcess  = "event:process"
+	PermEventDelete   = "event:delete"
+	PermCommentWrite  = "comment:write"
+	PermCommentDelete = "comment:delete"
+)
+
+// FineGrainedPermissions 细粒度权限及说明
+func FineGrainedPermissions() map[string]string {
+	return map[string]string{
+		PermEventRead:     "查看及导出告警事件、评论",
+		PermEventProcess:  "认领、处理告警事件及修改注解",
+		PermEventDelete:   "删除告警事件",
+		PermCommentWrite:  "发表及编辑评论",
+		PermCommentDelete: "删除评论",
+	}
+}
+
+// TenantRolePermission 租户内角色的细粒度权限, 租户未给角色配置时沿用角色的接口权限
+type TenantRolePermission struct {
+	TenantId    string   `json:"tenantId" gorm:"primaryKey;size:64"`
+	RoleId      string   `json:"roleId" gorm:"primaryKey;size:64"`
+	Permissions []string `json:"permissions" gorm:"permissions;serializer:json"`
+	UpdateAt    int64    `json:"updateAt"`
+}
+
+// HasPermission 是否拥有细粒度权限
+func (t TenantRolePermission) HasPermission(permission string) bool {
+	for _, p := range t.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
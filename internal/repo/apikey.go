@@ -2,6 +2,7 @@ package repo
 
 import (
 	"fmt"
+	"time"
 	"watchAlert/internal/models"
 
 	"gorm.io/gorm"
@@ -18,6 +19,7 @@ type (
 		Create(key models.ApiKey) error
 		Update(key models.ApiKey) error
 		Delete(id int, userId string) error
+		Revoke(id int, userId string) error
 		GetByKey(key string) (models.ApiKey, bool, error)
 	}
 )
@@ -129,3 +131,12 @@ func (ar ApiKeyRepo) Delete(id int, userId string) error {
 
 	return nil
 }
+
+// Revoke 吊销 API 密钥, 保留记录便于审计追溯
+func (ar ApiKeyRepo) Revoke(id int, userId string) error {
+	if _, _, err := ar.Get(id, userId); err != nil {
+		return err
+	}
+
+	return ar.db.Model(&models.ApiKey{}).Where("id = ?", id).Update("revoked_at", time.Now().Unix()).Error
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"gorm.io/gorm"
 	"strconv"
+	"strings"
 	"time"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
@@ -16,7 +17,9 @@ type (
 	InterAuditLogRepo interface {
 		List(r types.RequestAuditLogQuery) (types.ResponseAuditLog, error)
 		Search(r types.RequestAuditLogQuery) (types.ResponseAuditLog, error)
+		Filter(r types.RequestAuditLogFilter) (types.ResponseAuditLog, error)
 		Create(r models.AuditLog) error
+		Prune(tenantId string, before int64, batchSize int) (int64, error)
 	}
 )
 
@@ -115,3 +118,88 @@ func (a AuditLogRepo) Search(r types.RequestAuditLogQuery) (types.ResponseAuditL
 
 	return d, nil
 }
+
+// Filter 按用户、路径前缀、方法、状态码范围、审计类型及时间范围检索租户的审计日志
+func (a AuditLogRepo) Filter(r types.RequestAuditLogFilter) (types.ResponseAuditLog, error) {
+	var db = a.db.Model(&models.AuditLog{})
+	var data []models.AuditLog
+	var count int64
+
+	if r.Page.Index <= 0 {
+		r.Page.Index = 1
+	}
+	if r.Page.Size <= 0 {
+		r.Page.Size = 10
+	}
+
+	db.Where("tenant_id = ?", r.TenantId)
+	if r.Username != "" {
+		db.Where("username = ?", r.Username)
+	}
+	if r.PathPrefix != "" {
+		db.Where("path LIKE ?", r.PathPrefix+"%")
+	}
+	if r.Method != "" {
+		db.Where("method = ?", strings.ToUpper(r.Method))
+	}
+	if r.StatusMin > 0 {
+		db.Where("status_code >= ?", r.StatusMin)
+	}
+	if r.StatusMax > 0 {
+		db.Where("status_code <= ?", r.StatusMax)
+	}
+	if r.AuditType != "" {
+		db.Where("audit_type = ?", r.AuditType)
+	}
+	if r.StartAt > 0 {
+		db.Where("created_at >= ?", r.StartAt)
+	}
+	if r.EndAt > 0 {
+		db.Where("created_at <= ?", r.EndAt)
+	}
+
+	db.Count(&count)
+
+	db.Limit(int(r.Page.Size)).Offset(int((r.Page.Index - 1) * r.Page.Size)).Order("created_at desc")
+	err := db.Find(&data).Error
+	if err != nil {
+		return types.ResponseAuditLog{}, err
+	}
+
+	return types.ResponseAuditLog{
+		List: data,
+		Page: models.Page{
+			Index: r.Page.Index,
+			Size:  r.Page.Size,
+			Total: count,
+		},
+	}, nil
+}
+
+// Prune 分批删除租户在 before 之前的审计日志, 返回删除的总行数
+func (a AuditLogRepo) Prune(tenantId string, before int64, batchSize int) (int64, error) {
+	var total int64
+	for {
+		var ids []string
+		err := a.db.Model(&models.AuditLog{}).
+			Where("tenant_id = ? AND created_at < ?", tenantId, before).
+			Limit(batchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		res := a.db.Where("tenant_id = ? AND id IN ?", tenantId, ids).Delete(&models.AuditLog{})
+		if res.Error != nil {
+			return total, res.Error
+		}
+		total += res.RowsAffected
+
+		if len(ids) < batchSize {
+			return total, nil
+		}
+	}
+}
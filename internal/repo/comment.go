@@ -16,6 +16,8 @@ type (
 	InterCommentRepo interface {
 		Add(r types.RequestAddEventComment) error
 		Delete(r types.RequestDeleteEventComment) error
+		Get(tenantId, commentId string) (models.Comment, error)
+		Edit(r types.RequestEditEventComment) error
 		List(r types.RequestListEventComments) ([]models.Comment, error)
 	}
 )
@@ -51,6 +53,7 @@ func (c CommentRepo) Add(r types.RequestAddEventComment) error {
 		UserId:      r.UserId,
 		Time:        time.Now().Unix(),
 		Content:     r.Content,
+		Mentions:    r.Mentions,
 	}).Error
 }
 
@@ -58,3 +61,22 @@ func (c CommentRepo) Delete(r types.RequestDeleteEventComment) error {
 	db := c.db.Model(&models.Comment{})
 	return db.Where("tenant_id = ? AND comment_id = ?", r.TenantId, r.CommentId).Delete(&models.Comment{}).Error
 }
+
+func (c CommentRepo) Get(tenantId, commentId string) (models.Comment, error) {
+	var data models.Comment
+	db := c.db.Model(&models.Comment{})
+	db.Where("tenant_id = ? AND comment_id = ?", tenantId, commentId)
+	if err := db.First(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// Edit 仅更新评论内容及编辑时间, 保留原始评论时间
+func (c CommentRepo) Edit(r types.RequestEditEventComment) error {
+	db := c.db.Model(&models.Comment{})
+	return db.Where("tenant_id = ? AND comment_id = ?", r.TenantId, r.CommentId).Updates(map[string]interface{}{
+		"content":    r.Content,
+		"updated_at": time.Now().Unix(),
+	}).Error
+}
@@ -12,7 +12,7 @@ type (
 		entryRepo
 	}
 	InterDutyRepo interface {
-		GetQuota(id string) bool
+		GetQuota(id string) error
 		List(tenantId string) ([]models.DutyManagement, error)
 		Create(r models.DutyManagement) error
 		Update(r models.DutyManagement) error
@@ -30,7 +30,8 @@ func newDutyInterface(db *gorm.DB, g InterGormDBCli) InterDutyRepo {
 	}
 }
 
-func (d DutyRepo) GetQuota(id string) bool {
+// GetQuota 校验租户值班表配额, 配额不足时返回 QuotaExceededError
+func (d DutyRepo) GetQuota(id string) error {
 	var (
 		db     = d.DB().Model(&models.Tenant{})
 		data   models.Tenant
@@ -43,10 +44,10 @@ func (d DutyRepo) GetQuota(id string) bool {
 	d.DB().Model(&models.DutyManagement{}).Where("tenant_id = ?", id).Count(&Number)
 
 	if Number < data.DutyNumber {
-		return true
+		return nil
 	}
 
-	return false
+	return models.QuotaExceededError{Resource: "值班表", Used: Number, Quota: data.DutyNumber}
 }
 
 func (d DutyRepo) List(tenantId string) ([]models.DutyManagement, error) {
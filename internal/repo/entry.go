@@ -40,6 +40,7 @@ type (
 		Comment() InterCommentRepo
 		Topology() InterTopologyRepo
 		ApiKey() InterApiKeyRepo
+		Heartbeat() InterHeartbeatRepo
 	}
 )
 
@@ -92,3 +93,4 @@ func (e *entryRepo) Ai() InterAiRepo                   { return newAiRepoInterfa
 func (e *entryRepo) Comment() InterCommentRepo         { return newCommentInterface(e.db, e.g) }
 func (e *entryRepo) Topology() InterTopologyRepo       { return newInterTopologyRepo(e.db, e.g) }
 func (e *entryRepo) ApiKey() InterApiKeyRepo           { return newApiKeyInterface(e.db, e.g) }
+func (e *entryRepo) Heartbeat() InterHeartbeatRepo     { return newHeartbeatInterface(e.db, e.g) }
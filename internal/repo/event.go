@@ -1,6 +1,9 @@
 package repo
 
 import (
+	"fmt"
+	"strings"
+
 	"gorm.io/gorm"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
@@ -13,7 +16,10 @@ type (
 
 	InterEventRepo interface {
 		GetHistoryEvent(r types.RequestAlertHisEventQuery) (types.ResponseHistoryEventList, error)
+		StreamHistoryEvent(r types.RequestAlertHisEventQuery, fn func(models.AlertHisEvent) error) error
 		CreateHistoryEvent(r models.AlertHisEvent) error
+		PruneHistoryEvent(tenantId string, before int64, batchSize int) (int64, error)
+		GetHistoryEventCutoff(tenantId string, maxCount int64) (int64, error)
 	}
 )
 
@@ -30,25 +36,7 @@ func (e EventRepo) GetHistoryEvent(r types.RequestAlertHisEventQuery) (types.Res
 	var data []models.AlertHisEvent
 	var count int64
 
-	db := e.DB().Model(&models.AlertHisEvent{})
-	db.Where("tenant_id = ?", r.TenantId)
-	db.Where("fault_center_id = ?", r.FaultCenterId)
-
-	if r.Query != "" {
-		db.Where("rule_name LIKE ? OR severity LIKE ? OR annotations LIKE ? OR fingerprint LIKE ?", "%"+r.Query+"%", "%"+r.Query+"%", "%"+r.Query+"%", "%"+r.Query+"%")
-	}
-
-	if r.DatasourceType != "" {
-		db = db.Where("datasource_type = ?", r.DatasourceType)
-	}
-
-	if r.Severity != "" {
-		db = db.Where("severity = ?", r.Severity)
-	}
-
-	if r.StartAt != 0 && r.EndAt != 0 {
-		db = db.Where("first_trigger_time > ? and first_trigger_time < ?", r.StartAt, r.EndAt)
-	}
+	db := e.historyEventQuery(r)
 
 	if err := db.Count(&count).Error; err != nil {
 		return types.ResponseHistoryEventList{}, err
@@ -77,6 +65,74 @@ func (e EventRepo) GetHistoryEvent(r types.RequestAlertHisEventQuery) (types.Res
 	}, nil
 }
 
+// StreamHistoryEvent 按查询条件逐行读取历史事件, 避免一次性加载全部数据
+func (e EventRepo) StreamHistoryEvent(r types.RequestAlertHisEventQuery, fn func(models.AlertHisEvent) error) error {
+	rows, err := e.historyEventQuery(r).Order("first_trigger_time asc").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event models.AlertHisEvent
+		if err := e.DB().ScanRows(rows, &event); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// historyEventQuery 构建历史事件的查询条件
+func (e EventRepo) historyEventQuery(r types.RequestAlertHisEventQuery) *gorm.DB {
+	db := e.DB().Model(&models.AlertHisEvent{})
+	db.Where("tenant_id = ?", r.TenantId)
+	db.Where("fault_center_id = ?", r.FaultCenterId)
+
+	if r.Query != "" {
+		db.Where("(rule_name LIKE ? OR severity LIKE ? OR annotations LIKE ? OR fingerprint LIKE ?)", "%"+r.Query+"%", "%"+r.Query+"%", "%"+r.Query+"%", "%"+r.Query+"%")
+	}
+
+	// 关键字搜索, 在租户及故障中心范围内匹配 rule_name、annotations、labels, 多个关键字以空格分隔且需同时满足
+	for _, keyword := range strings.Fields(r.Search) {
+		like := "%" + keyword + "%"
+		db.Where("(rule_name LIKE ? OR annotations LIKE ? OR labels LIKE ?)", like, like, like)
+	}
+
+	if r.DatasourceType != "" {
+		db = db.Where("datasource_type = ?", r.DatasourceType)
+	}
+
+	if r.Severity != "" {
+		db = db.Where("severity = ?", r.Severity)
+	}
+
+	if r.StartAt != 0 && r.EndAt != 0 {
+		db = db.Where("first_trigger_time > ? and first_trigger_time < ?", r.StartAt, r.EndAt)
+	}
+
+	// 标签匹配条件, 标签以 JSON 存储, 正则与静默规则一致为非锚定匹配
+	for _, matcher := range r.LabelMatchers {
+		value := "JSON_UNQUOTE(JSON_EXTRACT(labels, ?))"
+		path := fmt.Sprintf(`$."%s"`, matcher.Key)
+		switch matcher.Operator {
+		case "=", "==":
+			db = db.Where(value+" = ?", path, matcher.Value)
+		case "!=":
+			db = db.Where("JSON_EXTRACT(labels, ?) IS NOT NULL AND "+value+" != ?", path, path, matcher.Value)
+		case "=~":
+			db = db.Where(value+" REGEXP ?", path, matcher.Value)
+		case "!~":
+			db = db.Where("JSON_EXTRACT(labels, ?) IS NOT NULL AND "+value+" NOT REGEXP ?", path, path, matcher.Value)
+		}
+	}
+
+	return db
+}
+
 func (e EventRepo) CreateHistoryEvent(r models.AlertHisEvent) error {
 	err := e.g.Create(models.AlertHisEvent{}, r)
 	if err != nil {
@@ -85,3 +141,47 @@ func (e EventRepo) CreateHistoryEvent(r models.AlertHisEvent) error {
 
 	return nil
 }
+
+// PruneHistoryEvent 分批删除租户恢复时间早于 before 的历史事件, 返回删除的行数
+func (e EventRepo) PruneHistoryEvent(tenantId string, before int64, batchSize int) (int64, error) {
+	var total int64
+	for {
+		var ids []string
+		err := e.db.Model(&models.AlertHisEvent{}).
+			Where("tenant_id = ? AND recover_time < ?", tenantId, before).
+			Limit(batchSize).
+			Pluck("event_id", &ids).Error
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		res := e.db.Where("tenant_id = ? AND recover_time < ? AND event_id IN ?", tenantId, before, ids).Delete(&models.AlertHisEvent{})
+		if res.Error != nil {
+			return total, res.Error
+		}
+		total += res.RowsAffected
+
+		if len(ids) < batchSize || res.RowsAffected == 0 {
+			return total, nil
+		}
+	}
+}
+
+// GetHistoryEventCutoff 获取保留最近 maxCount 条历史事件时的恢复时间下限, 未超出数量时返回 0
+func (e EventRepo) GetHistoryEventCutoff(tenantId string, maxCount int64) (int64, error) {
+	var recoverTimes []int64
+	err := e.db.Model(&models.AlertHisEvent{}).
+		Where("tenant_id = ?", tenantId).
+		Order("recover_time desc").
+		Offset(int(maxCount-1)).
+		Limit(1).
+		Pluck("recover_time", &recoverTimes).Error
+	if err != nil || len(recoverTimes) == 0 {
+		return 0, err
+	}
+
+	return recoverTimes[0], nil
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"fmt"
+	"watchAlert/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type (
+	HeartbeatRepo struct {
+		entryRepo
+	}
+
+	InterHeartbeatRepo interface {
+		List(tenantId, faultCenterId, query string) ([]models.Heartbeat, error)
+		ListAll() ([]models.Heartbeat, error)
+		Get(tenantId, id, name string) (models.Heartbeat, error)
+		Create(r models.Heartbeat) error
+		Update(r models.Heartbeat) error
+		Delete(tenantId, id string) error
+	}
+)
+
+func newHeartbeatInterface(db *gorm.DB, g InterGormDBCli) InterHeartbeatRepo {
+	return &HeartbeatRepo{
+		entryRepo{
+			g:  g,
+			db: db,
+		},
+	}
+}
+
+func (hr HeartbeatRepo) List(tenantId, faultCenterId, query string) ([]models.Heartbeat, error) {
+	var data []models.Heartbeat
+	db := hr.db.Model(&models.Heartbeat{})
+	db.Where("tenant_id = ?", tenantId)
+
+	if faultCenterId != "" {
+		db.Where("fault_center_id = ?", faultCenterId)
+	}
+
+	if query != "" {
+		db.Where("id LIKE ? OR name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%")
+	}
+
+	err := db.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListAll 获取所有租户的心跳, 用于超时检查
+func (hr HeartbeatRepo) ListAll() ([]models.Heartbeat, error) {
+	var data []models.Heartbeat
+	err := hr.db.Model(&models.Heartbeat{}).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Get 按 ID 或名称获取心跳
+func (hr HeartbeatRepo) Get(tenantId, id, name string) (models.Heartbeat, error) {
+	var data models.Heartbeat
+	db := hr.db.Model(&models.Heartbeat{})
+	db.Where("tenant_id = ?", tenantId)
+
+	if id != "" {
+		db.Where("id = ?", id)
+	}
+
+	if name != "" {
+		db.Where("name = ?", name)
+	}
+
+	err := db.First(&data).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return data, fmt.Errorf("心跳不存在")
+		}
+		return data, err
+	}
+
+	return data, nil
+}
+
+func (hr HeartbeatRepo) Create(r models.Heartbeat) error {
+	err := hr.g.Create(&models.Heartbeat{}, &r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (hr HeartbeatRepo) Update(r models.Heartbeat) error {
+	u := Updates{
+		Table: &models.Heartbeat{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": r.TenantId,
+			"id = ?":        r.ID,
+		},
+		Updates: r,
+	}
+
+	err := hr.g.Updates(u)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (hr HeartbeatRepo) Delete(tenantId, id string) error {
+	del := Delete{
+		Table: &models.Heartbeat{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": tenantId,
+			"id = ?":        id,
+		},
+	}
+
+	err := hr.g.Delete(del)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -17,7 +17,7 @@ type (
 
 	InterNoticeRepo interface {
 		Get(tenantId, id string) (models.AlertNotice, error)
-		GetQuota(id string) bool
+		GetQuota(id string) error
 		List(tenantId, noticeTmplId, query string) ([]models.AlertNotice, error)
 		Create(r models.AlertNotice) error
 		Update(r models.AlertNotice) error
@@ -38,7 +38,8 @@ func newNoticeInterface(db *gorm.DB, g InterGormDBCli) InterNoticeRepo {
 	}
 }
 
-func (nr NoticeRepo) GetQuota(id string) bool {
+// GetQuota 校验租户通知对象配额, 配额不足时返回 QuotaExceededError
+func (nr NoticeRepo) GetQuota(id string) error {
 	var (
 		db     = nr.db.Model(&models.Tenant{})
 		data   models.Tenant
@@ -51,10 +52,10 @@ func (nr NoticeRepo) GetQuota(id string) bool {
 	nr.db.Model(&models.AlertNotice{}).Where("tenant_id = ?", id).Count(&Number)
 
 	if Number < data.NoticeNumber {
-		return true
+		return nil
 	}
 
-	return false
+	return models.QuotaExceededError{Resource: "通知对象", Used: Number, Quota: data.NoticeNumber}
 }
 
 func (nr NoticeRepo) Get(tenantId, id string) (models.AlertNotice, error) {
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"time"
 	"watchAlert/internal/models"
 
 	"gorm.io/gorm"
@@ -12,7 +13,7 @@ type (
 	}
 
 	InterRuleRepo interface {
-		GetQuota(id string) bool
+		GetQuota(id string) error
 		Get(tenantId, ruleGroupId, ruleId string) (models.AlertRule, error)
 		List(tenantId, ruleGroupId, datasourceType, query, status string, page models.Page) ([]models.AlertRule, int64, error)
 		Create(r models.AlertRule) error
@@ -21,6 +22,11 @@ type (
 		GetRuleIsExist(ruleId string) bool
 		GetRuleObject(ruleId string) models.AlertRule
 		ChangeStatus(tenantId, ruleGroupId, ruleId string, state *bool) error
+		Pause(tenantId, ruleGroupId, ruleId string, pausedUntil int64) error
+		MarkEvalError(tenantId, ruleId, message string, at int64) error
+		ClearEvalError(tenantId, ruleId string) error
+		ListByTemplate(templateGroup, templateName string) ([]models.AlertRule, error)
+		GetStates(ruleIds []string) (map[string]models.AlertRule, error)
 	}
 )
 
@@ -33,7 +39,8 @@ func newRuleInterface(db *gorm.DB, g InterGormDBCli) InterRuleRepo {
 	}
 }
 
-func (rr RuleRepo) GetQuota(id string) bool {
+// GetQuota 校验租户告警规则配额, 配额不足时返回 QuotaExceededError
+func (rr RuleRepo) GetQuota(id string) error {
 	var (
 		db     = rr.db.Model(&models.Tenant{})
 		data   models.Tenant
@@ -46,10 +53,10 @@ func (rr RuleRepo) GetQuota(id string) bool {
 	rr.db.Model(&models.AlertRule{}).Where("tenant_id = ?", id).Count(&Number)
 
 	if Number < data.RuleNumber {
-		return true
+		return nil
 	}
 
-	return false
+	return models.QuotaExceededError{Resource: "告警规则", Used: Number, Quota: data.RuleNumber}
 }
 
 func (rr RuleRepo) Get(tenantId, ruleGroupId, ruleId string) (models.AlertRule, error) {
@@ -92,6 +99,8 @@ func (rr RuleRepo) List(tenantId, ruleGroupId, datasourceType, query, status str
 			db.Where("enabled = ?", true)
 		case "disabled":
 			db.Where("enabled = ?", false)
+		case "paused":
+			db.Where("enabled = ? AND paused_until > ?", true, time.Now().Unix())
 		}
 	}
 
@@ -179,3 +188,66 @@ func (rr RuleRepo) ChangeStatus(tenantId, ruleGroupId, ruleId string, state *boo
 		Where("tenant_id = ? AND rule_group_id = ? AND rule_id = ?", tenantId, ruleGroupId, ruleId).
 		Update("enabled", state).Error
 }
+
+// Pause 设置规则暂停评估的截止时间, 为 0 时恢复评估
+func (rr RuleRepo) Pause(tenantId, ruleGroupId, ruleId string, pausedUntil int64) error {
+	return rr.DB().Model(&models.AlertRule{}).
+		Where("tenant_id = ? AND rule_group_id = ? AND rule_id = ?", tenantId, ruleGroupId, ruleId).
+		Update("paused_until", pausedUntil).Error
+}
+
+// MarkEvalError 停用评估异常的规则并记录异常信息
+func (rr RuleRepo) MarkEvalError(tenantId, ruleId, message string, at int64) error {
+	return rr.DB().Model(&models.AlertRule{}).
+		Where("tenant_id = ? AND rule_id = ?", tenantId, ruleId).
+		Updates(map[string]interface{}{
+			"enabled":       false,
+			"eval_error":    message,
+			"eval_error_at": at,
+		}).Error
+}
+
+// ClearEvalError 清除规则的评估异常信息
+func (rr RuleRepo) ClearEvalError(tenantId, ruleId string) error {
+	return rr.DB().Model(&models.AlertRule{}).
+		Where("tenant_id = ? AND rule_id = ?", tenantId, ruleId).
+		Updates(map[string]interface{}{
+			"eval_error":    "",
+			"eval_error_at": 0,
+		}).Error
+}
+
+// ListByTemplate 获取由指定模版实例化的规则, 模版由模版组及名称共同确定
+func (rr RuleRepo) ListByTemplate(templateGroup, templateName string) ([]models.AlertRule, error) {
+	var data []models.AlertRule
+	err := rr.db.Model(&models.AlertRule{}).
+		Where("template_group = ? AND template_name = ?", templateGroup, templateName).
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetStates 批量获取规则的启用状态及评估异常信息, 不存在的规则不会出现在结果中
+func (rr RuleRepo) GetStates(ruleIds []string) (map[string]models.AlertRule, error) {
+	states := make(map[string]models.AlertRule, len(ruleIds))
+	if len(ruleIds) == 0 {
+		return states, nil
+	}
+
+	var data []models.AlertRule
+	err := rr.db.Model(&models.AlertRule{}).
+		Select("rule_id", "enabled", "eval_error").
+		Where("rule_id IN ?", ruleIds).
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range data {
+		states[rule.RuleId] = rule
+	}
+	return states, nil
+}
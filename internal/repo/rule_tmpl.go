@@ -12,6 +12,7 @@ type (
 
 	InterRuleTmplRepo interface {
 		List(tmplGroup, tmplType, query string, page models.Page) ([]models.RuleTemplate, int64, error)
+		Get(tmplGroupName, tmplName string) (models.RuleTemplate, error)
 		Create(r models.RuleTemplate) error
 		Update(r models.RuleTemplate) error
 		Delete(tmplGroupName, tmplName string) error
@@ -52,6 +53,18 @@ func (rt RuleTmplRepo) List(tmplGroup, tmplType, query string, page models.Page)
 	return data, count, nil
 }
 
+func (rt RuleTmplRepo) Get(tmplGroupName, tmplName string) (models.RuleTemplate, error) {
+	var data models.RuleTemplate
+	err := rt.db.Model(&models.RuleTemplate{}).
+		Where("rule_group_name = ? AND rule_name = ?", tmplGroupName, tmplName).
+		First(&data).Error
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
 func (rt RuleTmplRepo) Create(r models.RuleTemplate) error {
 	err := rt.g.Create(models.RuleTemplate{}, r)
 	if err != nil {
@@ -18,6 +18,7 @@ type (
 		Update(t models.Tenant) error
 		Delete(tenantId string) error
 		List(userId string) (data []models.Tenant, err error)
+		ListAll() ([]models.Tenant, error)
 		Get(tenantId string) (data models.Tenant, err error)
 		CreateTenantLinkedUserRecord(t models.TenantLinkedUsers) error
 		AddTenantLinkedUsers(tenantId string, users []models.TenantUser, userRole string) error
@@ -152,6 +153,17 @@ func (tr TenantRepo) List(userId string) (data []models.Tenant, err error) {
 	return *ts, nil
 }
 
+// ListAll 获取全部租户
+func (tr TenantRepo) ListAll() ([]models.Tenant, error) {
+	var data []models.Tenant
+	err := tr.db.Model(&models.Tenant{}).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 func (tr TenantRepo) Get(tenantId string) (data models.Tenant, err error) {
 	var d models.Tenant
 	err = tr.db.Model(&models.Tenant{}).Where("id = ?", tenantId).First(&d).Error
@@ -1,8 +1,10 @@
 package repo
 
 import (
-	"gorm.io/gorm"
+	"errors"
 	"watchAlert/internal/models"
+
+	"gorm.io/gorm"
 )
 
 type (
@@ -15,6 +17,10 @@ type (
 		Create(r models.UserRole) error
 		Update(r models.UserRole) error
 		Delete(id string) error
+		ListTenantPermissions(tenantId string) ([]models.TenantRolePermission, error)
+		GetTenantPermission(tenantId, roleId string) (models.TenantRolePermission, bool, error)
+		SaveTenantPermission(p models.TenantRolePermission) error
+		DeleteTenantPermission(tenantId, roleId string) error
 	}
 )
 
@@ -82,3 +88,44 @@ func (ur UserRoleRepo) Delete(id string) error {
 
 	return nil
 }
+
+// ListTenantPermissions 获取租户内角色的细粒度权限
+func (ur UserRoleRepo) ListTenantPermissions(tenantId string) ([]models.TenantRolePermission, error) {
+	var data []models.TenantRolePermission
+	err := ur.DB().Model(&models.TenantRolePermission{}).Where("tenant_id = ?", tenantId).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetTenantPermission 获取租户内单个角色的细粒度权限, 未配置时返回 false
+func (ur UserRoleRepo) GetTenantPermission(tenantId, roleId string) (models.TenantRolePermission, bool, error) {
+	var data models.TenantRolePermission
+	err := ur.DB().Model(&models.TenantRolePermission{}).Where("tenant_id = ? AND role_id = ?", tenantId, roleId).First(&data).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return data, false, nil
+		}
+		return data, false, err
+	}
+
+	return data, true, nil
+}
+
+// SaveTenantPermission 新增或覆盖租户内角色的细粒度权限
+func (ur UserRoleRepo) SaveTenantPermission(p models.TenantRolePermission) error {
+	return ur.DB().Save(&p).Error
+}
+
+// DeleteTenantPermission 删除租户内角色的细粒度权限, 删除后恢复为角色的接口权限
+func (ur UserRoleRepo) DeleteTenantPermission(tenantId, roleId string) error {
+	return ur.g.Delete(Delete{
+		Table: models.TenantRolePermission{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": tenantId,
+			"role_id = ?":   roleId,
+		},
+	})
+}
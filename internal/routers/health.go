@@ -1,13 +1,22 @@
 package routers
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
 	"net/http"
+	"time"
+	"watchAlert/internal/ctx"
+
+	"github.com/gin-gonic/gin"
 )
 
+// 就绪检查中单项依赖检查的超时时间
+const readinessCheckTimeout = 2 * time.Second
+
 func HealthCheck(gin *gin.Engine) {
 
 	gin.GET("hello", health)
+	gin.GET("healthz", liveness)
+	gin.GET("readyz", readiness)
 
 }
 
@@ -18,3 +27,67 @@ func health(ctx *gin.Context) {
 	})
 
 }
+
+// liveness 存活检查, 进程能够处理请求即返回成功, 不检查外部依赖
+func liveness(ctx *gin.Context) {
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+
+}
+
+// readiness 就绪检查, 检查 Redis 及数据库的连接, 任一不可用时返回 503, 避免流量转发到异常的实例
+func readiness(c *gin.Context) {
+	checks := gin.H{
+		"redis":    checkRedis(),
+		"database": checkDatabase(c.Request.Context()),
+	}
+
+	status, code := "ok", http.StatusOK
+	for _, result := range checks {
+		if result != "ok" {
+			status, code = "degraded", http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status":      status,
+		"checks":      checks,
+		"activeEvals": ctx.Metrics.ActiveEvals(),
+	})
+}
+
+// checkRedis 检查 Redis 连接, 返回 ok 或错误信息
+func checkRedis() string {
+	if ctx.Redis == nil {
+		return "not initialized"
+	}
+
+	if err := ctx.Redis.Redis().Ping().Err(); err != nil {
+		return err.Error()
+	}
+
+	return "ok"
+}
+
+// checkDatabase 检查数据库连接, 返回 ok 或错误信息
+func checkDatabase(c context.Context) string {
+	if ctx.DB == nil {
+		return "not initialized"
+	}
+
+	db, err := ctx.DB.DB().DB()
+	if err != nil {
+		return err.Error()
+	}
+
+	pingCtx, cancel := context.WithTimeout(c, readinessCheckTimeout)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		return err.Error()
+	}
+
+	return "ok"
+}
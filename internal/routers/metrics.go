@@ -0,0 +1,15 @@
+package routers
+
+import (
+	"watchAlert/internal/ctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics 暴露评估引擎的 Prometheus 指标
+func Metrics(engine *gin.Engine) {
+
+	engine.GET("metrics", gin.WrapH(promhttp.HandlerFor(ctx.Metrics.Registry, promhttp.HandlerOpts{})))
+
+}
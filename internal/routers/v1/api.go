@@ -48,6 +48,7 @@ func Router(engine *gin.Engine) {
 			api.AiController.API(w8t)
 			api.TopologyController.API(w8t)
 			api.ApiKeyController.API(w8t)
+			api.HeartbeatController.API(w8t)
 		}
 
 		oidc := v1.Group("oidc")
@@ -20,6 +20,7 @@ type InterApiKeyService interface {
 	Get(req interface{}) (interface{}, interface{})
 	Update(req interface{}) (interface{}, interface{})
 	Delete(req interface{}) (interface{}, interface{})
+	Revoke(req interface{}) (interface{}, interface{})
 	GetApiKeyByUserId(userId string) ([]types.ResponseApiKeyInfo, error)
 }
 
@@ -44,11 +45,18 @@ func (aks apiKeyService) Create(req interface{}) (interface{}, interface{}) {
 		return nil, fmt.Errorf("用户ID不能为空")
 	}
 
+	if err := aks.validateScope(userId, r.TenantId, r.Permissions, r.ExpiresAt); err != nil {
+		return nil, err
+	}
+
 	model := models.ApiKey{
 		UserId:      userId,
 		Name:        r.Name,
 		Description: r.Description,
 		Key:         apiKey,
+		TenantId:    r.TenantId,
+		Permissions: r.Permissions,
+		ExpiresAt:   r.ExpiresAt,
 		CreatedAt:   time.Now(),
 	}
 
@@ -58,14 +66,7 @@ func (aks apiKeyService) Create(req interface{}) (interface{}, interface{}) {
 	}
 
 	// 返回不包含敏感信息的结果
-	result := types.ResponseApiKeyInfo{
-		ID:          model.ID,
-		UserId:      model.UserId,
-		Name:        model.Name,
-		Description: model.Description,
-		Key:         model.Key,
-		CreatedAt:   model.CreatedAt.Unix(),
-	}
+	result := newApiKeyInfo(model)
 
 	return result, nil
 }
@@ -80,14 +81,7 @@ func (aks apiKeyService) List(req interface{}) (interface{}, interface{}) {
 
 	var result []types.ResponseApiKeyInfo
 	for _, item := range data {
-		result = append(result, types.ResponseApiKeyInfo{
-			ID:          item.ID,
-			UserId:      item.UserId,
-			Name:        item.Name,
-			Description: item.Description,
-			Key:         item.Key,
-			CreatedAt:   item.CreatedAt.Unix(),
-		})
+		result = append(result, newApiKeyInfo(item))
 	}
 
 	return result, nil
@@ -101,14 +95,7 @@ func (aks apiKeyService) Get(req interface{}) (interface{}, interface{}) {
 		return nil, err
 	}
 
-	result := types.ResponseApiKeyInfo{
-		ID:          data.ID,
-		UserId:      data.UserId,
-		Name:        data.Name,
-		Description: data.Description,
-		Key:         data.Key,
-		CreatedAt:   data.CreatedAt.Unix(),
-	}
+	result := newApiKeyInfo(data)
 
 	return result, nil
 }
@@ -122,11 +109,19 @@ func (aks apiKeyService) Update(req interface{}) (interface{}, interface{}) {
 		return nil, err
 	}
 
+	if err := aks.validateScope(existing.UserId, r.TenantId, r.Permissions, r.ExpiresAt); err != nil {
+		return nil, err
+	}
+
 	model := models.ApiKey{
 		ID:          r.ID,
 		UserId:      existing.UserId, // 确保不能更改所属用户
 		Name:        r.Name,
 		Description: r.Description,
+		TenantId:    r.TenantId,
+		Permissions: r.Permissions,
+		ExpiresAt:   r.ExpiresAt,
+		RevokedAt:   existing.RevokedAt,
 		CreatedAt:   existing.CreatedAt,
 	}
 
@@ -136,14 +131,7 @@ func (aks apiKeyService) Update(req interface{}) (interface{}, interface{}) {
 	}
 
 	// 返回不包含敏感信息的结果
-	result := types.ResponseApiKeyInfo{
-		ID:          model.ID,
-		UserId:      model.UserId,
-		Name:        model.Name,
-		Description: model.Description,
-		Key:         model.Key,
-		CreatedAt:   model.CreatedAt.Unix(),
-	}
+	result := newApiKeyInfo(model)
 
 	return result, nil
 }
@@ -159,6 +147,18 @@ func (aks apiKeyService) Delete(req interface{}) (interface{}, interface{}) {
 	return nil, nil
 }
 
+// Revoke 吊销API密钥, 吊销后立即失效且不可恢复
+func (aks apiKeyService) Revoke(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestApiKeyQuery)
+
+	err := aks.ctx.DB.ApiKey().Revoke(r.ID, r.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 // GetApiKeyByUserId 根据用户ID获取API密钥列表
 func (aks apiKeyService) GetApiKeyByUserId(userId string) ([]types.ResponseApiKeyInfo, error) {
 	data, err := aks.ctx.DB.ApiKey().List(userId)
@@ -168,19 +168,75 @@ func (aks apiKeyService) GetApiKeyByUserId(userId string) ([]types.ResponseApiKe
 
 	var result []types.ResponseApiKeyInfo
 	for _, item := range data {
-		result = append(result, types.ResponseApiKeyInfo{
-			ID:          item.ID,
-			UserId:      item.UserId,
-			Name:        item.Name,
-			Description: item.Description,
-			Key:         item.Key,
-			CreatedAt:   item.CreatedAt.Unix(),
-		})
+		result = append(result, newApiKeyInfo(item))
 	}
 
 	return result, nil
 }
 
+// validateScope 校验API密钥的租户范围、接口权限及过期时间, 只能限定到所属用户已加入的租户, 且接口权限不能超出所属用户的角色权限
+func (aks apiKeyService) validateScope(userId, tenantId string, permissions []string, expiresAt int64) error {
+	if expiresAt != 0 && expiresAt <= time.Now().Unix() {
+		return fmt.Errorf("过期时间不能早于当前时间")
+	}
+
+	if userId == "admin" || (tenantId == "" && len(permissions) == 0) {
+		return nil
+	}
+
+	// 未限定租户时, 以用户在所有已加入租户中的角色权限为上限
+	tenants := []string{tenantId}
+	if tenantId == "" {
+		user, _, err := aks.ctx.DB.User().Get(userId, "", "")
+		if err != nil {
+			return fmt.Errorf("获取用户信息失败: %v", err)
+		}
+		tenants = user.Tenants
+	}
+
+	allowed := make(map[string]struct{})
+	for _, tid := range tenants {
+		user, err := aks.ctx.DB.Tenant().GetTenantLinkedUserInfo(tid, userId)
+		if err != nil || user.UserID == "" {
+			return fmt.Errorf("用户未加入租户 %s", tid)
+		}
+
+		var role models.UserRole
+		err = aks.ctx.DB.DB().Model(&models.UserRole{}).Where("id = ?", user.UserRole).First(&role).Error
+		if err != nil {
+			return fmt.Errorf("获取用户在租户 %s 的角色失败: %v", tid, err)
+		}
+
+		for _, p := range role.Permissions {
+			allowed[p.API] = struct{}{}
+		}
+	}
+
+	for _, p := range permissions {
+		if _, ok := allowed[p]; !ok {
+			return fmt.Errorf("接口权限 %s 超出所属用户的角色权限", p)
+		}
+	}
+
+	return nil
+}
+
+// newApiKeyInfo 转换为API密钥响应信息
+func newApiKeyInfo(item models.ApiKey) types.ResponseApiKeyInfo {
+	return types.ResponseApiKeyInfo{
+		ID:          item.ID,
+		UserId:      item.UserId,
+		Name:        item.Name,
+		Description: item.Description,
+		Key:         item.Key,
+		TenantId:    item.TenantId,
+		Permissions: item.Permissions,
+		ExpiresAt:   item.ExpiresAt,
+		RevokedAt:   item.RevokedAt,
+		CreatedAt:   item.CreatedAt.Unix(),
+	}
+}
+
 // generateApiKey 生成随机API密钥
 func generateApiKey() (string, error) {
 	bytes := make([]byte, 32) // 256位密钥
@@ -1,8 +1,16 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"time"
+	"watchAlert/config"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+
+	"github.com/robfig/cron/v3"
+	"github.com/zeromicro/go-zero/core/logc"
 )
 
 type auditLogService struct {
@@ -12,6 +20,8 @@ type auditLogService struct {
 type InterAuditLogService interface {
 	List(req interface{}) (interface{}, interface{})
 	Search(req interface{}) (interface{}, interface{})
+	Filter(req interface{}) (interface{}, interface{})
+	PruneCronjob(ctx context.Context)
 }
 
 func newInterAuditLogService(ctx *ctx.Context) InterAuditLogService {
@@ -39,3 +49,80 @@ func (as auditLogService) Search(req interface{}) (interface{}, interface{}) {
 
 	return data, nil
 }
+
+func (as auditLogService) Filter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestAuditLogFilter)
+	if r.StartAt > 0 && r.EndAt > 0 && r.StartAt > r.EndAt {
+		return nil, fmt.Errorf("开始时间不能晚于结束时间")
+	}
+	if r.StatusMin > 0 && r.StatusMax > 0 && r.StatusMin > r.StatusMax {
+		return nil, fmt.Errorf("状态码下限不能大于上限")
+	}
+
+	data, err := as.ctx.DB.AuditLog().Filter(*r)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// PruneCronjob 定期清理超出保留期的审计日志
+func (as auditLogService) PruneCronjob(ctx context.Context) {
+	spec := config.Application.AuditLog.PruneCronjob
+	if spec == "" {
+		spec = "0 3 * * *"
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(spec, as.prune)
+	if err != nil {
+		logc.Errorf(ctx, "创建审计日志清理任务失败, err: %s", err.Error())
+		return
+	}
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case <-ctx.Done():
+		logc.Infof(ctx, "停止审计日志清理!")
+		return
+	}
+}
+
+// prune 按租户保留天数分批删除过期的审计日志, 租户未配置时使用全局保留天数
+func (as auditLogService) prune() {
+	batchSize := config.Application.AuditLog.PruneBatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	tenants, err := as.ctx.DB.Tenant().ListAll()
+	if err != nil {
+		logc.Errorf(as.ctx.Ctx, "获取租户列表失败, err: %s", err.Error())
+		return
+	}
+
+	var total int64
+	for _, tenant := range tenants {
+		retention := tenant.AuditLogRetention
+		if retention <= 0 {
+			retention = config.Application.AuditLog.Retention
+		}
+		if retention <= 0 {
+			continue
+		}
+
+		before := time.Now().Add(-time.Duration(retention) * 24 * time.Hour).Unix()
+		count, err := as.ctx.DB.AuditLog().Prune(tenant.ID, before, batchSize)
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(as.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "清理审计日志失败, err: %s", err.Error())
+		}
+		if count > 0 {
+			logc.Infof(tools.WithLogFields(as.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "清理审计日志 %d 条, 保留天数: %d", count, retention)
+		}
+		total += count
+	}
+
+	logc.Infof(as.ctx.Ctx, "审计日志清理完成, 共删除 %d 条", total)
+}
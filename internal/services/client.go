@@ -30,7 +30,17 @@ func (cs clientService) GetJaegerService(req interface{}) (interface{}, interfac
 		return nil, err
 	}
 
-	cli, err := provider.NewJaegerClient(getInfo)
+	var cli provider.TracesFactoryProvider
+	switch getInfo.Type {
+	case provider.TempoDsProviderName:
+		cli, err = provider.NewTempoClient(getInfo)
+	default:
+		cli, err = provider.NewJaegerClient(getInfo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := cli.GetJaegerService()
 	if err != nil {
 		return nil, err
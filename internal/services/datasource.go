@@ -47,13 +47,25 @@ func (ds datasourceService) Create(req interface{}) (interface{}, interface{}) {
 		DsAliCloudConfig: dataSource.DsAliCloudConfig,
 		AWSCloudWatch:    dataSource.AWSCloudWatch,
 		ClickHouseConfig: dataSource.ClickHouseConfig,
+		InfluxDBConfig:   dataSource.InfluxDBConfig,
+		DatadogConfig:    dataSource.DatadogConfig,
 		Description:      dataSource.Description,
 		KubeConfig:       dataSource.KubeConfig,
+		QueryTimeout:     dataSource.QueryTimeout,
+		QueryCacheTTL:    dataSource.QueryCacheTTL,
+		LinkTemplate:     dataSource.LinkTemplate,
 		UpdateBy:         dataSource.UpdateBy,
 		UpdateAt:         time.Now().Unix(),
 		Enabled:          dataSource.Enabled,
 	}
 
+	if err := provider.ValidateHTTP(data.HTTP); err != nil {
+		return nil, fmt.Errorf("数据源配置无效, %s", err.Error())
+	}
+	if err := provider.ValidateClickHouse(data.ClickHouseConfig); err != nil {
+		return nil, fmt.Errorf("数据源配置无效, %s", err.Error())
+	}
+
 	err := ds.ctx.DB.Datasource().Create(data)
 	if err != nil {
 		return nil, err
@@ -90,13 +102,25 @@ func (ds datasourceService) Update(req interface{}) (interface{}, interface{}) {
 		DsAliCloudConfig: dataSource.DsAliCloudConfig,
 		AWSCloudWatch:    dataSource.AWSCloudWatch,
 		ClickHouseConfig: dataSource.ClickHouseConfig,
+		InfluxDBConfig:   dataSource.InfluxDBConfig,
+		DatadogConfig:    dataSource.DatadogConfig,
 		Description:      dataSource.Description,
 		KubeConfig:       dataSource.KubeConfig,
+		QueryTimeout:     dataSource.QueryTimeout,
+		QueryCacheTTL:    dataSource.QueryCacheTTL,
+		LinkTemplate:     dataSource.LinkTemplate,
 		UpdateBy:         dataSource.UpdateBy,
 		UpdateAt:         time.Now().Unix(),
 		Enabled:          dataSource.Enabled,
 	}
 
+	if err := provider.ValidateHTTP(data.HTTP); err != nil {
+		return nil, fmt.Errorf("数据源配置无效, %s", err.Error())
+	}
+	if err := provider.ValidateClickHouse(data.ClickHouseConfig); err != nil {
+		return nil, fmt.Errorf("数据源配置无效, %s", err.Error())
+	}
+
 	err := ds.ctx.DB.Datasource().Update(data)
 	if err != nil {
 		return nil, err
@@ -171,12 +195,18 @@ func (ds datasourceService) WithAddClientToProviderPools(datasource models.Alert
 		cli, err = provider.NewElasticSearchClient(ctx.Ctx, datasource)
 	case provider.VictoriaLogsDsProviderName:
 		cli, err = provider.NewVictoriaLogsClient(ctx.Ctx, datasource)
+	case provider.InfluxDBDsProviderName:
+		cli, err = provider.NewInfluxDBClient(datasource)
+	case provider.DatadogDsProviderName:
+		cli, err = provider.NewDatadogClient(datasource)
 	case provider.JaegerDsProviderName:
 		cli, err = provider.NewJaegerClient(datasource)
+	case provider.TempoDsProviderName:
+		cli, err = provider.NewTempoClient(datasource)
 	case "Kubernetes":
 		cli, err = provider.NewKubernetesClient(ds.ctx.Ctx, datasource.KubeConfig, datasource.Labels)
 	case "CloudWatch":
-		cli, err = provider.NewAWSCredentialCfg(datasource.AWSCloudWatch.Region, datasource.AWSCloudWatch.AccessKey, datasource.AWSCloudWatch.SecretKey, datasource.Labels)
+		cli, err = provider.NewAWSCredentialCfg(datasource.AWSCloudWatch, datasource.Labels)
 	case "ClickHouse":
 		cli, err = provider.NewClickHouseClient(ctx.Ctx, datasource)
 	}
@@ -186,10 +216,16 @@ func (ds datasourceService) WithAddClientToProviderPools(datasource models.Alert
 	}
 
 	pools.SetClient(datasource.ID, cli)
+	// 配置变更后重置熔断状态及健康检查缓存
+	provider.RemoveBreaker(datasource.ID)
+	provider.InvalidateHealthCache(datasource.ID)
 	return nil
 }
 
 func (ds datasourceService) WithRemoveClientForProviderPools(datasourceId string) {
 	pools := ds.ctx.Redis.ProviderPools()
 	pools.RemoveClient(datasourceId)
+	provider.RemoveBreaker(datasourceId)
+	provider.InvalidateHealthCache(datasourceId)
+	provider.RemoveTransport(datasourceId)
 }
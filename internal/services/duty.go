@@ -1,7 +1,6 @@
 package services
 
 import (
-	"fmt"
 	"time"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
@@ -38,21 +37,27 @@ func (dms *dutyManageService) List(req interface{}) (interface{}, interface{}) {
 
 func (dms *dutyManageService) Create(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestDutyManagementCreate)
-	ok := dms.ctx.DB.Duty().GetQuota(r.TenantId)
-	if !ok {
-		return nil, fmt.Errorf("创建失败, 配额不足")
+	if err := dms.ctx.DB.Duty().GetQuota(r.TenantId); err != nil {
+		return nil, err
+	}
+
+	duty := models.DutyManagement{
+		TenantId:     r.TenantId,
+		ID:           "dt-" + tools.RandId(),
+		Name:         r.Name,
+		Manager:      r.Manager,
+		Description:  r.Description,
+		CurDutyUser:  r.CurDutyUser,
+		NoticeTmplId: r.NoticeTmplId,
+		Overrides:    r.Overrides,
+		UpdateBy:     r.UpdateBy,
+		UpdateAt:     time.Now().Unix(),
+	}
+	if err := duty.Validate(); err != nil {
+		return nil, err
 	}
 
-	err := dms.ctx.DB.Duty().Create(models.DutyManagement{
-		TenantId:    r.TenantId,
-		ID:          "dt-" + tools.RandId(),
-		Name:        r.Name,
-		Manager:     r.Manager,
-		Description: r.Description,
-		CurDutyUser: r.CurDutyUser,
-		UpdateBy:    r.UpdateBy,
-		UpdateAt:    time.Now().Unix(),
-	})
+	err := dms.ctx.DB.Duty().Create(duty)
 	if err != nil {
 		return nil, err
 	}
@@ -61,16 +66,23 @@ func (dms *dutyManageService) Create(req interface{}) (interface{}, interface{})
 
 func (dms *dutyManageService) Update(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestDutyManagementUpdate)
-	err := dms.ctx.DB.Duty().Update(models.DutyManagement{
-		TenantId:    r.TenantId,
-		ID:          r.ID,
-		Name:        r.Name,
-		Manager:     r.Manager,
-		Description: r.Description,
-		CurDutyUser: r.CurDutyUser,
-		UpdateBy:    r.UpdateBy,
-		UpdateAt:    time.Now().Unix(),
-	})
+	duty := models.DutyManagement{
+		TenantId:     r.TenantId,
+		ID:           r.ID,
+		Name:         r.Name,
+		Manager:      r.Manager,
+		Description:  r.Description,
+		CurDutyUser:  r.CurDutyUser,
+		NoticeTmplId: r.NoticeTmplId,
+		Overrides:    r.Overrides,
+		UpdateBy:     r.UpdateBy,
+		UpdateAt:     time.Now().Unix(),
+	}
+	if err := duty.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := dms.ctx.DB.Duty().Update(duty)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +38,7 @@ var (
 	OidcService             InterOidcService
 	TopologyService         InterTopologyService
 	ApiKeyService           InterApiKeyService
+	HeartbeatService        InterHeartbeatService
 )
 
 func NewServices(ctx *ctx.Context) {
@@ -72,4 +73,5 @@ func NewServices(ctx *ctx.Context) {
 	OidcService = newInterOidcService(ctx)
 	TopologyService = newInterTopologyService(ctx)
 	ApiKeyService = newInterApiKeyService(ctx)
+	HeartbeatService = newInterHeartbeatService(ctx)
 }
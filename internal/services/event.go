@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"sync"
@@ -21,10 +23,21 @@ type InterEventService interface {
 	ListHistoryEvent(req interface{}) (interface{}, interface{})
 	ProcessAlertEvent(req interface{}) (interface{}, interface{})
 	DeleteAlertEvent(req interface{}) (interface{}, interface{})
+	BulkProcessAlertEvent(req interface{}) (interface{}, interface{})
+	BulkDeleteAlertEvent(req interface{}) (interface{}, interface{})
+	UpdateEventAnnotations(req interface{}) (interface{}, interface{})
+	SnoozeAlertEvent(req interface{}) (interface{}, interface{})
+	CancelSnoozeAlertEvent(req interface{}) (interface{}, interface{})
+	IngestAlertmanager(req interface{}) (interface{}, interface{})
+	PruneCronjob(ctx context.Context)
+	ExportCurrentEvent(r *types.RequestAlertCurEventQuery, format string, w io.Writer) error
+	ExportHistoryEvent(r *types.RequestAlertHisEventQuery, format string, w io.Writer) error
+	StreamCurrentEvent(ctx context.Context, r *types.RequestAlertEventStream, send func(name string, data interface{}) error) error
 
 	ListComments(req interface{}) (interface{}, interface{})
 	AddComment(req interface{}) (interface{}, interface{})
 	DeleteComment(req interface{}) (interface{}, interface{})
+	EditComment(req interface{}) (interface{}, interface{})
 }
 
 func newInterEventService(ctx *ctx.Context) InterEventService {
@@ -35,6 +48,9 @@ func newInterEventService(ctx *ctx.Context) InterEventService {
 
 func (e eventService) ProcessAlertEvent(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProcessAlertEvent)
+	if r.AckTimeout < 0 {
+		return nil, fmt.Errorf("认领有效期不能小于 0")
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(len(r.Fingerprints))
@@ -46,14 +62,10 @@ func (e eventService) ProcessAlertEvent(req interface{}) (interface{}, interface
 				return
 			}
 
-			if cache.ConfirmState.IsOk {
+			if !acknowledgeEvent(&cache, r.Username, r.Time, r.AckTimeout) {
 				return
 			}
 
-			cache.ConfirmState.IsOk = true
-			cache.ConfirmState.ConfirmUsername = r.Username
-			cache.ConfirmState.ConfirmActionTime = r.Time
-
 			e.ctx.Redis.Alert().PushAlertEvent(&cache)
 		}(fingerprint)
 	}
@@ -62,6 +74,23 @@ func (e eventService) ProcessAlertEvent(req interface{}) (interface{}, interface
 	return nil, nil
 }
 
+// acknowledgeEvent 认领事件, 记录认领人、认领时间及过期时间; 已认领且未过期的事件不重复认领, 返回是否需要更新
+func acknowledgeEvent(event *models.AlertCurEvent, username string, now, ackTimeout int64) bool {
+	if event.ConfirmState.IsAcknowledged(now) {
+		return false
+	}
+
+	event.ConfirmState.IsOk = true
+	event.ConfirmState.ConfirmUsername = username
+	event.ConfirmState.ConfirmActionTime = now
+	event.ConfirmState.ConfirmExpireTime = 0
+	if ackTimeout > 0 {
+		event.ConfirmState.ConfirmExpireTime = now + ackTimeout*60
+	}
+
+	return true
+}
+
 func (e eventService) DeleteAlertEvent(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProcessAlertEvent)
 
@@ -78,12 +107,284 @@ func (e eventService) DeleteAlertEvent(req interface{}) (interface{}, interface{
 	return nil, nil
 }
 
+// BulkProcessAlertEvent 批量认领/关闭/抑制告警事件, 所有写操作在同一个 Redis Pipeline 中提交
+func (e eventService) BulkProcessAlertEvent(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestBulkProcessAlertEvent)
+
+	switch r.Action {
+	case types.BulkActionAck, types.BulkActionClose, types.BulkActionSuppress:
+	default:
+		return nil, fmt.Errorf("不支持的操作类型: %s", r.Action)
+	}
+
+	if len(r.Fingerprints) == 0 {
+		return nil, fmt.Errorf("指纹列表不能为空")
+	}
+
+	if r.AckTimeout < 0 {
+		return nil, fmt.Errorf("认领有效期不能小于 0")
+	}
+
+	// 与告警评估写入事件共用锁, 避免读取后评估更新的状态被旧数据覆盖
+	e.ctx.Mux.Lock()
+	defer e.ctx.Mux.Unlock()
+
+	events, err := e.ctx.Redis.Alert().GetEventsFromCache(r.TenantId, r.FaultCenterId, r.Fingerprints)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results = make([]types.BulkProcessResult, 0, len(r.Fingerprints))
+		push    []*models.AlertCurEvent
+		remove  []string
+	)
+	for _, fingerprint := range r.Fingerprints {
+		event, ok := events[fingerprint]
+		if !ok {
+			results = append(results, types.BulkProcessResult{Fingerprint: fingerprint, Error: "事件不存在"})
+			continue
+		}
+
+		switch r.Action {
+		case types.BulkActionAck:
+			if acknowledgeEvent(&event, r.Username, r.Time, r.AckTimeout) {
+				push = append(push, &event)
+			}
+		case types.BulkActionSuppress:
+			// 手动抑制只标记 IsSuppressed 屏蔽通知, 不转为已抑制状态: StateSuppressed 用于静默、抑制规则及暂停通知等
+			// 会自动结束的场景, 处于该状态的事件不参与恢复判断; 手动抑制没有结束时间, 转为该状态后事件将无法恢复
+			if !event.IsSuppressed {
+				event.IsSuppressed = true
+				push = append(push, &event)
+			}
+		case types.BulkActionClose:
+			remove = append(remove, fingerprint)
+		}
+
+		results = append(results, types.BulkProcessResult{Fingerprint: fingerprint, Success: true})
+	}
+
+	if err := e.ctx.Redis.Alert().PipelineUpdateEvents(r.TenantId, r.FaultCenterId, push, remove); err != nil {
+		for i := range results {
+			if results[i].Success {
+				results[i].Success = false
+				results[i].Error = err.Error()
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// BulkDeleteAlertEvent 按状态、时间及标签匹配条件批量删除故障中心的活跃告警事件, 返回删除的数量
+func (e eventService) BulkDeleteAlertEvent(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestBulkDeleteAlertEvent)
+	if r.FaultCenterId == "" {
+		return nil, fmt.Errorf("故障中心 ID 不能为空")
+	}
+	if r.OlderThan < 0 {
+		return nil, fmt.Errorf("时间条件不能小于 0")
+	}
+
+	switch models.AlertStatus(r.Status) {
+	case "", models.StatePreAlert, models.StateAlerting, models.StateSuppressed, models.StatePendingRecovery, models.StateRecovered:
+	default:
+		return nil, fmt.Errorf("不支持的事件状态: %s", r.Status)
+	}
+
+	matchers, err := models.ParseLabelMatchers(r.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status == "" && r.OlderThan == 0 && len(matchers) == 0 {
+		return nil, fmt.Errorf("至少需要指定一个删除条件")
+	}
+
+	// 与告警评估写入事件共用锁, 避免删除过程中事件被重新写入
+	e.ctx.Mux.Lock()
+	defer e.ctx.Mux.Unlock()
+
+	events, err := e.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(r.TenantId, r.FaultCenterId))
+	if err != nil {
+		return nil, fmt.Errorf("获取告警事件失败: %s", err.Error())
+	}
+
+	before := r.Time - r.OlderThan*60
+	var remove []string
+	for fingerprint, event := range events {
+		if r.Status != "" && string(event.Status) != r.Status {
+			continue
+		}
+
+		if r.OlderThan > 0 {
+			lastSeen := event.LastEvalTime
+			if event.Status == models.StateRecovered && event.RecoverTime > 0 {
+				lastSeen = event.RecoverTime
+			}
+			if lastSeen >= before {
+				continue
+			}
+		}
+
+		if len(matchers) > 0 && !mute.MatchLabels(event.Labels, matchers) {
+			continue
+		}
+
+		remove = append(remove, fingerprint)
+	}
+
+	if err := e.ctx.Redis.Alert().PipelineUpdateEvents(r.TenantId, r.FaultCenterId, nil, remove); err != nil {
+		return nil, fmt.Errorf("删除告警事件失败: %s", err.Error())
+	}
+
+	return types.ResponseBulkDeleteAlertEvent{Deleted: len(remove)}, nil
+}
+
+// UpdateEventAnnotations 修改活跃告警事件的补充注解, 不参与指纹计算, 事件状态流转后仍保留
+func (e eventService) UpdateEventAnnotations(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestUpdateEventAnnotations)
+	if r.Fingerprint == "" {
+		return nil, fmt.Errorf("指纹不能为空")
+	}
+
+	for key := range r.Annotations {
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("注解名称不能为空")
+		}
+	}
+
+	// 与告警评估写入事件共用锁, 避免并发覆盖
+	e.ctx.Mux.Lock()
+	defer e.ctx.Mux.Unlock()
+
+	event, err := e.ctx.Redis.Alert().GetEventFromCache(r.TenantId, r.FaultCenterId, r.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("事件不存在")
+	}
+
+	if event.ExtraAnnotations == nil {
+		event.ExtraAnnotations = make(map[string]string)
+	}
+	for key, value := range r.Annotations {
+		event.ExtraAnnotations[key] = value
+	}
+	for _, key := range r.Remove {
+		delete(event.ExtraAnnotations, key)
+	}
+
+	e.ctx.Redis.Alert().PushAlertEvent(&event)
+
+	return event.ExtraAnnotations, nil
+}
+
+// SnoozeAlertEvent 暂停单个事件的通知, 截止时间前事件转为已抑制状态, 到期后若仍在告警则回到告警中
+func (e eventService) SnoozeAlertEvent(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestSnoozeAlertEvent)
+	if r.Fingerprint == "" {
+		return nil, fmt.Errorf("指纹不能为空")
+	}
+
+	until := r.Until
+	if until == 0 {
+		until = r.Time + r.Duration*60
+	}
+	if until <= r.Time {
+		return nil, fmt.Errorf("暂停截止时间必须晚于当前时间")
+	}
+
+	// 与告警评估写入事件共用锁, 避免并发覆盖
+	e.ctx.Mux.Lock()
+	defer e.ctx.Mux.Unlock()
+
+	event, err := e.ctx.Redis.Alert().GetEventFromCache(r.TenantId, r.FaultCenterId, r.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("事件不存在")
+	}
+	if event.IsRecovered || event.Status == models.StateRecovered {
+		return nil, fmt.Errorf("事件已恢复, 无需暂停通知")
+	}
+
+	if err := e.ctx.Redis.Snooze().Set(r.TenantId, r.FaultCenterId, r.Fingerprint, until); err != nil {
+		return nil, fmt.Errorf("暂停通知失败: %s", err.Error())
+	}
+
+	// 告警中的事件立即转为已抑制, 其余状态的事件进入告警中后由消费者处理
+	if event.Status == models.StateAlerting {
+		if err := event.TransitionStatus(models.StateSuppressed); err == nil {
+			e.ctx.Redis.Alert().PushAlertEvent(&event)
+		}
+	}
+
+	return nil, nil
+}
+
+// CancelSnoozeAlertEvent 取消事件的暂停通知, 未命中静默或抑制规则的事件回到告警中
+func (e eventService) CancelSnoozeAlertEvent(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestSnoozeAlertEvent)
+	if r.Fingerprint == "" {
+		return nil, fmt.Errorf("指纹不能为空")
+	}
+
+	if e.ctx.Redis.Snooze().Get(r.TenantId, r.FaultCenterId, r.Fingerprint) == 0 {
+		return nil, fmt.Errorf("事件未暂停通知")
+	}
+
+	e.ctx.Mux.Lock()
+	defer e.ctx.Mux.Unlock()
+
+	e.ctx.Redis.Snooze().Delete(r.TenantId, r.FaultCenterId, r.Fingerprint)
+
+	event, err := e.ctx.Redis.Alert().GetEventFromCache(r.TenantId, r.FaultCenterId, r.Fingerprint)
+	if err != nil {
+		return nil, nil
+	}
+
+	silenced := mute.IsSilence(mute.MuteParams{TenantId: r.TenantId, FaultCenterId: r.FaultCenterId, Labels: event.Labels})
+	if event.Status == models.StateSuppressed && !event.IsInhibited && !silenced {
+		if err := event.TransitionStatus(models.StateAlerting); err == nil {
+			e.ctx.Redis.Alert().PushAlertEvent(&event)
+		}
+	}
+
+	return nil, nil
+}
+
 func (e eventService) ListCurrentEvent(req interface{}) (interface{}, interface{}) {
 	r, ok := req.(*types.RequestAlertCurEventQuery)
 	if !ok {
 		return nil, fmt.Errorf("invalid request type: expected *models.AlertCurEventQuery")
 	}
 
+	switch r.SortBy {
+	case "", models.SortBySeverity, models.SortByLastSeen, models.SortByStatus:
+	default:
+		return nil, fmt.Errorf("不支持的排序字段: %s", r.SortBy)
+	}
+
+	filteredEvents, err := e.filterCurrentEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	paginatedList := pageSlice(filteredEvents, int(r.Page.Index), int(r.Page.Size))
+	return types.ResponseAlertCurEventList{
+		List: paginatedList,
+		Page: models.Page{
+			Total: int64(len(filteredEvents)),
+			Index: r.Page.Index,
+			Size:  r.Page.Size,
+		},
+	}, nil
+}
+
+// filterCurrentEvents 按查询条件过滤并排序活跃告警事件
+func (e eventService) filterCurrentEvents(r *types.RequestAlertCurEventQuery) ([]models.AlertCurEvent, error) {
+	matchers, err := models.ParseLabelMatchers(r.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
 	center, err := e.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(r.TenantId, r.FaultCenterId))
 	if err != nil {
 		return nil, err
@@ -94,7 +395,21 @@ func (e eventService) ListCurrentEvent(req interface{}) (interface{}, interface{
 		filteredEvents []models.AlertCurEvent
 		curTime        = time.Now()
 	)
+	snoozes, err := e.ctx.Redis.Snooze().List(r.TenantId, r.FaultCenterId)
+	if err != nil {
+		return nil, err
+	}
+
+	flapping, err := e.ctx.Redis.Flapping().List(r.TenantId, r.FaultCenterId)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, alert := range center {
+		if until := snoozes[alert.Fingerprint]; until > curTime.Unix() {
+			alert.SnoozeUntil = until
+		}
+		_, alert.IsFlapping = flapping[alert.Fingerprint]
 		allEvents = append(allEvents, *alert)
 	}
 
@@ -126,6 +441,10 @@ func (e eventService) ListCurrentEvent(req interface{}) (interface{}, interface{
 			continue
 		}
 
+		if len(matchers) > 0 && !mute.MatchLabels(event.Labels, matchers) {
+			continue
+		}
+
 		if !matchStatus(&event, r.Status, mute.MuteParams{TenantId: r.TenantId, FaultCenterId: event.FaultCenterId, Labels: event.Labels}) {
 			continue
 		}
@@ -133,40 +452,67 @@ func (e eventService) ListCurrentEvent(req interface{}) (interface{}, interface{
 		filteredEvents = append(filteredEvents, event)
 	}
 
-	sort.Slice(filteredEvents, func(i, j int) bool {
-		a, b := &filteredEvents[i], &filteredEvents[j]
+	sortCurrentEvents(filteredEvents, r.SortBy, r.SortOrder)
+
+	return filteredEvents, nil
+}
 
-		// 按持续时间降序
-		durA := a.LastEvalTime - a.FirstTriggerTime
-		durB := b.LastEvalTime - b.FirstTriggerTime
-		switch r.SortOrder {
-		case models.SortOrderASC:
-			if durA != durB {
-				return durA < durB // 升序
+// currentEventStatusRank 按状态排序时的先后顺序, 需要处理的状态在前
+var currentEventStatusRank = map[models.AlertStatus]int{
+	models.StateAlerting:        0,
+	models.StatePreAlert:        1,
+	"processing":                2,
+	models.StatePendingRecovery: 3,
+	models.StateSuppressed:      4,
+	"muting":                    5,
+}
+
+// sortCurrentEvents 按排序字段排序活跃告警事件, 字段相同时按指纹升序保证分页稳定
+func sortCurrentEvents(events []models.AlertCurEvent, sortBy, sortOrder string) {
+	// 按最近评估时间排序时默认最近评估的在前
+	if sortBy == models.SortByLastSeen && sortOrder == "" {
+		sortOrder = models.SortOrderDesc
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := &events[i], &events[j]
+
+		var less, greater bool
+		switch sortBy {
+		case models.SortBySeverity:
+			less, greater = a.Severity < b.Severity, a.Severity > b.Severity
+		case models.SortByLastSeen:
+			less, greater = a.LastEvalTime < b.LastEvalTime, a.LastEvalTime > b.LastEvalTime
+		case models.SortByStatus:
+			rankA, okA := currentEventStatusRank[a.Status]
+			if !okA {
+				rankA = len(currentEventStatusRank)
 			}
-		case models.SortOrderDesc:
-			if durA != durB {
-				return durA > durB // 降序
+			rankB, okB := currentEventStatusRank[b.Status]
+			if !okB {
+				rankB = len(currentEventStatusRank)
 			}
+			less, greater = rankA < rankB, rankA > rankB
 		default:
-			if a.FirstTriggerTime != b.FirstTriggerTime {
+			// 按持续时间排序, 未指定排序方向时仅按指纹排序
+			if sortOrder == "" {
 				return a.Fingerprint < b.Fingerprint
 			}
+			durA := a.LastEvalTime - a.FirstTriggerTime
+			durB := b.LastEvalTime - b.FirstTriggerTime
+			less, greater = durA < durB, durA > durB
+		}
+
+		if sortOrder == models.SortOrderDesc {
+			less, greater = greater, less
+		}
+		if less || greater {
+			return less
 		}
 
 		// 默认按指纹升序
 		return a.Fingerprint < b.Fingerprint
 	})
-
-	paginatedList := pageSlice(filteredEvents, int(r.Page.Index), int(r.Page.Size))
-	return types.ResponseAlertCurEventList{
-		List: paginatedList,
-		Page: models.Page{
-			Total: int64(len(filteredEvents)),
-			Index: r.Page.Index,
-			Size:  r.Page.Size,
-		},
-	}, nil
 }
 
 func matchQuery(event models.AlertCurEvent, query string) bool {
@@ -188,27 +534,35 @@ func matchQuery(event models.AlertCurEvent, query string) bool {
 }
 
 func matchStatus(event *models.AlertCurEvent, status string, muteParams mute.MuteParams) bool {
+	// 标记命中的静默规则, 便于确认事件未通知的原因
+	event.SilenceId, event.IsSilenced = mute.MatchSilence(muteParams)
+
+	acknowledged := event.ConfirmState.IsAcknowledged(time.Now().Unix())
+	if acknowledged {
+		event.AckOwner = event.ConfirmState.ConfirmUsername
+	}
+
 	if status == "" {
-		if event.ConfirmState.IsOk {
+		if acknowledged {
 			event.Status = "processing"
 		}
-		if mute.IsSilence(muteParams) {
+		if event.IsSuppressed || event.IsSilenced || event.SnoozeUntil > 0 || event.IsFlapping {
 			event.Status = "muting"
 		}
 		return true
 	}
 
 	switch status {
-	case "pre_alert", "alerting", "pending_recovery":
+	case "pre_alert", "alerting", "suppressed", "pending_recovery":
 		return string(event.Status) == status
 	case "processing":
-		if event.ConfirmState.IsOk {
+		if acknowledged {
 			event.Status = "processing"
 			return true
 		}
 		return false
 	case "muting":
-		if mute.IsSilence(muteParams) {
+		if event.IsSuppressed || event.IsSilenced || event.SnoozeUntil > 0 || event.IsFlapping {
 			event.Status = "muting"
 			return true
 		}
@@ -220,6 +574,12 @@ func matchStatus(event *models.AlertCurEvent, status string, muteParams mute.Mut
 
 func (e eventService) ListHistoryEvent(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestAlertHisEventQuery)
+	matchers, err := models.ParseLabelMatchers(r.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	r.LabelMatchers = matchers
+
 	data, err := e.ctx.DB.Event().GetHistoryEvent(*r)
 	if err != nil {
 		return nil, err
@@ -234,15 +594,16 @@ func pageSlice(data []models.AlertCurEvent, index, size int) []models.AlertCurEv
 		index = 1
 	}
 
-	if size <= 0 {
-		index = 10
-	}
-
 	total := len(data)
 	if total == 0 {
 		return []models.AlertCurEvent{}
 	}
 
+	// 未指定分页大小时返回全部事件
+	if size <= 0 {
+		return data
+	}
+
 	offset := (index - 1) * size
 	if offset >= total {
 		return []models.AlertCurEvent{}
@@ -269,13 +630,22 @@ func (e eventService) ListComments(req interface{}) (interface{}, interface{}) {
 
 func (e eventService) AddComment(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestAddEventComment)
+	r.Mentions = e.resolveMentions(r.Content)
+
 	comment := e.ctx.DB.Comment()
 	err := comment.Add(*r)
 	if err != nil {
 		return nil, fmt.Errorf("评论失败, %s", err.Error())
 	}
 
-	return "评论成功", nil
+	if len(r.Mentions) > 0 {
+		go e.notifyMentions(*r)
+	}
+
+	return types.ResponseAddEventComment{
+		Message:  "评论成功",
+		Mentions: r.Mentions,
+	}, nil
 }
 
 func (e eventService) DeleteComment(req interface{}) (interface{}, interface{}) {
@@ -288,3 +658,27 @@ func (e eventService) DeleteComment(req interface{}) (interface{}, interface{})
 
 	return "删除评论成功", nil
 }
+
+func (e eventService) EditComment(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEditEventComment)
+	if strings.TrimSpace(r.Content) == "" {
+		return nil, fmt.Errorf("评论内容不能为空")
+	}
+
+	comment := e.ctx.DB.Comment()
+	data, err := comment.Get(r.TenantId, r.CommentId)
+	if err != nil {
+		return nil, fmt.Errorf("评论不存在")
+	}
+
+	// 仅允许评论者本人编辑
+	if data.UserId != r.UserId {
+		return nil, fmt.Errorf("无权编辑他人的评论")
+	}
+
+	if err := comment.Edit(*r); err != nil {
+		return nil, fmt.Errorf("编辑评论失败, %s", err.Error())
+	}
+
+	return "编辑评论成功", nil
+}
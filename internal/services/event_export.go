@@ -0,0 +1,167 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+)
+
+// 每写入多少行刷新一次输出
+const exportFlushRows = 200
+
+var eventExportHeader = []string{"event_id", "fingerprint", "rule_name", "status", "severity", "fault_center_id", "labels", "first_seen", "last_seen"}
+
+// eventExporter 以 CSV 或 JSON Lines 格式流式写出告警事件
+type eventExporter struct {
+	format string
+	w      io.Writer
+	csv    *csv.Writer
+	rows   int
+}
+
+func newEventExporter(format string, w io.Writer) (*eventExporter, error) {
+	switch format {
+	case "", types.EventExportFormatCSV:
+		e := &eventExporter{format: types.EventExportFormatCSV, w: w, csv: csv.NewWriter(w)}
+		return e, e.csv.Write(eventExportHeader)
+	case types.EventExportFormatJSONL:
+		return &eventExporter{format: format, w: w}, nil
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+func (e *eventExporter) Write(row types.AlertEventExportRow) error {
+	var err error
+	if e.format == types.EventExportFormatCSV {
+		record := []string{
+			row.EventId,
+			row.Fingerprint,
+			row.RuleName,
+			row.Status,
+			row.Severity,
+			row.FaultCenterId,
+			flattenLabels(row.Labels),
+			formatExportTime(row.FirstSeen),
+			formatExportTime(row.LastSeen),
+		}
+		for i := range record {
+			record[i] = escapeCSVCell(record[i])
+		}
+		err = e.csv.Write(record)
+	} else {
+		_, err = io.WriteString(e.w, tools.JsonMarshalToString(row)+"\n")
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		e.Flush()
+	}
+	return nil
+}
+
+func (e *eventExporter) Flush() {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// ExportCurrentEvent 导出活跃告警事件
+func (e eventService) ExportCurrentEvent(r *types.RequestAlertCurEventQuery, format string, w io.Writer) error {
+	events, err := e.filterCurrentEvents(r)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := newEventExporter(format, w)
+	if err != nil {
+		return err
+	}
+	defer exporter.Flush()
+
+	for _, event := range events {
+		err := exporter.Write(types.AlertEventExportRow{
+			EventId:       event.EventId,
+			Fingerprint:   event.Fingerprint,
+			RuleName:      event.RuleName,
+			Status:        string(event.Status),
+			Severity:      event.Severity,
+			FaultCenterId: event.FaultCenterId,
+			Labels:        event.Labels,
+			FirstSeen:     event.FirstTriggerTime,
+			LastSeen:      event.LastEvalTime,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportHistoryEvent 导出历史告警事件, 逐行读取数据库并写出
+func (e eventService) ExportHistoryEvent(r *types.RequestAlertHisEventQuery, format string, w io.Writer) error {
+	matchers, err := models.ParseLabelMatchers(r.Matchers)
+	if err != nil {
+		return err
+	}
+	r.LabelMatchers = matchers
+
+	exporter, err := newEventExporter(format, w)
+	if err != nil {
+		return err
+	}
+	defer exporter.Flush()
+
+	return e.ctx.DB.Event().StreamHistoryEvent(*r, func(event models.AlertHisEvent) error {
+		return exporter.Write(types.AlertEventExportRow{
+			EventId:       event.EventId,
+			Fingerprint:   event.Fingerprint,
+			RuleName:      event.RuleName,
+			Status:        string(models.StateRecovered),
+			Severity:      event.Severity,
+			FaultCenterId: event.FaultCenterId,
+			Labels:        event.Labels,
+			FirstSeen:     event.FirstTriggerTime,
+			LastSeen:      event.RecoverTime,
+		})
+	})
+}
+
+// escapeCSVCell 以公式字符开头的单元格加上单引号前缀, 避免在表格软件中打开时被当作公式执行
+func escapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// flattenLabels 将标签展开为 k=v 形式, 按标签名排序后以分号分隔
+func flattenLabels(labels map[string]interface{}) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+func formatExportTime(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).Format(time.RFC3339)
+}
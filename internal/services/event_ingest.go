@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+)
+
+const (
+	// 外部告警的数据源类型及规则ID, 不与评估引擎的规则关联, 不参与规则恢复逻辑
+	ExternalDatasourceAlertmanager = "Alertmanager"
+	ExternalRuleIdAlertmanager     = "alertmanager"
+
+	alertmanagerStatusResolved = "resolved"
+)
+
+// alertmanagerSeverity Alertmanager 常用的告警等级与告警等级的映射
+var alertmanagerSeverity = map[string]string{
+	"critical": "P0",
+	"error":    "P1",
+	"warning":  "P1",
+	"info":     "P2",
+}
+
+// IngestAlertmanager 接收 Alertmanager Webhook 格式的外部告警, 写入故障中心后与原生事件一样进行通知及恢复
+func (e eventService) IngestAlertmanager(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestAlertmanagerWebhook)
+
+	if r.UserId != "admin" {
+		user, err := e.ctx.DB.Tenant().GetTenantLinkedUserInfo(r.TenantId, r.UserId)
+		if err != nil || user.UserID == "" {
+			return nil, fmt.Errorf("无权向租户 %s 推送告警", r.TenantId)
+		}
+	}
+
+	faultCenter, err := e.ctx.DB.FaultCenter().Get(r.TenantId, r.FaultCenterId, "")
+	if err != nil {
+		return nil, fmt.Errorf("故障中心不存在, id: %s", r.FaultCenterId)
+	}
+
+	var res types.ResponseIngestAlerts
+	now := time.Now()
+	for _, alert := range r.Alerts {
+		if len(alert.Labels) == 0 {
+			continue
+		}
+
+		event := buildAlertmanagerEvent(faultCenter, alert)
+		if alert.Status == alertmanagerStatusResolved || (!alert.EndsAt.IsZero() && alert.EndsAt.Before(now)) {
+			if resolveExternalEvent(e.ctx, event) {
+				res.Resolved++
+			}
+			continue
+		}
+
+		// 上游已处理持续时间, 以 startsAt 作为首次满足条件的时间, 推送后直接进入告警状态
+		pendingAt := now.Unix() - 1
+		if !alert.StartsAt.IsZero() && alert.StartsAt.Unix() < pendingAt {
+			pendingAt = alert.StartsAt.Unix()
+		}
+		if _, err := e.ctx.Redis.Pending().Get(event.TenantId, event.RuleId, event.Fingerprint); err != nil {
+			e.ctx.Redis.Pending().Set(event.TenantId, event.RuleId, event.Fingerprint, pendingAt)
+		}
+
+		event.MarkTriggered()
+		process.PushEventToFaultCenter(e.ctx, &event)
+		res.Firing++
+	}
+
+	return res, nil
+}
+
+// resolveExternalEvent 将外部告警转换为已恢复状态, 由消费者发送恢复通知并记录历史
+func resolveExternalEvent(ctx *ctx.Context, event models.AlertCurEvent) bool {
+	ctx.Mux.Lock()
+	defer ctx.Mux.Unlock()
+
+	cache, err := ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if err != nil {
+		return false
+	}
+
+	ctx.Redis.Pending().Delete(cache.TenantId, cache.RuleId, cache.Fingerprint)
+	switch cache.GetEventStatus() {
+	case models.StatePreAlert:
+		// 未发送过告警通知, 直接移除
+		ctx.Redis.Alert().RemoveAlertEvent(cache.TenantId, cache.FaultCenterId, cache.Fingerprint)
+		return true
+	case models.StateSuppressed, models.StateAlerting:
+		// 上游已恢复, 已抑制的事件同样经告警中转为已恢复
+		if err := cache.TransitionStatus(models.StateAlerting); err != nil {
+			return false
+		}
+		if err := cache.TransitionStatus(models.StatePendingRecovery); err != nil {
+			return false
+		}
+	}
+
+	if err := cache.TransitionStatus(models.StateRecovered); err != nil {
+		return false
+	}
+	ctx.Redis.Alert().PushAlertEvent(&cache)
+
+	return true
+}
+
+// buildAlertmanagerEvent 将 Alertmanager 告警转换为故障中心事件, 指纹由告警标签计算
+func buildAlertmanagerEvent(faultCenter models.FaultCenter, alert types.AlertmanagerAlert) models.AlertCurEvent {
+	labels := make(map[string]interface{}, len(alert.Labels))
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+	fingerprint := provider.Metrics{Metric: labels}.GetFingerprint()
+
+	severity := alert.Labels["severity"]
+	if s, ok := alertmanagerSeverity[strings.ToLower(severity)]; ok {
+		severity = s
+	} else if !strings.HasPrefix(strings.ToUpper(severity), "P") {
+		severity = "P1"
+	}
+
+	ruleName := alert.Labels["alertname"]
+	labels["rule_name"] = ruleName
+	labels["fingerprint"] = fingerprint
+	if alert.GeneratorURL != "" {
+		labels["generator_url"] = alert.GeneratorURL
+	}
+
+	return models.AlertCurEvent{
+		TenantId:       faultCenter.TenantId,
+		FaultCenterId:  faultCenter.ID,
+		RuleId:         ExternalRuleIdAlertmanager,
+		RuleName:       ruleName,
+		DatasourceType: ExternalDatasourceAlertmanager,
+		Fingerprint:    fingerprint,
+		Severity:       strings.ToUpper(severity),
+		Labels:         labels,
+		Annotations:    formatAlertmanagerAnnotations(alert.Annotations),
+	}
+}
+
+// formatAlertmanagerAnnotations 按 key 排序拼接注解, summary 及 description 优先展示
+func formatAlertmanagerAnnotations(annotations map[string]string) string {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		if k != "summary" && k != "description" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range append([]string{"summary", "description"}, keys...) {
+		if v, ok := annotations[k]; ok && v != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", k, v))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/sender"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// mentionRegexp 匹配评论中的 @username
+var mentionRegexp = regexp.MustCompile(`@([^\s@,，:：]+)`)
+
+// resolveMentions 解析评论中 @ 提及的用户, 不存在的用户名按普通文本处理
+func (e eventService) resolveMentions(content string) []models.CommentMention {
+	var (
+		mentions []models.CommentMention
+		seen     = make(map[string]struct{})
+	)
+	for _, match := range mentionRegexp.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+
+		user, ok, _ := e.ctx.DB.User().Get("", username, "")
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, models.CommentMention{
+			UserId:   user.UserId,
+			Username: user.UserName,
+		})
+	}
+
+	return mentions
+}
+
+// notifyMentions 通过被提及用户的个人通知渠道发送评论提醒
+func (e eventService) notifyMentions(r types.RequestAddEventComment) {
+	text := fmt.Sprintf("%s 在告警事件评论中提到了你\n事件指纹: %s\n评论内容: %s", r.Username, r.Fingerprint, r.Content)
+	if event, err := e.ctx.Redis.Alert().GetEventFromCache(r.TenantId, r.FaultCenterId, r.Fingerprint); err == nil {
+		text = fmt.Sprintf("%s 在告警事件「%s」的评论中提到了你\n事件指纹: %s\n评论内容: %s", r.Username, event.RuleName, r.Fingerprint, r.Content)
+	}
+
+	for _, mention := range r.Mentions {
+		user, ok, _ := e.ctx.DB.User().Get(mention.UserId, "", "")
+		if !ok {
+			continue
+		}
+
+		channel := user.NoticeChannel
+		if channel.NoticeType == "" {
+			logc.Infof(e.ctx.Ctx, "用户 %s 未配置通知渠道, 跳过评论提醒", user.UserName)
+			continue
+		}
+
+		params := sender.SendParams{
+			TenantId:    r.TenantId,
+			RuleName:    "告警事件评论提醒",
+			NoticeType:  channel.NoticeType,
+			NoticeId:    user.UserId,
+			NoticeName:  user.UserName,
+			UserChannel: true,
+			Hook:        channel.Hook,
+			Sign:        channel.Sign,
+			ChatId:      channel.ChatId,
+			Content:     sender.BuildTextContent(channel.NoticeType, text),
+		}
+		if channel.NoticeType == "Email" {
+			params.Email = models.Email{
+				Subject: "告警事件评论提醒",
+				To:      []string{user.Email},
+			}
+		}
+
+		if err := sender.Sender(e.ctx, params); err != nil {
+			logc.Errorf(e.ctx.Ctx, "发送评论提醒失败, user: %s, err: %s", user.UserName, err.Error())
+		}
+	}
+}
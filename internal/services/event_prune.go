@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/robfig/cron/v3"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// PruneCronjob 定期清理超出保留策略的历史事件、长时间未被评估的当前事件, 以及规则已删除或禁用的孤立事件
+func (e eventService) PruneCronjob(ctx context.Context) {
+	spec := config.Application.Event.PruneCronjob
+	if spec == "" {
+		spec = "0 * * * *"
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(spec, func() {
+		e.pruneHistoryEvents()
+		e.pruneCurrentEvents()
+		e.reconcileOrphanEvents()
+	})
+	if err != nil {
+		logc.Errorf(ctx, "创建事件清理任务失败, err: %s", err.Error())
+		return
+	}
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case <-ctx.Done():
+		logc.Infof(ctx, "停止事件清理!")
+		return
+	}
+}
+
+// pruneHistoryEvents 按租户的保留天数及最大数量删除历史事件, 租户未配置时使用全局配置
+func (e eventService) pruneHistoryEvents() {
+	c := config.Application.Event
+	batchSize := c.PruneBatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	tenants, err := e.ctx.DB.Tenant().ListAll()
+	if err != nil {
+		logc.Errorf(e.ctx.Ctx, "获取租户列表失败, err: %s", err.Error())
+		return
+	}
+
+	var total int64
+	for _, tenant := range tenants {
+		retention := tenant.HistoryEventRetention
+		if retention <= 0 {
+			retention = c.HistoryRetention
+		}
+		maxCount := tenant.HistoryEventMaxCount
+		if maxCount <= 0 {
+			maxCount = c.HistoryMaxCount
+		}
+
+		var before int64
+		if retention > 0 {
+			before = time.Now().Add(-time.Duration(retention) * 24 * time.Hour).Unix()
+		}
+		if maxCount > 0 {
+			cutoff, err := e.ctx.DB.Event().GetHistoryEventCutoff(tenant.ID, maxCount)
+			if err != nil {
+				logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "获取历史事件数量上限失败, err: %s", err.Error())
+			} else if cutoff > before {
+				before = cutoff
+			}
+		}
+		if before <= 0 {
+			continue
+		}
+
+		count, err := e.ctx.DB.Event().PruneHistoryEvent(tenant.ID, before, batchSize)
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "清理历史事件失败, err: %s", err.Error())
+		}
+		if count > 0 {
+			logc.Infof(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "清理历史事件 %d 条, 保留天数: %d, 最大数量: %d", count, retention, maxCount)
+		}
+		total += count
+	}
+
+	logc.Infof(e.ctx.Ctx, "历史事件清理完成, 共删除 %d 条", total)
+}
+
+// pruneCurrentEvents 移除超过 TTL 未被评估的当前事件, 如规则删除后遗留在故障中心的事件
+func (e eventService) pruneCurrentEvents() {
+	ttl := config.Application.Event.CurrentEventTTL
+	if ttl <= 0 {
+		return
+	}
+
+	tenants, err := e.ctx.DB.Tenant().ListAll()
+	if err != nil {
+		logc.Errorf(e.ctx.Ctx, "获取租户列表失败, err: %s", err.Error())
+		return
+	}
+
+	var total int
+	expireAt := time.Now().Unix() - ttl
+	for _, tenant := range tenants {
+		faultCenters, err := e.ctx.DB.FaultCenter().List(tenant.ID, "")
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "获取故障中心列表失败, err: %s", err.Error())
+			continue
+		}
+
+		for _, fc := range faultCenters {
+			events, err := e.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(tenant.ID, fc.ID))
+			if err != nil {
+				continue
+			}
+
+			var expired []string
+			for fingerprint, event := range events {
+				if event.LastEvalTime > 0 && event.LastEvalTime < expireAt {
+					expired = append(expired, fingerprint)
+				}
+			}
+			if len(expired) == 0 {
+				continue
+			}
+
+			e.ctx.Mux.Lock()
+			err = e.ctx.Redis.Alert().PipelineUpdateEvents(tenant.ID, fc.ID, nil, expired)
+			e.ctx.Mux.Unlock()
+			if err != nil {
+				logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID, tools.LogFieldFaultCenterId, fc.ID), "清理失效事件失败, err: %s", err.Error())
+				continue
+			}
+
+			logc.Infof(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID, tools.LogFieldFaultCenterId, fc.ID), "清理超过 %d 秒未评估的事件 %d 条", ttl, len(expired))
+			total += len(expired)
+		}
+	}
+
+	logc.Infof(e.ctx.Ctx, "失效事件清理完成, 共删除 %d 条", total)
+}
+
+// reconcileOrphanEvents 规则已删除或禁用后不再有评估任务恢复其事件, 此处强制恢复这些事件, 由消费者发送恢复通知并记录历史.
+// 仅处理在规则表中找不到或已禁用的规则产生的事件, 评估异常被停用的规则除外, 心跳、外部告警等非规则事件不受影响
+func (e eventService) reconcileOrphanEvents() {
+	tenants, err := e.ctx.DB.Tenant().ListAll()
+	if err != nil {
+		logc.Errorf(e.ctx.Ctx, "获取租户列表失败, err: %s", err.Error())
+		return
+	}
+
+	var total int
+	for _, tenant := range tenants {
+		faultCenters, err := e.ctx.DB.FaultCenter().List(tenant.ID, "")
+		if err != nil {
+			logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID), "获取故障中心列表失败, err: %s", err.Error())
+			continue
+		}
+
+		for _, fc := range faultCenters {
+			events, err := e.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(tenant.ID, fc.ID))
+			if err != nil || len(events) == 0 {
+				continue
+			}
+
+			var ruleIds []string
+			for _, event := range events {
+				if isRuleEvent(event.RuleId) && !slices.Contains(ruleIds, event.RuleId) {
+					ruleIds = append(ruleIds, event.RuleId)
+				}
+			}
+			if len(ruleIds) == 0 {
+				continue
+			}
+
+			// 查询失败时跳过, 避免误恢复仍在评估的事件
+			states, err := e.ctx.DB.Rule().GetStates(ruleIds)
+			if err != nil {
+				logc.Errorf(tools.WithLogFields(e.ctx.Ctx, tools.LogFieldTenantId, tenant.ID, tools.LogFieldFaultCenterId, fc.ID), "获取规则状态失败, err: %s", err.Error())
+				continue
+			}
+
+			for fingerprint, event := range events {
+				if !isRuleEvent(event.RuleId) || event.Status == models.StateRecovered {
+					continue
+				}
+
+				// 评估异常被停用的规则仍在告警, 保留其事件, 避免发送错误的恢复通知
+				rule, exists := states[event.RuleId]
+				if exists && (*rule.GetEnabled() || rule.EvalError != "") {
+					continue
+				}
+
+				reason := "规则已删除"
+				if exists {
+					reason = "规则已禁用"
+				}
+
+				e.ctx.Redis.PendingRecover().Delete(event.TenantId, event.RuleId, fingerprint)
+				if !resolveExternalEvent(e.ctx, *event) {
+					continue
+				}
+
+				logc.Infof(tools.WithLogFields(e.ctx.Ctx,
+					tools.LogFieldTenantId, tenant.ID,
+					tools.LogFieldFaultCenterId, fc.ID,
+					tools.LogFieldRuleId, event.RuleId,
+					tools.LogFieldRuleName, event.RuleName,
+					tools.LogFieldFingerprint, fingerprint,
+				), "恢复孤立事件, %s, status: %s", reason, event.Status)
+				total++
+			}
+		}
+	}
+
+	logc.Infof(e.ctx.Ctx, "孤立事件清理完成, 共恢复 %d 条", total)
+}
+
+// isRuleEvent 事件是否由告警规则产生, 心跳及外部告警的事件不与规则关联
+func isRuleEvent(ruleId string) bool {
+	switch ruleId {
+	case "", models.HeartbeatRuleId, ExternalRuleIdAlertmanager:
+		return false
+	}
+	return true
+}
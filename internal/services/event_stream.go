@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+)
+
+// StreamCurrentEvent 推送故障中心的活跃告警事件流, 先订阅变更再推送当前全部事件,
+// 保证快照与增量之间不丢失变更, 快照之后的变更可能与快照重复, 由客户端按指纹覆盖
+func (e eventService) StreamCurrentEvent(ctx context.Context, r *types.RequestAlertEventStream, send func(name string, data interface{}) error) error {
+	if r.FaultCenterId == "" {
+		return fmt.Errorf("故障中心 ID 不能为空")
+	}
+
+	changes, err := e.ctx.Redis.EventStream().Subscribe(ctx, r.TenantId, r.FaultCenterId)
+	if err != nil {
+		return fmt.Errorf("订阅事件变更失败: %s", err.Error())
+	}
+
+	snapshot, err := e.filterCurrentEvents(&types.RequestAlertCurEventQuery{TenantId: r.TenantId, FaultCenterId: r.FaultCenterId})
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		snapshot = []models.AlertCurEvent{}
+	}
+	if err := send(types.EventStreamSnapshot, snapshot); err != nil {
+		return err
+	}
+
+	for change := range changes {
+		if err := send(types.EventStreamChange, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -49,6 +49,46 @@ func (f faultCenterService) Create(req interface{}) (data interface{}, err inter
 		IsUpgradeEnabled:     r.IsUpgradeEnabled,
 		UpgradableSeverity:   r.UpgradableSeverity,
 		UpgradeStrategy:      r.UpgradeStrategy,
+		MaintenanceWindows:   r.MaintenanceWindows,
+		GroupBy:              r.GroupBy,
+		GroupWait:            r.GroupWait,
+		GroupInterval:        r.GroupInterval,
+		EscalationPolicy:     r.EscalationPolicy,
+		DutyIds:              r.DutyIds,
+		NoticeDedup:          r.NoticeDedup,
+		Enrichment:           r.Enrichment,
+		InhibitRules:         r.InhibitRules,
+		FlappingDetection:    r.FlappingDetection,
+	}
+
+	for _, window := range fc.MaintenanceWindows {
+		if err := window.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fc.EscalationPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, route := range fc.NoticeRoutes {
+		if err := route.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range fc.InhibitRules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fc.Enrichment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := fc.FlappingDetection.Validate(); err != nil {
+		return nil, err
 	}
 
 	err = f.ctx.DB.FaultCenter().Create(fc)
@@ -56,7 +96,14 @@ func (f faultCenterService) Create(req interface{}) (data interface{}, err inter
 		return nil, err
 	}
 
-	f.ctx.Redis.FaultCenter().PushFaultCenterInfo(fc)
+	startFaultCenter(f.ctx, fc)
+
+	return nil, nil
+}
+
+// startFaultCenter 缓存新建的故障中心并启动消费协程
+func startFaultCenter(ctx *ctx.Context, fc models.FaultCenter) {
+	ctx.Redis.FaultCenter().PushFaultCenterInfo(fc)
 
 	// 判断当前节点角色
 	if alert.IsLeader() {
@@ -64,15 +111,13 @@ func (f faultCenterService) Create(req interface{}) (data interface{}, err inter
 		alert.ConsumerWork.Submit(fc)
 	} else {
 		// Follower: 发布消息通知 Leader
-		tools.PublishReloadMessage(f.ctx.Ctx, client.Redis, tools.ChannelFaultCenterReload, tools.ReloadMessage{
+		tools.PublishReloadMessage(ctx.Ctx, client.Redis, tools.ChannelFaultCenterReload, tools.ReloadMessage{
 			Action:   tools.ActionCreate,
 			ID:       fc.ID,
 			TenantID: fc.TenantId,
 			Name:     fc.Name,
 		})
 	}
-
-	return nil, nil
 }
 
 func (f faultCenterService) Update(req interface{}) (data interface{}, err interface{}) {
@@ -92,6 +137,46 @@ func (f faultCenterService) Update(req interface{}) (data interface{}, err inter
 		IsUpgradeEnabled:     r.IsUpgradeEnabled,
 		UpgradableSeverity:   r.UpgradableSeverity,
 		UpgradeStrategy:      r.UpgradeStrategy,
+		MaintenanceWindows:   r.MaintenanceWindows,
+		GroupBy:              r.GroupBy,
+		GroupWait:            r.GroupWait,
+		GroupInterval:        r.GroupInterval,
+		EscalationPolicy:     r.EscalationPolicy,
+		DutyIds:              r.DutyIds,
+		NoticeDedup:          r.NoticeDedup,
+		Enrichment:           r.Enrichment,
+		InhibitRules:         r.InhibitRules,
+		FlappingDetection:    r.FlappingDetection,
+	}
+
+	for _, window := range fc.MaintenanceWindows {
+		if err := window.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fc.EscalationPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, route := range fc.NoticeRoutes {
+		if err := route.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range fc.InhibitRules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fc.Enrichment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := fc.FlappingDetection.Validate(); err != nil {
+		return nil, err
 	}
 
 	err = f.ctx.DB.FaultCenter().Update(fc)
@@ -166,7 +251,7 @@ func (f faultCenterService) List(req interface{}) (data interface{}, err interfa
 			switch event.Status {
 			case models.StatePreAlert:
 				faultCenters[index].CurrentPreAlertNumber++
-			case models.StateAlerting:
+			case models.StateAlerting, models.StateSuppressed:
 				faultCenters[index].CurrentAlertNumber++
 			case models.StatePendingRecovery:
 				faultCenters[index].CurrentRecoverNumber++
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"watchAlert/alert"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 心跳超时检查周期
+const heartbeatCheckInterval = 30 * time.Second
+
+type heartbeatService struct {
+	ctx *ctx.Context
+}
+
+type InterHeartbeatService interface {
+	Create(req interface{}) (interface{}, interface{})
+	Update(req interface{}) (interface{}, interface{})
+	Delete(req interface{}) (interface{}, interface{})
+	List(req interface{}) (interface{}, interface{})
+	Ping(req interface{}) (interface{}, interface{})
+	CheckCronjob(ctx context.Context)
+}
+
+func newInterHeartbeatService(ctx *ctx.Context) InterHeartbeatService {
+	return &heartbeatService{
+		ctx: ctx,
+	}
+}
+
+func (h heartbeatService) Create(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestHeartbeatCreate)
+	data := models.Heartbeat{
+		TenantId:      r.TenantId,
+		ID:            "hb-" + tools.RandId(),
+		Name:          r.Name,
+		FaultCenterId: r.FaultCenterId,
+		Interval:      r.Interval,
+		Severity:      r.Severity,
+		Labels:        r.Labels,
+		Description:   r.Description,
+		Enabled:       r.Enabled,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := h.ctx.DB.Heartbeat().Get(r.TenantId, "", r.Name); err == nil {
+		return nil, fmt.Errorf("心跳名称 %s 已存在", r.Name)
+	}
+
+	err := h.ctx.DB.Heartbeat().Create(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (h heartbeatService) Update(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestHeartbeatUpdate)
+	oldData, err := h.ctx.DB.Heartbeat().Get(r.TenantId, r.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	data := models.Heartbeat{
+		TenantId:      r.TenantId,
+		ID:            r.ID,
+		Name:          r.Name,
+		FaultCenterId: r.FaultCenterId,
+		Interval:      r.Interval,
+		Severity:      r.Severity,
+		Labels:        r.Labels,
+		Description:   r.Description,
+		Enabled:       r.Enabled,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
+	if exist, err := h.ctx.DB.Heartbeat().Get(r.TenantId, "", r.Name); err == nil && exist.ID != r.ID {
+		return nil, fmt.Errorf("心跳名称 %s 已存在", r.Name)
+	}
+
+	err = h.ctx.DB.Heartbeat().Update(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 禁用或更换故障中心后, 恢复原故障中心中的心跳告警
+	if !data.GetEnabled() || oldData.FaultCenterId != data.FaultCenterId {
+		resolveExternalEvent(h.ctx, buildHeartbeatEvent(oldData, 0))
+	}
+
+	return nil, nil
+}
+
+func (h heartbeatService) Delete(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestHeartbeatQuery)
+	data, err := h.ctx.DB.Heartbeat().Get(r.TenantId, r.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.ctx.DB.Heartbeat().Delete(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.ctx.Redis.Heartbeat().Delete(r.TenantId, r.ID)
+	resolveExternalEvent(h.ctx, buildHeartbeatEvent(data, 0))
+
+	return nil, nil
+}
+
+func (h heartbeatService) List(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestHeartbeatQuery)
+	data, err := h.ctx.DB.Heartbeat().List(r.TenantId, r.FaultCenterId, r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSeen, err := h.ctx.Redis.Heartbeat().GetAll(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range data {
+		data[i].LastSeen = lastSeen[data[i].ID]
+	}
+
+	return data, nil
+}
+
+// Ping 记录心跳上报时间, 已产生的心跳告警随之恢复
+func (h heartbeatService) Ping(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestHeartbeatPing)
+
+	if r.UserId != "admin" {
+		user, err := h.ctx.DB.Tenant().GetTenantLinkedUserInfo(r.TenantId, r.UserId)
+		if err != nil || user.UserID == "" {
+			return nil, fmt.Errorf("无权向租户 %s 上报心跳", r.TenantId)
+		}
+	}
+
+	data, err := h.ctx.DB.Heartbeat().Get(r.TenantId, "", r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if err := h.ctx.Redis.Heartbeat().Ping(r.TenantId, data.ID, now); err != nil {
+		return nil, err
+	}
+
+	resolveExternalEvent(h.ctx, buildHeartbeatEvent(data, now))
+
+	return nil, nil
+}
+
+// CheckCronjob 定期检查心跳是否超时, 仅由 Leader 节点推送告警
+func (h heartbeatService) CheckCronjob(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if alert.IsLeader() {
+				h.check()
+			}
+		case <-ctx.Done():
+			logc.Infof(ctx, "停止心跳检查!")
+			return
+		}
+	}
+}
+
+func (h heartbeatService) check() {
+	heartbeats, err := h.ctx.DB.Heartbeat().ListAll()
+	if err != nil {
+		logc.Errorf(h.ctx.Ctx, "获取心跳列表失败, err: %s", err.Error())
+		return
+	}
+
+	now := time.Now().Unix()
+	lastSeenByTenant := make(map[string]map[string]int64)
+	for _, hb := range heartbeats {
+		if !hb.GetEnabled() {
+			continue
+		}
+
+		lastSeen, ok := lastSeenByTenant[hb.TenantId]
+		if !ok {
+			lastSeen, err = h.ctx.Redis.Heartbeat().GetAll(hb.TenantId)
+			if err != nil {
+				logc.Errorf(tools.WithLogFields(h.ctx.Ctx, tools.LogFieldTenantId, hb.TenantId), "获取心跳上报时间失败, err: %s", err.Error())
+				continue
+			}
+			lastSeenByTenant[hb.TenantId] = lastSeen
+		}
+
+		// 从未上报时以创建或更新时间开始计算
+		seen := lastSeen[hb.ID]
+		since := seen
+		if since == 0 {
+			since = hb.UpdateAt
+		}
+		if now-since <= hb.Interval {
+			continue
+		}
+
+		event := buildHeartbeatEvent(hb, seen)
+		if _, err := h.ctx.Redis.Pending().Get(event.TenantId, event.RuleId, event.Fingerprint); err != nil {
+			h.ctx.Redis.Pending().Set(event.TenantId, event.RuleId, event.Fingerprint, now-1)
+		}
+
+		event.MarkTriggered()
+		process.PushEventToFaultCenter(h.ctx, &event)
+	}
+}
+
+// buildHeartbeatEvent 构造心跳超时事件, 指纹由心跳 ID 计算, 修改名称或标签后保持不变
+func buildHeartbeatEvent(hb models.Heartbeat, lastSeen int64) models.AlertCurEvent {
+	fingerprint := provider.Metrics{Metric: map[string]interface{}{"heartbeat_id": hb.ID}}.GetFingerprint()
+
+	labels := make(map[string]interface{}, len(hb.Labels)+4)
+	for k, v := range hb.Labels {
+		labels[k] = v
+	}
+	labels["heartbeat"] = hb.Name
+	labels["heartbeat_id"] = hb.ID
+	labels["rule_name"] = hb.Name
+	labels["fingerprint"] = fingerprint
+
+	severity := strings.ToUpper(hb.Severity)
+	if severity == "" {
+		severity = "P1"
+	}
+
+	lastSeenAt := "从未上报"
+	if lastSeen > 0 {
+		lastSeenAt = time.Unix(lastSeen, 0).Format("2006-01-02 15:04:05")
+	}
+	annotations := fmt.Sprintf("心跳 %s 超过 %d 秒未上报, 最近一次上报时间: %s", hb.Name, hb.Interval, lastSeenAt)
+	if hb.Description != "" {
+		annotations += "\n" + hb.Description
+	}
+
+	return models.AlertCurEvent{
+		TenantId:       hb.TenantId,
+		FaultCenterId:  hb.FaultCenterId,
+		RuleId:         models.HeartbeatRuleId,
+		RuleName:       hb.Name,
+		DatasourceType: models.HeartbeatDatasourceType,
+		Fingerprint:    fingerprint,
+		Severity:       severity,
+		Labels:         labels,
+		Annotations:    annotations,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
 	"watchAlert/pkg/sender"
+	"watchAlert/pkg/templates"
 	"watchAlert/pkg/tools"
 
 	"github.com/zeromicro/go-zero/core/logc"
@@ -27,6 +28,8 @@ type InterNoticeService interface {
 	GetRecordMetric(req interface{}) (interface{}, interface{})
 	DeleteRecord(req interface{}) (interface{}, interface{})
 	Test(req interface{}) (interface{}, interface{})
+	ListDeadLetter(req interface{}) (interface{}, interface{})
+	RequeueDeadLetter(req interface{}) (interface{}, interface{})
 }
 
 func newInterAlertNoticeService(ctx *ctx.Context) InterNoticeService {
@@ -46,9 +49,12 @@ func (n noticeService) List(req interface{}) (interface{}, interface{}) {
 
 func (n noticeService) Create(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestNoticeCreate)
-	ok := n.ctx.DB.Notice().GetQuota(r.TenantId)
-	if !ok {
-		return models.AlertNotice{}, fmt.Errorf("创建失败, 配额不足")
+	if err := n.ctx.DB.Notice().GetQuota(r.TenantId); err != nil {
+		return models.AlertNotice{}, err
+	}
+
+	if err := validateNoticeRoutes(r.Routes); err != nil {
+		return nil, err
 	}
 
 	err := n.ctx.DB.Notice().Create(models.AlertNotice{
@@ -68,6 +74,10 @@ func (n noticeService) Create(req interface{}) (interface{}, interface{}) {
 
 func (n noticeService) Update(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestNoticeUpdate)
+	if err := validateNoticeRoutes(r.Routes); err != nil {
+		return nil, err
+	}
+
 	err := n.ctx.DB.Notice().Update(models.AlertNotice{
 		TenantId: r.TenantId,
 		Uuid:     r.Uuid,
@@ -192,6 +202,8 @@ func (n noticeService) Test(req interface{}) (interface{}, interface{}) {
 		Hook:       r.Hook,
 		Email:      r.Email,
 		Sign:       r.Sign,
+		ChatId:     r.ChatId,
+		RoutingKey: r.RoutingKey,
 	})
 	if err != nil {
 		errList = append(errList, struct {
@@ -206,3 +218,76 @@ func (n noticeService) Test(req interface{}) (interface{}, interface{}) {
 
 	return nil, nil
 }
+
+// ListDeadLetter 查看重试后仍发送失败的通知
+func (n noticeService) ListDeadLetter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeDeadLetterQuery)
+	data, err := n.ctx.Redis.DeadLetter().List(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RequeueDeadLetter 重新投递死信通知, 再次失败时会重新进入死信队列
+func (n noticeService) RequeueDeadLetter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeDeadLetterRequeue)
+	if len(r.Ids) == 0 {
+		return nil, errors.New("请选择需要重新投递的通知")
+	}
+
+	results := make([]types.DeadLetterRequeueResult, 0, len(r.Ids))
+	for _, id := range r.Ids {
+		result := types.DeadLetterRequeueResult{Id: id}
+
+		letter, err := n.ctx.Redis.DeadLetter().Get(r.TenantId, id)
+		if err != nil {
+			result.Error = fmt.Sprintf("死信通知不存在, err: %s", err.Error())
+			results = append(results, result)
+			continue
+		}
+
+		params, err := sender.NewSendParamsFromDeadLetter(n.ctx, letter)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if err := n.ctx.Redis.DeadLetter().Remove(r.TenantId, id); err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if err := sender.Sender(n.ctx, params); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// validateNoticeRoutes 校验通知渠道上配置的模版及邮件主题
+func validateNoticeRoutes(routes []models.Route) error {
+	for _, route := range routes {
+		if err := templates.ValidateTemplate(route.Template); err != nil {
+			return fmt.Errorf("%s 通知模版解析失败: %s", route.NoticeType, err.Error())
+		}
+		if err := templates.ValidateTemplate(route.RecoverTemplate); err != nil {
+			return fmt.Errorf("%s 恢复通知模版解析失败: %s", route.NoticeType, err.Error())
+		}
+		if err := templates.ValidateTemplate(route.Subject); err != nil {
+			return fmt.Errorf("%s 邮件主题解析失败: %s", route.NoticeType, err.Error())
+		}
+		if route.NoticeType == "PagerDuty" && route.RoutingKey == "" {
+			return fmt.Errorf("PagerDuty 通知渠道的 Routing Key 不能为空")
+		}
+	}
+
+	return nil
+}
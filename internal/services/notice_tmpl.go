@@ -7,6 +7,7 @@ import (
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
+	"watchAlert/pkg/templates"
 	"watchAlert/pkg/tools"
 )
 
@@ -20,6 +21,7 @@ type InterNoticeTmplService interface {
 	Create(req interface{}) (interface{}, interface{})
 	Update(req interface{}) (interface{}, interface{})
 	Delete(req interface{}) (interface{}, interface{})
+	Test(req interface{}) (interface{}, interface{})
 }
 
 func newInterNoticeTmplService(ctx *ctx.Context) InterNoticeTmplService {
@@ -112,3 +114,59 @@ func (nts noticeTmplService) Delete(req interface{}) (interface{}, interface{})
 
 	return nil, nil
 }
+
+// Test 使用示例事件渲染模版, 不发送通知
+func (nts noticeTmplService) Test(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeTemplateTest)
+	for _, tmpl := range []string{r.Template, r.TemplateFiring, r.TemplateRecover, r.Subject} {
+		if err := templates.ValidateTemplate(tmpl); err != nil {
+			return nil, fmt.Errorf("模版解析失败: %s", err.Error())
+		}
+	}
+
+	event := sampleNoticeEvent()
+	if r.Event != nil {
+		event = *r.Event
+	}
+	event.IsRecovered = r.IsRecovered
+	if event.IsRecovered && event.RecoverTime == 0 {
+		event.RecoverTime = time.Now().Unix()
+		event.LastTriggerTime = event.RecoverTime - 60
+	}
+
+	tmpl := r.Template
+	if tmpl == "" {
+		tmpl = templates.DefaultTemplate(event.IsRecovered)
+	}
+	noticeTmpl := templates.InlineTemplate(r.NoticeType, tmpl)
+	noticeTmpl.TemplateFiring = r.TemplateFiring
+	noticeTmpl.TemplateRecover = r.TemplateRecover
+	if r.EnableFeiShuJsonCard != nil {
+		noticeTmpl.EnableFeiShuJsonCard = r.EnableFeiShuJsonCard
+	}
+
+	return types.ResponseNoticeTemplateTest{
+		Subject: templates.RenderSubject(event, r.Subject),
+		Content: templates.RenderTemplate(event, r.NoticeType, noticeTmpl).CardContentMsg,
+	}, nil
+}
+
+// sampleNoticeEvent 模版测试使用的示例事件
+func sampleNoticeEvent() models.AlertCurEvent {
+	now := time.Now().Unix()
+	return models.AlertCurEvent{
+		TenantId:         "default",
+		RuleId:           "a-example",
+		RuleName:         "示例告警规则",
+		DatasourceType:   "Prometheus",
+		DatasourceId:     "ds-example",
+		Fingerprint:      "1234567890",
+		Severity:         "P1",
+		FaultCenterId:    "fc-example",
+		Labels:           map[string]interface{}{"instance": "10.0.0.1:9100", "job": "node", "severity": "P1", "value": 95.2},
+		Annotations:      "实例 10.0.0.1:9100 CPU 使用率 95.2%",
+		FirstTriggerTime: now - 300,
+		LastEvalTime:     now,
+		DutyUser:         "@example",
+	}
+}
@@ -1,9 +1,9 @@
 package services
 
 import (
-	"encoding/base64"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 	"watchAlert/config"
@@ -13,7 +13,6 @@ import (
 	"watchAlert/pkg/oidc"
 	"watchAlert/pkg/tools"
 
-	"github.com/bytedance/sonic"
 	"github.com/gin-gonic/gin"
 	"github.com/zeromicro/go-zero/core/logc"
 )
@@ -40,12 +39,13 @@ func (os oidcService) GetOidcInfo() (interface{}, interface{}) {
 		return nil, err
 	}
 
+	oc := getOidcConfig(setting.OidcConfig)
 	return &types.OidcInfo{
 		AuthType:     setting.AuthType,
-		ClientID:     setting.OidcConfig.ClientID,
-		ClientSecret: setting.OidcConfig.ClientSecret,
-		UpperURI:     setting.OidcConfig.UpperURI,
-		RedirectURI:  setting.OidcConfig.RedirectURI,
+		ClientID:     oc.ClientID,
+		ClientSecret: oc.ClientSecret,
+		UpperURI:     oc.UpperURI,
+		RedirectURI:  oc.RedirectURI,
 	}, nil
 }
 
@@ -54,33 +54,24 @@ func (os oidcService) CallBack(ctx *gin.Context, req interface{}) (interface{},
 	if err != nil {
 		return nil, err
 	}
+	oc := getOidcConfig(setting.OidcConfig)
 
-	cfg, err := oidc.GetOpenIDConfiguration(setting.OidcConfig.UpperURI)
+	cfg, err := oidc.GetOpenIDConfiguration(oc.UpperURI)
 	if err != nil {
 		return nil, err
 	}
 
 	r := req.(*types.RequestOidcCodeQuery)
-	data, err := oidc.GetOauthToken(cfg.TokenEndpoint, r.Code, setting.OidcConfig.ClientID, setting.OidcConfig.ClientSecret)
+	data, err := oidc.GetOauthToken(cfg.TokenEndpoint, r.Code, oc.ClientID, oc.ClientSecret, oc.RedirectURI)
 	if err != nil {
 		return nil, err
 	}
 
-	parts := strings.Split(data.AccessToken, ".")
-	if len(parts) < 2 {
-		return nil, err
-	}
-
-	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	claims, err := oidc.VerifyIDToken(data.IdToken, cfg, oc.ClientID)
 	if err != nil {
 		return nil, err
 	}
 
-	var payload map[string]interface{}
-	if err = sonic.Unmarshal(payloadBytes, &payload); err != nil {
-		return nil, err
-	}
-
 	result, err := oidc.GetCurrentUser(cfg.UserinfoEndpoint, data.AccessToken)
 	if err != nil {
 		return nil, err
@@ -90,11 +81,20 @@ func (os oidcService) CallBack(ctx *gin.Context, req interface{}) (interface{},
 		return nil, fmt.Errorf("获取用户信息失败")
 	}
 
-	_, ok, _ := os.ctx.DB.User().Get("", result.Id, "")
+	// 用户信息接口的返回未经签名校验, 需与 ID Token 中的用户一致
+	if err := oidc.MatchUserInfo(claims, result); err != nil {
+		return nil, err
+	}
+
+	user, ok, _ := os.ctx.DB.User().Get("", result.Id, "")
 	if ok {
 		logc.Infof(os.ctx.Ctx, fmt.Sprintf("用户 %s 已存在", result.Id))
 	} else {
-		err = os.ctx.DB.User().Create(models.Member{
+		if !oc.GetAutoCreateUser() {
+			return nil, fmt.Errorf("用户 %s 不存在, 请联系管理员创建", result.Id)
+		}
+
+		user = models.Member{
 			UserId:   tools.RandUid(),
 			UserName: result.Id,
 			Email:    result.Email,
@@ -102,19 +102,68 @@ func (os oidcService) CallBack(ctx *gin.Context, req interface{}) (interface{},
 			Password: tools.GenerateHashPassword(types.OidcPassword),
 			CreateBy: "OIDC",
 			CreateAt: time.Now().Unix(),
-		})
+		}
+		err = os.ctx.DB.User().Create(user)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	ctx.SetCookie("token", data.AccessToken, 60*60*24, "/", setting.OidcConfig.Domain, false, false)
-	ctx.SetCookie("rftoken", data.RefreshToken, 60*60*24, "/", setting.OidcConfig.Domain, false, false)
+	os.syncGroupRoles(user, oidc.GetClaimStrings(claims, oc.GetGroupsClaim()), oc.GroupRoles)
+
+	ctx.SetCookie("token", data.AccessToken, 60*60*24, "/", oc.Domain, false, false)
+	ctx.SetCookie("rftoken", data.RefreshToken, 60*60*24, "/", oc.Domain, false, false)
 	ctx.Redirect(http.StatusTemporaryRedirect, "/")
 
 	return nil, nil
 }
 
+// syncGroupRoles 按用户组映射将用户加入租户, 只追加不移除, 单个租户失败不影响登录
+// 角色只在首次加入租户时按映射设置, 已是租户成员的用户保留现有角色, 避免覆盖管理员手动调整的角色;
+// 同一租户匹配多个用户组时以配置顺序中第一个匹配的映射为准
+func (os oidcService) syncGroupRoles(user models.Member, groups []string, groupRoles []models.OidcGroupRole) {
+	for _, gr := range groupRoles {
+		if !slices.Contains(groups, gr.Group) {
+			continue
+		}
+
+		logCtx := tools.WithLogFields(os.ctx.Ctx, tools.LogFieldTenantId, gr.TenantId)
+		member, err := os.ctx.DB.Tenant().GetTenantLinkedUserInfo(gr.TenantId, user.UserId)
+		if err != nil {
+			logc.Errorf(logCtx, "OIDC 用户组映射失败, user: %s, group: %s, err: %s", user.UserName, gr.Group, err.Error())
+			continue
+		}
+		if member.UserID != "" {
+			continue
+		}
+
+		err = os.ctx.DB.Tenant().AddTenantLinkedUsers(gr.TenantId, []models.TenantUser{
+			{UserID: user.UserId, UserName: user.UserName},
+		}, gr.RoleId)
+		if err != nil {
+			logc.Errorf(logCtx, "OIDC 用户组映射失败, user: %s, group: %s, err: %s", user.UserName, gr.Group, err.Error())
+		}
+	}
+}
+
+// getOidcConfig 配置文件中配置了身份提供方时, 连接配置以配置文件为准
+func getOidcConfig(oc models.OidcConfig) models.OidcConfig {
+	c := config.Application.Oidc
+	if c.Issuer == "" {
+		return oc
+	}
+
+	oc.UpperURI = strings.TrimSuffix(c.Issuer, "/") + "/.well-known/openid-configuration"
+	oc.ClientID = c.ClientID
+	oc.ClientSecret = c.ClientSecret
+	oc.RedirectURI = c.RedirectURI
+	if c.Domain != "" {
+		oc.Domain = c.Domain
+	}
+
+	return oc
+}
+
 func (os oidcService) CookieConvertToken(ctx *gin.Context) (interface{}, interface{}) {
 	setting, err := os.ctx.DB.Setting().Get()
 	if err != nil {
@@ -126,7 +175,7 @@ func (os oidcService) CookieConvertToken(ctx *gin.Context) (interface{}, interfa
 		return nil, err
 	}
 
-	cfg, err := oidc.GetOpenIDConfiguration(setting.OidcConfig.UpperURI)
+	cfg, err := oidc.GetOpenIDConfiguration(getOidcConfig(setting.OidcConfig).UpperURI)
 	if err != nil {
 		return nil, err
 	}
@@ -2,15 +2,20 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 	"watchAlert/alert"
+	"watchAlert/alert/process"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
 	"watchAlert/pkg/client"
+	"watchAlert/pkg/provider"
 	"watchAlert/pkg/tools"
 
 	"github.com/bytedance/sonic"
+	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/zeromicro/go-zero/core/logc"
 	"gopkg.in/yaml.v3"
 )
@@ -26,8 +31,13 @@ type InterRuleService interface {
 	List(req interface{}) (interface{}, interface{})
 	Get(req interface{}) (interface{}, interface{})
 	ChangeStatus(req interface{}) (interface{}, interface{})
+	Pause(req interface{}) (interface{}, interface{})
+	EvalHistory(req interface{}) (interface{}, interface{})
 	Import(req interface{}) (interface{}, interface{})
+	ExportPrometheus(req interface{}) (interface{}, interface{})
+	Shard(req interface{}) (interface{}, interface{})
 	Change(req interface{}) (interface{}, interface{})
+	Preview(req interface{}) (interface{}, interface{})
 }
 
 func newInterRuleService(ctx *ctx.Context) InterRuleService {
@@ -38,9 +48,8 @@ func newInterRuleService(ctx *ctx.Context) InterRuleService {
 
 func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestRuleCreate)
-	ok := rs.ctx.DB.Rule().GetQuota(r.TenantId)
-	if !ok {
-		return nil, fmt.Errorf("创建失败, 配额不足")
+	if err := rs.ctx.DB.Rule().GetQuota(r.TenantId); err != nil {
+		return nil, err
 	}
 
 	data := models.AlertRule{
@@ -56,7 +65,18 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 		Description:          r.Description,
 		EffectiveTime:        r.EffectiveTime,
 		Severity:             r.Severity,
+		SeverityExpr:         r.SeverityExpr,
+		ForDuration:          r.ForDuration,
+		NoDataAlert:          r.NoDataAlert,
+		OverrunPolicy:        r.OverrunPolicy,
+		DatasourceStrategy:   r.DatasourceStrategy,
+		DatasourceQuorum:     r.DatasourceQuorum,
+		MaxFingerprints:      r.MaxFingerprints,
+		LinkTemplate:         r.LinkTemplate,
+		Enrichment:           r.Enrichment,
+		RecoverWaitTime:      r.RecoverWaitTime,
 		PrometheusConfig:     r.PrometheusConfig,
+		InfluxDBConfig:       r.InfluxDBConfig,
 		AliCloudSLSConfig:    r.AliCloudSLSConfig,
 		LokiConfig:           r.LokiConfig,
 		VictoriaLogsConfig:   r.VictoriaLogsConfig,
@@ -66,12 +86,59 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 		KubernetesConfig:     r.KubernetesConfig,
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		LogEvalCondition:     r.LogEvalCondition,
+		LogGroupBy:           r.LogGroupBy,
+		FingerprintLabels:    r.FingerprintLabels,
 		FaultCenterId:        r.FaultCenterId,
+		TemplateGroup:        r.TemplateGroup,
+		TemplateName:         r.TemplateName,
+		TemplateVariables:    r.TemplateVariables,
 		UpdateAt:             time.Now().Unix(),
 		UpdateBy:             r.UpdateBy,
 		Enabled:              r.Enabled,
 	}
 
+	if err := data.Enrichment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := validateThresholdRules(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateLogRule(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateFingerprintLabels(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateDatasourceStrategy(data); err != nil {
+		return nil, err
+	}
+
+	if data.MaxFingerprints < 0 {
+		return nil, fmt.Errorf("最大事件数不能小于 0")
+	}
+
+	if data.LinkTemplate != "" {
+		if _, err := models.ParseQueryLinkTemplate(data.LinkTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if !r.SkipQueryValidation {
+		if err := validateRuleQuery(rs.ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.SeverityExpr != "" {
+		if _, err := process.CompileSeverityExpr(data.SeverityExpr); err != nil {
+			return nil, err
+		}
+	}
+
 	err := rs.ctx.DB.Rule().Create(data)
 	if err != nil {
 		return nil, err
@@ -79,7 +146,7 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 
 	// 判断当前节点角色
 	if *r.GetEnabled() {
-		if alert.IsLeader() {
+		if alert.IsRuleOwner(data.RuleId) {
 			// Leader: 直接启动评估协程
 			alert.AlertRule.Submit(data)
 		} else {
@@ -103,7 +170,8 @@ func (rs ruleService) Update(req interface{}) (interface{}, interface{}) {
 		Where("tenant_id = ? AND rule_id = ?", r.TenantId, r.RuleId).
 		First(&oldRule)
 
-	if oldRule.FaultCenterId != r.FaultCenterId {
+	// 指纹标签变化后事件的指纹随之变化, 清除旧事件以免按旧指纹触发恢复通知
+	if oldRule.FaultCenterId != r.FaultCenterId || !slices.Equal(oldRule.FingerprintLabels, r.FingerprintLabels) {
 		fingerprints := rs.ctx.Redis.Alert().GetFingerprintsByRuleId(oldRule.TenantId, oldRule.FaultCenterId, oldRule.RuleId)
 		for _, fingerprint := range fingerprints {
 			rs.ctx.Redis.Alert().RemoveAlertEvent(oldRule.TenantId, oldRule.FaultCenterId, fingerprint)
@@ -136,7 +204,18 @@ func (rs ruleService) Update(req interface{}) (interface{}, interface{}) {
 		Description:          r.Description,
 		EffectiveTime:        r.EffectiveTime,
 		Severity:             r.Severity,
+		SeverityExpr:         r.SeverityExpr,
+		ForDuration:          r.ForDuration,
+		NoDataAlert:          r.NoDataAlert,
+		OverrunPolicy:        r.OverrunPolicy,
+		DatasourceStrategy:   r.DatasourceStrategy,
+		DatasourceQuorum:     r.DatasourceQuorum,
+		MaxFingerprints:      r.MaxFingerprints,
+		LinkTemplate:         r.LinkTemplate,
+		Enrichment:           r.Enrichment,
+		RecoverWaitTime:      r.RecoverWaitTime,
 		PrometheusConfig:     r.PrometheusConfig,
+		InfluxDBConfig:       r.InfluxDBConfig,
 		AliCloudSLSConfig:    r.AliCloudSLSConfig,
 		LokiConfig:           r.LokiConfig,
 		VictoriaLogsConfig:   r.VictoriaLogsConfig,
@@ -146,21 +225,72 @@ func (rs ruleService) Update(req interface{}) (interface{}, interface{}) {
 		KubernetesConfig:     r.KubernetesConfig,
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		LogEvalCondition:     r.LogEvalCondition,
+		LogGroupBy:           r.LogGroupBy,
+		FingerprintLabels:    r.FingerprintLabels,
 		FaultCenterId:        r.FaultCenterId,
 		UpdateAt:             time.Now().Unix(),
 		UpdateBy:             r.UpdateBy,
 		Enabled:              r.Enabled,
 	}
 
+	if err := data.Enrichment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := validateThresholdRules(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateLogRule(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateFingerprintLabels(data); err != nil {
+		return nil, err
+	}
+
+	if err := validateDatasourceStrategy(data); err != nil {
+		return nil, err
+	}
+
+	if data.MaxFingerprints < 0 {
+		return nil, fmt.Errorf("最大事件数不能小于 0")
+	}
+
+	if data.LinkTemplate != "" {
+		if _, err := models.ParseQueryLinkTemplate(data.LinkTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if !r.SkipQueryValidation {
+		if err := validateRuleQuery(rs.ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.SeverityExpr != "" {
+		if _, err := process.CompileSeverityExpr(data.SeverityExpr); err != nil {
+			return nil, err
+		}
+	}
+
 	// 更新数据
 	err := rs.ctx.DB.Rule().Update(data)
 	if err != nil {
 		return nil, err
 	}
 
+	// 重新启用后清除评估异常信息
+	if action == tools.ActionEnable {
+		if err := rs.ctx.DB.Rule().ClearEvalError(r.TenantId, r.RuleId); err != nil {
+			return nil, err
+		}
+	}
+
 	// 判断当前节点角色并处理
 	if action != "" {
-		if alert.IsLeader() {
+		if alert.IsRuleOwner(r.RuleId) {
 			// Leader: 直接操作协程
 			if action == tools.ActionDisable || action == tools.ActionUpdate {
 				alert.AlertRule.Stop(r.RuleId)
@@ -204,7 +334,7 @@ func (rs ruleService) Delete(req interface{}) (interface{}, interface{}) {
 
 	// 判断当前节点角色
 	if *info.GetEnabled() {
-		if alert.IsLeader() {
+		if alert.IsRuleOwner(r.RuleId) {
 			// Leader: 直接停止协程
 			alert.AlertRule.Stop(r.RuleId)
 		} else {
@@ -234,6 +364,11 @@ func (rs ruleService) List(req interface{}) (interface{}, interface{}) {
 		return nil, err
 	}
 
+	now := time.Now()
+	for i := range data {
+		data[i].Paused = *data[i].GetEnabled() && data[i].IsPaused(now)
+	}
+
 	return types.ResponseRuleList{
 		List: data,
 		Page: models.Page{
@@ -275,9 +410,16 @@ func (rs ruleService) ChangeStatus(req interface{}) (interface{}, interface{}) {
 		return nil, err
 	}
 
+	// 重新启用后清除评估异常信息
+	if *r.GetEnabled() {
+		if err := rs.ctx.DB.Rule().ClearEvalError(r.TenantId, r.RuleId); err != nil {
+			return nil, err
+		}
+	}
+
 	// 判断当前节点角色
 	rule := rs.ctx.DB.Rule().GetRuleObject(r.RuleId)
-	if alert.LeaderElector != nil && alert.LeaderElector.IsLeader() {
+	if alert.IsRuleOwner(r.RuleId) {
 		// Leader: 直接操作协程
 		switch *r.GetEnabled() {
 		case true:
@@ -301,55 +443,89 @@ func (rs ruleService) ChangeStatus(req interface{}) (interface{}, interface{}) {
 	return nil, nil
 }
 
+// Pause 暂停规则评估至指定时间, 保留规则配置及当前事件, 到期后评估器自动恢复
+func (rs ruleService) Pause(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRulePause)
+	if r.PausedUntil != 0 && r.PausedUntil <= time.Now().Unix() {
+		return nil, fmt.Errorf("暂停截止时间必须晚于当前时间")
+	}
+
+	rule, err := rs.ctx.DB.Rule().Get(r.TenantId, r.RuleGroupId, r.RuleId)
+	if err != nil {
+		return nil, err
+	}
+	if !*rule.GetEnabled() {
+		return nil, fmt.Errorf("规则未启用, 无需暂停")
+	}
+
+	if err := rs.ctx.DB.Rule().Pause(r.TenantId, r.RuleGroupId, r.RuleId, r.PausedUntil); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// EvalHistory 获取规则最近的评估记录, 最新的记录在前
+func (rs ruleService) EvalHistory(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleEvalHistory)
+	if r.RuleId == "" {
+		return nil, fmt.Errorf("规则ID不能为空")
+	}
+
+	return rs.ctx.Redis.RuleEvalHistory().List(r.TenantId, r.RuleId, r.Limit)
+}
+
+// Shard 获取负责评估规则的实例, 用于排查多副本部署时规则由哪个实例评估
+func (rs ruleService) Shard(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleShard)
+	if r.RuleId == "" {
+		return nil, fmt.Errorf("规则ID不能为空")
+	}
+
+	res := types.ResponseRuleShard{Shard: -1, Workers: []string{}}
+	switch {
+	case alert.ShardWorker != nil:
+		res.Sharding = true
+		res.Owner, res.Shard = alert.ShardWorker.Owner(r.RuleId)
+		res.Workers = alert.ShardWorker.Workers()
+		res.Instance = alert.ShardWorker.GetInstanceID()
+	case alert.LeaderElector != nil:
+		owner, err := alert.LeaderElector.GetLeaderID()
+		if err != nil {
+			return nil, err
+		}
+		res.Owner = owner
+		res.Instance = alert.LeaderElector.GetInstanceID()
+	}
+
+	return res, nil
+}
+
 func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestRuleImport)
 	var (
-		rules []types.RequestRuleCreate
+		rules  []types.RequestRuleCreate
+		result = types.ResponseRuleImport{
+			Failed:   []types.RuleImportIssue{},
+			Warnings: []types.RuleImportIssue{},
+		}
 		// 导入的规则默认为关闭状态
 		disable bool
 	)
 
 	switch r.ImportType {
 	case types.WithPrometheusRuleImport:
+		if r.DatasourceType != provider.PrometheusDsProvider {
+			return nil, fmt.Errorf("Prometheus 告警规则仅支持导入到 Prometheus 类型的数据源")
+		}
+
 		var alerts types.PrometheusAlerts
 		err := yaml.Unmarshal([]byte(r.Rules), &alerts)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, alert := range alerts.Rules {
-			var forDuration int64
-			d, err := time.ParseDuration(alert.For)
-			if err != nil {
-				forDuration = 1
-				logc.Error(rs.ctx.Ctx, err.Error())
-			}
-			forDuration = int64(d.Seconds())
-
-			rule := types.RequestRuleCreate{
-				TenantId:         r.TenantId,
-				RuleGroupId:      r.RuleGroupId,
-				ExternalLabels:   alert.Labels,
-				DatasourceType:   r.DatasourceType,
-				DatasourceIdList: r.DatasourceIdList,
-				RuleName:         alert.Alert,
-				EvalInterval:     15,
-				PrometheusConfig: models.PrometheusConfig{
-					PromQL:      alert.Expr,
-					Annotations: alert.Annotations.Description,
-					Rules: []models.Rules{
-						{
-							ForDuration: forDuration,
-							Severity:    "P1",
-							Expr:        ">= 0",
-						},
-					},
-				},
-				FaultCenterId: r.FaultCenterId,
-				Enabled:       alert.GetEnable(),
-			}
-			rules = append(rules, rule)
-		}
+		rules = translatePrometheusRules(r, alerts, &result)
 
 	case types.WithWatchAlertJsonImport:
 		err := sonic.Unmarshal([]byte(r.Rules), &rules)
@@ -358,7 +534,7 @@ func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 		}
 	}
 
-	if len(rules) == 0 {
+	if len(rules) == 0 && len(result.Failed) == 0 {
 		return nil, fmt.Errorf("导入失败, 识别到 0 条规则")
 	}
 
@@ -380,7 +556,18 @@ func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 			Description:          rule.Description,
 			EffectiveTime:        rule.EffectiveTime,
 			Severity:             rule.Severity,
+			SeverityExpr:         rule.SeverityExpr,
+			ForDuration:          rule.ForDuration,
+			NoDataAlert:          rule.NoDataAlert,
+			OverrunPolicy:        rule.OverrunPolicy,
+			DatasourceStrategy:   rule.DatasourceStrategy,
+			DatasourceQuorum:     rule.DatasourceQuorum,
+			MaxFingerprints:      rule.MaxFingerprints,
+			LinkTemplate:         rule.LinkTemplate,
+			Enrichment:           rule.Enrichment,
+			RecoverWaitTime:      rule.RecoverWaitTime,
 			PrometheusConfig:     rule.PrometheusConfig,
+			InfluxDBConfig:       rule.InfluxDBConfig,
 			AliCloudSLSConfig:    rule.AliCloudSLSConfig,
 			LokiConfig:           rule.LokiConfig,
 			VictoriaLogsConfig:   rule.VictoriaLogsConfig,
@@ -390,16 +577,20 @@ func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 			KubernetesConfig:     rule.KubernetesConfig,
 			ElasticSearchConfig:  rule.ElasticSearchConfig,
 			LogEvalCondition:     rule.LogEvalCondition,
+			LogGroupBy:           rule.LogGroupBy,
+			FingerprintLabels:    rule.FingerprintLabels,
 			FaultCenterId:        rule.FaultCenterId,
 			Enabled:              &disable,
 		})
 		if err != nil {
-			logc.Errorf(rs.ctx.Ctx, err.Error())
+			logc.Errorf(tools.WithLogFields(rs.ctx.Ctx, tools.LogFieldTenantId, r.TenantId, tools.LogFieldRuleName, rule.RuleName), "导入规则失败, err: %s", err.Error())
+			result.Failed = append(result.Failed, types.RuleImportIssue{Name: rule.RuleName, Reason: err.Error()})
 			continue
 		}
+		result.Imported++
 	}
 
-	return nil, nil
+	return result, nil
 }
 
 func (rs ruleService) Change(req interface{}) (interface{}, interface{}) {
@@ -443,7 +634,7 @@ func (rs ruleService) Change(req interface{}) (interface{}, interface{}) {
 					for _, val := range v {
 						ds, err := rs.ctx.DB.Datasource().Get(val.(string))
 						if err != nil {
-							logc.Errorf(rs.ctx.Ctx, "获取数据源信息错误, id: %s, error: %v", val.(string), err)
+							logc.Errorf(tools.WithLogFields(rs.ctx.Ctx, tools.LogFieldTenantId, r.TenantId, tools.LogFieldRuleId, ruleId, tools.LogFieldDatasourceId, val.(string)), "获取数据源信息错误, error: %v", err)
 						}
 						if ds.Type != rule.DatasourceType {
 							continue
@@ -487,7 +678,7 @@ func (rs ruleService) Change(req interface{}) (interface{}, interface{}) {
 
 		// 규칙 활성화 상태에 따라 적절한 처리
 		if *rule.Enabled {
-			if alert.IsLeader() {
+			if alert.IsRuleOwner(ruleId) {
 				// 리더: 기존 평가 고루틴을 중지하고 새로운 것을 시작합니다
 				alert.AlertRule.Stop(ruleId)
 				alert.AlertRule.Submit(rule)
@@ -506,7 +697,7 @@ func (rs ruleService) Change(req interface{}) (interface{}, interface{}) {
 				rs.ctx.Redis.Alert().RemoveAlertEvent(r.TenantId, rule.FaultCenterId, fingerprint)
 			}
 
-			if alert.IsLeader() {
+			if alert.IsRuleOwner(ruleId) {
 				// 리더: 평가 고루틴 중지
 				alert.AlertRule.Stop(ruleId)
 			} else {
@@ -523,3 +714,242 @@ func (rs ruleService) Change(req interface{}) (interface{}, interface{}) {
 
 	return nil, nil
 }
+
+// Preview 预览规则当前的评估结果, 不会产生告警事件
+func (rs ruleService) Preview(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleCreate)
+	rule := models.AlertRule{
+		TenantId:             r.TenantId,
+		RuleGroupId:          r.RuleGroupId,
+		ExternalLabels:       r.ExternalLabels,
+		DatasourceType:       r.DatasourceType,
+		DatasourceIdList:     r.DatasourceIdList,
+		RuleName:             r.RuleName,
+		EvalInterval:         r.EvalInterval,
+		RepeatNoticeInterval: r.RepeatNoticeInterval,
+		Description:          r.Description,
+		EffectiveTime:        r.EffectiveTime,
+		Severity:             r.Severity,
+		SeverityExpr:         r.SeverityExpr,
+		ForDuration:          r.ForDuration,
+		NoDataAlert:          r.NoDataAlert,
+		OverrunPolicy:        r.OverrunPolicy,
+		DatasourceStrategy:   r.DatasourceStrategy,
+		DatasourceQuorum:     r.DatasourceQuorum,
+		MaxFingerprints:      r.MaxFingerprints,
+		LinkTemplate:         r.LinkTemplate,
+		Enrichment:           r.Enrichment,
+		RecoverWaitTime:      r.RecoverWaitTime,
+		PrometheusConfig:     r.PrometheusConfig,
+		InfluxDBConfig:       r.InfluxDBConfig,
+		AliCloudSLSConfig:    r.AliCloudSLSConfig,
+		LokiConfig:           r.LokiConfig,
+		VictoriaLogsConfig:   r.VictoriaLogsConfig,
+		ClickHouseConfig:     r.ClickHouseConfig,
+		JaegerConfig:         r.JaegerConfig,
+		CloudWatchConfig:     r.CloudWatchConfig,
+		KubernetesConfig:     r.KubernetesConfig,
+		ElasticSearchConfig:  r.ElasticSearchConfig,
+		LogEvalCondition:     r.LogEvalCondition,
+		LogGroupBy:           r.LogGroupBy,
+		FingerprintLabels:    r.FingerprintLabels,
+		FaultCenterId:        r.FaultCenterId,
+	}
+
+	return alert.AlertRule.Preview(rule)
+}
+
+// validateThresholdRules 校验指标类规则的分级阈值, 每个等级只能配置一次
+func validateThresholdRules(rule models.AlertRule) error {
+	severities := make(map[string]struct{})
+	for _, r := range rule.GetThresholdRules() {
+		if r.Severity == "" {
+			return fmt.Errorf("告警等级不能为空")
+		}
+		if _, ok := severities[r.Severity]; ok {
+			return fmt.Errorf("告警等级 %s 重复配置", r.Severity)
+		}
+		severities[r.Severity] = struct{}{}
+
+		if _, _, err := process.ProcessRuleExpr(r.Expr); err != nil {
+			return fmt.Errorf("告警等级 %s 的阈值表达式无效: %s", r.Severity, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateRuleQuery 校验规则的查询语句, PromQL 先在本地解析, 再由数据源校验或执行一次查询,
+// 校验失败时返回数据源的错误信息; 数据源暂时不可用时可通过 SkipQueryValidation 跳过
+func validateRuleQuery(ctx *ctx.Context, rule models.AlertRule) error {
+	switch rule.DatasourceType {
+	case provider.PrometheusDsProvider:
+		if _, err := parser.ParseExpr(rule.PrometheusConfig.PromQL); err != nil {
+			return fmt.Errorf("PromQL 语法错误: %s", err.Error())
+		}
+	case provider.LokiDsProviderName, provider.ElasticSearchDsProviderName:
+	default:
+		return nil
+	}
+
+	for _, dsId := range rule.DatasourceIdList {
+		cli, err := ctx.Redis.ProviderPools().GetClient(dsId)
+		if err != nil {
+			return fmt.Errorf("获取数据源客户端失败, 数据源ID: %s, err: %s", dsId, err.Error())
+		}
+
+		switch c := cli.(type) {
+		case provider.PrometheusProvider:
+			var metrics []provider.Metrics
+			if metrics, err = c.Query(ctx.Ctx, rule.PrometheusConfig.PromQL); err == nil {
+				if err := checkFingerprintCollision(rule, metrics); err != nil {
+					return err
+				}
+			}
+		case provider.LokiProvider:
+			err = c.ValidateQuery(rule.LokiConfig.LogQL)
+		case provider.ElasticSearchDsProvider:
+			err = c.ValidateQuery(provider.Elasticsearch{
+				Index:         rule.ElasticSearchConfig.Index,
+				QueryType:     rule.ElasticSearchConfig.EsQueryType,
+				RawJson:       rule.ElasticSearchConfig.RawJson,
+				QueryLanguage: rule.ElasticSearchConfig.GetQueryLanguage(),
+				EsQL:          rule.ElasticSearchConfig.EsQL,
+				Scope:         rule.ElasticSearchConfig.Scope,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("查询语句校验失败, 数据源ID: %s, err: %s", dsId, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateDatasourceStrategy 校验多个数据源的评估方式, quorum 方式下最少数据源数量不能超过数据源总数
+func validateDatasourceStrategy(rule models.AlertRule) error {
+	switch rule.DatasourceStrategy {
+	case "", models.DatasourceStrategyUnion, models.DatasourceStrategyFailover:
+		return nil
+	case models.DatasourceStrategyQuorum:
+		if rule.DatasourceQuorum < 0 || int(rule.DatasourceQuorum) > len(rule.DatasourceIdList) {
+			return fmt.Errorf("最少满足条件的数据源数量需在 0 到 %d 之间", len(rule.DatasourceIdList))
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的数据源评估方式: %s", rule.DatasourceStrategy)
+	}
+}
+
+// validateFingerprintLabels 校验指纹标签, 仅指标类规则支持配置
+func validateFingerprintLabels(rule models.AlertRule) error {
+	if len(rule.FingerprintLabels) == 0 {
+		return nil
+	}
+
+	switch rule.DatasourceType {
+	case provider.PrometheusDsProvider, provider.InfluxDBDsProviderName, provider.DatadogDsProviderName:
+	default:
+		return fmt.Errorf("数据源类型 %s 不支持配置指纹标签", rule.DatasourceType)
+	}
+
+	labels := make(map[string]struct{}, len(rule.FingerprintLabels))
+	for _, label := range rule.FingerprintLabels {
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("指纹标签不能为空")
+		}
+		if _, ok := labels[label]; ok {
+			return fmt.Errorf("指纹标签 %s 重复配置", label)
+		}
+		labels[label] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkFingerprintCollision 使用当前的查询结果校验指纹标签, 多个序列生成相同指纹时会被合并为同一事件, 返回冲突的序列
+func checkFingerprintCollision(rule models.AlertRule, metrics []provider.Metrics) error {
+	if len(rule.FingerprintLabels) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]map[string]interface{}, len(metrics))
+	for _, m := range metrics {
+		fingerprint := provider.Metrics{Metric: rule.BuildFingerprintLabels(m.GetMetric())}.GetFingerprint()
+		if first, ok := seen[fingerprint]; ok {
+			return fmt.Errorf("指纹标签 %v 无法区分序列 %v 与 %v, 请增加指纹标签", rule.FingerprintLabels, first, m.GetMetric())
+		}
+		seen[fingerprint] = m.GetMetric()
+	}
+
+	return nil
+}
+
+// validateLogRule 校验日志类规则的评估配置
+func validateLogRule(rule models.AlertRule) error {
+	groupBy := make(map[string]struct{}, len(rule.LogGroupBy))
+	for _, field := range rule.LogGroupBy {
+		if field == "" {
+			return fmt.Errorf("日志分组字段不能为空")
+		}
+		if _, ok := groupBy[field]; ok {
+			return fmt.Errorf("日志分组字段 %s 重复配置", field)
+		}
+		groupBy[field] = struct{}{}
+	}
+
+	if rule.DatasourceType == provider.LokiDsProviderName {
+		switch rule.LokiConfig.GetEvalMode() {
+		case models.LogEvalModeCount:
+		case models.LogEvalModeRate:
+			if rule.LokiConfig.LogScope <= 0 {
+				return fmt.Errorf("rate 评估模式需要配置查询范围")
+			}
+		default:
+			return fmt.Errorf("不支持的日志评估模式: %s", rule.LokiConfig.EvalMode)
+		}
+	}
+
+	if rule.DatasourceType == provider.AliCloudSLSDsProviderName {
+		if err := rule.AliCloudSLSConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.DatasourceType == provider.VictoriaLogsDsProviderName && rule.VictoriaLogsConfig.ValueField != "" {
+		if len(rule.LogGroupBy) > 0 {
+			return fmt.Errorf("按 stats 聚合结果评估时按 stats 管道的 by 字段分组, 不支持配置日志分组字段")
+		}
+		stats, err := models.ParseLogsQLStats(rule.VictoriaLogsConfig.LogQL)
+		if err != nil {
+			return err
+		}
+		if !stats.HasResult(rule.VictoriaLogsConfig.ValueField) {
+			return fmt.Errorf("LogsQL 查询的 stats 管道未产生聚合结果字段 %s, 可用的结果字段: %v", rule.VictoriaLogsConfig.ValueField, stats.Results)
+		}
+	}
+
+	if rule.DatasourceType == provider.ElasticSearchDsProviderName {
+		es := rule.ElasticSearchConfig
+		switch es.GetQueryLanguage() {
+		case models.EsQueryLanguageLucene:
+			if es.EsQL != "" {
+				return fmt.Errorf("lucene 查询语言不支持 ES|QL 语句")
+			}
+			if es.ValueField != "" {
+				return fmt.Errorf("lucene 查询语言不支持按数值列评估, 请使用 esql")
+			}
+		case models.EsQueryLanguageEsql:
+			if es.EsQL == "" {
+				return fmt.Errorf("ES|QL 语句不能为空")
+			}
+			if es.RawJson != "" || len(es.Filter) > 0 {
+				return fmt.Errorf("esql 查询语言不支持同时配置 RawJson 或过滤条件")
+			}
+		default:
+			return fmt.Errorf("不支持的 ElasticSearch 查询语言: %s", es.QueryLanguage)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// prometheusExportSeverity 告警等级与 Prometheus 常用 severity 标签的映射
+var prometheusExportSeverity = map[string]string{
+	"P0": "critical",
+	"P1": "warning",
+	"P2": "info",
+}
+
+// ExportPrometheus 将 Prometheus 类型的告警规则导出为 Prometheus 告警规则文件, 按规则组及评估周期分组,
+// 无法表示为 Prometheus 规则的规则记录原因后跳过
+func (rs ruleService) ExportPrometheus(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleExportPrometheus)
+
+	db := rs.ctx.DB.DB().Where("tenant_id = ?", r.TenantId)
+	if r.RuleGroupId != "" {
+		db = db.Where("rule_group_id = ?", r.RuleGroupId)
+	}
+
+	var rules []models.AlertRule
+	if err := db.Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("查询告警规则失败: %s", err.Error())
+	}
+
+	var ruleGroups []models.RuleGroups
+	if err := rs.ctx.DB.DB().Where("tenant_id = ?", r.TenantId).Find(&ruleGroups).Error; err != nil {
+		return nil, fmt.Errorf("查询规则组失败: %s", err.Error())
+	}
+	groupNames := make(map[string]string, len(ruleGroups))
+	for _, g := range ruleGroups {
+		groupNames[g.ID] = g.Name
+	}
+
+	result := types.ResponseRuleExportPrometheus{Skipped: []types.RuleImportIssue{}}
+
+	type groupKey struct {
+		ruleGroupId  string
+		evalInterval int64
+	}
+	var (
+		keys      []groupKey
+		grouped   = make(map[groupKey][]types.Rule)
+		intervals = make(map[string]map[int64]struct{})
+	)
+	for _, rule := range rules {
+		groupName := groupNames[rule.RuleGroupId]
+		alerts, err := translateToPrometheusRule(rule)
+		if err != nil {
+			result.Skipped = append(result.Skipped, types.RuleImportIssue{Group: groupName, Name: rule.RuleName, Reason: err.Error()})
+			continue
+		}
+
+		key := groupKey{ruleGroupId: rule.RuleGroupId, evalInterval: rule.EvalInterval}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], alerts...)
+		if intervals[rule.RuleGroupId] == nil {
+			intervals[rule.RuleGroupId] = make(map[int64]struct{})
+		}
+		intervals[rule.RuleGroupId][rule.EvalInterval] = struct{}{}
+		result.Exported++
+	}
+
+	var file types.PrometheusAlerts
+	for _, key := range keys {
+		name := groupNames[key.ruleGroupId]
+		if name == "" {
+			name = key.ruleGroupId
+		}
+		// Prometheus 规则组只有一个评估周期, 同一规则组内评估周期不同的规则拆分为多个规则组
+		if len(intervals[key.ruleGroupId]) > 1 {
+			name = fmt.Sprintf("%s-%ds", name, key.evalInterval)
+		}
+
+		group := types.PrometheusRuleGroup{
+			Name:  name,
+			Rules: grouped[key],
+		}
+		if key.evalInterval > 0 {
+			group.Interval = fmt.Sprintf("%ds", key.evalInterval)
+		}
+		file.Groups = append(file.Groups, group)
+	}
+
+	if len(file.Groups) > 0 {
+		out, err := yaml.Marshal(file)
+		if err != nil {
+			return nil, fmt.Errorf("生成 Prometheus 规则文件失败: %s", err.Error())
+		}
+		result.Rules = string(out)
+	}
+
+	return result, nil
+}
+
+// translateToPrometheusRule 将告警规则的每个阈值条件转换为一条 Prometheus 告警规则,
+// 阈值拼接到 PromQL 之后, 告警等级写入 severity 标签, 注解模版写入 description 注解
+func translateToPrometheusRule(rule models.AlertRule) ([]types.Rule, error) {
+	if rule.DatasourceType != provider.PrometheusDsProvider {
+		return nil, fmt.Errorf("数据源类型 %s 不支持导出", rule.DatasourceType)
+	}
+	if rule.Enabled == nil || !*rule.Enabled {
+		return nil, fmt.Errorf("规则未启用")
+	}
+	if rule.PrometheusConfig.PromQL == "" || len(rule.PrometheusConfig.Rules) == 0 {
+		return nil, fmt.Errorf("规则缺少 PromQL 或阈值条件")
+	}
+
+	var alerts []types.Rule
+	for _, threshold := range rule.PrometheusConfig.Rules {
+		expr := rule.PrometheusConfig.PromQL
+		if threshold.Expr != prometheusImportMatchAny {
+			operator, value, err := process.ProcessRuleExpr(threshold.Expr)
+			if err != nil {
+				return nil, err
+			}
+			if operator == "=" {
+				operator = "=="
+			}
+			expr = fmt.Sprintf("(%s) %s %s", expr, operator, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+
+		labels := make(map[string]string, len(rule.ExternalLabels)+1)
+		for k, v := range rule.ExternalLabels {
+			labels[k] = v
+		}
+		if severity, ok := prometheusExportSeverity[threshold.Severity]; ok {
+			labels["severity"] = severity
+		}
+
+		alert := types.Rule{
+			Alert:  rule.RuleName,
+			Expr:   expr,
+			Labels: labels,
+		}
+		if forDuration := rule.GetForDuration(threshold.Severity); forDuration > 0 {
+			alert.For = model.Duration(time.Duration(forDuration) * time.Second).String()
+		}
+		if rule.PrometheusConfig.Annotations != "" {
+			alert.Annotations = map[string]string{"description": rule.PrometheusConfig.Annotations}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
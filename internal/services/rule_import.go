@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// prometheusImportMatchAny Prometheus 告警规则中 expr 返回的每个序列都会触发告警, 且值可能为负数,
+	// 导入时使用 "!= NaN" 作为阈值, 任意值均满足条件
+	prometheusImportMatchAny = "!= NaN"
+
+	// prometheusImportEvalInterval 规则组未配置 interval 时的评估周期(秒)
+	prometheusImportEvalInterval = 15
+)
+
+// translatePrometheusRules 将 Prometheus 告警规则转换为规则创建请求, 无法转换的规则记录原因后跳过
+func translatePrometheusRules(r *types.RequestRuleImport, alerts types.PrometheusAlerts, result *types.ResponseRuleImport) []types.RequestRuleCreate {
+	groups := alerts.Groups
+	if len(alerts.Rules) > 0 {
+		groups = append(groups, types.PrometheusRuleGroup{Rules: alerts.Rules})
+	}
+
+	var rules []types.RequestRuleCreate
+	for _, group := range groups {
+		evalInterval := int64(prometheusImportEvalInterval)
+		if group.Interval != "" {
+			d, err := model.ParseDuration(group.Interval)
+			if err != nil {
+				result.Warnings = append(result.Warnings, types.RuleImportIssue{
+					Group:  group.Name,
+					Reason: fmt.Sprintf("无法解析规则组 interval: %s, 使用默认评估周期 %ds", group.Interval, prometheusImportEvalInterval),
+				})
+			} else {
+				evalInterval = max(int64(time.Duration(d).Seconds()), 1)
+			}
+		}
+
+		for _, alert := range group.Rules {
+			name := alert.Alert
+			if name == "" {
+				name = alert.Record
+			}
+
+			rule, warnings, err := translatePrometheusRule(r, group, evalInterval, alert)
+			if err != nil {
+				result.Failed = append(result.Failed, types.RuleImportIssue{Group: group.Name, Name: name, Reason: err.Error()})
+				continue
+			}
+			for _, warning := range warnings {
+				result.Warnings = append(result.Warnings, types.RuleImportIssue{Group: group.Name, Name: name, Reason: warning})
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// translatePrometheusRule 转换单条告警规则, expr 作为 PromQL, for 作为持续时间, labels 作为额外标签,
+// severity 标签映射为告警等级, annotations 合并为注解模版
+func translatePrometheusRule(r *types.RequestRuleImport, group types.PrometheusRuleGroup, evalInterval int64, alert types.Rule) (types.RequestRuleCreate, []string, error) {
+	if alert.Record != "" {
+		return types.RequestRuleCreate{}, nil, fmt.Errorf("记录规则(recording rule)不支持导入")
+	}
+	if alert.Alert == "" {
+		return types.RequestRuleCreate{}, nil, fmt.Errorf("缺少 alert 名称")
+	}
+	if _, err := parser.ParseExpr(alert.Expr); err != nil {
+		return types.RequestRuleCreate{}, nil, fmt.Errorf("PromQL 语法错误: %s", err.Error())
+	}
+
+	var forDuration int64
+	if alert.For != "" {
+		d, err := model.ParseDuration(alert.For)
+		if err != nil {
+			return types.RequestRuleCreate{}, nil, fmt.Errorf("无法解析 for: %s", alert.For)
+		}
+		forDuration = int64(time.Duration(d).Seconds())
+	}
+
+	var warnings []string
+	severity := "P1"
+	if v, ok := alert.Labels["severity"]; ok {
+		if s, ok := alertmanagerSeverity[strings.ToLower(v)]; ok {
+			severity = s
+		} else if slices.Contains([]string{"P0", "P1", "P2"}, v) {
+			severity = v
+		} else {
+			warnings = append(warnings, fmt.Sprintf("未识别的 severity 标签: %s, 使用 P1", v))
+		}
+	}
+
+	annotations := buildPrometheusAnnotations(alert.Annotations)
+	if _, err := template.New("annotations").Parse(`{{ $labels := .Labels }}{{ $value := .Value }}` + annotations); err != nil {
+		warnings = append(warnings, fmt.Sprintf("注解模版包含不支持的语法, 将按原文展示: %s", err.Error()))
+	}
+
+	description := "从 Prometheus 告警规则导入"
+	if group.Name != "" {
+		description = fmt.Sprintf("从 Prometheus 规则组 %s 导入", group.Name)
+	}
+
+	return types.RequestRuleCreate{
+		TenantId:         r.TenantId,
+		RuleGroupId:      r.RuleGroupId,
+		ExternalLabels:   alert.Labels,
+		DatasourceType:   r.DatasourceType,
+		DatasourceIdList: r.DatasourceIdList,
+		RuleName:         alert.Alert,
+		EvalInterval:     evalInterval,
+		Description:      description,
+		PrometheusConfig: models.PrometheusConfig{
+			PromQL:      alert.Expr,
+			Annotations: annotations,
+			Rules: []models.Rules{
+				{
+					ForDuration: forDuration,
+					Severity:    severity,
+					Expr:        prometheusImportMatchAny,
+				},
+			},
+		},
+		FaultCenterId: r.FaultCenterId,
+		Enabled:       alert.GetEnable(),
+	}, warnings, nil
+}
+
+// buildPrometheusAnnotations 合并注解, summary 与 description 在前, 其余注解按名称排序
+func buildPrometheusAnnotations(annotations map[string]string) string {
+	var lines []string
+	for _, key := range []string{"summary", "description"} {
+		if v := annotations[key]; v != "" {
+			lines = append(lines, v)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		if key == "summary" || key == "description" || annotations[key] == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", key, annotations[key]))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,9 +1,16 @@
 package services
 
 import (
+	"fmt"
+	"time"
+	"watchAlert/alert"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
+	"watchAlert/pkg/client"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
 )
 
 type ruleTmplService struct {
@@ -15,6 +22,7 @@ type InterRuleTmplService interface {
 	Create(req interface{}) (interface{}, interface{})
 	Update(req interface{}) (interface{}, interface{})
 	Delete(req interface{}) (interface{}, interface{})
+	Instantiate(req interface{}) (interface{}, interface{})
 }
 
 func newInterRuleTmplService(ctx *ctx.Context) InterRuleTmplService {
@@ -42,7 +50,7 @@ func (rt ruleTmplService) List(req interface{}) (interface{}, interface{}) {
 
 func (rt ruleTmplService) Create(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestRuleTemplateCreate)
-	err := rt.ctx.DB.RuleTmpl().Create(models.RuleTemplate{
+	data := models.RuleTemplate{
 		Type:                 r.Type,
 		RuleGroupName:        r.RuleGroupName,
 		RuleName:             r.RuleName,
@@ -59,7 +67,13 @@ func (rt ruleTmplService) Create(req interface{}) (interface{}, interface{}) {
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		VictoriaLogsConfig:   r.VictoriaLogsConfig,
 		ClickHouseConfig:     r.ClickHouseConfig,
-	})
+		Variables:            r.Variables,
+	}
+	if err := data.ValidateVariables(); err != nil {
+		return nil, err
+	}
+
+	err := rt.ctx.DB.RuleTmpl().Create(data)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +83,7 @@ func (rt ruleTmplService) Create(req interface{}) (interface{}, interface{}) {
 
 func (rt ruleTmplService) Update(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestRuleTemplateUpdate)
-	err := rt.ctx.DB.RuleTmpl().Update(models.RuleTemplate{
+	data := models.RuleTemplate{
 		Type:                 r.Type,
 		RuleGroupName:        r.RuleGroupName,
 		RuleName:             r.RuleName,
@@ -86,12 +100,22 @@ func (rt ruleTmplService) Update(req interface{}) (interface{}, interface{}) {
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		VictoriaLogsConfig:   r.VictoriaLogsConfig,
 		ClickHouseConfig:     r.ClickHouseConfig,
-	})
+		Variables:            r.Variables,
+	}
+	if err := data.ValidateVariables(); err != nil {
+		return nil, err
+	}
+
+	err := rt.ctx.DB.RuleTmpl().Update(data)
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, nil
+	if !r.Propagate {
+		return nil, nil
+	}
+
+	return rt.propagate(data), nil
 }
 
 func (rt ruleTmplService) Delete(req interface{}) (interface{}, interface{}) {
@@ -103,3 +127,109 @@ func (rt ruleTmplService) Delete(req interface{}) (interface{}, interface{}) {
 
 	return nil, nil
 }
+
+// Instantiate 绑定变量渲染模版并创建告警规则, 规则记录模版组、模版名称及变量以便后续同步
+func (rt ruleTmplService) Instantiate(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleTemplateInstantiate)
+	tmpl, err := rt.ctx.DB.RuleTmpl().Get(r.TemplateGroup, r.TemplateName)
+	if err != nil {
+		return nil, fmt.Errorf("获取规则模版失败: %s", err.Error())
+	}
+
+	rendered, err := tmpl.Render(r.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleName := r.RuleName
+	if ruleName == "" {
+		ruleName = rendered.RuleName
+	}
+
+	return RuleService.Create(&types.RequestRuleCreate{
+		TenantId:             r.TenantId,
+		RuleGroupId:          r.RuleGroupId,
+		ExternalLabels:       r.ExternalLabels,
+		DatasourceType:       rendered.DatasourceType,
+		DatasourceIdList:     r.DatasourceIdList,
+		RuleName:             ruleName,
+		EvalInterval:         rendered.EvalInterval,
+		RepeatNoticeInterval: rendered.RepeatNoticeInterval,
+		Description:          rendered.Description,
+		Severity:             r.Severity,
+		ForDuration:          rendered.ForDuration,
+		PrometheusConfig:     rendered.PrometheusConfig,
+		AliCloudSLSConfig:    rendered.AliCloudSLSConfig,
+		LokiConfig:           rendered.LokiConfig,
+		VictoriaLogsConfig:   rendered.VictoriaLogsConfig,
+		ClickHouseConfig:     rendered.ClickHouseConfig,
+		JaegerConfig:         rendered.JaegerConfig,
+		KubernetesConfig:     rendered.KubernetesConfig,
+		ElasticSearchConfig:  rendered.ElasticSearchConfig,
+		FaultCenterId:        r.FaultCenterId,
+		TemplateGroup:        tmpl.RuleGroupName,
+		TemplateName:         tmpl.RuleName,
+		TemplateVariables:    r.Variables,
+		UpdateBy:             r.UpdateBy,
+		Enabled:              r.Enabled,
+	})
+}
+
+// propagate 使用实例绑定的变量重新渲染模版并更新规则, 单条规则失败时记录并继续
+func (rt ruleTmplService) propagate(tmpl models.RuleTemplate) types.ResponseRuleTemplatePropagate {
+	var res types.ResponseRuleTemplatePropagate
+	rules, err := rt.ctx.DB.Rule().ListByTemplate(tmpl.RuleGroupName, tmpl.RuleName)
+	if err != nil {
+		logc.Errorf(rt.ctx.Ctx, "获取模版实例规则失败, 模版: %s, 错误: %v", tmpl.RuleName, err)
+		return res
+	}
+
+	for _, rule := range rules {
+		if err := rt.applyTemplate(tmpl, rule); err != nil {
+			logc.Errorf(tools.WithLogFields(rt.ctx.Ctx, tools.LogFieldTenantId, rule.TenantId, tools.LogFieldRuleId, rule.RuleId), "同步模版到规则失败, 模版: %s, 错误: %v", tmpl.RuleName, err)
+			res.Failed = append(res.Failed, rule.RuleId)
+			continue
+		}
+		res.Updated++
+	}
+
+	return res
+}
+
+func (rt ruleTmplService) applyTemplate(tmpl models.RuleTemplate, rule models.AlertRule) error {
+	rendered, err := tmpl.Render(rule.TemplateVariables)
+	if err != nil {
+		return err
+	}
+	rendered.ApplyTo(&rule)
+	rule.UpdateAt = time.Now().Unix()
+
+	if err := validateThresholdRules(rule); err != nil {
+		return err
+	}
+	if err := validateLogRule(rule); err != nil {
+		return err
+	}
+
+	if err := rt.ctx.DB.Rule().Update(rule); err != nil {
+		return err
+	}
+
+	if !*rule.GetEnabled() {
+		return nil
+	}
+
+	if alert.IsRuleOwner(rule.RuleId) {
+		alert.AlertRule.Stop(rule.RuleId)
+		alert.AlertRule.Submit(rule)
+	} else {
+		tools.PublishReloadMessage(rt.ctx.Ctx, client.Redis, tools.ChannelRuleReload, tools.ReloadMessage{
+			Action:   tools.ActionUpdate,
+			ID:       rule.RuleId,
+			TenantID: rule.TenantId,
+			Name:     rule.RuleName,
+		})
+	}
+
+	return nil
+}
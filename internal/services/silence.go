@@ -44,7 +44,11 @@ func (ass alertSilenceService) Create(req interface{}) (interface{}, interface{}
 	}
 
 	if r.StartsAt > updateAt {
-		r.Status = 0
+		silence.Status = 0
+	}
+
+	if err := silence.Validate(); err != nil {
+		return nil, err
 	}
 
 	ass.ctx.Redis.Silence().PushAlertMute(silence)
@@ -72,10 +76,12 @@ func (ass alertSilenceService) Update(req interface{}) (interface{}, interface{}
 		Status:        1,
 	}
 
-	if r.StartsAt > r.UpdateAt {
-		r.Status = 0
-	} else {
-		r.Status = 1
+	if r.StartsAt > silence.UpdateAt {
+		silence.Status = 0
+	}
+
+	if err := silence.Validate(); err != nil {
+		return nil, err
 	}
 
 	ass.ctx.Redis.Silence().PushAlertMute(silence)
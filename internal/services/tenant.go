@@ -23,6 +23,8 @@ type InterTenantService interface {
 	DelUsersOfTenant(req interface{}) (data interface{}, err interface{})
 	GetUsersForTenant(req interface{}) (data interface{}, err interface{})
 	ChangeTenantUserRole(req interface{}) (data interface{}, err interface{})
+	ExportConfig(req interface{}) (data interface{}, err interface{})
+	ImportConfig(req interface{}) (data interface{}, err interface{})
 }
 
 func newInterTenantService(ctx *ctx.Context) InterTenantService {
@@ -33,18 +35,27 @@ func newInterTenantService(ctx *ctx.Context) InterTenantService {
 
 func (ts tenantService) Create(req interface{}) (data interface{}, err interface{}) {
 	r := req.(*types.RequestTenantCreate)
+	if err := models.ValidateTimezone(r.Timezone); err != nil {
+		return nil, err
+	}
+
 	tenant := models.Tenant{
-		ID:               "tid-" + tools.RandId(),
-		Name:             r.Name,
-		UserId:           r.UserId,
-		UpdateAt:         time.Now().Unix(),
-		Manager:          r.Manager,
-		Description:      r.Description,
-		RuleNumber:       r.RuleNumber,
-		UserNumber:       r.UserNumber,
-		DutyNumber:       r.DutyNumber,
-		NoticeNumber:     r.NoticeNumber,
-		RemoveProtection: r.GetRemoveProtection(),
+		ID:                    "tid-" + tools.RandId(),
+		Name:                  r.Name,
+		UserId:                r.UserId,
+		UpdateAt:              time.Now().Unix(),
+		Manager:               r.Manager,
+		Description:           r.Description,
+		RuleNumber:            r.RuleNumber,
+		UserNumber:            r.UserNumber,
+		DutyNumber:            r.DutyNumber,
+		NoticeNumber:          r.NoticeNumber,
+		RemoveProtection:      r.GetRemoveProtection(),
+		AuditLogRetention:     r.AuditLogRetention,
+		EventNumber:           r.EventNumber,
+		HistoryEventRetention: r.HistoryEventRetention,
+		HistoryEventMaxCount:  r.HistoryEventMaxCount,
+		Timezone:              r.Timezone,
 	}
 
 	err = ts.ctx.DB.Tenant().Create(tenant)
@@ -56,18 +67,27 @@ func (ts tenantService) Create(req interface{}) (data interface{}, err interface
 
 func (ts tenantService) Update(req interface{}) (data interface{}, err interface{}) {
 	r := req.(*types.RequestTenantUpdate)
+	if err := models.ValidateTimezone(r.Timezone); err != nil {
+		return nil, err
+	}
+
 	tenant := models.Tenant{
-		ID:               r.ID,
-		Name:             r.Name,
-		UserId:           r.UserId,
-		UpdateAt:         time.Now().Unix(),
-		Manager:          r.Manager,
-		Description:      r.Description,
-		RuleNumber:       r.RuleNumber,
-		UserNumber:       r.UserNumber,
-		DutyNumber:       r.DutyNumber,
-		NoticeNumber:     r.NoticeNumber,
-		RemoveProtection: r.GetRemoveProtection(),
+		ID:                    r.ID,
+		Name:                  r.Name,
+		UserId:                r.UserId,
+		UpdateAt:              time.Now().Unix(),
+		Manager:               r.Manager,
+		Description:           r.Description,
+		RuleNumber:            r.RuleNumber,
+		UserNumber:            r.UserNumber,
+		DutyNumber:            r.DutyNumber,
+		NoticeNumber:          r.NoticeNumber,
+		RemoveProtection:      r.GetRemoveProtection(),
+		AuditLogRetention:     r.AuditLogRetention,
+		EventNumber:           r.EventNumber,
+		HistoryEventRetention: r.HistoryEventRetention,
+		HistoryEventMaxCount:  r.HistoryEventMaxCount,
+		Timezone:              r.Timezone,
 	}
 
 	err = ts.ctx.DB.Tenant().Update(tenant)
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+)
+
+// 租户配置快照格式版本, 格式不兼容时递增
+const tenantConfigBundleVersion = 1
+
+// ExportConfig 导出租户的规则组、告警规则、故障中心、通知对象及静默规则
+func (ts tenantService) ExportConfig(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestTenantQuery)
+	if _, err := ts.ctx.DB.Tenant().Get(r.ID); err != nil {
+		return nil, fmt.Errorf("租户不存在, id: %s", r.ID)
+	}
+
+	bundle := types.TenantConfigBundle{
+		Version:  tenantConfigBundleVersion,
+		TenantId: r.ID,
+		ExportAt: time.Now().Unix(),
+	}
+
+	db := ts.ctx.DB.DB()
+	for _, list := range []interface{}{&bundle.RuleGroups, &bundle.Rules, &bundle.FaultCenters, &bundle.Notices, &bundle.Silences} {
+		if err := db.Where("tenant_id = ?", r.ID).Find(list).Error; err != nil {
+			return nil, fmt.Errorf("导出租户配置失败: %s", err.Error())
+		}
+	}
+
+	return bundle, nil
+}
+
+// tenantConfigImporter 将快照中的资源重建到目标租户, 记录源 ID 到目标 ID 的映射以重建资源间的关联
+type tenantConfigImporter struct {
+	ts       tenantService
+	tenantId string
+	dryRun   bool
+	updateBy string
+	res      types.ResponseTenantConfigImport
+
+	ruleGroups   map[string]string
+	notices      map[string]string
+	faultCenters map[string]string
+	// 快照中不存在的通知对象 ID, 导入时移除, 避免关联到其他租户的通知对象
+	unmappedNotices []string
+}
+
+// ImportConfig 导入租户配置快照, 同名资源已存在时复用并记录为冲突, 导入的规则默认为关闭状态
+func (ts tenantService) ImportConfig(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestTenantConfigImport)
+	if _, err := ts.ctx.DB.Tenant().Get(r.ID); err != nil {
+		return nil, fmt.Errorf("目标租户不存在, id: %s", r.ID)
+	}
+	if r.Bundle.Version != tenantConfigBundleVersion {
+		return nil, fmt.Errorf("不支持的配置快照版本: %d", r.Bundle.Version)
+	}
+
+	im := &tenantConfigImporter{
+		ts:           ts,
+		tenantId:     r.ID,
+		dryRun:       r.DryRun,
+		updateBy:     r.UpdateBy,
+		res:          types.ResponseTenantConfigImport{DryRun: r.DryRun},
+		ruleGroups:   make(map[string]string),
+		notices:      make(map[string]string),
+		faultCenters: make(map[string]string),
+	}
+
+	// 按依赖顺序导入, 被引用的资源先创建
+	steps := []func(types.TenantConfigBundle) error{
+		im.importRuleGroups,
+		im.importNotices,
+		im.importFaultCenters,
+		im.importSilences,
+		im.importRules,
+	}
+	for _, step := range steps {
+		if err := step(r.Bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	return im.res, nil
+}
+
+func (im *tenantConfigImporter) importRuleGroups(bundle types.TenantConfigBundle) error {
+	var existing []models.RuleGroups
+	if err := im.ts.ctx.DB.DB().Where("tenant_id = ?", im.tenantId).Find(&existing).Error; err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(existing))
+	for _, rg := range existing {
+		byName[rg.Name] = rg.ID
+	}
+
+	for _, rg := range bundle.RuleGroups {
+		if id, ok := byName[rg.Name]; ok {
+			im.ruleGroups[rg.ID] = id
+			im.record("ruleGroup", rg.Name, rg.ID, id, types.TenantConfigImportConflict, "同名规则组已存在, 复用已有规则组")
+			continue
+		}
+
+		source := rg.ID
+		rg.TenantId, rg.ID = im.tenantId, "rg-"+tools.RandId()
+		im.ruleGroups[source] = rg.ID
+		im.create("ruleGroup", rg.Name, source, rg.ID, func() error {
+			return im.ts.ctx.DB.RuleGroup().Create(rg)
+		})
+	}
+
+	return nil
+}
+
+func (im *tenantConfigImporter) importNotices(bundle types.TenantConfigBundle) error {
+	var existing []models.AlertNotice
+	if err := im.ts.ctx.DB.DB().Where("tenant_id = ?", im.tenantId).Find(&existing).Error; err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(existing))
+	for _, n := range existing {
+		byName[n.Name] = n.Uuid
+	}
+
+	for _, n := range bundle.Notices {
+		if id, ok := byName[n.Name]; ok {
+			im.notices[n.Uuid] = id
+			im.record("notice", n.Name, n.Uuid, id, types.TenantConfigImportConflict, "同名通知对象已存在, 复用已有通知对象")
+			continue
+		}
+
+		source := n.Uuid
+		n.TenantId, n.Uuid = im.tenantId, "n-"+tools.RandId()
+		n.UpdateAt, n.UpdateBy = time.Now().Unix(), im.updateBy
+		im.notices[source] = n.Uuid
+		im.create("notice", n.Name, source, n.Uuid, func() error {
+			if err := im.ts.ctx.DB.Notice().GetQuota(im.tenantId); err != nil {
+				return err
+			}
+			return im.ts.ctx.DB.Notice().Create(n)
+		})
+	}
+
+	return nil
+}
+
+func (im *tenantConfigImporter) importFaultCenters(bundle types.TenantConfigBundle) error {
+	existing, err := im.ts.ctx.DB.FaultCenter().List(im.tenantId, "")
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(existing))
+	for _, fc := range existing {
+		byName[fc.Name] = fc.ID
+	}
+
+	for _, fc := range bundle.FaultCenters {
+		if id, ok := byName[fc.Name]; ok {
+			im.faultCenters[fc.ID] = id
+			im.record("faultCenter", fc.Name, fc.ID, id, types.TenantConfigImportConflict, "同名故障中心已存在, 复用已有故障中心")
+			continue
+		}
+
+		source := fc.ID
+		fc.TenantId, fc.ID, fc.CreateAt = im.tenantId, "fc-"+tools.RandId(), time.Now().Unix()
+		im.unmappedNotices = nil
+		fc.NoticeIds = im.mapNoticeIds(fc.NoticeIds)
+		im.mapNoticeRoutes(fc.NoticeRoutes)
+		for i := range fc.EscalationPolicy.Steps {
+			fc.EscalationPolicy.Steps[i].NoticeId = im.mapNoticeId(fc.EscalationPolicy.Steps[i].NoticeId)
+		}
+		fc.UpgradeStrategy.NoticeId = im.mapNoticeId(fc.UpgradeStrategy.NoticeId)
+
+		im.faultCenters[source] = fc.ID
+		im.create("faultCenter", fc.Name, source, fc.ID, func() error {
+			if err := im.ts.ctx.DB.FaultCenter().Create(fc); err != nil {
+				return err
+			}
+			startFaultCenter(im.ts.ctx, fc)
+			return nil
+		})
+		if last := &im.res.Items[len(im.res.Items)-1]; len(im.unmappedNotices) > 0 && last.Action == types.TenantConfigImportCreate {
+			last.Reason = fmt.Sprintf("已移除快照中不存在的通知对象 %v, 请重新配置", im.unmappedNotices)
+		}
+	}
+
+	return nil
+}
+
+func (im *tenantConfigImporter) importSilences(bundle types.TenantConfigBundle) error {
+	var existing []models.AlertSilences
+	if err := im.ts.ctx.DB.DB().Where("tenant_id = ?", im.tenantId).Find(&existing).Error; err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(existing))
+	for _, s := range existing {
+		byName[s.FaultCenterId+"/"+s.Name] = s.ID
+	}
+
+	now := time.Now().Unix()
+	for _, s := range bundle.Silences {
+		// 已失效的静默规则不再导入
+		if s.EndsAt <= now {
+			continue
+		}
+
+		faultCenterId, ok := im.faultCenters[s.FaultCenterId]
+		if !ok {
+			im.record("silence", s.Name, s.ID, "", types.TenantConfigImportFailed, fmt.Sprintf("关联的故障中心不在快照中, id: %s", s.FaultCenterId))
+			continue
+		}
+		if id, ok := byName[faultCenterId+"/"+s.Name]; ok {
+			im.record("silence", s.Name, s.ID, id, types.TenantConfigImportConflict, "故障中心下已存在同名静默规则")
+			continue
+		}
+
+		source := s.ID
+		s.TenantId, s.ID, s.FaultCenterId = im.tenantId, "s-"+tools.RandId(), faultCenterId
+		s.UpdateAt, s.UpdateBy = now, im.updateBy
+		s.Status = 1
+		if s.StartsAt > now {
+			s.Status = 0
+		}
+		im.create("silence", s.Name, source, s.ID, func() error {
+			if err := s.Validate(); err != nil {
+				return err
+			}
+			im.ts.ctx.Redis.Silence().PushAlertMute(s)
+			return im.ts.ctx.DB.Silence().Create(s)
+		})
+	}
+
+	return nil
+}
+
+func (im *tenantConfigImporter) importRules(bundle types.TenantConfigBundle) error {
+	var existing []models.AlertRule
+	if err := im.ts.ctx.DB.DB().Where("tenant_id = ?", im.tenantId).Find(&existing).Error; err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(existing))
+	for _, rule := range existing {
+		byName[rule.RuleGroupId+"/"+rule.RuleName] = rule.RuleId
+	}
+
+	disable := false
+	for _, rule := range bundle.Rules {
+		ruleGroupId, ok := im.ruleGroups[rule.RuleGroupId]
+		if !ok {
+			im.record("rule", rule.RuleName, rule.RuleId, "", types.TenantConfigImportFailed, fmt.Sprintf("关联的规则组不在快照中, id: %s", rule.RuleGroupId))
+			continue
+		}
+		faultCenterId, ok := im.faultCenters[rule.FaultCenterId]
+		if !ok {
+			im.record("rule", rule.RuleName, rule.RuleId, "", types.TenantConfigImportFailed, fmt.Sprintf("关联的故障中心不在快照中, id: %s", rule.FaultCenterId))
+			continue
+		}
+		if id, ok := byName[ruleGroupId+"/"+rule.RuleName]; ok {
+			im.record("rule", rule.RuleName, rule.RuleId, id, types.TenantConfigImportConflict, "规则组下已存在同名告警规则")
+			continue
+		}
+
+		source := rule.RuleId
+		rule.TenantId, rule.RuleId = im.tenantId, "a-"+tools.RandId()
+		rule.RuleGroupId, rule.FaultCenterId = ruleGroupId, faultCenterId
+		rule.UpdateAt, rule.UpdateBy = time.Now().Unix(), im.updateBy
+		rule.Enabled, rule.PausedUntil = &disable, 0
+
+		// 数据源不随快照迁移, 目标租户中不存在的数据源从规则中移除并提示, 规则仍以关闭状态导入
+		var datasourceIds, missing []string
+		for _, dsId := range rule.DatasourceIdList {
+			ds, err := im.ts.ctx.DB.Datasource().Get(dsId)
+			if err != nil || ds.TenantId != im.tenantId {
+				missing = append(missing, dsId)
+				continue
+			}
+			datasourceIds = append(datasourceIds, dsId)
+		}
+		rule.DatasourceIdList = datasourceIds
+
+		im.create("rule", rule.RuleName, source, rule.RuleId, func() error {
+			if err := im.ts.ctx.DB.Rule().GetQuota(im.tenantId); err != nil {
+				return err
+			}
+			return im.ts.ctx.DB.Rule().Create(rule)
+		})
+		if last := &im.res.Items[len(im.res.Items)-1]; len(missing) > 0 && last.Action == types.TenantConfigImportCreate {
+			last.Reason = fmt.Sprintf("已移除目标租户中不存在的数据源 %v, 启用前请重新选择", missing)
+		}
+	}
+
+	return nil
+}
+
+// create 创建资源并记录结果, DryRun 时仅记录
+func (im *tenantConfigImporter) create(kind, name, sourceId, targetId string, fn func() error) {
+	if !im.dryRun {
+		if err := fn(); err != nil {
+			im.record(kind, name, sourceId, targetId, types.TenantConfigImportFailed, err.Error())
+			return
+		}
+	}
+	im.record(kind, name, sourceId, targetId, types.TenantConfigImportCreate, "")
+}
+
+func (im *tenantConfigImporter) record(kind, name, sourceId, targetId, action, reason string) {
+	switch action {
+	case types.TenantConfigImportCreate:
+		im.res.Created++
+	case types.TenantConfigImportConflict:
+		im.res.Conflicts++
+	case types.TenantConfigImportFailed:
+		im.res.Failed++
+	}
+
+	im.res.Items = append(im.res.Items, types.TenantConfigImportItem{
+		Kind:     kind,
+		Name:     name,
+		SourceId: sourceId,
+		TargetId: targetId,
+		Action:   action,
+		Reason:   reason,
+	})
+}
+
+// mapNoticeId 映射通知对象 ID, 快照外的通知对象返回空并记录, 不保留原 ID
+func (im *tenantConfigImporter) mapNoticeId(id string) string {
+	if id == "" {
+		return ""
+	}
+	if target, ok := im.notices[id]; ok {
+		return target
+	}
+	im.unmappedNotices = append(im.unmappedNotices, id)
+	return ""
+}
+
+func (im *tenantConfigImporter) mapNoticeIds(ids []string) []string {
+	mapped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if target := im.mapNoticeId(id); target != "" {
+			mapped = append(mapped, target)
+		}
+	}
+	return mapped
+}
+
+// mapNoticeRoutes 映射通知路由及其子路由中的通知对象 ID
+func (im *tenantConfigImporter) mapNoticeRoutes(routes []models.NoticeRoute) {
+	for i := range routes {
+		routes[i].NoticeIds = im.mapNoticeIds(routes[i].NoticeIds)
+		im.mapNoticeRoutes(routes[i].Routes)
+	}
+}
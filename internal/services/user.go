@@ -136,17 +136,18 @@ func (us userService) Register(req interface{}) (interface{}, interface{}) {
 	}
 
 	err := us.ctx.DB.User().Create(models.Member{
-		UserId:     r.UserId,
-		UserName:   r.UserName,
-		Email:      r.Email,
-		Phone:      r.Phone,
-		Password:   tools.GenerateHashPassword(r.Password),
-		Role:       r.Role,
-		CreateBy:   r.CreateBy,
-		CreateAt:   time.Now().Unix(),
-		JoinDuty:   r.JoinDuty,
-		DutyUserId: r.DutyUserId,
-		Tenants:    r.Tenants,
+		UserId:        r.UserId,
+		UserName:      r.UserName,
+		Email:         r.Email,
+		Phone:         r.Phone,
+		Password:      tools.GenerateHashPassword(r.Password),
+		Role:          r.Role,
+		CreateBy:      r.CreateBy,
+		CreateAt:      time.Now().Unix(),
+		JoinDuty:      r.JoinDuty,
+		DutyUserId:    r.DutyUserId,
+		Tenants:       r.Tenants,
+		NoticeChannel: r.NoticeChannel,
 	})
 	if err != nil {
 		return nil, err
@@ -168,17 +169,18 @@ func (us userService) Update(req interface{}) (interface{}, interface{}) {
 		r.Password = tools.GenerateHashPassword(r.Password)
 	}
 	err := us.ctx.DB.User().Update(models.Member{
-		UserId:     r.UserId,
-		UserName:   r.UserName,
-		Email:      r.Email,
-		Phone:      r.Phone,
-		Password:   r.Password,
-		Role:       r.Role,
-		CreateBy:   r.CreateBy,
-		CreateAt:   r.CreateAt,
-		JoinDuty:   r.JoinDuty,
-		DutyUserId: r.DutyUserId,
-		Tenants:    r.Tenants,
+		UserId:        r.UserId,
+		UserName:      r.UserName,
+		Email:         r.Email,
+		Phone:         r.Phone,
+		Password:      r.Password,
+		Role:          r.Role,
+		CreateBy:      r.CreateBy,
+		CreateAt:      r.CreateAt,
+		JoinDuty:      r.JoinDuty,
+		DutyUserId:    r.DutyUserId,
+		Tenants:       r.Tenants,
+		NoticeChannel: r.NoticeChannel,
 	})
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"fmt"
 	"time"
 	"watchAlert/internal/ctx"
 	models "watchAlert/internal/models"
@@ -17,6 +18,9 @@ type InterUserRoleService interface {
 	Create(req interface{}) (interface{}, interface{})
 	Update(req interface{}) (interface{}, interface{})
 	Delete(req interface{}) (interface{}, interface{})
+	ListTenantPermissions(req interface{}) (interface{}, interface{})
+	UpdateTenantPermission(req interface{}) (interface{}, interface{})
+	DeleteTenantPermission(req interface{}) (interface{}, interface{})
 }
 
 func newInterUserRoleService(ctx *ctx.Context) InterUserRoleService {
@@ -77,3 +81,52 @@ func (ur userRoleService) Delete(req interface{}) (interface{}, interface{}) {
 
 	return nil, nil
 }
+
+func (ur userRoleService) ListTenantPermissions(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestTenantRolePermission)
+	data, err := ur.ctx.DB.UserRole().ListTenantPermissions(r.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.ResponseTenantRolePermissions{
+		Catalog: models.FineGrainedPermissions(),
+		Items:   data,
+	}, nil
+}
+
+func (ur userRoleService) UpdateTenantPermission(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestTenantRolePermission)
+	if r.RoleId == "" {
+		return nil, fmt.Errorf("角色ID不能为空")
+	}
+
+	catalog := models.FineGrainedPermissions()
+	for _, p := range r.Permissions {
+		if _, ok := catalog[p]; !ok {
+			return nil, fmt.Errorf("未知的权限: %s", p)
+		}
+	}
+
+	err := ur.ctx.DB.UserRole().SaveTenantPermission(models.TenantRolePermission{
+		TenantId:    r.TenantId,
+		RoleId:      r.RoleId,
+		Permissions: r.Permissions,
+		UpdateAt:    time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (ur userRoleService) DeleteTenantPermission(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestTenantRolePermission)
+	err := ur.ctx.DB.UserRole().DeleteTenantPermission(r.TenantId, r.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -1,30 +1,40 @@
 package types
 
 type RequestApiKeyCreate struct {
-	UserId      string `json:"userId" form:"userId"`
-	Name        string `json:"name" form:"name" binding:"required"`
-	Description string `json:"description" form:"description"`
+	UserId      string   `json:"-" form:"-"`
+	Name        string   `json:"name" form:"name" binding:"required"`
+	Description string   `json:"description" form:"description"`
+	TenantId    string   `json:"tenantId" form:"tenantId"`
+	Permissions []string `json:"permissions" form:"permissions"`
+	ExpiresAt   int64    `json:"expiresAt" form:"expiresAt"`
 }
 
 type RequestApiKeyUpdate struct {
-	ID          int    `json:"id" form:"id" binding:"required"`
-	UserId      string `json:"userId" form:"userId"`
-	Name        string `json:"name" form:"name"`
-	Description string `json:"description" form:"description"`
+	ID          int      `json:"id" form:"id" binding:"required"`
+	UserId      string   `json:"-" form:"-"`
+	Name        string   `json:"name" form:"name"`
+	Description string   `json:"description" form:"description"`
+	TenantId    string   `json:"tenantId" form:"tenantId"`
+	Permissions []string `json:"permissions" form:"permissions"`
+	ExpiresAt   int64    `json:"expiresAt" form:"expiresAt"`
 }
 
 type RequestApiKeyQuery struct {
 	ID     int    `json:"id" form:"id"`
-	UserId string `json:"userId" form:"userId"`
+	UserId string `json:"-" form:"-"`
 	Name   string `json:"name" form:"name"`
 	Query  string `json:"query" form:"query"`
 }
 
 type ResponseApiKeyInfo struct {
-	ID          int    `json:"id"`
-	UserId      string `json:"userId"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Key         string `json:"key"`
-	CreatedAt   int64  `json:"createdAt"`
+	ID          int      `json:"id"`
+	UserId      string   `json:"userId"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Key         string   `json:"key"`
+	TenantId    string   `json:"tenantId"`
+	Permissions []string `json:"permissions"`
+	ExpiresAt   int64    `json:"expiresAt"`
+	RevokedAt   int64    `json:"revokedAt"`
+	CreatedAt   int64    `json:"createdAt"`
 }
@@ -9,6 +9,20 @@ type RequestAuditLogQuery struct {
 	models.Page
 }
 
+// RequestAuditLogFilter 按条件检索审计日志
+type RequestAuditLogFilter struct {
+	TenantId   string `json:"tenantId" form:"tenantId"`
+	Username   string `json:"username" form:"username"`
+	PathPrefix string `json:"pathPrefix" form:"pathPrefix"` // 请求路径前缀
+	Method     string `json:"method" form:"method"`
+	StatusMin  int    `json:"statusMin" form:"statusMin"` // 状态码下限, 包含
+	StatusMax  int    `json:"statusMax" form:"statusMax"` // 状态码上限, 包含
+	AuditType  string `json:"auditType" form:"auditType"`
+	StartAt    int64  `json:"startAt" form:"startAt"` // 创建时间范围, 秒级时间戳
+	EndAt      int64  `json:"endAt" form:"endAt"`
+	models.Page
+}
+
 type ResponseAuditLog struct {
 	List []models.AuditLog `json:"list"`
 	models.Page
@@ -6,12 +6,57 @@ type ResponseDashboardInfo struct {
 	UserNumber        int64             `json:"userNumber"`
 	CurAlertList      []AlertList       `json:"curAlertList"`
 	AlarmDistribution AlarmDistribution `json:"alarmDistribution"`
+	// 租户下各故障中心的告警分布, key 为故障中心 ID
+	FaultCenterDistribution map[string]AlarmDistribution `json:"faultCenterDistribution"`
+	// 指定时间范围时返回, 范围内触发的告警总数
+	AlertCount int64 `json:"alertCount,omitempty"`
+	// 指定时间范围时返回, 按时间分桶的告警触发趋势
+	AlertTrend []AlertTrendPoint `json:"alertTrend,omitempty"`
+	// 租户资源配额使用情况
+	QuotaUsage QuotaUsage `json:"quotaUsage"`
+}
+
+// QuotaUsage 租户资源的已用数量及配额, Quota 为 0 表示不限制
+type QuotaUsage struct {
+	Rules   QuotaItem `json:"rules"`
+	Notices QuotaItem `json:"notices"`
+	Duties  QuotaItem `json:"duties"`
+	Events  QuotaItem `json:"events"`
+}
+
+type QuotaItem struct {
+	Used  int64 `json:"used"`
+	Quota int64 `json:"quota"`
+}
+
+// AlertTrendPoint 趋势图中的一个时间桶, Time 为桶的起始时间
+type AlertTrendPoint struct {
+	Time  int64 `json:"time"`
+	Count int64 `json:"count"`
 }
 
 type AlarmDistribution struct {
 	P0 int64 `json:"P0"`
 	P1 int64 `json:"P1"`
 	P2 int64 `json:"P2"`
+	P3 int64 `json:"P3"`
+	P4 int64 `json:"P4"`
+}
+
+// Add 按告警等级累加计数, 未知等级忽略
+func (a *AlarmDistribution) Add(severity string) {
+	switch severity {
+	case "P0":
+		a.P0++
+	case "P1":
+		a.P1++
+	case "P2":
+		a.P2++
+	case "P3":
+		a.P3++
+	case "P4":
+		a.P4++
+	}
 }
 
 type AlertList struct {
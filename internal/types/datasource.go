@@ -22,8 +22,13 @@ type RequestDatasourceCreate struct {
 	DsAliCloudConfig models.DsAliCloudConfig   `json:"dsAliCloudConfig" `
 	AWSCloudWatch    models.AWSCloudWatch      `json:"awsCloudwatch" `
 	ClickHouseConfig models.DsClickHouseConfig `json:"clickhouseConfig"`
+	InfluxDBConfig   models.DsInfluxDBConfig   `json:"influxdbConfig"`
+	DatadogConfig    models.DsDatadogConfig    `json:"datadogConfig"`
 	Description      string                    `json:"description"`
 	KubeConfig       string                    `json:"kubeConfig"`
+	QueryTimeout     int64                     `json:"queryTimeout"`
+	QueryCacheTTL    int64                     `json:"queryCacheTTL"`
+	LinkTemplate     string                    `json:"linkTemplate"`
 	UpdateBy         string                    `json:"updateBy"`
 	Enabled          *bool                     `json:"enabled" `
 }
@@ -40,8 +45,13 @@ type RequestDatasourceUpdate struct {
 	DsAliCloudConfig models.DsAliCloudConfig   `json:"dsAliCloudConfig" `
 	AWSCloudWatch    models.AWSCloudWatch      `json:"awsCloudwatch" `
 	ClickHouseConfig models.DsClickHouseConfig `json:"clickhouseConfig"`
+	InfluxDBConfig   models.DsInfluxDBConfig   `json:"influxdbConfig"`
+	DatadogConfig    models.DsDatadogConfig    `json:"datadogConfig"`
 	Description      string                    `json:"description"`
 	KubeConfig       string                    `json:"kubeConfig"`
+	QueryTimeout     int64                     `json:"queryTimeout"`
+	QueryCacheTTL    int64                     `json:"queryCacheTTL"`
+	LinkTemplate     string                    `json:"linkTemplate"`
 	UpdateBy         string                    `json:"updateBy"`
 	Enabled          *bool                     `json:"enabled" `
 }
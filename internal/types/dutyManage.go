@@ -3,24 +3,28 @@ package types
 import "watchAlert/internal/models"
 
 type RequestDutyManagementCreate struct {
-	TenantId    string            `json:"tenantId"`
-	Name        string            `json:"name"`
-	Manager     models.DutyUser   `json:"manager" gorm:"manager;serializer:json"`
-	Description string            `json:"description"`
-	CurDutyUser []models.DutyUser `json:"curDutyUser" gorm:"curDutyUser;serializer:json"`
-	UpdateBy    string            `json:"updateBy"`
-	UpdateAt    int64             `json:"updateAt"`
+	TenantId     string                `json:"tenantId"`
+	Name         string                `json:"name"`
+	Manager      models.DutyUser       `json:"manager" gorm:"manager;serializer:json"`
+	Description  string                `json:"description"`
+	CurDutyUser  []models.DutyUser     `json:"curDutyUser" gorm:"curDutyUser;serializer:json"`
+	NoticeTmplId string                `json:"noticeTmplId"`
+	Overrides    []models.DutyOverride `json:"overrides"`
+	UpdateBy     string                `json:"updateBy"`
+	UpdateAt     int64                 `json:"updateAt"`
 }
 
 type RequestDutyManagementUpdate struct {
-	TenantId    string            `json:"tenantId"`
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Manager     models.DutyUser   `json:"manager" gorm:"manager;serializer:json"`
-	Description string            `json:"description"`
-	CurDutyUser []models.DutyUser `json:"curDutyUser" gorm:"curDutyUser;serializer:json"`
-	UpdateBy    string            `json:"updateBy"`
-	UpdateAt    int64             `json:"updateAt"`
+	TenantId     string                `json:"tenantId"`
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	Manager      models.DutyUser       `json:"manager" gorm:"manager;serializer:json"`
+	Description  string                `json:"description"`
+	CurDutyUser  []models.DutyUser     `json:"curDutyUser" gorm:"curDutyUser;serializer:json"`
+	NoticeTmplId string                `json:"noticeTmplId"`
+	Overrides    []models.DutyOverride `json:"overrides"`
+	UpdateBy     string                `json:"updateBy"`
+	UpdateAt     int64                 `json:"updateAt"`
 }
 
 type RequestDutyManagementQuery struct {
@@ -1,30 +1,94 @@
 package types
 
-import "watchAlert/internal/models"
+import (
+	"time"
+	"watchAlert/internal/models"
+)
 
 // RequestProcessAlertEvent 请求处理告警事件
 type RequestProcessAlertEvent struct {
 	TenantId      string   `json:"tenantId"`
 	FaultCenterId string   `json:"faultCenterId"`
 	Fingerprints  []string `json:"fingerprints"`
+	AckTimeout    int64    `json:"ackTimeout"` // 认领有效期（分钟）, 为 0 时不过期
 	Time          int64    `json:"time"`
 	Username      string   `json:"username"`
 }
 
+const (
+	BulkActionAck      = "ack"      // 认领
+	BulkActionClose    = "close"    // 关闭, 从活跃告警中移除
+	BulkActionSuppress = "suppress" // 抑制, 不再发送通知
+)
+
+// RequestBulkProcessAlertEvent 请求批量处理告警事件
+type RequestBulkProcessAlertEvent struct {
+	TenantId      string   `json:"tenantId"`
+	FaultCenterId string   `json:"faultCenterId"`
+	Fingerprints  []string `json:"fingerprints"`
+	Action        string   `json:"action"`
+	AckTimeout    int64    `json:"ackTimeout"` // 认领有效期（分钟）, 仅 ack 操作生效, 为 0 时不过期
+	Time          int64    `json:"time"`
+	Username      string   `json:"username"`
+}
+
+// BulkProcessResult 单个事件的批量处理结果
+type BulkProcessResult struct {
+	Fingerprint string `json:"fingerprint"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RequestBulkDeleteAlertEvent 请求按条件批量删除活跃告警事件, 条件需同时满足且至少指定一个
+type RequestBulkDeleteAlertEvent struct {
+	TenantId      string   `json:"tenantId"`
+	FaultCenterId string   `json:"faultCenterId"`
+	Status        string   `json:"status"`    // 事件状态, 如 recovered
+	OlderThan     int64    `json:"olderThan"` // 最近一次评估（已恢复事件为恢复时间）早于多少分钟
+	Matchers      []string `json:"matchers"`  // 标签匹配条件, 如 severity=P0、team=~infra.*
+	Time          int64    `json:"time"`
+}
+
+// ResponseBulkDeleteAlertEvent 批量删除结果
+type ResponseBulkDeleteAlertEvent struct {
+	Deleted int `json:"deleted"`
+}
+
+// RequestSnoozeAlertEvent 请求暂停单个事件的通知, Until 为空时按 Duration 计算截止时间
+type RequestSnoozeAlertEvent struct {
+	TenantId      string `json:"tenantId"`
+	FaultCenterId string `json:"faultCenterId"`
+	Fingerprint   string `json:"fingerprint"`
+	Duration      int64  `json:"duration"` // 暂停时长（分钟）
+	Until         int64  `json:"until"`    // 暂停截止时间
+	Time          int64  `json:"time"`
+}
+
+// RequestUpdateEventAnnotations 请求修改活跃告警事件的补充注解
+type RequestUpdateEventAnnotations struct {
+	TenantId      string            `json:"tenantId"`
+	FaultCenterId string            `json:"faultCenterId"`
+	Fingerprint   string            `json:"fingerprint"`
+	Annotations   map[string]string `json:"annotations"` // 新增或修改的注解
+	Remove        []string          `json:"remove"`      // 需要删除的注解
+}
+
 // RequestAlertCurEventQuery 请求活跃告警事件
 type RequestAlertCurEventQuery struct {
-	TenantId       string `json:"tenantId" form:"tenantId"`
-	RuleId         string `json:"ruleId" form:"ruleId"`
-	RuleName       string `json:"ruleName" form:"ruleName"`
-	DatasourceType string `json:"datasourceType" form:"datasourceType"`
-	DatasourceId   string `json:"datasourceId" form:"datasourceId"`
-	Fingerprint    string `json:"fingerprint" form:"fingerprint"`
-	Query          string `json:"query" form:"query"`
-	Scope          int64  `json:"scope" form:"scope"`
-	Severity       string `json:"severity" form:"severity"`
-	FaultCenterId  string `json:"faultCenterId" form:"faultCenterId"`
-	Status         string `json:"status" form:"status"`
-	SortOrder      string `json:"sortOrder" form:"sortOrder"`
+	TenantId       string   `json:"tenantId" form:"tenantId"`
+	RuleId         string   `json:"ruleId" form:"ruleId"`
+	RuleName       string   `json:"ruleName" form:"ruleName"`
+	DatasourceType string   `json:"datasourceType" form:"datasourceType"`
+	DatasourceId   string   `json:"datasourceId" form:"datasourceId"`
+	Fingerprint    string   `json:"fingerprint" form:"fingerprint"`
+	Query          string   `json:"query" form:"query"`
+	Scope          int64    `json:"scope" form:"scope"`
+	Severity       string   `json:"severity" form:"severity"`
+	FaultCenterId  string   `json:"faultCenterId" form:"faultCenterId"`
+	Status         string   `json:"status" form:"status"`
+	Matchers       []string `json:"matchers" form:"matchers"` // 标签匹配条件, 如 severity=P0、team=~infra.*, 需全部满足
+	SortBy         string   `json:"sortBy" form:"sortBy"`     // 排序字段: severity、lastSeen、status, 为空时按持续时间排序
+	SortOrder      string   `json:"sortOrder" form:"sortOrder"`
 	models.Page
 }
 
@@ -34,21 +98,37 @@ type ResponseAlertCurEventList struct {
 	models.Page
 }
 
+// RequestAlertEventStream 请求订阅故障中心的活跃告警事件流
+type RequestAlertEventStream struct {
+	TenantId      string `json:"tenantId" form:"tenantId"`
+	FaultCenterId string `json:"faultCenterId" form:"faultCenterId"`
+}
+
+// 事件流消息类型, 先推送当前全部活跃告警, 之后推送增量变更
+const (
+	EventStreamSnapshot = "snapshot"
+	EventStreamChange   = "change"
+)
+
 // RequestAlertHisEventQuery 请求查询历史事件
 type RequestAlertHisEventQuery struct {
-	TenantId       string `json:"tenantId" form:"tenantId"`
-	DatasourceId   string `json:"datasourceId" form:"datasourceId"`
-	DatasourceType string `json:"datasourceType" form:"datasourceType"`
-	Fingerprint    string `json:"fingerprint" form:"fingerprint"`
-	Severity       string `json:"severity" form:"severity"`
-	RuleId         string `json:"ruleId" form:"ruleId"`
-	RuleName       string `json:"ruleName" form:"ruleName"`
-	StartAt        int64  `json:"startAt" form:"startAt"`
-	EndAt          int64  `json:"endAt" form:"endAt"`
-	Query          string `json:"query" form:"query"`
-	FaultCenterId  string `json:"faultCenterId" form:"faultCenterId"`
-	SortOrder      string `json:"sortOrder" form:"sortOrder"`
+	TenantId       string   `json:"tenantId" form:"tenantId"`
+	DatasourceId   string   `json:"datasourceId" form:"datasourceId"`
+	DatasourceType string   `json:"datasourceType" form:"datasourceType"`
+	Fingerprint    string   `json:"fingerprint" form:"fingerprint"`
+	Severity       string   `json:"severity" form:"severity"`
+	RuleId         string   `json:"ruleId" form:"ruleId"`
+	RuleName       string   `json:"ruleName" form:"ruleName"`
+	StartAt        int64    `json:"startAt" form:"startAt"`
+	EndAt          int64    `json:"endAt" form:"endAt"`
+	Query          string   `json:"query" form:"query"`
+	Search         string   `json:"search" form:"search"`     // 关键字搜索, 匹配规则名称、告警详情及标签
+	Matchers       []string `json:"matchers" form:"matchers"` // 标签匹配条件, 如 severity=P0、team=~infra.*, 需全部满足
+	FaultCenterId  string   `json:"faultCenterId" form:"faultCenterId"`
+	SortOrder      string   `json:"sortOrder" form:"sortOrder"`
 	models.Page
+	// 解析后的标签匹配条件, 由服务端填充
+	LabelMatchers []models.SilenceLabel `json:"-" form:"-"`
 }
 
 // ResponseHistoryEventList 返回历史事件列表
@@ -71,6 +151,14 @@ type RequestAddEventComment struct {
 	UserId string `json:"userId"`
 	// 评论内容
 	Content string `json:"content"`
+	// 解析出的 @ 提及用户, 由服务端填充
+	Mentions []models.CommentMention `json:"-"`
+}
+
+// ResponseAddEventComment 添加评论结果
+type ResponseAddEventComment struct {
+	Message  string                  `json:"message"`
+	Mentions []models.CommentMention `json:"mentions"`
 }
 
 // RequestDeleteEventComment 删除评论
@@ -81,6 +169,18 @@ type RequestDeleteEventComment struct {
 	CommentId string `json:"commentId"`
 }
 
+// RequestEditEventComment 编辑评论
+type RequestEditEventComment struct {
+	// 租户
+	TenantId string `json:"tenantId"`
+	// 评论 ID
+	CommentId string `json:"commentId"`
+	// 用户 ID
+	UserId string `json:"userId"`
+	// 评论内容
+	Content string `json:"content"`
+}
+
 // RequestListEventComments 获取评论
 type RequestListEventComments struct {
 	// 租户
@@ -88,3 +188,63 @@ type RequestListEventComments struct {
 	// 告警指纹
 	Fingerprint string `json:"fingerprint" form:"fingerprint"`
 }
+
+// RequestAlertEventExport 导出告警事件, 查询条件与活跃/历史事件列表一致
+type RequestAlertEventExport struct {
+	Type   string `json:"type" form:"type"`     // current 活跃事件, history 历史事件
+	Format string `json:"format" form:"format"` // csv 或 jsonl, 默认 csv
+}
+
+const (
+	EventExportTypeCurrent = "current"
+	EventExportTypeHistory = "history"
+
+	EventExportFormatCSV   = "csv"
+	EventExportFormatJSONL = "jsonl"
+)
+
+// AlertEventExportRow 导出的单条告警事件
+type AlertEventExportRow struct {
+	EventId       string                 `json:"eventId"`
+	Fingerprint   string                 `json:"fingerprint"`
+	RuleName      string                 `json:"ruleName"`
+	Status        string                 `json:"status"`
+	Severity      string                 `json:"severity"`
+	FaultCenterId string                 `json:"faultCenterId"`
+	Labels        map[string]interface{} `json:"labels"`
+	FirstSeen     int64                  `json:"firstSeen"`
+	LastSeen      int64                  `json:"lastSeen"`
+}
+
+// RequestAlertmanagerWebhook Alertmanager Webhook 格式的外部告警
+type RequestAlertmanagerWebhook struct {
+	TenantId          string              `json:"-"`
+	FaultCenterId     string              `json:"-"`
+	UserId            string              `json:"-"`
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert Alertmanager Webhook 中的单条告警
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// ResponseIngestAlerts 外部告警接收结果
+type ResponseIngestAlerts struct {
+	Firing   int `json:"firing"`
+	Resolved int `json:"resolved"`
+}
@@ -0,0 +1,43 @@
+package types
+
+// RequestHeartbeatCreate 请求创建心跳
+type RequestHeartbeatCreate struct {
+	TenantId      string            `json:"tenantId"`
+	Name          string            `json:"name"`
+	FaultCenterId string            `json:"faultCenterId"`
+	Interval      int64             `json:"interval"`
+	Severity      string            `json:"severity"`
+	Labels        map[string]string `json:"labels"`
+	Description   string            `json:"description"`
+	Enabled       *bool             `json:"enabled"`
+	UpdateBy      string            `json:"updateBy"`
+}
+
+// RequestHeartbeatUpdate 请求更新心跳
+type RequestHeartbeatUpdate struct {
+	TenantId      string            `json:"tenantId"`
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	FaultCenterId string            `json:"faultCenterId"`
+	Interval      int64             `json:"interval"`
+	Severity      string            `json:"severity"`
+	Labels        map[string]string `json:"labels"`
+	Description   string            `json:"description"`
+	Enabled       *bool             `json:"enabled"`
+	UpdateBy      string            `json:"updateBy"`
+}
+
+// RequestHeartbeatQuery 请求查询心跳
+type RequestHeartbeatQuery struct {
+	TenantId      string `json:"tenantId" form:"tenantId"`
+	ID            string `json:"id" form:"id"`
+	FaultCenterId string `json:"faultCenterId" form:"faultCenterId"`
+	Query         string `json:"query" form:"query"`
+}
+
+// RequestHeartbeatPing 外部系统上报心跳, 租户及心跳名称由路径指定
+type RequestHeartbeatPing struct {
+	TenantId string `json:"tenantId"`
+	Name     string `json:"name"`
+	UserId   string `json:"userId"`
+}
@@ -42,5 +42,23 @@ type RequestNoticeTest struct {
 	NoticeType string       `json:"noticeType"`
 	Hook       string       `json:"hook"`
 	Sign       string       `json:"sign"`
+	ChatId     string       `json:"chatId"`
+	RoutingKey string       `json:"routingKey"`
 	Email      models.Email `json:"email"`
 }
+
+type RequestNoticeDeadLetterQuery struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+}
+
+type RequestNoticeDeadLetterRequeue struct {
+	TenantId string   `json:"tenantId"`
+	Ids      []string `json:"ids"`
+}
+
+// DeadLetterRequeueResult 单条死信通知的重新投递结果
+type DeadLetterRequeueResult struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "watchAlert/internal/models"
+
 type RequestNoticeTemplateCreate struct {
 	Name                 string `json:"name"`
 	NoticeType           string `json:"noticeType"`
@@ -29,3 +31,20 @@ type RequestNoticeTemplateQuery struct {
 	NoticeType string `json:"noticeType" form:"noticeType"`
 	Query      string `json:"query" form:"query"`
 }
+
+// RequestNoticeTemplateTest 使用示例事件渲染模版, 未传入事件时使用内置的示例事件
+type RequestNoticeTemplateTest struct {
+	NoticeType           string                `json:"noticeType"`
+	Template             string                `json:"template"`
+	TemplateFiring       string                `json:"templateFiring"`
+	TemplateRecover      string                `json:"templateRecover"`
+	EnableFeiShuJsonCard *bool                 `json:"enableFeiShuJsonCard"`
+	Subject              string                `json:"subject"`
+	IsRecovered          bool                  `json:"isRecovered"`
+	Event                *models.AlertCurEvent `json:"event"`
+}
+
+type ResponseNoticeTemplateTest struct {
+	Subject string `json:"subject"`
+	Content string `json:"content"`
+}
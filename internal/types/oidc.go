@@ -17,6 +17,7 @@ type OidcInfo struct {
 type OauthToken struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	IdToken      string `json:"id_token"`
 }
 
 type RespOpenIDConfiguration struct {
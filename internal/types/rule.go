@@ -14,7 +14,18 @@ type RequestRuleCreate struct {
 	Description          string                     `json:"description"`
 	EffectiveTime        models.EffectiveTime       `json:"effectiveTime"`
 	Severity             string                     `json:"severity"`
+	SeverityExpr         string                     `json:"severityExpr"`
+	ForDuration          int64                      `json:"forDuration"`
+	NoDataAlert          models.NoDataAlert         `json:"noDataAlert"`
+	RecoverWaitTime      int64                      `json:"recoverWaitTime"`
+	OverrunPolicy        string                     `json:"overrunPolicy"`
+	DatasourceStrategy   string                     `json:"datasourceStrategy"`
+	DatasourceQuorum     int64                      `json:"datasourceQuorum"`
+	MaxFingerprints      int64                      `json:"maxFingerprints"`
+	LinkTemplate         string                     `json:"linkTemplate"`
+	Enrichment           models.EventEnrichment     `json:"enrichment"`
 	PrometheusConfig     models.PrometheusConfig    `json:"prometheusConfig"`
+	InfluxDBConfig       models.InfluxDBConfig      `json:"influxdbConfig"`
 	AliCloudSLSConfig    models.AliCloudSLSConfig   `json:"alicloudSLSConfig"`
 	LokiConfig           models.LokiConfig          `json:"lokiConfig"`
 	VictoriaLogsConfig   models.VictoriaLogsConfig  `json:"victoriaLogsConfig"`
@@ -24,9 +35,16 @@ type RequestRuleCreate struct {
 	KubernetesConfig     models.KubernetesConfig    `json:"kubernetesConfig"`
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	LogEvalCondition     string                     `json:"logEvalCondition"`
+	LogGroupBy           []string                   `json:"logGroupBy"`
+	FingerprintLabels    []string                   `json:"fingerprintLabels"`
 	FaultCenterId        string                     `json:"faultCenterId"`
+	TemplateGroup        string                     `json:"templateGroup"`
+	TemplateName         string                     `json:"templateName"`
+	TemplateVariables    map[string]string          `json:"templateVariables"`
 	UpdateBy             string                     `json:"updateBy"`
 	Enabled              *bool                      `json:"enabled"`
+	// 跳过查询语句校验, 用于数据源暂时不可用时保存规则
+	SkipQueryValidation bool `json:"skipQueryValidation"`
 }
 
 func (requestRuleCreate *RequestRuleCreate) GetEnabled() *bool {
@@ -50,7 +68,18 @@ type RequestRuleUpdate struct {
 	Description          string                     `json:"description"`
 	EffectiveTime        models.EffectiveTime       `json:"effectiveTime"`
 	Severity             string                     `json:"severity"`
+	SeverityExpr         string                     `json:"severityExpr"`
+	ForDuration          int64                      `json:"forDuration"`
+	NoDataAlert          models.NoDataAlert         `json:"noDataAlert"`
+	RecoverWaitTime      int64                      `json:"recoverWaitTime"`
+	OverrunPolicy        string                     `json:"overrunPolicy"`
+	DatasourceStrategy   string                     `json:"datasourceStrategy"`
+	DatasourceQuorum     int64                      `json:"datasourceQuorum"`
+	MaxFingerprints      int64                      `json:"maxFingerprints"`
+	LinkTemplate         string                     `json:"linkTemplate"`
+	Enrichment           models.EventEnrichment     `json:"enrichment"`
 	PrometheusConfig     models.PrometheusConfig    `json:"prometheusConfig"`
+	InfluxDBConfig       models.InfluxDBConfig      `json:"influxdbConfig"`
 	AliCloudSLSConfig    models.AliCloudSLSConfig   `json:"alicloudSLSConfig"`
 	LokiConfig           models.LokiConfig          `json:"lokiConfig"`
 	VictoriaLogsConfig   models.VictoriaLogsConfig  `json:"victoriaLogsConfig"`
@@ -60,9 +89,13 @@ type RequestRuleUpdate struct {
 	KubernetesConfig     models.KubernetesConfig    `json:"kubernetesConfig"`
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	LogEvalCondition     string                     `json:"logEvalCondition"`
+	LogGroupBy           []string                   `json:"logGroupBy"`
+	FingerprintLabels    []string                   `json:"fingerprintLabels"`
 	FaultCenterId        string                     `json:"faultCenterId"`
 	UpdateBy             string                     `json:"updateBy"`
 	Enabled              *bool                      `json:"enabled"`
+	// 跳过查询语句校验, 用于数据源暂时不可用时保存规则
+	SkipQueryValidation bool `json:"skipQueryValidation"`
 }
 
 func (requestRuleUpdate *RequestRuleUpdate) GetEnabled() *bool {
@@ -99,6 +132,36 @@ type RequestRuleChangeStatus struct {
 	Enabled       *bool  `json:"enabled" form:"enabled"`
 }
 
+// RequestRuleEvalHistory 查询规则评估记录, Limit 为 0 时返回全部保留的记录
+type RequestRuleEvalHistory struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+	RuleId   string `json:"ruleId" form:"ruleId"`
+	Limit    int64  `json:"limit" form:"limit"`
+}
+
+// RequestRuleShard 查询负责评估规则的实例
+type RequestRuleShard struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+	RuleId   string `json:"ruleId" form:"ruleId"`
+}
+
+// ResponseRuleShard 规则的分片归属, 未启用分片时 Shard 为 -1, Owner 为 Leader 实例
+type ResponseRuleShard struct {
+	Sharding bool     `json:"sharding"`
+	Shard    int      `json:"shard"`
+	Owner    string   `json:"owner"`
+	Workers  []string `json:"workers"`
+	Instance string   `json:"instance"` // 处理本次请求的实例
+}
+
+// RequestRulePause 暂停规则评估, PausedUntil 为 0 时立即恢复
+type RequestRulePause struct {
+	TenantId    string `json:"tenantId" form:"tenantId"`
+	RuleId      string `json:"ruleId" form:"ruleId"`
+	RuleGroupId string `json:"ruleGroupId" form:"ruleGroupId"`
+	PausedUntil int64  `json:"pausedUntil" form:"pausedUntil"`
+}
+
 func (r *RequestRuleChangeStatus) GetEnabled() *bool {
 	if r.Enabled == nil {
 		isOk := false
@@ -122,20 +185,25 @@ type RequestRuleImport struct {
 	Rules            string   `json:"rules"`
 }
 
+// PrometheusAlerts Prometheus 告警规则文件, 支持 groups 格式及仅包含 rules 的简写格式
 type PrometheusAlerts struct {
-	Rules []Rule `yaml:"rules"`
+	Groups []PrometheusRuleGroup `yaml:"groups,omitempty"`
+	Rules  []Rule                `yaml:"rules,omitempty"`
 }
 
-type Rule struct {
-	Alert       string            `yaml:"alert"`
-	Expr        string            `yaml:"expr"`
-	For         string            `yaml:"for"`
-	Labels      map[string]string `yaml:"labels"`
-	Annotations Annotations       `yaml:"annotations"`
+type PrometheusRuleGroup struct {
+	Name     string `yaml:"name"`
+	Interval string `yaml:"interval,omitempty"`
+	Rules    []Rule `yaml:"rules"`
 }
 
-type Annotations struct {
-	Description string `yaml:"description"`
+type Rule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
 
 func (r Rule) GetEnable() *bool {
@@ -143,6 +211,33 @@ func (r Rule) GetEnable() *bool {
 	return &enable
 }
 
+// ResponseRuleImport 规则导入结果
+type ResponseRuleImport struct {
+	Imported int               `json:"imported"`
+	Failed   []RuleImportIssue `json:"failed"`
+	Warnings []RuleImportIssue `json:"warnings"`
+}
+
+// RuleImportIssue 未能导入或导入后需要人工确认的规则
+type RuleImportIssue struct {
+	Group  string `json:"group"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// RequestRuleExportPrometheus 导出 Prometheus 告警规则, RuleGroupId 为空时导出租户下全部规则
+type RequestRuleExportPrometheus struct {
+	TenantId    string `json:"tenantId" form:"tenantId"`
+	RuleGroupId string `json:"ruleGroupId" form:"ruleGroupId"`
+}
+
+// ResponseRuleExportPrometheus 规则导出结果, Rules 为 Prometheus 规则文件内容
+type ResponseRuleExportPrometheus struct {
+	Rules    string            `json:"rules"`
+	Exported int               `json:"exported"`
+	Skipped  []RuleImportIssue `json:"skipped"`
+}
+
 // RequestRuleChange 请求修改规则的任意字段
 type RequestRuleChange struct {
 	TenantId string                 `json:"tenantId"`
@@ -19,6 +19,7 @@ type RequestRuleTemplateCreate struct {
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	VictoriaLogsConfig   models.VictoriaLogsConfig  `json:"victoriaLogsConfig"`
 	ClickHouseConfig     models.ClickHouseConfig    `json:"clickhouseConfig"`
+	Variables            []string                   `json:"variables"`
 }
 
 type RequestRuleTemplateUpdate struct {
@@ -38,6 +39,9 @@ type RequestRuleTemplateUpdate struct {
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	VictoriaLogsConfig   models.VictoriaLogsConfig  `json:"victoriaLogsConfig"`
 	ClickHouseConfig     models.ClickHouseConfig    `json:"clickhouseConfig"`
+	Variables            []string                   `json:"variables"`
+	// 是否将更新同步到由该模版实例化的规则
+	Propagate bool `json:"propagate"`
 }
 
 type RequestRuleTemplateQuery struct {
@@ -55,3 +59,24 @@ type ResponseRuleTemplateList struct {
 	List []models.RuleTemplate `json:"list"`
 	models.Page
 }
+
+// RequestRuleTemplateInstantiate 绑定模版变量生成告警规则
+type RequestRuleTemplateInstantiate struct {
+	TenantId         string            `json:"tenantId"`
+	TemplateGroup    string            `json:"templateGroup"`
+	TemplateName     string            `json:"templateName"`
+	Variables        map[string]string `json:"variables"`
+	RuleGroupId      string            `json:"ruleGroupId"`
+	RuleName         string            `json:"ruleName"` // 为空时使用渲染后的模版名称
+	DatasourceIdList []string          `json:"datasourceId"`
+	Severity         string            `json:"severity"`
+	ExternalLabels   map[string]string `json:"externalLabels"`
+	FaultCenterId    string            `json:"faultCenterId"`
+	UpdateBy         string            `json:"updateBy"`
+	Enabled          *bool             `json:"enabled"`
+}
+
+type ResponseRuleTemplatePropagate struct {
+	Updated int      `json:"updated"`
+	Failed  []string `json:"failed"`
+}
@@ -12,8 +12,17 @@ type RequestTenantCreate struct {
 	DutyNumber       int64  `json:"dutyNumber"`
 	NoticeNumber     int64  `json:"noticeNumber"`
 	RemoveProtection *bool  `json:"removeProtection" gorm:"type:BOOL"`
-	UserId           string `json:"userId" gorm:"-"`
-	UpdateAt         int64  `json:"updateAt"`
+	// 审计日志保留天数, 为 0 时使用全局配置
+	AuditLogRetention int64 `json:"auditLogRetention"`
+	// 活跃告警事件配额, 为 0 时不限制
+	EventNumber int64 `json:"eventNumber"`
+	// 历史事件保留天数及最大保留数量, 为 0 时使用全局配置
+	HistoryEventRetention int64 `json:"historyEventRetention"`
+	HistoryEventMaxCount  int64 `json:"historyEventMaxCount"`
+	// IANA 时区名称, 为空时使用服务所在时区
+	Timezone string `json:"timezone"`
+	UserId   string `json:"userId" gorm:"-"`
+	UpdateAt int64  `json:"updateAt"`
 }
 
 func (requestTenantCreate *RequestTenantCreate) GetRemoveProtection() *bool {
@@ -35,8 +44,17 @@ type RequestTenantUpdate struct {
 	DutyNumber       int64  `json:"dutyNumber"`
 	NoticeNumber     int64  `json:"noticeNumber"`
 	RemoveProtection *bool  `json:"removeProtection" gorm:"type:BOOL"`
-	UserId           string `json:"userId" gorm:"-"`
-	UpdateAt         int64  `json:"updateAt"`
+	// 审计日志保留天数, 为 0 时使用全局配置
+	AuditLogRetention int64 `json:"auditLogRetention"`
+	// 活跃告警事件配额, 为 0 时不限制
+	EventNumber int64 `json:"eventNumber"`
+	// 历史事件保留天数及最大保留数量, 为 0 时使用全局配置
+	HistoryEventRetention int64 `json:"historyEventRetention"`
+	HistoryEventMaxCount  int64 `json:"historyEventMaxCount"`
+	// IANA 时区名称, 为空时使用服务所在时区
+	Timezone string `json:"timezone"`
+	UserId   string `json:"userId" gorm:"-"`
+	UpdateAt int64  `json:"updateAt"`
 }
 
 func (requestTenantUpdate *RequestTenantUpdate) GetRemoveProtection() *bool {
@@ -67,3 +85,47 @@ type RequestTenantAddUsers struct {
 	UserRole string              `json:"userRole" gorm:"-"` // 用于新增成员时统一的用户角色
 	Users    []models.TenantUser `json:"users" gorm:"users;serializer:json"`
 }
+
+// TenantConfigBundle 租户配置快照, 用于跨环境迁移及灾备恢复
+type TenantConfigBundle struct {
+	Version      int                    `json:"version"`
+	TenantId     string                 `json:"tenantId"`
+	ExportAt     int64                  `json:"exportAt"`
+	RuleGroups   []models.RuleGroups    `json:"ruleGroups"`
+	Rules        []models.AlertRule     `json:"rules"`
+	FaultCenters []models.FaultCenter   `json:"faultCenters"`
+	Notices      []models.AlertNotice   `json:"notices"`
+	Silences     []models.AlertSilences `json:"silences"`
+}
+
+// RequestTenantConfigImport 导入租户配置, DryRun 时仅返回导入计划, 不写入数据
+type RequestTenantConfigImport struct {
+	ID       string             `json:"id"`
+	DryRun   bool               `json:"dryRun"`
+	Bundle   TenantConfigBundle `json:"bundle"`
+	UpdateBy string             `json:"updateBy"`
+}
+
+const (
+	TenantConfigImportCreate   = "create"
+	TenantConfigImportConflict = "conflict"
+	TenantConfigImportFailed   = "failed"
+)
+
+// TenantConfigImportItem 单个资源的导入结果, 同名资源已存在时复用目标租户的资源
+type TenantConfigImportItem struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	SourceId string `json:"sourceId"`
+	TargetId string `json:"targetId"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ResponseTenantConfigImport struct {
+	DryRun    bool                     `json:"dryRun"`
+	Created   int                      `json:"created"`
+	Conflicts int                      `json:"conflicts"`
+	Failed    int                      `json:"failed"`
+	Items     []TenantConfigImportItem `json:"items"`
+}
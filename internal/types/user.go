@@ -1,5 +1,7 @@
 package types
 
+import "watchAlert/internal/models"
+
 type RequestUserLogin struct {
 	UserName string `json:"username"`
 	Email    string `json:"email"`
@@ -8,31 +10,33 @@ type RequestUserLogin struct {
 }
 
 type RequestUserCreate struct {
-	UserId     string   `json:"userid"`
-	UserName   string   `json:"username"`
-	Email      string   `json:"email"`
-	Phone      string   `json:"phone"`
-	Password   string   `json:"password"`
-	Role       string   `json:"role"`
-	CreateBy   string   `json:"create_by"`
-	CreateAt   int64    `json:"create_at"`
-	JoinDuty   string   `json:"joinDuty" `
-	DutyUserId string   `json:"dutyUserId"`
-	Tenants    []string `json:"tenants" gorm:"tenants;serializer:json"`
+	UserId        string               `json:"userid"`
+	UserName      string               `json:"username"`
+	Email         string               `json:"email"`
+	Phone         string               `json:"phone"`
+	Password      string               `json:"password"`
+	Role          string               `json:"role"`
+	CreateBy      string               `json:"create_by"`
+	CreateAt      int64                `json:"create_at"`
+	JoinDuty      string               `json:"joinDuty" `
+	DutyUserId    string               `json:"dutyUserId"`
+	Tenants       []string             `json:"tenants" gorm:"tenants;serializer:json"`
+	NoticeChannel models.NoticeChannel `json:"noticeChannel"`
 }
 
 type RequestUserUpdate struct {
-	UserId     string   `json:"userid"`
-	UserName   string   `json:"username"`
-	Email      string   `json:"email"`
-	Phone      string   `json:"phone"`
-	Password   string   `json:"password"`
-	Role       string   `json:"role"`
-	CreateBy   string   `json:"create_by"`
-	CreateAt   int64    `json:"create_at"`
-	JoinDuty   string   `json:"joinDuty" `
-	DutyUserId string   `json:"dutyUserId"`
-	Tenants    []string `json:"tenants" gorm:"tenants;serializer:json"`
+	UserId        string               `json:"userid"`
+	UserName      string               `json:"username"`
+	Email         string               `json:"email"`
+	Phone         string               `json:"phone"`
+	Password      string               `json:"password"`
+	Role          string               `json:"role"`
+	CreateBy      string               `json:"create_by"`
+	CreateAt      int64                `json:"create_at"`
+	JoinDuty      string               `json:"joinDuty" `
+	DutyUserId    string               `json:"dutyUserId"`
+	Tenants       []string             `json:"tenants" gorm:"tenants;serializer:json"`
+	NoticeChannel models.NoticeChannel `json:"noticeChannel"`
 }
 
 type RequestUserQuery struct {
@@ -25,3 +25,16 @@ type RequestUserRoleQuery struct {
 	Name        string `json:"name" form:"name"`
 	Description string `json:"description" form:"description"`
 }
+
+// RequestTenantRolePermission 请求修改租户内角色的细粒度权限
+type RequestTenantRolePermission struct {
+	TenantId    string   `json:"tenantId" form:"tenantId"`
+	RoleId      string   `json:"roleId" form:"roleId"`
+	Permissions []string `json:"permissions"`
+}
+
+// ResponseTenantRolePermissions 租户内角色的细粒度权限
+type ResponseTenantRolePermissions struct {
+	Catalog map[string]string             `json:"catalog"` // 可配置的细粒度权限及说明
+	Items   []models.TenantRolePermission `json:"items"`
+}
@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type (
+	// Sink 审计日志外发目的地
+	Sink interface {
+		Name() string
+		Write(log models.AuditLog) error
+	}
+
+	// SinkFactory 根据配置创建 Sink
+	SinkFactory func(c config.AuditSink) (Sink, error)
+)
+
+const (
+	FormatJson   = "json"
+	FormatString = "string"
+
+	// 未配置时的缓冲区大小
+	defaultBufferSize = 1000
+	// 重试退避时间上限
+	maxRetryBackoff = time.Minute
+)
+
+var (
+	factories = map[string]SinkFactory{
+		"syslog":  newSyslogSink,
+		"webhook": newWebhookSink,
+	}
+
+	forwarder *Forwarder
+)
+
+// RegisterSink 注册新的 Sink 类型
+func RegisterSink(sinkType string, factory SinkFactory) {
+	factories[sinkType] = factory
+}
+
+// permanentError 重试也无法成功的发送错误, 如序列化失败或对端拒绝请求, 转发时直接丢弃
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记不可重试的错误, 供 Sink 实现使用
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var pe permanentError
+	return errors.As(err, &pe)
+}
+
+// Forwarder 将审计日志异步转发到 Sink, 发送失败时保留在缓冲区中重试
+type Forwarder struct {
+	sink   Sink
+	queue  chan models.AuditLog
+	mu     sync.Mutex
+	closed bool
+}
+
+// Initialize 根据配置启动审计日志转发, 未配置 Sink 时不启用
+func Initialize(ctx context.Context, c config.AuditSink) {
+	if c.Type == "" {
+		return
+	}
+
+	factory, ok := factories[c.Type]
+	if !ok {
+		logc.Errorf(ctx, "无效的审计日志转发类型: %s", c.Type)
+		return
+	}
+
+	sink, err := factory(c)
+	if err != nil {
+		logc.Errorf(ctx, "创建审计日志转发失败, type: %s, err: %s", c.Type, err.Error())
+		return
+	}
+
+	size := c.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	forwarder = &Forwarder{
+		sink:  sink,
+		queue: make(chan models.AuditLog, size),
+	}
+	go forwarder.run(ctx)
+	logc.Infof(ctx, "审计日志转发已启用, type: %s", sink.Name())
+}
+
+// Forward 提交审计日志到转发队列, 不阻塞请求; 缓冲区已满时丢弃并记录日志
+func Forward(log models.AuditLog) {
+	if forwarder == nil {
+		return
+	}
+
+	forwarder.mu.Lock()
+	defer forwarder.mu.Unlock()
+	if forwarder.closed {
+		return
+	}
+
+	select {
+	case forwarder.queue <- log:
+	default:
+		logc.Errorf(context.Background(), "审计日志转发缓冲区已满, 丢弃: %s", log.String())
+	}
+}
+
+// run 按顺序发送审计日志, 失败时指数退避重试直到成功, 队列中的日志保持缓冲; 不可重试的错误直接丢弃
+func (f *Forwarder) run(ctx context.Context) {
+	defer func() {
+		f.mu.Lock()
+		f.closed = true
+		f.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case log := <-f.queue:
+			backoff := time.Second
+			for {
+				err := f.sink.Write(log)
+				if err == nil {
+					break
+				}
+				if isPermanent(err) {
+					logc.Errorf(ctx, "审计日志转发失败且无法重试, 丢弃, sink: %s, err: %s, log: %s", f.sink.Name(), err.Error(), log.String())
+					break
+				}
+
+				logc.Errorf(ctx, "审计日志转发失败, sink: %s, retry after %s, err: %s", f.sink.Name(), backoff, err.Error())
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(backoff*2, maxRetryBackoff)
+			}
+		}
+	}
+}
+
+// validateFormat 校验审计日志格式配置, 在创建 Sink 时调用
+func validateFormat(format string) error {
+	switch format {
+	case "", FormatJson, FormatString:
+		return nil
+	default:
+		return fmt.Errorf("无效的审计日志格式: %s", format)
+	}
+}
+
+// formatLog 按配置格式序列化审计日志
+func formatLog(format string, log models.AuditLog) (string, error) {
+	switch format {
+	case "", FormatJson:
+		return tools.JsonMarshalToString(log), nil
+	case FormatString:
+		return log.String(), nil
+	default:
+		return "", Permanent(fmt.Errorf("无效的审计日志格式: %s", format))
+	}
+}
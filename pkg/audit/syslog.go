@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+)
+
+// syslog facility: local0(16), severity: notice(5)
+const syslogPriority = 16*8 + 5
+
+// SyslogSink 以 RFC5424 格式通过 TCP 发送审计日志, 使用 RFC6587 octet-counting 分帧
+type SyslogSink struct {
+	address  string
+	format   string
+	hostname string
+	conn     net.Conn
+	mu       sync.Mutex
+}
+
+func newSyslogSink(c config.AuditSink) (Sink, error) {
+	if c.Address == "" {
+		return nil, fmt.Errorf("syslog 地址不能为空")
+	}
+	if err := validateFormat(c.Format); err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	return &SyslogSink{
+		address:  c.Address,
+		format:   c.Format,
+		hostname: hostname,
+	}, nil
+}
+
+func (s *SyslogSink) Name() string { return "syslog" }
+
+func (s *SyslogSink) Write(log models.AuditLog) error {
+	msg, err := formatLog(s.format, log)
+	if err != nil {
+		return err
+	}
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	line := fmt.Sprintf("<%d>1 %s %s watchalert %d audit - %s",
+		syslogPriority, time.Unix(log.CreatedAt, 0).Format(time.RFC3339), s.hostname, os.Getpid(), msg)
+	frame := fmt.Sprintf("%d %s", len(line), line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.address, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if _, err := s.conn.Write([]byte(frame)); err != nil {
+		// 连接异常时关闭, 下次重试重新建立连接
+		_ = s.conn.Close()
+		s.conn = nil
+		return err
+	}
+
+	return nil
+}
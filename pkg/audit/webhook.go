@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// WebhookSink 通过 HTTP POST 发送审计日志
+type WebhookSink struct {
+	url    string
+	format string
+}
+
+func newWebhookSink(c config.AuditSink) (Sink, error) {
+	if c.Address == "" {
+		return nil, fmt.Errorf("webhook 地址不能为空")
+	}
+	if err := validateFormat(c.Format); err != nil {
+		return nil, err
+	}
+
+	return &WebhookSink{
+		url:    c.Address,
+		format: c.Format,
+	}, nil
+}
+
+func (w *WebhookSink) Name() string { return "webhook" }
+
+func (w *WebhookSink) Write(log models.AuditLog) error {
+	msg, err := formatLog(w.format, log)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if w.format == FormatString {
+		headers["Content-Type"] = "text/plain; charset=utf-8"
+	}
+
+	res, err := tools.Post(headers, w.url, bytes.NewReader([]byte(msg)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(res.Body)
+		err := fmt.Errorf("status: %d, body: %s", res.StatusCode, string(body))
+		// 4xx 表示请求本身被拒绝, 重试也不会成功; 超时及限流除外
+		if res.StatusCode >= 400 && res.StatusCode < 500 &&
+			res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func MetricDataQuery(client *cloudwatch.Client, query CloudWatchQuery) ([]time.Time, []float64) {
+func MetricDataQuery(ctx context.Context, client *cloudwatch.Client, query CloudWatchQuery) ([]time.Time, []float64) {
 	input := &cloudwatch.GetMetricDataInput{
 		MetricDataQueries: []types.MetricDataQuery{
 			{
@@ -34,7 +34,7 @@ func MetricDataQuery(client *cloudwatch.Client, query CloudWatchQuery) ([]time.T
 		StartTime: aws.Time(query.Form),
 		EndTime:   aws.Time(query.To),
 	}
-	output, err := client.GetMetricData(context.TODO(), input)
+	output, err := client.GetMetricData(ctx, input)
 	if err != nil {
 		log.Errorf(err.Error())
 		return nil, nil
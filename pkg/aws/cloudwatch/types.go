@@ -28,6 +28,7 @@ var NamespaceDimensionKeysMap = map[string][]string{
 }
 
 type CloudWatchQuery struct {
+	Region     string    `json:"region"`
 	Endpoint   string    `json:"endpoint"`
 	Dimension  string    `json:"dimension"`
 	Namespace  string    `json:"namespace"`
@@ -44,6 +45,10 @@ func (c CloudWatchQuery) GetFingerprint() string {
 		"metricName": c.MetricName,
 		"statistic":  c.Statistic,
 	}
+	// 多区域时区分不同区域的相同指标
+	if c.Region != "" {
+		newMetric["region"] = c.Region
+	}
 	h := md5.New()
 	streamString := tools.JsonMarshalToString(newMetric)
 	h.Write([]byte(streamString))
@@ -54,6 +59,7 @@ func (c CloudWatchQuery) GetFingerprint() string {
 
 func (c CloudWatchQuery) GetMetrics() map[string]interface{} {
 	return map[string]interface{}{
+		"region":     c.Region,
 		"instance":   c.Endpoint,
 		"namespace":  c.Namespace,
 		"metricName": c.MetricName,
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	EventStoreRedis    = "redis"
+	EventStorePostgres = "postgres"
+)
+
+// InitEventStore 初始化事件状态的存储后端, 使用 Redis 时返回 nil
+func InitEventStore(c config.EventStore) *gorm.DB {
+	switch c.Backend {
+	case "", EventStoreRedis:
+		return nil
+	case EventStorePostgres:
+		db, err := initPostgresEventStore(c.DSN)
+		if err != nil {
+			logc.Errorf(context.Background(), "failed to connect event store: %s", err.Error())
+			panic(err)
+		}
+		return db
+	default:
+		panic(fmt.Sprintf("unsupported event store backend: %s", c.Backend))
+	}
+}
+
+// initPostgresEventStore 初始化 Postgres 事件存储并迁移表结构
+func initPostgresEventStore(dsn string) (*gorm.DB, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("postgres event store dsn is empty")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(
+		&models.StoreAlertEvent{},
+		&models.StorePendingRecover{},
+		&models.StoreFaultCenterInfo{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Application.Server.Mode != "debug" {
+		db.Logger = logger.Default.LogMode(logger.Silent)
+	}
+
+	logc.Infof(context.Background(), "connected to postgres event store")
+	return db, nil
+}
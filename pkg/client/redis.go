@@ -8,18 +8,25 @@ import (
 	"github.com/go-redis/redis"
 )
 
-var Redis *redis.Client
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
 
-func InitRedis() *redis.Client {
+// Redis 单机、Sentinel 及 Cluster 模式统一使用 UniversalClient,
+// 缓存中的多 key 操作(事务 Pipeline、Lua 脚本)均只涉及单个 key, 在集群模式下无需 hash tag
+var Redis redis.UniversalClient
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", config.Application.Redis.Host, config.Application.Redis.Port),
-		Password: config.Application.Redis.Pass,
-		DB:       config.Application.Redis.Database, // 使用默认的数据库
-	})
+func InitRedis() redis.UniversalClient {
+	client, err := newRedisClient(config.Application.Redis)
+	if err != nil {
+		log.Printf("redis Connection Failed %s", err)
+		panic(err)
+	}
 
 	// 尝试连接到 Redis 服务器
-	_, err := client.Ping().Result()
+	_, err = client.Ping().Result()
 	if err != nil {
 		log.Printf("redis Connection Failed %s", err)
 		panic(err)
@@ -30,3 +37,38 @@ func InitRedis() *redis.Client {
 	return client
 
 }
+
+// newRedisClient 按部署模式创建 Redis 客户端
+func newRedisClient(c config.Redis) (redis.UniversalClient, error) {
+	switch c.Mode {
+	case "", RedisModeStandalone:
+		return redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%s", c.Host, c.Port),
+			Password: c.Pass,
+			DB:       c.Database, // 使用默认的数据库
+		}), nil
+	case RedisModeSentinel:
+		if c.MasterName == "" || len(c.SentinelAddrs) == 0 {
+			return nil, fmt.Errorf("Sentinel 模式需要配置 masterName 及 sentinelAddrs")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    c.MasterName,
+			SentinelAddrs: c.SentinelAddrs,
+			Password:      c.Pass,
+			DB:            c.Database,
+		}), nil
+	case RedisModeCluster:
+		if len(c.ClusterAddrs) == 0 {
+			return nil, fmt.Errorf("Cluster 模式需要配置 clusterAddrs")
+		}
+		if c.Database != 0 {
+			return nil, fmt.Errorf("Cluster 模式不支持 database: %d", c.Database)
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    c.ClusterAddrs,
+			Password: c.Pass,
+		}), nil
+	default:
+		return nil, fmt.Errorf("不支持的 Redis 部署模式: %s", c.Mode)
+	}
+}
@@ -0,0 +1,173 @@
+package monitor
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "w8t"
+
+// EvalMetrics 告警评估引擎的自身监控指标
+type EvalMetrics struct {
+	Registry *prometheus.Registry
+
+	evalDuration     *prometheus.HistogramVec
+	evalFingerprints *prometheus.GaugeVec
+	queryFailures    *prometheus.CounterVec
+	evalOverruns     *prometheus.CounterVec
+	activeEvals      prometheus.Gauge
+	noticeThrottled  *prometheus.CounterVec
+	queryCache       *prometheus.CounterVec
+
+	// 运行中的评估协程数量, 与 activeEvals 一致, 供就绪检查读取
+	activeEvalCount atomic.Int64
+}
+
+// NewEvalMetrics 创建并注册评估引擎指标
+func NewEvalMetrics() *EvalMetrics {
+	m := &EvalMetrics{
+		Registry: prometheus.NewRegistry(),
+		evalDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "eval_duration_seconds",
+			Help:      "Duration of a single rule evaluation tick.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"rule_id", "rule_name"}),
+		evalFingerprints: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "eval_fingerprints",
+			Help:      "Number of alerting fingerprints produced by the last evaluation of a rule.",
+		}, []string{"rule_id", "rule_name"}),
+		queryFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "datasource_query_failures_total",
+			Help:      "Number of failed datasource queries during rule evaluation.",
+		}, []string{"datasource_id", "datasource_type"}),
+		evalOverruns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "eval_overrun_total",
+			Help:      "Number of evaluation ticks that fired while the previous evaluation of the rule was still running.",
+		}, []string{"rule_id", "rule_name", "policy"}),
+		activeEvals: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "eval_active_goroutines",
+			Help:      "Number of running rule evaluation goroutines.",
+		}),
+		noticeThrottled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notice_throttled_total",
+			Help:      "Number of notifications deferred by the per-channel rate limiter.",
+		}, []string{"notice_type", "notice_id"}),
+		queryCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "datasource_query_cache_total",
+			Help:      "Number of datasource query cache lookups, partitioned by hit or miss.",
+		}, []string{"datasource_id", "result"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.evalDuration,
+		m.evalFingerprints,
+		m.queryFailures,
+		m.evalOverruns,
+		m.activeEvals,
+		m.noticeThrottled,
+		m.queryCache,
+	)
+
+	return m
+}
+
+// ObserveEval 记录一次规则评估的耗时及产生的指纹数量
+func (m *EvalMetrics) ObserveEval(ruleId, ruleName string, seconds float64, fingerprints int) {
+	if m == nil {
+		return
+	}
+
+	m.evalDuration.WithLabelValues(ruleId, ruleName).Observe(seconds)
+	m.evalFingerprints.WithLabelValues(ruleId, ruleName).Set(float64(fingerprints))
+}
+
+// IncQueryFailure 记录一次数据源查询失败
+func (m *EvalMetrics) IncQueryFailure(datasourceId, datasourceType string) {
+	if m == nil {
+		return
+	}
+
+	m.queryFailures.WithLabelValues(datasourceId, datasourceType).Inc()
+}
+
+// IncEvalOverrun 记录一次评估超时，即上一次评估未完成时又到了评估周期
+func (m *EvalMetrics) IncEvalOverrun(ruleId, ruleName, policy string) {
+	if m == nil {
+		return
+	}
+
+	m.evalOverruns.WithLabelValues(ruleId, ruleName, policy).Inc()
+}
+
+// IncNoticeThrottled 记录一次因通知渠道限速而延迟的发送
+func (m *EvalMetrics) IncNoticeThrottled(noticeType, noticeId string) {
+	if m == nil {
+		return
+	}
+
+	m.noticeThrottled.WithLabelValues(noticeType, noticeId).Inc()
+}
+
+// IncQueryCache 记录一次数据源查询缓存的命中或未命中
+func (m *EvalMetrics) IncQueryCache(datasourceId string, hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.queryCache.WithLabelValues(datasourceId, result).Inc()
+}
+
+// EvalStarted 评估协程启动
+func (m *EvalMetrics) EvalStarted() {
+	if m == nil {
+		return
+	}
+
+	m.activeEvals.Inc()
+	m.activeEvalCount.Add(1)
+}
+
+// EvalStopped 评估协程退出
+func (m *EvalMetrics) EvalStopped() {
+	if m == nil {
+		return
+	}
+
+	m.activeEvals.Dec()
+	m.activeEvalCount.Add(-1)
+}
+
+// ActiveEvals 运行中的评估协程数量
+func (m *EvalMetrics) ActiveEvals() int64 {
+	if m == nil {
+		return 0
+	}
+
+	return m.activeEvalCount.Load()
+}
+
+// RemoveRule 规则停止评估后清理对应的指标
+func (m *EvalMetrics) RemoveRule(ruleId string) {
+	if m == nil {
+		return
+	}
+
+	m.evalDuration.DeletePartialMatch(prometheus.Labels{"rule_id": ruleId})
+	m.evalFingerprints.DeletePartialMatch(prometheus.Labels{"rule_id": ruleId})
+	m.evalOverruns.DeletePartialMatch(prometheus.Labels{"rule_id": ruleId})
+}
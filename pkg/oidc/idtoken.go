@@ -0,0 +1,192 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// JWKS 身份提供方公开的签名公钥集合
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func GetJWKS(jwksUri string) (*JWKS, error) {
+	resp, err := tools.Get(nil, jwksUri, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	var d JWKS
+	if err = tools.ParseReaderBody(resp.Body, &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// VerifyIDToken 校验 ID Token 的签名、签发者、受众及有效期, 返回其中的声明
+func VerifyIDToken(idToken string, cfg *types.RespOpenIDConfiguration, clientID string) (jwt.MapClaims, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("未返回 ID Token, 请确认授权范围包含 openid")
+	}
+
+	jwks, err := GetJWKS(cfg.JwksUri)
+	if err != nil {
+		return nil, fmt.Errorf("获取签名公钥失败: %s", err.Error())
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, key := range jwks.Keys {
+			if kid != "" && key.Kid != kid {
+				continue
+			}
+
+			switch token.Method.(type) {
+			case *jwt.SigningMethodRSA:
+				if key.Kty == "RSA" {
+					return key.rsaPublicKey()
+				}
+			case *jwt.SigningMethodECDSA:
+				if key.Kty == "EC" {
+					return key.ecdsaPublicKey()
+				}
+			}
+		}
+		return nil, fmt.Errorf("未找到匹配的签名公钥, kid: %s, alg: %v", kid, token.Header["alg"])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ID Token 校验失败: %s", err.Error())
+	}
+
+	if !claims.VerifyIssuer(cfg.Issuer, true) {
+		return nil, fmt.Errorf("ID Token 签发者不匹配: %v", claims["iss"])
+	}
+
+	if !containsAudience(claims["aud"], clientID) {
+		return nil, fmt.Errorf("ID Token 受众不匹配: %v", claims["aud"])
+	}
+
+	return claims, nil
+}
+
+// GetClaimStrings 获取字符串或字符串数组类型的声明, 如 groups
+func GetClaimStrings(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// MatchUserInfo 校验用户信息接口返回的用户与已校验的 ID Token 属于同一用户,
+// 优先比较 sub, 身份提供方未返回 sub 时比较用户 ID 或邮箱
+func MatchUserInfo(claims jwt.MapClaims, info *types.RespOidcUserInfo) error {
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return fmt.Errorf("ID Token 缺少 sub 声明")
+	}
+
+	email, _ := claims["email"].(string)
+	if email != "" && info.Email != "" && !strings.EqualFold(email, info.Email) {
+		return fmt.Errorf("用户信息与 ID Token 不匹配, email: %s", info.Email)
+	}
+
+	switch {
+	case info.Sub != "":
+		if info.Sub != sub {
+			return fmt.Errorf("用户信息与 ID Token 不匹配, sub: %s", info.Sub)
+		}
+	case info.Id == sub:
+	case email != "" && strings.EqualFold(email, info.Email):
+	default:
+		return fmt.Errorf("用户信息与 ID Token 不匹配, id: %s", info.Id)
+	}
+
+	return nil
+}
+
+// containsAudience aud 可以是字符串或字符串数组
+func containsAudience(aud interface{}, clientID string) bool {
+	for _, a := range GetClaimStrings(jwt.MapClaims{"aud": aud}, "aud") {
+		if a == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func (k JWK) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (k JWK) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("不支持的曲线类型: %s", k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
@@ -22,7 +22,7 @@ func GetOpenIDConfiguration(upper string) (*types.RespOpenIDConfiguration, error
 	return &d, nil
 }
 
-func GetOauthToken(tokenUrl, code, clientID, clientSecret string) (*types.OauthToken, error) {
+func GetOauthToken(tokenUrl, code, clientID, clientSecret, redirectURI string) (*types.OauthToken, error) {
 	header := make(map[string]string)
 	header["Content-Type"] = "application/x-www-form-urlencoded"
 
@@ -35,6 +35,9 @@ func GetOauthToken(tokenUrl, code, clientID, clientSecret string) (*types.OauthT
 	if clientSecret != "" {
 		form.Add("client_secret", clientSecret)
 	}
+	if redirectURI != "" {
+		form.Add("redirect_uri", redirectURI)
+	}
 
 	resp, err := tools.Post(header, tokenUrl, bytes.NewReader([]byte(form.Encode())), 10)
 	if err != nil {
@@ -2,25 +2,41 @@ package provider
 
 import (
 	"context"
+	"time"
+	"watchAlert/internal/models"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// 临时凭证到期前提前刷新的时间
+const assumeRoleExpiryWindow = 5 * time.Minute
+
 type AwsConfig struct {
 	ExternalLabels map[string]interface{}
 	cfg            aws.Config
+	regions        []string
 }
 
-func NewAWSCredentialCfg(region, ak, sk string, labels map[string]interface{}) (AwsConfig, error) {
+// NewAWSCredentialCfg 使用 AccessKey 创建配置, 配置了 RoleArn 时通过 STS 扮演跨账号角色, 临时凭证缓存至到期前刷新
+func NewAWSCredentialCfg(cw models.AWSCloudWatch, labels map[string]interface{}) (AwsConfig, error) {
+	regions := cw.GetRegions()
+	var region string
+	if len(regions) > 0 {
+		region = regions[0]
+	}
+
 	cfg, err := config.LoadDefaultConfig(context.Background(),
 		func(options *config.LoadOptions) error {
 			options.Region = region
 			options.Credentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
 				return aws.Credentials{
-					AccessKeyID:     ak,
-					SecretAccessKey: sk,
+					AccessKeyID:     cw.AccessKey,
+					SecretAccessKey: cw.SecretKey,
 				}, nil
 			})
 
@@ -31,9 +47,22 @@ func NewAWSCredentialCfg(region, ak, sk string, labels map[string]interface{}) (
 		return AwsConfig{}, err
 	}
 
+	if cw.RoleArn != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), cw.RoleArn, func(options *stscreds.AssumeRoleOptions) {
+			if cw.ExternalId != "" {
+				options.ExternalID = aws.String(cw.ExternalId)
+			}
+			options.RoleSessionName = "watchalert"
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider, func(options *aws.CredentialsCacheOptions) {
+			options.ExpiryWindow = assumeRoleExpiryWindow
+		})
+	}
+
 	return AwsConfig{
 		ExternalLabels: labels,
 		cfg:            cfg,
+		regions:        regions,
 	}, nil
 }
 
@@ -41,6 +70,18 @@ func (a AwsConfig) CloudWatchCli() *cloudwatch.Client {
 	return cloudwatch.NewFromConfig(a.cfg)
 }
 
+// CloudWatchRegionCli 获取指定区域的客户端, 与其他区域共享凭证缓存
+func (a AwsConfig) CloudWatchRegionCli(region string) *cloudwatch.Client {
+	return cloudwatch.NewFromConfig(a.cfg, func(options *cloudwatch.Options) {
+		options.Region = region
+	})
+}
+
+// GetRegions 获取数据源配置的区域
+func (a AwsConfig) GetRegions() []string {
+	return a.regions
+}
+
 func (a AwsConfig) RdsCli() *rds.Client {
 	return rds.NewFromConfig(a.cfg)
 }
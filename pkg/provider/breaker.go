@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	// 连续健康检查失败多少次后熔断
+	BreakerFailureThreshold = 3
+	// 熔断后的冷却时间，冷却结束后进入半开状态并放行一次探测
+	BreakerCooldown = 30 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker 数据源健康检查熔断器
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// breakerStore 按数据源 ID 存储熔断器，所有规则共享同一份状态
+type breakerStore struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+var healthBreakers = &breakerStore{
+	breakers: make(map[string]*circuitBreaker),
+}
+
+func (s *breakerStore) get(datasourceId string) *circuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.breakers[datasourceId]
+	if !ok {
+		b = &circuitBreaker{}
+		s.breakers[datasourceId] = b
+	}
+	return b
+}
+
+// RemoveBreaker 移除数据源的熔断器，数据源更新或删除后调用
+func RemoveBreaker(datasourceId string) {
+	healthBreakers.mu.Lock()
+	defer healthBreakers.mu.Unlock()
+
+	delete(healthBreakers.breakers, datasourceId)
+}
+
+// allow 判断是否允许执行健康检查
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < BreakerCooldown {
+			return false
+		}
+		// 冷却结束，放行一次探测
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// 探测进行中，其余请求直接拒绝
+		return false
+	default:
+		return true
+	}
+}
+
+// success 记录一次成功的健康检查
+func (b *circuitBreaker) success(datasourceId string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != breakerClosed {
+		logc.Infof(context.Background(), "Datasource %s circuit breaker closed, health check recovered", datasourceId)
+	}
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// failure 记录一次失败的健康检查
+func (b *circuitBreaker) failure(datasourceId string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= BreakerFailureThreshold) {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		logc.Errorf(context.Background(), "Datasource %s circuit breaker opened after %d consecutive failures, cooldown %s", datasourceId, b.failures, BreakerCooldown)
+	}
+}
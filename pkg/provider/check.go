@@ -21,6 +21,12 @@ var datasourceFactories = map[string]ClientFactory{
 	"Prometheus": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewPrometheusClient(ds)
 	},
+	"InfluxDB": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewInfluxDBClient(ds)
+	},
+	"Datadog": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewDatadogClient(ds)
+	},
 	"Kubernetes": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewKubernetesClient(context.Background(), ds.KubeConfig, ds.Labels)
 	},
@@ -36,6 +42,9 @@ var datasourceFactories = map[string]ClientFactory{
 	"Jaeger": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewJaegerClient(ds)
 	},
+	"Tempo": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewTempoClient(ds)
+	},
 	"CloudWatch": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return &CloudWatchDummyChecker{}, nil
 	},
@@ -55,7 +64,36 @@ func (c *CloudWatchDummyChecker) Check() (bool, error) {
 }
 
 // CheckDatasourceHealth 统一健康检查入口
+// 已保存的数据源会经过熔断器，连续失败后在冷却时间内直接返回不健康，避免频繁请求故障数据源
+// 已保存的数据源的检查结果会短暂缓存, 共享数据源的规则在缓存时间内复用结果
 func CheckDatasourceHealth(datasource models.AlertDataSource) (bool, error) {
+	if datasource.ID == "" {
+		return checkDatasourceHealth(datasource)
+	}
+
+	return healthResults.check(datasource, func() (bool, error) {
+		return checkDatasourceHealthWithBreaker(datasource)
+	})
+}
+
+// checkDatasourceHealthWithBreaker 经过熔断器执行健康检查
+func checkDatasourceHealthWithBreaker(datasource models.AlertDataSource) (bool, error) {
+	breaker := healthBreakers.get(datasource.ID)
+	if !breaker.allow() {
+		return false, fmt.Errorf("circuit breaker is open for datasource %s", datasource.ID)
+	}
+
+	healthy, err := checkDatasourceHealth(datasource)
+	if healthy {
+		breaker.success(datasource.ID)
+	} else {
+		breaker.failure(datasource.ID)
+	}
+
+	return healthy, err
+}
+
+func checkDatasourceHealth(datasource models.AlertDataSource) (bool, error) {
 	// 获取对应的工厂方法
 	factory, ok := datasourceFactories[datasource.Type]
 	if !ok {
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 健康检查结果默认缓存时间
+const defaultHealthCacheTTL = 5 * time.Second
+
+type healthResult struct {
+	healthy   bool
+	err       error
+	checkedAt time.Time
+}
+
+// healthCache 按数据源 ID 短暂缓存健康检查结果, 共享同一数据源的规则复用最近一次结果
+type healthCache struct {
+	mu      sync.RWMutex
+	results map[string]healthResult
+	group   singleflight.Group
+}
+
+var healthResults = &healthCache{
+	results: make(map[string]healthResult),
+}
+
+func getHealthCacheTTL() time.Duration {
+	ttl := config.Application.Eval.HealthCheckCacheTTL
+	if ttl < 0 {
+		return 0
+	}
+	if ttl == 0 {
+		return defaultHealthCacheTTL
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// check 缓存未过期时直接返回结果, 否则执行检查; 并发请求同一数据源时只检查一次
+func (c *healthCache) check(datasource models.AlertDataSource, fn func() (bool, error)) (bool, error) {
+	ttl := getHealthCacheTTL()
+	if ttl == 0 {
+		return fn()
+	}
+
+	c.mu.RLock()
+	result, ok := c.results[datasource.ID]
+	c.mu.RUnlock()
+	if ok && time.Since(result.checkedAt) < ttl {
+		return result.healthy, result.err
+	}
+
+	v, _, _ := c.group.Do(datasource.ID, func() (interface{}, error) {
+		healthy, err := fn()
+		result := healthResult{healthy: healthy, err: err, checkedAt: time.Now()}
+
+		c.mu.Lock()
+		c.results[datasource.ID] = result
+		c.mu.Unlock()
+		return result, nil
+	})
+
+	result = v.(healthResult)
+	return result.healthy, result.err
+}
+
+// InvalidateHealthCache 清除数据源的健康检查缓存, 数据源更新或删除后调用
+func InvalidateHealthCache(datasourceId string) {
+	healthResults.mu.Lock()
+	defer healthResults.mu.Unlock()
+
+	delete(healthResults.results, datasourceId)
+	healthResults.group.Forget(datasourceId)
+}
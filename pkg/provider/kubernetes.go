@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,9 +12,13 @@ import (
 
 	"github.com/zeromicro/go-zero/core/logc"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
 )
 
 type KubernetesClient struct {
@@ -59,58 +64,127 @@ func NewKubernetesClient(ctx context.Context, kubeConfigContent string, labels m
 	}, nil
 }
 
-func (a KubernetesClient) GetWarningEvent(reason string, scope int, filter []string) (map[string][]KubernetesEventItem, error) {
-	var warningEvents = corev1.EventList{}
-	cutoffTime := time.Now().Add(-time.Duration(scope) * time.Minute)
-	opts := metav1.ListOptions{
-		Limit:         50, // 减少每次请求的数量，防止过多资源占用
-		FieldSelector: "reason=" + reason,
-	}
+// KubernetesEventQuery Kubernetes 事件查询条件, 列表为空时不按该条件过滤
+type KubernetesEventQuery struct {
+	Reasons    []string
+	Namespaces []string
+	Kinds      []string
+	// 资源名称包含任一关键字时排除
+	Exclude []string
+	// 查询最近多少分钟内发生的事件
+	Scope int
+}
 
-	for {
-		list, err := a.Cli.CoreV1().Events(corev1.NamespaceAll).List(a.Ctx, opts)
-		if err != nil {
-			return nil, err
-		}
+// GetWarningEvent 按原因、命名空间及资源类型查询事件, 按 命名空间/资源类型/资源名称/原因 分组
+func (a KubernetesClient) GetWarningEvent(query KubernetesEventQuery) (map[string][]KubernetesEventItem, error) {
+	cutoffTime := time.Now().Add(-time.Duration(query.Scope) * time.Minute)
 
-		for _, event := range list.Items {
-			// 检查事件的 Reason 和事件发生时间
-			eventTime := event.LastTimestamp
-			if event.Reason == reason && eventTime.After(cutoffTime) {
-				warningEvents.Items = append(warningEvents.Items, event)
+	namespaces := query.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+	// 字段选择器不支持 OR, 多个原因时分别查询
+	reasons := query.Reasons
+	if len(reasons) == 0 {
+		reasons = []string{""}
+	}
+
+	warningEventsMap := make(map[string][]KubernetesEventItem)
+	for _, namespace := range namespaces {
+		for _, reason := range reasons {
+			opts := metav1.ListOptions{
+				Limit: 50, // 减少每次请求的数量，防止过多资源占用
+			}
+			if reason != "" {
+				opts.FieldSelector = "reason=" + reason
 			}
-		}
 
-		// 如果没有更多事件，则停止拉取
-		if list.Continue == "" {
-			break
+			for {
+				list, err := a.listEvents(namespace, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, event := range list.Items {
+					// 检查事件发生时间及过滤条件
+					if !event.LastTimestamp.After(cutoffTime) || !query.match(event) {
+						continue
+					}
+
+					key := fmt.Sprintf("%s/%s/%s/%s", event.Namespace, event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason)
+					warningEventsMap[key] = append(warningEventsMap[key], KubernetesEventItem(event))
+				}
+
+				// 如果没有更多事件，则停止拉取
+				if list.Continue == "" {
+					break
+				}
+
+				// 使用 Continue 获取下一页
+				opts.Continue = list.Continue
+			}
 		}
-
-		// 使用 Continue 获取下一页
-		opts.Continue = list.Continue
 	}
 
-	if len(warningEvents.Items) == 0 {
+	if len(warningEventsMap) == 0 {
 		return nil, nil
 	}
 
-	warningEventsMap := make(map[string][]KubernetesEventItem)
-	for _, event := range warningEvents.Items {
-		var found bool
-		for _, f := range filter {
-			if strings.Contains(event.InvolvedObject.Name, f) {
-				found = true
-				break
-			}
+	return warningEventsMap, nil
+}
+
+// kubernetesListBackoff API Server 断开或过载时的重试间隔, 依次为 1s、2s、4s
+var kubernetesListBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// listEvents 查询事件, 连接断开、超时及服务端过载时按退避间隔重试, 避免单次断连导致本轮评估失败
+func (a KubernetesClient) listEvents(namespace string, opts metav1.ListOptions) (*corev1.EventList, error) {
+	var list *corev1.EventList
+	err := retry.OnError(kubernetesListBackoff, isRetriableKubernetesError, func() error {
+		var err error
+		list, err = a.Cli.CoreV1().Events(namespace).List(a.Ctx, opts)
+		if err != nil && isRetriableKubernetesError(err) {
+			logc.Errorf(a.Ctx, "查询Kubernetes事件失败, 稍后重试, 命名空间: %s, 错误: %v", namespace, err)
 		}
+		return err
+	})
+
+	return list, err
+}
+
+func isRetriableKubernetesError(err error) bool {
+	return utilnet.IsConnectionReset(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsProbableEOF(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
+
+func (q KubernetesEventQuery) match(event corev1.Event) bool {
+	if len(q.Reasons) > 0 && !slices.Contains(q.Reasons, event.Reason) {
+		return false
+	}
+
+	if len(q.Kinds) > 0 && !slices.ContainsFunc(q.Kinds, func(kind string) bool {
+		return strings.EqualFold(kind, event.InvolvedObject.Kind)
+	}) {
+		return false
+	}
 
-		if !found {
-			key := fmt.Sprintf("%s/%s", event.InvolvedObject.Name, event.Reason)
-			warningEventsMap[key] = append(warningEventsMap[key], KubernetesEventItem(event))
+	for _, f := range q.Exclude {
+		if strings.Contains(event.InvolvedObject.Name, f) {
+			return false
 		}
 	}
 
-	return warningEventsMap, nil
+	return true
 }
 
 func (a KubernetesClient) GetExternalLabels() map[string]interface{} {
@@ -129,12 +203,10 @@ type KubernetesEvent struct {
 
 type KubernetesEventItem corev1.Event
 
-func (a KubernetesEventItem) GetFingerprint() string {
-	labels := map[string]interface{}{
-		"namespace": a.Namespace,
-		"resource":  a.Reason,
-		"podName":   a.InvolvedObject.Name,
-	}
+// GetFingerprint 指纹包含集群、命名空间及资源, 不同集群或资源的事件不会合并为同一告警
+func (a KubernetesEventItem) GetFingerprint(datasourceId string) string {
+	labels := a.GetMetrics()
+	labels["datasource_id"] = datasourceId
 
 	var result uint64
 	for labelName, labelValue := range labels {
@@ -151,6 +223,15 @@ func (a KubernetesEventItem) GetMetrics() map[string]interface{} {
 	return map[string]interface{}{
 		"namespace": a.Namespace,
 		"resource":  a.Reason,
+		"kind":      a.InvolvedObject.Kind,
 		"podName":   a.InvolvedObject.Name,
 	}
 }
+
+// GetCount 事件的累计发生次数
+func (a KubernetesEventItem) GetCount() int64 {
+	if a.Count <= 0 {
+		return 1
+	}
+	return int64(a.Count)
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -18,7 +19,7 @@ const (
 )
 
 type LogsFactoryProvider interface {
-	Query(options LogQueryOptions) (Logs, int, error)
+	Query(ctx context.Context, options LogQueryOptions) (Logs, int, error)
 	Check() (bool, error)
 	GetExternalLabels() map[string]interface{}
 }
@@ -40,9 +41,10 @@ type Loki struct {
 }
 
 type AliCloudSLS struct {
-	Query    string   // 查询语句
-	Project  string   // AliCloud SLS Project
-	LogStore []string // AliCloud SLS LogStore
+	Query    string                     // 查询语句
+	Project  string                     // AliCloud SLS Project
+	LogStore []string                   // AliCloud SLS LogStore
+	Targets  []models.AliCloudSLSTarget // 多个查询目标, 由 QueryTargets 使用
 }
 
 type Elasticsearch struct {
@@ -58,6 +60,12 @@ type Elasticsearch struct {
 	QueryWildcard int64
 	// 查询sql
 	RawJson string
+	// 查询语言, lucene 或 esql
+	QueryLanguage models.EsQueryLanguage
+	// ES|QL 语句
+	EsQL string
+	// ES|QL 查询的时间范围（单位分钟）, 为 0 时不限制
+	Scope int64
 }
 
 // VictoriaLogs 数据源配置
@@ -89,9 +97,17 @@ type Logs struct {
 }
 
 func (l Logs) GenerateFingerprint(ruleId string) string {
-	labels := map[string]string{
+	return l.GenerateFingerprintWithLabels(ruleId, nil)
+}
+
+// GenerateFingerprintWithLabels 基于 RuleId 及分组标签生成指纹, 无分组标签时与 GenerateFingerprint 一致
+func (l Logs) GenerateFingerprintWithLabels(ruleId string, groupLabels map[string]interface{}) string {
+	labels := map[string]interface{}{
 		"ruleId": ruleId,
 	}
+	for k, v := range groupLabels {
+		labels[k] = v
+	}
 
 	var result uint64
 	for labelName, labelValue := range labels {
@@ -8,38 +8,118 @@ import (
 	util "github.com/alibabacloud-go/tea-utils/v2/service"
 	"github.com/alibabacloud-go/tea/tea"
 	"github.com/zeromicro/go-zero/core/logc"
+	"sync"
+	"time"
 	"watchAlert/internal/models"
 )
 
 type AliCloudSlsDsProvider struct {
 	client         *sls20201230.Client
+	config         models.DsAliCloudConfig
 	ExternalLabels map[string]interface{}
 }
 
+// AliCloudSLSTargetResult 单个 SLS 查询目标的查询结果
+type AliCloudSLSTargetResult struct {
+	Target models.AliCloudSLSTarget
+	Logs   Logs
+	Err    error
+}
+
 func NewAliCloudSlsClient(source models.AlertDataSource) (LogsFactoryProvider, error) {
-	config := &openapi.Config{
-		AccessKeyId:     &source.DsAliCloudConfig.AliCloudAk,
-		AccessKeySecret: &source.DsAliCloudConfig.AliCloudSk,
-	}
-	config.Endpoint = tea.String(source.DsAliCloudConfig.AliCloudEndpoint)
-	result, err := sls20201230.NewClient(config)
+	result, err := newSlsClient(source.DsAliCloudConfig.AliCloudEndpoint, source.DsAliCloudConfig.AliCloudAk, source.DsAliCloudConfig.AliCloudSk)
 	if err != nil {
 		return AliCloudSlsDsProvider{}, err
 	}
 
 	return AliCloudSlsDsProvider{
 		client:         result,
+		config:         source.DsAliCloudConfig,
 		ExternalLabels: source.Labels,
 	}, nil
 }
 
-func (a AliCloudSlsDsProvider) Query(query LogQueryOptions) (Logs, int, error) {
+func newSlsClient(endpoint, ak, sk string) (*sls20201230.Client, error) {
+	config := &openapi.Config{
+		AccessKeyId:     tea.String(ak),
+		AccessKeySecret: tea.String(sk),
+	}
+	config.Endpoint = tea.String(endpoint)
+	return sls20201230.NewClient(config)
+}
+
+// clientFor 获取查询目标使用的客户端, 目标未配置服务地址及 AccessKey 时使用数据源的客户端
+func (a AliCloudSlsDsProvider) clientFor(target models.AliCloudSLSTarget) (*sls20201230.Client, error) {
+	if target.Endpoint == "" && target.AccessKeyId == "" {
+		return a.client, nil
+	}
+
+	endpoint, ak, sk := a.config.AliCloudEndpoint, a.config.AliCloudAk, a.config.AliCloudSk
+	if target.Endpoint != "" {
+		endpoint = target.Endpoint
+	}
+	if target.AccessKeyId != "" {
+		ak, sk = target.AccessKeyId, target.AccessKeySecret
+	}
+	return newSlsClient(endpoint, ak, sk)
+}
+
+// QueryTargets 并发查询所有查询目标, 单个目标查询失败不影响其他目标
+func (a AliCloudSlsDsProvider) QueryTargets(ctx context.Context, query LogQueryOptions) []AliCloudSLSTargetResult {
+	results := make([]AliCloudSLSTargetResult, len(query.AliCloudSLS.Targets))
+
+	var wg sync.WaitGroup
+	for i, target := range query.AliCloudSLS.Targets {
+		wg.Add(1)
+		go func(i int, target models.AliCloudSLSTarget) {
+			defer wg.Done()
+			results[i] = a.queryTarget(ctx, query, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (a AliCloudSlsDsProvider) queryTarget(ctx context.Context, query LogQueryOptions, target models.AliCloudSLSTarget) (result AliCloudSLSTargetResult) {
+	result.Target = target
+	defer func() {
+		if r := tea.Recover(recover()); r != nil {
+			result.Err = r
+		}
+	}()
+
+	cli, err := a.clientFor(target)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
 	getLogsRequest := &sls20201230.GetLogsRequest{
 		To:    tea.Int32(query.EndAt.(int32)),
 		From:  tea.Int32(query.StartAt.(int32)),
 		Query: tea.String(query.AliCloudSLS.Query),
 	}
-	runtime := &util.RuntimeOptions{}
+	res, err := cli.GetLogsWithOptions(tea.String(target.Project), tea.String(target.Logstore), getLogsRequest, make(map[string]*string), slsRuntimeOptions(ctx))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Logs = Logs{
+		ProviderName: AliCloudSLSDsProviderName,
+		Message:      res.Body,
+	}
+	return result
+}
+
+func (a AliCloudSlsDsProvider) Query(ctx context.Context, query LogQueryOptions) (Logs, int, error) {
+	getLogsRequest := &sls20201230.GetLogsRequest{
+		To:    tea.Int32(query.EndAt.(int32)),
+		From:  tea.Int32(query.StartAt.(int32)),
+		Query: tea.String(query.AliCloudSLS.Query),
+	}
+	runtime := slsRuntimeOptions(ctx)
 	headers := make(map[string]*string)
 	defer func() {
 		if r := tea.Recover(recover()); r != nil {
@@ -49,6 +129,9 @@ func (a AliCloudSlsDsProvider) Query(query LogQueryOptions) (Logs, int, error) {
 
 	var msg = []map[string]interface{}{}
 	for _, logstore := range query.AliCloudSLS.LogStore {
+		if ctx.Err() != nil {
+			return Logs{}, 0, ctx.Err()
+		}
 		res, err := a.client.GetLogsWithOptions(tea.String(query.AliCloudSLS.Project), tea.String(logstore), getLogsRequest, headers, runtime)
 		if err != nil {
 			logc.Error(context.Background(), err.Error())
@@ -63,6 +146,15 @@ func (a AliCloudSlsDsProvider) Query(query LogQueryOptions) (Logs, int, error) {
 	}, len(msg), nil
 }
 
+// slsRuntimeOptions SLS SDK 不支持 context, 按 ctx 剩余时间设置请求的读超时
+func slsRuntimeOptions(ctx context.Context) *util.RuntimeOptions {
+	runtime := &util.RuntimeOptions{}
+	if deadline, ok := ctx.Deadline(); ok {
+		runtime.SetReadTimeout(max(int(time.Until(deadline).Milliseconds()), 1))
+	}
+	return runtime
+}
+
 func (a AliCloudSlsDsProvider) Check() (bool, error) {
 	err := a.client.CheckConfig(&client.Config{})
 	if err != nil {
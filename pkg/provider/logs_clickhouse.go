@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 	"watchAlert/internal/models"
 
@@ -17,8 +21,50 @@ type ClickHouseProvider struct {
 }
 
 func NewClickHouseClient(ctx context.Context, ds models.AlertDataSource) (LogsFactoryProvider, error) {
-	conn := clickhouse.OpenDB(&clickhouse.Options{
-		Addr: []string{ds.ClickHouseConfig.Addr},
+	options, err := newClickHouseOptions(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := clickhouse.OpenDB(options)
+	if conn == nil {
+		return nil, errors.New("clickhouse connection failed")
+	}
+
+	return ClickHouseProvider{
+		client:         conn,
+		ExternalLabels: ds.Labels,
+	}, nil
+}
+
+// ValidateClickHouse 校验 ClickHouse 连接协议及压缩算法配置
+func ValidateClickHouse(c models.DsClickHouseConfig) error {
+	if _, err := getClickHouseProtocol(c.Protocol); err != nil {
+		return err
+	}
+	if _, err := getClickHouseCompression(c.Compression); err != nil {
+		return err
+	}
+	return nil
+}
+
+// newClickHouseOptions 根据数据源配置构建连接参数, 两种协议共用同一套查询及指纹处理逻辑
+func newClickHouseOptions(ds models.AlertDataSource) (*clickhouse.Options, error) {
+	c := ds.ClickHouseConfig
+
+	protocol, err := getClickHouseProtocol(c.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	compression, err := getClickHouseCompression(c.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &clickhouse.Options{
+		Protocol: protocol,
+		Addr:     []string{c.Addr},
 		Auth: clickhouse.Auth{
 			Username: ds.Auth.User,
 			Password: ds.Auth.Pass,
@@ -26,20 +72,62 @@ func NewClickHouseClient(ctx context.Context, ds models.AlertDataSource) (LogsFa
 		Settings: clickhouse.Settings{
 			"max_execution_time": 60,
 		},
-		DialTimeout: time.Second * time.Duration(ds.ClickHouseConfig.Timeout),
-	})
-	if conn == nil {
-		return nil, errors.New("clickhouse connection failed")
+		Compression: compression,
+		DialTimeout: time.Second * time.Duration(c.Timeout),
+	}
+	if c.ReadTimeout > 0 {
+		options.ReadTimeout = time.Second * time.Duration(c.ReadTimeout)
 	}
 
-	return ClickHouseProvider{
-		client:         conn,
-		ExternalLabels: ds.Labels,
-	}, nil
+	// HTTP 协议复用数据源的 TLS 及代理配置
+	if protocol == clickhouse.HTTP {
+		tlsConfig, err := NewTLSConfig(ds.HTTP.TLS)
+		if err != nil {
+			return nil, err
+		}
+		options.TLS = tlsConfig
+
+		proxy, err := newProxyFunc(ds.HTTP.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		options.HTTPProxyURL, err = proxy(&http.Request{URL: &url.URL{Scheme: "http", Host: c.Addr}})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return options, nil
+}
+
+// getClickHouseProtocol 解析连接协议, 未配置时沿用 native 协议, 已有数据源无需调整
+func getClickHouseProtocol(protocol string) (clickhouse.Protocol, error) {
+	switch strings.ToLower(protocol) {
+	case "", models.ClickHouseProtocolNative:
+		return clickhouse.Native, nil
+	case models.ClickHouseProtocolHTTP:
+		return clickhouse.HTTP, nil
+	default:
+		return clickhouse.Native, fmt.Errorf("不支持的 ClickHouse 连接协议: %s", protocol)
+	}
+}
+
+// getClickHouseCompression 解析压缩算法, 未配置时不压缩
+func getClickHouseCompression(method string) (*clickhouse.Compression, error) {
+	switch strings.ToLower(method) {
+	case "", "none":
+		return nil, nil
+	case "lz4":
+		return &clickhouse.Compression{Method: clickhouse.CompressionLZ4}, nil
+	case "zstd":
+		return &clickhouse.Compression{Method: clickhouse.CompressionZSTD}, nil
+	default:
+		return nil, fmt.Errorf("不支持的 ClickHouse 压缩算法: %s", method)
+	}
 }
 
-func (c ClickHouseProvider) Query(options LogQueryOptions) (Logs, int, error) {
-	rows, err := c.client.Query(options.ClickHouse.Query)
+func (c ClickHouseProvider) Query(ctx context.Context, options LogQueryOptions) (Logs, int, error) {
+	rows, err := c.client.QueryContext(ctx, options.ClickHouse.Query)
 	if err != nil {
 		return Logs{}, 0, err
 	}
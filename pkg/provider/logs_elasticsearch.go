@@ -5,8 +5,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"watchAlert/internal/models"
-	"watchAlert/pkg/tools"
 
 	"github.com/bytedance/sonic"
 	"github.com/olivere/elastic/v7"
@@ -14,6 +16,7 @@ import (
 
 type ElasticSearchDsProvider struct {
 	Cli            *elastic.Client
+	httpClient     *http.Client
 	Url            string
 	Username       string
 	Password       string
@@ -21,7 +24,13 @@ type ElasticSearchDsProvider struct {
 }
 
 func NewElasticSearchClient(ctx context.Context, ds models.AlertDataSource) (LogsFactoryProvider, error) {
+	httpClient, err := newHTTPClient(ds, ds.HTTP.Timeout)
+	if err != nil {
+		return ElasticSearchDsProvider{}, err
+	}
+
 	client, err := elastic.NewClient(
+		elastic.SetHttpClient(httpClient),
 		elastic.SetURL(ds.HTTP.URL),
 		elastic.SetBasicAuth(ds.Auth.User, ds.Auth.Pass),
 		elastic.SetSniff(false),
@@ -32,6 +41,7 @@ func NewElasticSearchClient(ctx context.Context, ds models.AlertDataSource) (Log
 
 	return ElasticSearchDsProvider{
 		Cli:            client,
+		httpClient:     httpClient,
 		Url:            ds.HTTP.URL,
 		Username:       ds.Auth.User,
 		Password:       ds.Auth.Pass,
@@ -43,7 +53,11 @@ type esQueryResponse struct {
 	Source map[string]interface{} `json:"_source"`
 }
 
-func (e ElasticSearchDsProvider) Query(options LogQueryOptions) (Logs, int, error) {
+func (e ElasticSearchDsProvider) Query(ctx context.Context, options LogQueryOptions) (Logs, int, error) {
+	if options.ElasticSearch.QueryLanguage == models.EsQueryLanguageEsql {
+		return e.queryEsql(ctx, options.ElasticSearch)
+	}
+
 	indexName := options.ElasticSearch.GetIndexName()
 	var query elastic.Query
 
@@ -95,7 +109,7 @@ func (e ElasticSearchDsProvider) Query(options LogQueryOptions) (Logs, int, erro
 		Index(indexName).
 		Query(query).
 		Pretty(true).
-		Do(context.Background())
+		Do(ctx)
 	if err != nil {
 		return Logs{}, 0, err
 	}
@@ -122,6 +136,66 @@ func (e ElasticSearchDsProvider) Query(options LogQueryOptions) (Logs, int, erro
 	}, len(response), nil
 }
 
+type esqlQueryResponse struct {
+	Columns []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"columns"`
+	Values [][]interface{} `json:"values"`
+}
+
+// queryEsql 通过 ES|QL 查询, 每一行结果按列名转换为一条日志, 聚合查询时每一行即为一个分组
+func (e ElasticSearchDsProvider) queryEsql(ctx context.Context, options Elasticsearch) (Logs, int, error) {
+	if options.EsQL == "" {
+		return Logs{}, 0, errors.New("ES|QL 语句为空")
+	}
+
+	body := map[string]interface{}{
+		"query": options.EsQL,
+	}
+	if options.Scope > 0 {
+		body["filter"] = map[string]interface{}{
+			"range": map[string]interface{}{
+				"@timestamp": map[string]interface{}{
+					"gte": fmt.Sprintf("now-%dm", options.Scope),
+					"lte": "now",
+				},
+			},
+		}
+	}
+
+	res, err := e.Cli.PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodPost,
+		Path:   "/_query",
+		Params: url.Values{"format": []string{"json"}},
+		Body:   body,
+	})
+	if err != nil {
+		return Logs{}, 0, err
+	}
+
+	var response esqlQueryResponse
+	if err := sonic.Unmarshal(res.Body, &response); err != nil {
+		return Logs{}, 0, fmt.Errorf("解析 ES|QL 查询结果失败: %w", err)
+	}
+
+	message := make([]map[string]interface{}, 0, len(response.Values))
+	for _, row := range response.Values {
+		m := make(map[string]interface{}, len(response.Columns))
+		for i, column := range response.Columns {
+			if i < len(row) {
+				m[column.Name] = row[i]
+			}
+		}
+		message = append(message, m)
+	}
+
+	return Logs{
+		ProviderName: ElasticSearchDsProviderName,
+		Message:      message,
+	}, len(message), nil
+}
+
 func (e ElasticSearchDsProvider) Check() (bool, error) {
 	header := make(map[string]string)
 	url := fmt.Sprintf("%s/_cat/health", e.Url)
@@ -131,7 +205,7 @@ func (e ElasticSearchDsProvider) Check() (bool, error) {
 		header["Authorization"] = basicAuth
 		url = fmt.Sprintf("%s/_cat/health", e.Url)
 	}
-	res, err := tools.Get(header, url, 10)
+	res, err := httpGet(context.Background(), e.httpClient, header, url)
 	if err != nil {
 		return false, err
 	}
@@ -142,6 +216,39 @@ func (e ElasticSearchDsProvider) Check() (bool, error) {
 	return true, nil
 }
 
+// ValidateQuery 校验查询语句, RawJson 通过 _validate/query 接口由 ElasticSearch 解析, ES|QL 在评估范围内执行一次查询
+func (e ElasticSearchDsProvider) ValidateQuery(options Elasticsearch) error {
+	if options.QueryLanguage == models.EsQueryLanguageEsql {
+		_, _, err := e.queryEsql(context.Background(), options)
+		return err
+	}
+
+	if options.QueryType != models.EsQueryTypeRawJson {
+		return nil
+	}
+
+	explain := true
+	res, err := e.Cli.Validate(options.GetIndexName()).
+		BodyString(fmt.Sprintf(`{"query":%s}`, options.RawJson)).
+		Explain(&explain).
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if !res.Valid {
+		var explanations []string
+		for _, explanation := range res.Explanations {
+			if m, ok := explanation.(map[string]interface{}); ok && m["error"] != nil {
+				explanations = append(explanations, fmt.Sprintf("%v", m["error"]))
+			}
+		}
+		return fmt.Errorf("查询语句校验失败: %s", strings.Join(explanations, "; "))
+	}
+
+	return nil
+}
+
 func (e ElasticSearchDsProvider) GetExternalLabels() map[string]interface{} {
 	return e.ExternalLabels
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 	"watchAlert/internal/models"
 	"watchAlert/pkg/tools"
@@ -16,6 +18,7 @@ import (
 )
 
 type LokiProvider struct {
+	httpClient     *http.Client
 	Url            string
 	Timeout        int64
 	ExternalLabels map[string]interface{}
@@ -23,7 +26,13 @@ type LokiProvider struct {
 }
 
 func NewLokiClient(datasource models.AlertDataSource) (LogsFactoryProvider, error) {
+	httpClient, err := newHTTPClient(datasource, datasource.HTTP.Timeout)
+	if err != nil {
+		return LokiProvider{}, err
+	}
+
 	return LokiProvider{
+		httpClient:     httpClient,
 		Url:            datasource.HTTP.URL,
 		Timeout:        datasource.HTTP.Timeout,
 		ExternalLabels: datasource.Labels,
@@ -45,7 +54,7 @@ type Result struct {
 	Values []interface{}          `json:"values"`
 }
 
-func (l LokiProvider) Query(options LogQueryOptions) (Logs, int, error) {
+func (l LokiProvider) Query(ctx context.Context, options LogQueryOptions) (Logs, int, error) {
 	curTime := time.Now()
 
 	if options.Loki.Query == "" {
@@ -77,7 +86,7 @@ func (l LokiProvider) Query(options LogQueryOptions) (Logs, int, error) {
 		headers[key] = value
 	}
 
-	res, err := tools.Get(nil, requestURL, 10)
+	res, err := httpGet(ctx, l.httpClient, nil, requestURL)
 	if err != nil {
 		return Logs{}, 0, err
 	}
@@ -148,7 +157,7 @@ func (l LokiProvider) Check() (bool, error) {
 		headers[key] = value
 	}
 
-	res, err := tools.Get(nil, l.Url+"/loki/api/v1/labels", int(l.Timeout))
+	res, err := httpGet(context.Background(), l.httpClient, nil, l.Url+"/loki/api/v1/labels")
 	if err != nil {
 		return false, err
 	}
@@ -161,6 +170,22 @@ func (l LokiProvider) Check() (bool, error) {
 	return true, nil
 }
 
+// ValidateQuery 通过 format_query 接口由 Loki 解析 LogQL, 语法错误时返回 Loki 的错误信息
+func (l LokiProvider) ValidateQuery(query string) error {
+	res, err := httpGet(context.Background(), l.httpClient, l.Headers, l.Url+"/loki/api/v1/format_query?query="+url.QueryEscape(query))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("LogQL 校验失败, status: %d, err: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
 func (l LokiProvider) GetExternalLabels() map[string]interface{} {
 	return l.ExternalLabels
 }
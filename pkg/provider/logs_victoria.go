@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 	"time"
-	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/pkg/tools"
 
@@ -20,6 +19,7 @@ import (
 
 type (
 	VictoriaLogsProvider struct {
+		httpClient     *http.Client
 		URL            string         `json:"url"`
 		Timeout        int64          `json:"timeout"`
 		ExternalLabels map[string]any `json:"external_labels"`
@@ -32,7 +32,13 @@ type (
 
 // NewVictoriaLogsClient 创建一个新的 VictoriaLogsProvider 实例。
 func NewVictoriaLogsClient(ctx context.Context, datasource models.AlertDataSource) (LogsFactoryProvider, error) {
+	httpClient, err := newHTTPClient(datasource, datasource.HTTP.Timeout)
+	if err != nil {
+		return VictoriaLogsProvider{}, err
+	}
+
 	return VictoriaLogsProvider{
+		httpClient:     httpClient,
 		URL:            datasource.HTTP.URL,
 		Timeout:        datasource.HTTP.Timeout,
 		ExternalLabels: datasource.Labels,
@@ -43,7 +49,7 @@ func NewVictoriaLogsClient(ctx context.Context, datasource models.AlertDataSourc
 	}, nil
 }
 
-func (v VictoriaLogsProvider) Query(options LogQueryOptions) (Logs, int, error) {
+func (v VictoriaLogsProvider) Query(ctx context.Context, options LogQueryOptions) (Logs, int, error) {
 	curTime := time.Now()
 
 	if options.StartAt == "" || options.StartAt == nil {
@@ -69,10 +75,10 @@ func (v VictoriaLogsProvider) Query(options LogQueryOptions) (Logs, int, error)
 		headers[key] = value
 	}
 
-	res, err := tools.Get(headers, requestURL, 10)
+	res, err := httpGet(ctx, v.httpClient, headers, requestURL)
 
 	if err != nil {
-		logc.Error(ctx.Ctx, fmt.Sprintf("查询VictoriaLogs失败: %s", err.Error()))
+		logc.Error(ctx, fmt.Sprintf("查询VictoriaLogs失败: %s", err.Error()))
 		return Logs{}, 0, err
 	}
 
@@ -118,7 +124,7 @@ func (v VictoriaLogsProvider) Check() (bool, error) {
 		headers[key] = value
 	}
 
-	res, err := tools.Get(headers, v.URL+"/health", int(v.Timeout))
+	res, err := httpGet(context.Background(), v.httpClient, headers, v.URL+"/health")
 	if err != nil {
 		return false, err
 	}
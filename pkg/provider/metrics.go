@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -8,12 +9,13 @@ import (
 )
 
 const (
-	PrometheusDsProvider string = "Prometheus"
+	PrometheusDsProvider   string = "Prometheus"
+	InfluxDBDsProviderName string = "InfluxDB"
 )
 
 type MetricsFactoryProvider interface {
-	Query(promQL string) ([]Metrics, error)
-	QueryRange(promQL string, start, end time.Time, step time.Duration) ([]Metrics, error)
+	Query(ctx context.Context, promQL string) ([]Metrics, error)
+	QueryRange(ctx context.Context, promQL string, start, end time.Time, step time.Duration) ([]Metrics, error)
 	Check() (bool, error)
 	GetExternalLabels() map[string]interface{}
 }
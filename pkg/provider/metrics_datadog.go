@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	DatadogDsProviderName = "Datadog"
+
+	// 默认站点, 其他站点如 https://api.datadoghq.eu 通过数据源地址配置
+	datadogDefaultAddress = "https://api.datadoghq.com"
+	// 查询的时间窗口, 取窗口内每个序列的最后一个点作为当前值
+	datadogQueryWindow = 5 * time.Minute
+	// 触发限流后的最大重试次数及单次最长等待时间
+	datadogMaxRetries   = 3
+	datadogMaxRetryWait = 10 * time.Second
+)
+
+type DatadogProvider struct {
+	httpClient     *http.Client
+	Address        string
+	ApiKey         string
+	AppKey         string
+	Headers        map[string]string
+	ExternalLabels map[string]interface{}
+	queryCache     *queryCache
+}
+
+type datadogQueryResponse struct {
+	Status string          `json:"status"`
+	Error  string          `json:"error"`
+	Series []datadogSeries `json:"series"`
+}
+
+type datadogSeries struct {
+	Metric    string       `json:"metric"`
+	Scope     string       `json:"scope"`
+	TagSet    []string     `json:"tag_set"`
+	Pointlist [][]*float64 `json:"pointlist"`
+}
+
+func NewDatadogClient(ds models.AlertDataSource) (DatadogProvider, error) {
+	if ds.DatadogConfig.ApiKey == "" || ds.DatadogConfig.AppKey == "" {
+		return DatadogProvider{}, fmt.Errorf("Datadog API Key 及 Application Key 不能为空")
+	}
+
+	httpClient, err := newHTTPClient(ds, ds.HTTP.Timeout)
+	if err != nil {
+		return DatadogProvider{}, err
+	}
+
+	address := strings.TrimSuffix(ds.HTTP.URL, "/")
+	if address == "" {
+		address = datadogDefaultAddress
+	}
+
+	return DatadogProvider{
+		httpClient:     httpClient,
+		Address:        address,
+		ApiKey:         ds.DatadogConfig.ApiKey,
+		AppKey:         ds.DatadogConfig.AppKey,
+		Headers:        ds.HTTP.Headers,
+		ExternalLabels: ds.Labels,
+		queryCache:     newQueryCache(ds),
+	}, nil
+}
+
+func (d DatadogProvider) headers() map[string]string {
+	headers := tools.MergeHeaders(nil, d.Headers)
+	headers["DD-API-KEY"] = d.ApiKey
+	headers["DD-APPLICATION-KEY"] = d.AppKey
+	return headers
+}
+
+// Query 查询最近时间窗口内的指标, 每个序列取最后一个点作为当前值, tag_set 作为标签
+func (d DatadogProvider) Query(ctx context.Context, query string) ([]Metrics, error) {
+	return d.queryCache.get(instantQueryKey(query), func() ([]Metrics, error) {
+		return d.query(ctx, query)
+	})
+}
+
+func (d DatadogProvider) query(ctx context.Context, query string) ([]Metrics, error) {
+	now := time.Now()
+	params := url.Values{}
+	params.Set("from", strconv.FormatInt(now.Add(-datadogQueryWindow).Unix(), 10))
+	params.Set("to", strconv.FormatInt(now.Unix(), 10))
+	params.Set("query", query)
+
+	res, err := d.get(ctx, fmt.Sprintf("%s/api/v1/query?%s", d.Address, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Datadog 查询失败, status: %d, body: %s", res.StatusCode, string(body))
+	}
+
+	var resp datadogQueryResponse
+	if err := sonic.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析 Datadog 查询结果失败: %w", err)
+	}
+	if resp.Status == "error" {
+		return nil, fmt.Errorf("Datadog 查询失败: %s", resp.Error)
+	}
+
+	var metrics []Metrics
+	for _, series := range resp.Series {
+		timestamp, value, ok := series.lastPoint()
+		if !ok {
+			continue
+		}
+
+		labels := map[string]interface{}{
+			"__name__": series.Metric,
+		}
+		for _, tag := range series.TagSet {
+			if k, v, found := strings.Cut(tag, ":"); found {
+				labels[k] = v
+			}
+		}
+
+		metrics = append(metrics, Metrics{
+			Metric:    labels,
+			Value:     value,
+			Timestamp: timestamp / 1000,
+		})
+	}
+
+	return metrics, nil
+}
+
+// lastPoint 获取序列中最后一个非空的点, 时间戳单位为毫秒
+func (s datadogSeries) lastPoint() (float64, float64, bool) {
+	for i := len(s.Pointlist) - 1; i >= 0; i-- {
+		point := s.Pointlist[i]
+		if len(point) == 2 && point[0] != nil && point[1] != nil {
+			return *point[0], *point[1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// get 发送请求, 触发限流时按 X-RateLimit-Reset 等待后重试, 未返回该响应头时指数退避
+func (d DatadogProvider) get(ctx context.Context, requestURL string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := httpGet(ctx, d.httpClient, d.headers(), requestURL)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusTooManyRequests || attempt >= datadogMaxRetries {
+			return res, nil
+		}
+		res.Body.Close()
+
+		wait := time.Second << attempt
+		if reset, err := strconv.Atoi(res.Header.Get("X-RateLimit-Reset")); err == nil && reset > 0 {
+			wait = time.Duration(reset) * time.Second
+		}
+		wait = min(wait, datadogMaxRetryWait)
+
+		logc.Errorf(ctx, "Datadog 查询触发限流, %s 后重试, 第 %d 次", wait, attempt+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (d DatadogProvider) Check() (bool, error) {
+	checkURL := d.Address + "/api/v1/validate"
+	res, err := httpGet(context.Background(), d.httpClient, d.headers(), checkURL)
+	if err != nil {
+		logc.Errorf(context.Background(), "Health check failed, URL: %s, Error: %v", checkURL, err)
+		return false, fmt.Errorf("health check failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		logc.Errorf(context.Background(), "Health check received unhealthy status: %d, URL: %s", res.StatusCode, checkURL)
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+func (d DatadogProvider) GetExternalLabels() map[string]interface{} {
+	return d.ExternalLabels
+}
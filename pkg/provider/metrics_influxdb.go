@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// InfluxDBBucketPlaceholder Flux 语句中的 Bucket 占位符, 查询时替换为数据源配置的 Bucket
+const InfluxDBBucketPlaceholder = "${bucket}"
+
+type InfluxDBProvider struct {
+	httpClient     *http.Client
+	Address        string
+	Org            string
+	Bucket         string
+	Token          string
+	Headers        map[string]string
+	Timeout        int64
+	ExternalLabels map[string]interface{}
+	queryCache     *queryCache
+}
+
+func NewInfluxDBClient(ds models.AlertDataSource) (InfluxDBProvider, error) {
+	if ds.InfluxDBConfig.Org == "" {
+		return InfluxDBProvider{}, fmt.Errorf("InfluxDB org 不能为空")
+	}
+
+	timeout := ds.HTTP.Timeout
+	if timeout <= 0 {
+		timeout = 10
+	}
+
+	httpClient, err := newHTTPClient(ds, timeout)
+	if err != nil {
+		return InfluxDBProvider{}, err
+	}
+
+	return InfluxDBProvider{
+		httpClient:     httpClient,
+		Address:        strings.TrimSuffix(ds.HTTP.URL, "/"),
+		Org:            ds.InfluxDBConfig.Org,
+		Bucket:         ds.InfluxDBConfig.Bucket,
+		Token:          ds.InfluxDBConfig.Token,
+		Headers:        ds.HTTP.Headers,
+		Timeout:        timeout,
+		ExternalLabels: ds.Labels,
+		queryCache:     newQueryCache(ds),
+	}, nil
+}
+
+func (i InfluxDBProvider) headers() map[string]string {
+	headers := tools.MergeHeaders(nil, i.Headers)
+	if i.Token != "" {
+		headers["Authorization"] = "Token " + i.Token
+	}
+	return headers
+}
+
+// Query 执行 Flux 查询, 每个结果表取最后一行作为当前值
+// Query Flux 语句中的 range 即为时间窗口, 缓存键仅包含查询语句
+func (i InfluxDBProvider) Query(ctx context.Context, flux string) ([]Metrics, error) {
+	return i.queryCache.get(instantQueryKey(flux), func() ([]Metrics, error) {
+		return i.query(ctx, flux)
+	})
+}
+
+func (i InfluxDBProvider) query(ctx context.Context, flux string) ([]Metrics, error) {
+	body, err := sonic.Marshal(map[string]interface{}{
+		"query": strings.ReplaceAll(flux, InfluxDBBucketPlaceholder, i.Bucket),
+		"type":  "flux",
+		"dialect": map[string]interface{}{
+			"header":      true,
+			"annotations": []string{},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v2/query?org=%s", i.Address, url.QueryEscape(i.Org))
+	headers := i.headers()
+	headers["Accept"] = "application/csv"
+	res, err := httpPost(ctx, i.httpClient, headers, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("InfluxDB 查询失败, status: %d, body: %s", res.StatusCode, string(respBody))
+	}
+
+	return parseFluxCSV(res.Body)
+}
+
+// parseFluxCSV 解析 Flux 返回的 CSV 结果, _value 作为值, 非内置列作为标签
+func parseFluxCSV(r io.Reader) ([]Metrics, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var (
+		header []string
+		order  []string
+		tables = make(map[string]Metrics)
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 InfluxDB 查询结果失败: %w", err)
+		}
+
+		// 不同结构的结果表之间会重新输出表头
+		if len(record) > 2 && record[1] == "result" && record[2] == "table" {
+			header = record
+			continue
+		}
+		if header == nil {
+			continue
+		}
+
+		var (
+			table  string
+			value  = math.NaN()
+			ts     float64
+			metric = make(map[string]interface{})
+		)
+		for idx, column := range header {
+			if idx >= len(record) {
+				break
+			}
+			switch column {
+			case "", "result", "_start", "_stop":
+			case "table":
+				table = record[idx]
+			case "_value":
+				if v, err := strconv.ParseFloat(record[idx], 64); err == nil {
+					value = v
+				}
+			case "_time":
+				if t, err := time.Parse(time.RFC3339Nano, record[idx]); err == nil {
+					ts = float64(t.Unix())
+				}
+			default:
+				metric[column] = record[idx]
+			}
+		}
+
+		if math.IsNaN(value) {
+			continue
+		}
+
+		key := strings.Join(header, ",") + "/" + table
+		if _, ok := tables[key]; !ok {
+			order = append(order, key)
+		}
+		tables[key] = Metrics{
+			Metric:    metric,
+			Value:     value,
+			Timestamp: ts,
+		}
+	}
+
+	metrics := make([]Metrics, 0, len(order))
+	for _, key := range order {
+		metrics = append(metrics, tables[key])
+	}
+
+	return metrics, nil
+}
+
+func (i InfluxDBProvider) Check() (bool, error) {
+	checkURL := i.Address + "/health"
+	res, err := httpGet(context.Background(), i.httpClient, i.headers(), checkURL)
+	if err != nil {
+		logc.Errorf(context.Background(), "Health check failed, URL: %s, Error: %v", checkURL, err)
+		return false, fmt.Errorf("health check failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		logc.Errorf(context.Background(), "Health check received unhealthy status: %d, URL: %s", res.StatusCode, checkURL)
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+func (i InfluxDBProvider) GetExternalLabels() map[string]interface{} {
+	return i.ExternalLabels
+}
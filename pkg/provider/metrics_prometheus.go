@@ -18,12 +18,14 @@ import (
 
 type PrometheusProvider struct {
 	client         v1.API
+	httpClient     *http.Client
 	ExternalLabels map[string]interface{}
 	Address        string
 	Username       string
 	Password       string
 	Headers        map[string]string
 	Timeout        int64
+	queryCache     *queryCache
 }
 
 // authenticatedTransport 包装 http.RoundTripper 以添加认证头和额外的headers
@@ -48,12 +50,11 @@ func (t *authenticatedTransport) RoundTrip(req *http.Request) (*http.Response, e
 }
 
 func NewPrometheusClient(ds models.AlertDataSource) (MetricsFactoryProvider, error) {
-	transport := &http.Transport{
-		Proxy:               http.ProxyFromEnvironment,
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
+	httpClient, err := newHTTPClient(ds, ds.HTTP.Timeout)
+	if err != nil {
+		return nil, err
 	}
+	transport := httpClient.Transport
 
 	var roundTripper http.RoundTripper = transport
 	if ds.Auth.User != "" || ds.Auth.Pass != "" || len(ds.HTTP.Headers) > 0 {
@@ -77,12 +78,14 @@ func NewPrometheusClient(ds models.AlertDataSource) (MetricsFactoryProvider, err
 
 	return PrometheusProvider{
 		client:         v1.NewAPI(client),
+		httpClient:     httpClient,
 		Address:        ds.HTTP.URL,
 		ExternalLabels: ds.Labels,
 		Username:       ds.Auth.User,
 		Password:       ds.Auth.Pass,
 		Headers:        ds.HTTP.Headers,
 		Timeout:        ds.HTTP.Timeout,
+		queryCache:     newQueryCache(ds),
 	}, nil
 }
 
@@ -102,8 +105,14 @@ type MetricResult struct {
 	Values [][]interface{}        `json:"values"`
 }
 
-func (v PrometheusProvider) Query(promQL string) ([]Metrics, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(v.Timeout)*time.Second)
+func (v PrometheusProvider) Query(ctx context.Context, promQL string) ([]Metrics, error) {
+	return v.queryCache.get(instantQueryKey(promQL), func() ([]Metrics, error) {
+		return v.query(ctx, promQL)
+	})
+}
+
+func (v PrometheusProvider) query(ctx context.Context, promQL string) ([]Metrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(v.Timeout)*time.Second)
 	defer cancel()
 	result, _, err := v.client.Query(ctx, promQL, time.Now(), v1.WithTimeout(time.Duration(v.Timeout)*time.Second))
 	if err != nil {
@@ -112,8 +121,14 @@ func (v PrometheusProvider) Query(promQL string) ([]Metrics, error) {
 	return Vectors(result), nil
 }
 
-func (v PrometheusProvider) QueryRange(promQL string, start, end time.Time, step time.Duration) ([]Metrics, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(v.Timeout)*time.Second)
+func (v PrometheusProvider) QueryRange(ctx context.Context, promQL string, start, end time.Time, step time.Duration) ([]Metrics, error) {
+	return v.queryCache.get(rangeQueryKey(promQL, start, end, step), func() ([]Metrics, error) {
+		return v.queryRange(ctx, promQL, start, end, step)
+	})
+}
+
+func (v PrometheusProvider) queryRange(ctx context.Context, promQL string, start, end time.Time, step time.Duration) ([]Metrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(v.Timeout)*time.Second)
 	defer cancel()
 
 	r := v1.Range{
@@ -195,7 +210,7 @@ func (v PrometheusProvider) Check() (bool, error) {
 		headers = tools.CreateBasicAuthHeader(v.Username, v.Password)
 	}
 	headers = tools.MergeHeaders(headers, v.Headers)
-	res, err := tools.Get(headers, checkURL, int(v.Timeout))
+	res, err := httpGet(context.Background(), v.httpClient, headers, checkURL)
 	if err != nil {
 		logc.Errorf(context.Background(), "Health check failed, URL: %s, Error: %v", checkURL, err)
 		return false, fmt.Errorf("health check failed: %w", err)
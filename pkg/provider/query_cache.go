@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"watchAlert/internal/models"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// queryCacheMetrics 查询缓存命中情况的指标上报, 启动时通过 SetQueryCacheMetrics 注入
+var queryCacheMetrics interface {
+	IncQueryCache(datasourceId string, hit bool)
+}
+
+// SetQueryCacheMetrics 设置查询缓存命中情况的指标上报
+func SetQueryCacheMetrics(m interface {
+	IncQueryCache(datasourceId string, hit bool)
+}) {
+	queryCacheMetrics = m
+}
+
+type queryResult struct {
+	metrics  []Metrics
+	expireAt time.Time
+}
+
+// queryCache 按数据源缓存查询结果, 多个规则在 TTL 内执行相同查询时复用结果; 查询失败不缓存
+type queryCache struct {
+	datasourceId string
+	ttl          time.Duration
+
+	mu        sync.RWMutex
+	results   map[string]queryResult
+	lastSweep time.Time
+	group     singleflight.Group
+}
+
+// newQueryCache 数据源未开启查询缓存时返回 nil
+func newQueryCache(ds models.AlertDataSource) *queryCache {
+	if ds.QueryCacheTTL <= 0 {
+		return nil
+	}
+
+	return &queryCache{
+		datasourceId: ds.ID,
+		ttl:          time.Duration(ds.QueryCacheTTL) * time.Second,
+		results:      make(map[string]queryResult),
+		lastSweep:    time.Now(),
+	}
+}
+
+// instantQueryKey 即时查询以当前时间执行, 缓存键仅包含查询语句
+func instantQueryKey(query string) string {
+	return "instant|" + query
+}
+
+// rangeQueryKey 范围查询以查询时长及步长作为时间窗口, 相对当前时间的查询在 TTL 内可复用
+func rangeQueryKey(query string, start, end time.Time, step time.Duration) string {
+	return fmt.Sprintf("range|%s|%s|%s", end.Sub(start), step, query)
+}
+
+// get 缓存未过期时直接返回结果, 否则执行查询; 并发执行相同查询时只请求一次数据源
+func (c *queryCache) get(key string, fn func() ([]Metrics, error)) ([]Metrics, error) {
+	if c == nil {
+		return fn()
+	}
+
+	c.mu.RLock()
+	result, ok := c.results[key]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(result.expireAt) {
+		c.observe(true)
+		return result.metrics, nil
+	}
+
+	c.observe(false)
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		metrics, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		c.mu.Lock()
+		c.results[key] = queryResult{metrics: metrics, expireAt: now.Add(c.ttl)}
+		c.sweep(now)
+		c.mu.Unlock()
+
+		return metrics, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]Metrics), nil
+}
+
+// sweep 每个 TTL 周期清理一次过期结果, 调用方需持有写锁
+func (c *queryCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+
+	for key, result := range c.results {
+		if now.After(result.expireAt) {
+			delete(c.results, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+func (c *queryCache) observe(hit bool) {
+	if queryCacheMetrics != nil {
+		queryCacheMetrics.IncQueryCache(c.datasourceId, hit)
+	}
+}
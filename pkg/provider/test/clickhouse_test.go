@@ -24,7 +24,7 @@ func TestNewClickHouseClient(t *testing.T) {
 	}
 	cli.Check()
 
-	cli.Query(provider.LogQueryOptions{ClickHouse: provider.ClickHouse{
+	cli.Query(context.Background(), provider.LogQueryOptions{ClickHouse: provider.ClickHouse{
 		Query: "SELECT * FROM zprod.express WHERE `_time_second_`='2025-05-29 00:00:00';",
 	}})
 }
@@ -16,7 +16,7 @@ func TestKubernetesClient(t *testing.T) {
 		return
 	}
 
-	event, err := cli.GetWarningEvent("", 10, []string{})
+	event, err := cli.GetWarningEvent(provider.KubernetesEventQuery{Scope: 10})
 	if err != nil {
 		logrus.Errorf(err.Error())
 		return
@@ -17,7 +17,7 @@ func TestNewElasticSearchClient(t *testing.T) {
 		return
 	}
 
-	client.Query(provider.LogQueryOptions{})
+	client.Query(context.Background(), provider.LogQueryOptions{})
 }
 
 func TestElasticsearch_GetIndexName(t *testing.T) {
@@ -57,7 +57,7 @@ func TestElasticSearch_Query(t *testing.T) {
 		return
 	}
 
-	query, _, err := client.Query(provider.LogQueryOptions{ElasticSearch: provider.Elasticsearch{
+	query, _, err := client.Query(context.Background(), provider.LogQueryOptions{ElasticSearch: provider.Elasticsearch{
 		Index:     "test-2024-05.20",
 		QueryType: "RawJson",
 		RawJson:   `{"match_all":{}}`,
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"watchAlert/internal/models"
@@ -9,10 +10,11 @@ import (
 
 const (
 	JaegerDsProviderName string = "Jaeger"
+	TempoDsProviderName  string = "Tempo"
 )
 
 type TracesFactoryProvider interface {
-	Query(options TraceQueryOptions) ([]Traces, error)
+	Query(ctx context.Context, options TraceQueryOptions) ([]Traces, error)
 	Check() (bool, error)
 	GetJaegerService() (JaegerServiceData, error)
 	GetExternalLabels() map[string]interface{}
@@ -27,8 +29,9 @@ type TraceQueryOptions struct {
 }
 
 type Traces struct {
-	Service string
-	TraceId string
+	Service   string
+	Operation string
+	TraceId   string
 }
 
 func (t Traces) GetFingerprint() string {
@@ -1,8 +1,10 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"time"
 	"watchAlert/internal/models"
@@ -10,17 +12,25 @@ import (
 )
 
 type JaegerDsProvider struct {
+	httpClient     *http.Client
 	ExternalLabels map[string]interface{}
 	url            string
 }
 
 func NewJaegerClient(datasource models.AlertDataSource) (TracesFactoryProvider, error) {
-	_, err := tools.Get(nil, datasource.HTTP.URL, 10)
+	httpClient, err := newHTTPClient(datasource, datasource.HTTP.Timeout)
 	if err != nil {
 		return JaegerDsProvider{}, err
 	}
 
+	res, err := httpGet(context.Background(), httpClient, nil, datasource.HTTP.URL)
+	if err != nil {
+		return JaegerDsProvider{}, err
+	}
+	res.Body.Close()
+
 	return JaegerDsProvider{
+		httpClient:     httpClient,
 		url:            datasource.HTTP.URL,
 		ExternalLabels: datasource.Labels,
 	}, nil
@@ -34,7 +44,7 @@ type JaegerData struct {
 	TraceId string `json:"traceID"`
 }
 
-func (j JaegerDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
+func (j JaegerDsProvider) Query(ctx context.Context, options TraceQueryOptions) ([]Traces, error) {
 	curTime := time.Now()
 
 	if options.Limit == 0 {
@@ -52,7 +62,7 @@ func (j JaegerDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
 
 	args := fmt.Sprintf("/api/traces?service=%s&start=%d&end=%d&limit=%d&tags=%s", options.Service, options.StartAt, options.EndAt, options.Limit, options.Tags)
 	requestURL := j.url + args
-	res, err := tools.Get(nil, requestURL, 10)
+	res, err := httpGet(ctx, j.httpClient, nil, requestURL)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +84,7 @@ func (j JaegerDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
 }
 
 func (j JaegerDsProvider) Check() (bool, error) {
-	res, err := tools.Get(nil, j.url, 10)
+	res, err := httpGet(context.Background(), j.httpClient, nil, j.url)
 	if err != nil {
 		return false, err
 	}
@@ -91,7 +101,7 @@ type JaegerServiceData struct {
 
 func (j JaegerDsProvider) GetJaegerService() (JaegerServiceData, error) {
 	url := j.url + "/api/services"
-	res, err := tools.Get(nil, url, 10)
+	res, err := httpGet(context.Background(), j.httpClient, nil, url)
 	if err != nil {
 		return JaegerServiceData{}, err
 	}
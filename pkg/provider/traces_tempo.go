@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type TempoDsProvider struct {
+	httpClient     *http.Client
+	ExternalLabels map[string]interface{}
+	url            string
+	headers        map[string]string
+	timeout        int
+}
+
+func NewTempoClient(datasource models.AlertDataSource) (TracesFactoryProvider, error) {
+	timeout := int(datasource.HTTP.Timeout)
+	if timeout <= 0 {
+		timeout = 10
+	}
+
+	httpClient, err := newHTTPClient(datasource, int64(timeout))
+	if err != nil {
+		return TempoDsProvider{}, err
+	}
+
+	return TempoDsProvider{
+		httpClient:     httpClient,
+		url:            strings.TrimSuffix(datasource.HTTP.URL, "/"),
+		headers:        tools.MergeHeaders(tools.CreateBasicAuthHeader(datasource.Auth.User, datasource.Auth.Pass), datasource.HTTP.Headers),
+		timeout:        timeout,
+		ExternalLabels: datasource.Labels,
+	}, nil
+}
+
+type TempoSearchResult struct {
+	Traces []TempoTrace `json:"traces"`
+}
+
+type TempoTrace struct {
+	TraceId         string `json:"traceID"`
+	RootServiceName string `json:"rootServiceName"`
+	RootTraceName   string `json:"rootTraceName"`
+}
+
+// BuildTraceQL 根据服务名称和过滤条件拼接 TraceQL, 未配置过滤条件时匹配所有链路
+func BuildTraceQL(service, filter string) string {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		filter = "{}"
+	}
+	if service == "" {
+		return filter
+	}
+
+	return fmt.Sprintf(`{ resource.service.name = "%s" } && %s`, service, filter)
+}
+
+// Query 通过 TraceQL 搜索链路, Tags 字段为 TraceQL 过滤条件, StartAt/EndAt 单位为微秒
+func (t TempoDsProvider) Query(ctx context.Context, options TraceQueryOptions) ([]Traces, error) {
+	curTime := time.Now()
+
+	if options.Limit == 0 {
+		options.Limit = 1000
+	}
+
+	if options.StartAt == 0 {
+		options.StartAt = curTime.Add(-time.Hour).UnixMicro()
+	}
+
+	if options.EndAt == 0 {
+		options.EndAt = curTime.UnixMicro()
+	}
+
+	args := fmt.Sprintf("/api/search?q=%s&start=%d&end=%d&limit=%d",
+		url.QueryEscape(BuildTraceQL(options.Service, options.Tags)),
+		time.UnixMicro(options.StartAt).Unix(),
+		time.UnixMicro(options.EndAt).Unix(),
+		options.Limit,
+	)
+	res, err := httpGet(ctx, t.httpClient, t.headers, t.url+args)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("Tempo 查询失败, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var result TempoSearchResult
+	if err := tools.ParseReaderBody(res.Body, &result); err != nil {
+		return nil, err
+	}
+
+	var data []Traces
+	for _, tr := range result.Traces {
+		data = append(data, Traces{
+			Service:   tr.RootServiceName,
+			Operation: tr.RootTraceName,
+			TraceId:   tr.TraceId,
+		})
+	}
+
+	return data, nil
+}
+
+func (t TempoDsProvider) Check() (bool, error) {
+	res, err := httpGet(context.Background(), t.httpClient, t.headers, t.url+"/ready")
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+type TempoTagValues struct {
+	TagValues []string `json:"tagValues"`
+}
+
+// GetJaegerService 获取服务列表, 与 Jaeger 返回结构保持一致
+func (t TempoDsProvider) GetJaegerService() (JaegerServiceData, error) {
+	res, err := httpGet(context.Background(), t.httpClient, t.headers, t.url+"/api/search/tag/service.name/values")
+	if err != nil {
+		return JaegerServiceData{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return JaegerServiceData{}, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var values TempoTagValues
+	if err := tools.ParseReaderBody(res.Body, &values); err != nil {
+		return JaegerServiceData{}, fmt.Errorf("json.Unmarshal failed, %s", err.Error())
+	}
+
+	return JaegerServiceData{Data: values.TagValues}, nil
+}
+
+func (t TempoDsProvider) GetExternalLabels() map[string]interface{} {
+	return t.ExternalLabels
+}
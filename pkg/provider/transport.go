@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ValidateHTTP 校验数据源的 TLS 及代理配置
+func ValidateHTTP(c models.HTTP) error {
+	if _, err := NewTLSConfig(c.TLS); err != nil {
+		return fmt.Errorf("TLS 配置无效, %s", err.Error())
+	}
+	if _, err := newProxyFunc(c.Proxy); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 按数据源 ID 及配置摘要缓存 HTTP Transport, 证书只解析一次, 共享数据源的规则复用连接池
+var transports = struct {
+	sync.Mutex
+	m map[string]*http.Transport
+}{m: make(map[string]*http.Transport)}
+
+// NewTLSConfig 解析数据源 TLS 配置, 未配置时返回 nil
+func NewTLSConfig(c models.TLS) (*tls.Config, error) {
+	if !c.IsEnabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+			return nil, fmt.Errorf("CA 证书解析失败, 请检查是否为 PEM 格式")
+		}
+		config.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("客户端证书与私钥加载失败: %s", err.Error())
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// newHTTPTransport 获取数据源的 HTTP Transport, 配置未变化时复用已创建的 Transport
+func newHTTPTransport(ds models.AlertDataSource) (*http.Transport, error) {
+	key := ds.ID + ":" + transportHash(ds.HTTP)
+
+	transports.Lock()
+	defer transports.Unlock()
+
+	if t, ok := transports.m[key]; ok {
+		return t, nil
+	}
+
+	tlsConfig, err := NewTLSConfig(ds.HTTP.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := newProxyFunc(ds.HTTP.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdleConns, maxIdleConnsPerHost, idleConnTimeout := getPoolConfig()
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		Proxy:               proxy,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+
+	// 数据源未保存时不缓存, 避免预览及测试连接产生无法回收的 Transport
+	if ds.ID != "" {
+		removeTransports(ds.ID)
+		transports.m[key] = transport
+	}
+
+	return transport, nil
+}
+
+// RemoveTransport 关闭并移除数据源的 HTTP Transport, 数据源删除后调用
+func RemoveTransport(datasourceId string) {
+	transports.Lock()
+	defer transports.Unlock()
+
+	removeTransports(datasourceId)
+}
+
+// removeTransports 移除数据源所有配置版本的 Transport, 调用方需持有锁
+func removeTransports(datasourceId string) {
+	for k, t := range transports.m {
+		if strings.HasPrefix(k, datasourceId+":") {
+			t.CloseIdleConnections()
+			delete(transports.m, k)
+		}
+	}
+}
+
+// getPoolConfig 获取连接池配置, 未配置时使用默认值
+func getPoolConfig() (int, int, time.Duration) {
+	c := config.Application.Provider
+
+	maxIdleConns := c.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = 100
+	}
+	maxIdleConnsPerHost := c.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = 32
+	}
+	idleConnTimeout := c.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = 90
+	}
+
+	return maxIdleConns, maxIdleConnsPerHost, time.Duration(idleConnTimeout) * time.Second
+}
+
+// newHTTPClient 使用数据源 Transport 创建 HTTP Client
+func newHTTPClient(ds models.AlertDataSource, timeout int64) (*http.Client, error) {
+	transport, err := newHTTPTransport(ds)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = 10
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(timeout) * time.Second,
+	}, nil
+}
+
+// newProxyFunc 数据源配置了代理地址时优先使用, 仍遵循 NO_PROXY 环境变量; 未配置时完全使用环境变量
+func newProxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("代理地址无效: %s", proxy)
+	}
+
+	config := httpproxy.Config{
+		HTTPProxy:  proxy,
+		HTTPSProxy: proxy,
+		NoProxy:    httpproxy.FromEnvironment().NoProxy,
+	}
+	proxyFunc := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// transportHash 计算影响 Transport 的配置摘要, 配置变化时重新创建 Transport
+func transportHash(c models.HTTP) string {
+	sum := sha256.Sum256([]byte(tools.JsonMarshalToString(struct {
+		TLS   models.TLS
+		Proxy string
+	}{c.TLS, c.Proxy})))
+	return hex.EncodeToString(sum[:8])
+}
+
+// httpGet 使用数据源 HTTP Client 发送 GET 请求
+func httpGet(ctx context.Context, client *http.Client, headers map[string]string, url string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+
+	return client.Do(request)
+}
+
+// httpPost 使用数据源 HTTP Client 发送 JSON POST 请求
+func httpPost(ctx context.Context, client *http.Client, headers map[string]string, url string, body io.Reader) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+
+	return client.Do(request)
+}
@@ -38,3 +38,9 @@ func PermissionFail(ctx *gin.Context) {
 	code := 403
 	Response(ctx, code, code, CodeInfo[int64(code)], "failed")
 }
+
+// PermissionDenied 缺少细粒度权限, 返回缺少的权限名称
+func PermissionDenied(ctx *gin.Context, permission string) {
+	code := 403
+	Response(ctx, code, code, CodeInfo[int64(code)]+", 缺少权限: "+permission, "failed")
+}
@@ -3,7 +3,9 @@ package sender
 import (
 	"fmt"
 	"time"
+	"watchAlert/config"
 	"watchAlert/internal/ctx"
+	"watchAlert/pkg/tools"
 
 	"github.com/bytedance/sonic"
 
@@ -34,6 +36,22 @@ type (
 		Content string
 		// 签名
 		Sign string `json:"sign,omitempty"`
+		// Telegram 会话 ID
+		ChatId string `json:"chatId,omitempty"`
+		// PagerDuty Routing Key
+		RoutingKey string `json:"routingKey,omitempty"`
+		// 路由在通知对象中的序号
+		RouteIndex int `json:"-"`
+		// 通过用户个人通知渠道发送, NoticeId 为用户 ID
+		UserChannel bool `json:"-"`
+		// 发送结果, 不为空时由发送器回填
+		Result *SendResult `json:"-"`
+	}
+
+	// SendResult 发送成功后由外部平台返回的信息
+	SendResult struct {
+		// PagerDuty 返回的 incident key
+		IncidentKey string
 	}
 
 	// SendInter 发送通知的接口
@@ -43,7 +61,12 @@ type (
 	}
 )
 
-const RobotTestContent = "这是一条来自 WatchAlert 的测试消息"
+const (
+	RobotTestContent = "这是一条来自 WatchAlert 的测试消息"
+
+	// 重试退避时间上限
+	maxRetryBackoff = time.Minute
+)
 
 // Sender 发送通知的主函数
 func Sender(ctx *ctx.Context, sendParams SendParams) error {
@@ -53,10 +76,29 @@ func Sender(ctx *ctx.Context, sendParams SendParams) error {
 		return fmt.Errorf("Send alarm failed, %s", err.Error())
 	}
 
-	// 发送通知
-	if err := sender.Send(sendParams); err != nil {
-		addRecord(ctx, sendParams, 1, sendParams.Content, err.Error())
-		return fmt.Errorf("Send alarm failed to %s, err: %s", sendParams.NoticeType, err.Error())
+	// 发送通知, 失败时按指数退避重试, 最终失败的通知进入死信队列
+	attempts, backoff := getRetryPolicy()
+	var sendErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		// 渠道限速, 超出速率时延迟发送
+		waitRateLimit(ctx, sendParams)
+
+		sendErr = sender.Send(sendParams)
+		if sendErr == nil {
+			break
+		}
+
+		if attempt < attempts {
+			logc.Errorf(ctx.Ctx, "Send alarm failed to %s, attempt: %d/%d, retry after %s, err: %s", sendParams.NoticeType, attempt, attempts, backoff, sendErr.Error())
+			time.Sleep(backoff)
+			backoff = min(backoff*2, maxRetryBackoff)
+		}
+	}
+
+	if sendErr != nil {
+		addRecord(ctx, sendParams, 1, sendParams.Content, sendErr.Error())
+		pushDeadLetter(ctx, sendParams, attempts, sendErr)
+		return fmt.Errorf("Send alarm failed to %s, err: %s", sendParams.NoticeType, sendErr.Error())
 	}
 
 	// 记录成功发送的日志
@@ -95,11 +137,104 @@ func senderFactory(noticeType string) (SendInter, error) {
 		return NewWebHookSender(), nil
 	case "Slack":
 		return NewSlackSender(), nil
+	case "Telegram":
+		return NewTelegramSender(), nil
+	case "Teams":
+		return NewTeamsSender(), nil
+	case "PagerDuty":
+		return NewPagerDutySender(), nil
 	default:
 		return nil, fmt.Errorf("无效的通知类型: %s", noticeType)
 	}
 }
 
+// getRetryPolicy 获取发送失败时的最大尝试次数及首次重试的退避时间
+func getRetryPolicy() (int, time.Duration) {
+	attempts := config.Application.Notice.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	backoff := config.Application.Notice.RetryBackoff
+	if backoff <= 0 {
+		backoff = 1
+	}
+
+	return attempts, time.Duration(backoff) * time.Second
+}
+
+// pushDeadLetter 将重试后仍发送失败的通知写入租户的死信队列
+func pushDeadLetter(ctx *ctx.Context, sendParams SendParams, attempts int, sendErr error) {
+	err := ctx.Redis.DeadLetter().Push(models.NoticeDeadLetter{
+		Id:          "dl-" + tools.RandId(),
+		TenantId:    sendParams.TenantId,
+		EventId:     sendParams.EventId,
+		RuleName:    sendParams.RuleName,
+		Severity:    sendParams.Severity,
+		NoticeType:  sendParams.NoticeType,
+		NoticeId:    sendParams.NoticeId,
+		NoticeName:  sendParams.NoticeName,
+		IsRecovered: sendParams.IsRecovered,
+		RouteIndex:  sendParams.RouteIndex,
+		UserChannel: sendParams.UserChannel,
+		Email:       sendParams.Email,
+		Content:     sendParams.Content,
+		Attempts:    attempts,
+		Error:       sendErr.Error(),
+		CreateAt:    time.Now().Unix(),
+	})
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "写入通知死信队列失败, err: %s", err.Error())
+	}
+}
+
+// NewSendParamsFromDeadLetter 由死信通知还原发送参数, 地址及密钥从当前的通知配置中读取, 用于重新投递
+func NewSendParamsFromDeadLetter(ctx *ctx.Context, letter models.NoticeDeadLetter) (SendParams, error) {
+	params := SendParams{
+		TenantId:    letter.TenantId,
+		EventId:     letter.EventId,
+		RuleName:    letter.RuleName,
+		Severity:    letter.Severity,
+		NoticeType:  letter.NoticeType,
+		NoticeId:    letter.NoticeId,
+		NoticeName:  letter.NoticeName,
+		IsRecovered: letter.IsRecovered,
+		Email:       letter.Email,
+		Content:     letter.Content,
+		RouteIndex:  letter.RouteIndex,
+		UserChannel: letter.UserChannel,
+	}
+
+	if letter.UserChannel {
+		user, ok, err := ctx.DB.User().Get(letter.NoticeId, "", "")
+		if err != nil || !ok {
+			return params, fmt.Errorf("用户 %s 不存在", letter.NoticeName)
+		}
+		channel := user.NoticeChannel
+		if channel.NoticeType != letter.NoticeType {
+			return params, fmt.Errorf("用户 %s 的通知渠道已变更", user.UserName)
+		}
+		params.Hook, params.Sign, params.ChatId = channel.Hook, channel.Sign, channel.ChatId
+		if channel.NoticeType == "Email" {
+			params.Email.To = []string{user.Email}
+		}
+		return params, nil
+	}
+
+	notice, err := ctx.DB.Notice().Get(letter.TenantId, letter.NoticeId)
+	if err != nil {
+		return params, fmt.Errorf("通知对象 %s 不存在", letter.NoticeName)
+	}
+	if letter.RouteIndex < 0 || letter.RouteIndex >= len(notice.Routes) || notice.Routes[letter.RouteIndex].NoticeType != letter.NoticeType {
+		return params, fmt.Errorf("通知对象 %s 的通知路由已变更", notice.Name)
+	}
+	route := notice.Routes[letter.RouteIndex]
+	params.Hook, params.Sign, params.ChatId, params.RoutingKey = route.Hook, route.Sign, route.ChatId, route.RoutingKey
+	params.Email.To, params.Email.CC = route.To, route.CC
+
+	return params, nil
+}
+
 // addRecord 记录通知发送结果
 func addRecord(ctx *ctx.Context, sendParams SendParams, status int, msg, errMsg string) {
 	err := ctx.DB.Notice().AddRecord(models.NoticeRecord{
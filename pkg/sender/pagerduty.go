@@ -0,0 +1,125 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// PagerDuty 限流(429)时的最大重试次数及退避时间上限
+	pagerDutyMaxRateLimitRetries = 3
+	pagerDutyMaxRateLimitBackoff = 30 * time.Second
+)
+
+type (
+	// PagerDutySender PagerDuty Events API v2 发送策略
+	PagerDutySender struct{}
+)
+
+func NewPagerDutySender() SendInter { return &PagerDutySender{} }
+
+func (p *PagerDutySender) Send(params SendParams) error {
+	var event models.PagerDutyEvent
+	if err := sonic.Unmarshal([]byte(params.Content), &event); err != nil {
+		return fmt.Errorf("PagerDuty 事件解析失败, err: %s", err.Error())
+	}
+	event.RoutingKey = params.RoutingKey
+
+	res, err := p.post(params.Hook, event)
+	if err != nil {
+		return err
+	}
+
+	if params.Result != nil {
+		params.Result.IncidentKey = res.DedupKey
+	}
+	return nil
+}
+
+func (p *PagerDutySender) Test(params SendParams) error {
+	dedupKey := "w8t-test-" + tools.RandId()
+	_, err := p.post(params.Hook, models.PagerDutyEvent{
+		RoutingKey:  params.RoutingKey,
+		EventAction: models.PagerDutyActionTrigger,
+		DedupKey:    dedupKey,
+		Payload: &models.PagerDutyPayload{
+			Summary:  RobotTestContent,
+			Source:   "WatchAlert",
+			Severity: "info",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// 测试完成后关闭测试 incident
+	_, err = p.post(params.Hook, models.PagerDutyEvent{
+		RoutingKey:  params.RoutingKey,
+		EventAction: models.PagerDutyActionResolve,
+		DedupKey:    dedupKey,
+	})
+	return err
+}
+
+// post 发送事件, hook 为空时使用默认的 Events API 地址(EU 账户可配置为 events.eu.pagerduty.com),
+// 触发限流时按 Retry-After 或指数退避重试
+func (p *PagerDutySender) post(hook string, event models.PagerDutyEvent) (models.PagerDutyResponse, error) {
+	if event.RoutingKey == "" {
+		return models.PagerDutyResponse{}, errors.New("PagerDuty Routing Key 不能为空")
+	}
+	if hook == "" {
+		hook = models.PagerDutyEventsApi
+	}
+
+	body := tools.JsonMarshalToString(event)
+	backoff := time.Second
+	for retry := 0; ; retry++ {
+		res, err := tools.Post(nil, hook, bytes.NewReader([]byte(body)), 10)
+		if err != nil {
+			return models.PagerDutyResponse{}, err
+		}
+
+		bodyByte, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return models.PagerDutyResponse{}, fmt.Errorf("Error reading PagerDuty response: %s", err.Error())
+		}
+
+		if res.StatusCode == http.StatusTooManyRequests && retry < pagerDutyMaxRateLimitRetries {
+			time.Sleep(pagerDutyRetryAfter(res.Header.Get("Retry-After"), backoff))
+			backoff = min(backoff*2, pagerDutyMaxRateLimitBackoff)
+			continue
+		}
+
+		var response models.PagerDutyResponse
+		_ = sonic.Unmarshal(bodyByte, &response)
+		if res.StatusCode != http.StatusAccepted {
+			msg := strings.TrimSpace(string(bodyByte))
+			if response.Message != "" {
+				msg = strings.Join(append([]string{response.Message}, response.Errors...), "; ")
+			}
+			return response, fmt.Errorf("PagerDuty 事件发送失败, status: %d, err: %s", res.StatusCode, msg)
+		}
+
+		return response, nil
+	}
+}
+
+// pagerDutyRetryAfter 解析 Retry-After 响应头(秒), 未返回时使用当前退避时间
+func pagerDutyRetryAfter(header string, backoff time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds <= 0 {
+		return backoff
+	}
+	return min(time.Duration(seconds)*time.Second, pagerDutyMaxRateLimitBackoff)
+}
@@ -0,0 +1,56 @@
+package sender
+
+import (
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/ctx"
+
+	"github.com/zeromicro/go-zero/core/logc"
+	"golang.org/x/time/rate"
+)
+
+// 通知渠道限速器, key 为 通知类型 + Hook
+var channelLimiters sync.Map
+
+// waitRateLimit 按通知渠道进行令牌桶限速, 令牌不足时阻塞等待而不丢弃消息
+func waitRateLimit(ctx *ctx.Context, params SendParams) {
+	limiter := getChannelLimiter(params)
+	if limiter == nil {
+		return
+	}
+
+	r := limiter.Reserve()
+	delay := r.Delay()
+	if delay <= 0 {
+		return
+	}
+
+	ctx.Metrics.IncNoticeThrottled(params.NoticeType, params.NoticeId)
+	logc.Infof(ctx.Ctx, "通知渠道触发限速, 延迟 %s 发送, type: %s, notice: %s, rule: %s", delay, params.NoticeType, params.NoticeName, params.RuleName)
+	time.Sleep(delay)
+}
+
+// getChannelLimiter 获取通知渠道的限速器, 未配置限速时返回 nil
+func getChannelLimiter(params SendParams) *rate.Limiter {
+	limit := config.Application.Notice.RateLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	burst := config.Application.Notice.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	key := params.NoticeType + ":" + params.Hook
+	switch params.NoticeType {
+	case "Email":
+		key = params.NoticeType + ":" + params.NoticeId
+	case "PagerDuty":
+		key = params.NoticeType + ":" + params.RoutingKey
+	}
+
+	limiter, _ := channelLimiters.LoadOrStore(key, rate.NewLimiter(rate.Limit(limit), burst))
+	return limiter.(*rate.Limiter)
+}
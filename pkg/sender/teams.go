@@ -0,0 +1,68 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// TeamsSender Microsoft Teams 发送策略
+	TeamsSender struct{}
+)
+
+func NewTeamsSender() SendInter { return &TeamsSender{} }
+
+func (t *TeamsSender) Send(params SendParams) error {
+	return t.post(params.Hook, params.Content)
+}
+
+func (t *TeamsSender) Test(params SendParams) error {
+	msg := models.TeamsMsgTemplate{
+		Type: "message",
+		Attachments: []models.TeamsAttachment{
+			{
+				ContentType: models.TeamsAdaptiveCardContentType,
+				Content: models.TeamsAdaptiveCard{
+					Schema:  models.TeamsAdaptiveCardSchema,
+					Type:    "AdaptiveCard",
+					Version: models.TeamsAdaptiveCardVersion,
+					Body: []models.TeamsCardElement{
+						{Type: "TextBlock", Text: RobotTestContent, Wrap: true},
+					},
+				},
+			},
+		},
+	}
+	return t.post(params.Hook, tools.JsonMarshalToString(msg))
+}
+
+// post 发送消息, Teams 的错误响应为纯文本而非 JSON, 成功时响应体为空或 "1"
+func (t *TeamsSender) post(hook, content string) error {
+	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(content)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	bodyByte, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error reading Teams response: %s", err.Error()))
+	}
+
+	body := strings.TrimSpace(string(bodyByte))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Teams 消息发送失败, status: %d, err: %s", res.StatusCode, body)
+	}
+
+	// 旧版 Connector 在投递失败时仍可能返回 200, 需根据响应体判断
+	if body != "" && body != "1" {
+		return fmt.Errorf("Teams 消息发送失败, err: %s", body)
+	}
+
+	return nil
+}
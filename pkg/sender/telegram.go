@@ -0,0 +1,112 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+)
+
+type (
+	// TelegramSender Telegram 发送策略
+	TelegramSender struct{}
+
+	// TelegramResponse Telegram Bot API 响应
+	TelegramResponse struct {
+		Ok          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+	}
+)
+
+const (
+	// Telegram Bot API 地址
+	telegramApiUrl = "https://api.telegram.org"
+	// Telegram 单条消息的最大字符数
+	telegramMaxMessageLength = 4096
+)
+
+func NewTelegramSender() SendInter { return &TelegramSender{} }
+
+// Send 发送告警, Hook 为 Bot Token, 超长消息会被拆分为多条发送
+func (t *TelegramSender) Send(params SendParams) error {
+	var msg models.TelegramMsgTemplate
+	if err := sonic.Unmarshal([]byte(params.Content), &msg); err != nil {
+		return fmt.Errorf("发送的内容解析失败, err: %s", err.Error())
+	}
+
+	for _, text := range splitTelegramText(msg.Text, telegramMaxMessageLength) {
+		if err := t.post(params.Hook, params.ChatId, text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *TelegramSender) Test(params SendParams) error {
+	return t.post(params.Hook, params.ChatId, RobotTestContent)
+}
+
+func (t *TelegramSender) post(token, chatId, text string) error {
+	if token == "" || chatId == "" {
+		return errors.New("Telegram Bot Token 和 Chat ID 不能为空")
+	}
+
+	msg := models.TelegramMsgTemplate{
+		ChatId: chatId,
+		Text:   text,
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramApiUrl, token)
+	res, err := tools.Post(nil, url, bytes.NewReader([]byte(tools.JsonMarshalToString(msg))), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	bodyByte, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error reading Telegram response: %s", err.Error()))
+	}
+
+	var response TelegramResponse
+	if err := sonic.Unmarshal(bodyByte, &response); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling Telegram response: %s", err.Error()))
+	}
+
+	if !response.Ok {
+		return fmt.Errorf("Telegram 消息发送失败, code: %d, err: %s", response.ErrorCode, response.Description)
+	}
+
+	return nil
+}
+
+// splitTelegramText 按最大长度拆分消息, 优先在换行处截断
+func splitTelegramText(text string, limit int) []string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	for len(runes) > limit {
+		cut := limit
+		for i := limit - 1; i > 0; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
@@ -0,0 +1,45 @@
+package sender
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// BuildTextContent 按通知类型将纯文本封装为发送器可识别的消息内容, 用于告警事件之外的提醒类通知
+func BuildTextContent(noticeType, text string) string {
+	switch noticeType {
+	case "FeiShu":
+		return tools.JsonMarshalToString(map[string]any{
+			"msg_type": "text",
+			"content":  map[string]any{"text": text},
+		})
+	case "DingDing", "WeChat":
+		return tools.JsonMarshalToString(map[string]any{
+			"msgtype": "text",
+			"text":    map[string]any{"content": text},
+		})
+	case "Slack", "WebHook":
+		return tools.JsonMarshalToString(models.SlackMsgTemplate{Text: text})
+	case "Telegram":
+		return tools.JsonMarshalToString(models.TelegramMsgTemplate{Text: text})
+	case "Teams":
+		return tools.JsonMarshalToString(models.TeamsMsgTemplate{
+			Type: "message",
+			Attachments: []models.TeamsAttachment{
+				{
+					ContentType: models.TeamsAdaptiveCardContentType,
+					Content: models.TeamsAdaptiveCard{
+						Schema:  models.TeamsAdaptiveCardSchema,
+						Type:    "AdaptiveCard",
+						Version: models.TeamsAdaptiveCardVersion,
+						Body: []models.TeamsCardElement{
+							{Type: "TextBlock", Text: text, Wrap: true},
+						},
+					},
+				},
+			},
+		})
+	default:
+		return text
+	}
+}
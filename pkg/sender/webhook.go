@@ -2,12 +2,24 @@ package sender
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"time"
 	"watchAlert/pkg/tools"
 )
 
+const (
+	// WebhookTimestampHeader 签名时间戳（Unix 秒）
+	WebhookTimestampHeader = "X-W8t-Timestamp"
+	// WebhookSignatureHeader 请求签名, 格式为 sha256=<hex>
+	WebhookSignatureHeader = "X-W8t-Signature"
+)
+
 type (
 	// WebHookSender 自定义Hook发送策略
 	WebHookSender struct{}
@@ -21,15 +33,24 @@ var WebhookTestContent = fmt.Sprintf(`{
 func NewWebHookSender() SendInter { return &WebHookSender{} }
 
 func (w *WebHookSender) Send(params SendParams) error {
-	return w.post(params.Hook, params.Content)
+	return w.post(params.Hook, params.Sign, params.Content)
 }
 
 func (w *WebHookSender) Test(params SendParams) error {
-	return w.post(params.Hook, WebhookTestContent)
+	return w.post(params.Hook, params.Sign, WebhookTestContent)
 }
 
-func (w *WebHookSender) post(hook, content string) error {
-	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(content)), 10)
+func (w *WebHookSender) post(hook, secret, content string) error {
+	var headers map[string]string
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers = map[string]string{
+			WebhookTimestampHeader: timestamp,
+			WebhookSignatureHeader: "sha256=" + generateWebhookSignature(secret, timestamp, []byte(content)),
+		}
+	}
+
+	res, err := tools.Post(headers, hook, bytes.NewReader([]byte(content)), 10)
 	if err != nil {
 		return err
 	}
@@ -44,3 +65,17 @@ func (w *WebHookSender) post(hook, content string) error {
 
 	return nil
 }
+
+// generateWebhookSignature 生成 Webhook 签名, 渠道配置了签名密钥时使用
+//
+// 签名方式: hex(HMAC-SHA256(secret, "{timestamp}.{body}")), 其中 timestamp 为 X-W8t-Timestamp 请求头的值, body 为原始请求体
+// 接收方校验步骤:
+//  1. 读取 X-W8t-Timestamp, 与当前时间相差过大(如超过 5 分钟)时拒绝, 防止重放
+//  2. 使用相同的密钥按上述方式计算签名, 与 X-W8t-Signature 中 sha256= 之后的部分进行常量时间比较
+func generateWebhookSignature(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
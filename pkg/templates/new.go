@@ -9,26 +9,124 @@ type Template struct {
 	CardContentMsg string
 }
 
-// NewTemplate 创建模板
+// defaultNoticeTemplate 内置的默认模版, 渠道未配置模版时使用
+const defaultNoticeTemplate = `{{ define "Title" }}{{ if .IsRecovered }}[已恢复]{{ else }}[{{ .Severity }}]{{ end }} {{ .RuleName }}{{ end }}
+{{ define "TitleColor" }}{{ if .IsRecovered }}green{{ else }}red{{ end }}{{ end }}
+{{ define "Event" }}**告警等级**: {{ .Severity }}
+**触发时间**: {{ .FirstTriggerTime | formatTime }}
+**持续时间**: {{ duration .FirstTriggerTime }}
+{{ if .IsRecovered }}**恢复时间**: {{ .RecoverTime | formatTime }}
+{{ end }}**告警标签**:
+{{ range $k, $v := .Labels }}- {{ $k }}: {{ $v }}
+{{ end }}**告警详情**: {{ .Annotations }}
+{{ with eventLink }}**事件链接**: {{ . }}
+{{ end }}{{ with .QueryLink }}**查询链接**: {{ . }}
+{{ end }}{{ end }}
+{{ define "Footer" }}{{ if .DutyUser }}值班人员: {{ .DutyUser }}{{ end }}{{ end }}`
+
+// defaultRecoverNoticeTemplate 内置的默认恢复通知模版
+const defaultRecoverNoticeTemplate = `{{ define "Title" }}[已恢复] {{ .RuleName }}{{ end }}
+{{ define "TitleColor" }}green{{ end }}
+{{ define "Event" }}**告警等级**: {{ .Severity }}
+**触发时间**: {{ .FirstTriggerTime | formatTime }}
+**最后触发**: {{ .LastTriggerTime | formatTime }}
+**恢复时间**: {{ .RecoverTime | formatTime }}
+**持续时长**: {{ durationBetween .FirstTriggerTime .RecoverTime }}
+**告警标签**:
+{{ range $k, $v := .Labels }}- {{ $k }}: {{ $v }}
+{{ end }}{{ with eventLink }}**事件链接**: {{ . }}
+{{ end }}{{ with .QueryLink }}**查询链接**: {{ . }}
+{{ end }}{{ end }}
+{{ define "Footer" }}{{ if .DutyUser }}值班人员: {{ .DutyUser }}{{ end }}{{ end }}`
+
+// NewTemplate 创建模板, 优先使用通知渠道上配置的模版, 其次使用关联的通知模版, 均未配置时使用内置的默认模版
 func NewTemplate(ctx *ctx.Context, alert models.AlertCurEvent, route models.Route) (Template, error) {
-	noticeTmpl, err := ctx.DB.NoticeTmpl().Get(route.NoticeTmplId)
+	noticeTmpl, err := getNoticeTemplate(ctx, alert, route)
 	if err != nil {
 		return Template{}, err
 	}
-	switch route.NoticeType {
+
+	return RenderTemplate(alert, route.NoticeType, noticeTmpl), nil
+}
+
+func getNoticeTemplate(ctx *ctx.Context, alert models.AlertCurEvent, route models.Route) (models.NoticeTemplateExample, error) {
+	if alert.IsRecovered {
+		if route.RecoverTemplate != "" {
+			return InlineTemplate(route.NoticeType, route.RecoverTemplate), nil
+		}
+		if route.Template == "" && route.NoticeTmplId == "" {
+			return InlineTemplate(route.NoticeType, defaultRecoverNoticeTemplate), nil
+		}
+	}
+
+	if route.Template != "" {
+		return InlineTemplate(route.NoticeType, route.Template), nil
+	}
+
+	if route.NoticeTmplId == "" {
+		return InlineTemplate(route.NoticeType, defaultNoticeTemplate), nil
+	}
+
+	return ctx.DB.NoticeTmpl().Get(route.NoticeTmplId)
+}
+
+// DefaultTemplate 获取内置的默认模版
+func DefaultTemplate(isRecovered bool) string {
+	if isRecovered {
+		return defaultRecoverNoticeTemplate
+	}
+	return defaultNoticeTemplate
+}
+
+// InlineTemplate 构造渠道上配置的模版, 模版为空时使用内置的默认模版
+func InlineTemplate(noticeType, tmpl string) models.NoticeTemplateExample {
+	if tmpl == "" {
+		tmpl = defaultNoticeTemplate
+	}
+
+	enableJsonCard := false
+	return models.NoticeTemplateExample{
+		NoticeType:           noticeType,
+		Template:             tmpl,
+		EnableFeiShuJsonCard: &enableJsonCard,
+	}
+}
+
+// RenderTemplate 按通知类型渲染模版
+func RenderTemplate(alert models.AlertCurEvent, noticeType string, noticeTmpl models.NoticeTemplateExample) Template {
+	if noticeTmpl.EnableFeiShuJsonCard == nil {
+		enableJsonCard := false
+		noticeTmpl.EnableFeiShuJsonCard = &enableJsonCard
+	}
+
+	switch noticeType {
 	case "FeiShu":
-		return Template{CardContentMsg: feishuTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: feishuTemplate(alert, noticeTmpl)}
 	case "DingDing":
-		return Template{CardContentMsg: dingdingTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: dingdingTemplate(alert, noticeTmpl)}
 	case "Email":
-		return Template{CardContentMsg: emailTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: emailTemplate(alert, noticeTmpl)}
 	case "WeChat":
-		return Template{CardContentMsg: wechatTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: wechatTemplate(alert, noticeTmpl)}
 	case "PhoneCall":
-		return Template{CardContentMsg: phoneCallTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: phoneCallTemplate(alert, noticeTmpl)}
 	case "Slack":
-		return Template{CardContentMsg: slackTemplate(alert, noticeTmpl)}, nil
+		return Template{CardContentMsg: slackTemplate(alert, noticeTmpl)}
+	case "Telegram":
+		return Template{CardContentMsg: telegramTemplate(alert, noticeTmpl)}
+	case "Teams":
+		return Template{CardContentMsg: teamsTemplate(alert, noticeTmpl)}
+	case "PagerDuty":
+		return Template{CardContentMsg: pagerDutyTemplate(alert, noticeTmpl)}
 	}
 
-	return Template{}, nil
+	return Template{}
+}
+
+// RenderSubject 渲染邮件主题, 主题中可引用事件字段
+func RenderSubject(alert models.AlertCurEvent, subject string) string {
+	if subject == "" {
+		return ""
+	}
+	return ParserTemplate("Subject", alert, subject)
 }
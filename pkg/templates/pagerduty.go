@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// pagerDutyTemplate PagerDuty Events API v2 事件, 以事件指纹作为 dedup_key, 恢复时发送 resolve 关闭对应的 incident
+func pagerDutyTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	event := models.PagerDutyEvent{
+		EventAction: models.PagerDutyActionTrigger,
+		DedupKey:    alert.Fingerprint,
+	}
+	if alert.IsRecovered {
+		// resolve 事件只需 dedup_key
+		event.EventAction = models.PagerDutyActionResolve
+		return tools.JsonMarshalToString(event)
+	}
+
+	summary := ParserTemplate("Title", alert, noticeTmpl.Template)
+	if summary == "" {
+		summary = alert.RuleName
+	}
+
+	source := alert.DatasourceId
+	if instance, ok := alert.Labels["instance"].(string); ok && instance != "" {
+		source = instance
+	}
+
+	event.Payload = &models.PagerDutyPayload{
+		Summary:   summary,
+		Source:    source,
+		Severity:  models.PagerDutySeverity(alert.Severity),
+		Timestamp: time.Unix(alert.FirstTriggerTime, 0).Format(time.RFC3339),
+		Group:     alert.RuleName,
+		Class:     alert.DatasourceType,
+		CustomDetails: map[string]any{
+			"labels":      alert.Labels,
+			"annotations": alert.Annotations,
+			"ruleId":      alert.RuleId,
+			"fingerprint": alert.Fingerprint,
+			"dutyUser":    alert.DutyUser,
+		},
+	}
+	event.Client = "WatchAlert"
+	if link := EventLink(alert); link != "" {
+		event.ClientUrl = link
+		event.Links = []models.PagerDutyLink{{Href: link, Text: "查看告警"}}
+	}
+
+	return tools.JsonMarshalToString(event)
+}
@@ -3,8 +3,13 @@ package templates
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
 	"text/template"
 	"time"
+	"watchAlert/config"
+	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/pkg/tools"
 
@@ -13,14 +18,30 @@ import (
 
 // ParserTemplate 处理告警推送的消息模版
 func ParserTemplate(defineName string, alert models.AlertCurEvent, templateStr string) string {
-	// 1. 定义模板函数
-	funcMap := template.FuncMap{
-		// 时间戳转格式化字符串: {{ .FirstTriggerTime | formatTime }}
+	tmpl, err := template.New("tmpl").Funcs(templateFuncs(alert)).Parse(templateStr)
+	if err != nil {
+		logc.Errorf(context.Background(), "模板解析失败: %v, template: %s", err, templateStr)
+		return ""
+	}
+
+	return renderNamedTemplate(tmpl, defineName, alert)
+}
+
+// ValidateTemplate 校验模版语法
+func ValidateTemplate(templateStr string) error {
+	_, err := template.New("tmpl").Funcs(templateFuncs(models.AlertCurEvent{})).Parse(templateStr)
+	return err
+}
+
+// templateFuncs 模版函数
+func templateFuncs(alert models.AlertCurEvent) template.FuncMap {
+	return template.FuncMap{
+		// 时间戳按租户时区转格式化字符串: {{ .FirstTriggerTime | formatTime }}
 		"formatTime": func(timestamp int64) string {
 			if timestamp == 0 {
 				return "-"
 			}
-			return time.Unix(timestamp, 0).Format("2006-01-02 15:04:05")
+			return time.Unix(timestamp, 0).In(ctx.DO().TenantLocation(alert.TenantId)).Format("2006-01-02 15:04:05")
 		},
 		// 计算持续时间: {{ duration .FirstTriggerTime }}
 		"duration": func(first int64) string {
@@ -31,16 +52,28 @@ func ParserTemplate(defineName string, alert models.AlertCurEvent, templateStr s
 			d := time.Duration(cur-first) * time.Second
 			return d.String()
 		},
+		// 计算两个时间戳之间的持续时间: {{ durationBetween .FirstTriggerTime .RecoverTime }}
+		"durationBetween": func(start, end int64) string {
+			if start == 0 || end < start {
+				return "0s"
+			}
+			return (time.Duration(end-start) * time.Second).String()
+		},
+		// 告警事件详情链接: {{ eventLink }}
+		"eventLink": func() string {
+			return EventLink(alert)
+		},
 	}
+}
 
-	// 2. 解析模板并注入函数
-	tmpl, err := template.New("tmpl").Funcs(funcMap).Parse(templateStr)
-	if err != nil {
-		logc.Errorf(context.Background(), "模板解析失败: %v, template: %s", err, templateStr)
+// EventLink 生成跳转回故障中心告警事件的链接, 未配置外部访问地址时返回空
+func EventLink(alert models.AlertCurEvent) string {
+	externalUrl := strings.TrimRight(config.Application.Server.ExternalUrl, "/")
+	if externalUrl == "" || alert.FaultCenterId == "" {
 		return ""
 	}
 
-	return renderNamedTemplate(tmpl, defineName, alert)
+	return fmt.Sprintf("%s/faultCenter/detail/%s?fingerprint=%s", externalUrl, alert.FaultCenterId, url.QueryEscape(alert.Fingerprint))
 }
 
 // renderNamedTemplate 渲染模板
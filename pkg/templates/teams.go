@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// teamsTemplate Teams Adaptive Card 模版
+func teamsTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	title := ParserTemplate("Title", alert, noticeTmpl.Template)
+	if title == "" {
+		title = alert.RuleName
+	}
+
+	card := models.TeamsAdaptiveCard{
+		Schema:  models.TeamsAdaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: models.TeamsAdaptiveCardVersion,
+		Body: []models.TeamsCardElement{
+			{
+				Type:   "TextBlock",
+				Text:   title,
+				Size:   "Medium",
+				Weight: "Bolder",
+				Color:  teamsSeverityColor(alert),
+				Wrap:   true,
+			},
+			{
+				Type: "FactSet",
+				Facts: []models.TeamsFact{
+					{Title: "规则名称", Value: alert.RuleName},
+					{Title: "告警等级", Value: alert.Severity},
+					{Title: "告警指纹", Value: alert.Fingerprint},
+				},
+			},
+			{
+				Type: "TextBlock",
+				Text: ParserTemplate("Event", alert, noticeTmpl.Template),
+				Wrap: true,
+			},
+		},
+		MsTeams: map[string]string{"width": "Full"},
+	}
+
+	if link := EventLink(alert); link != "" {
+		card.Actions = []models.TeamsCardAction{
+			{Type: "Action.OpenUrl", Title: "查看告警", Url: link},
+		}
+	}
+
+	return tools.JsonMarshalToString(models.TeamsMsgTemplate{
+		Type: "message",
+		Attachments: []models.TeamsAttachment{
+			{
+				ContentType: models.TeamsAdaptiveCardContentType,
+				Content:     card,
+			},
+		},
+	})
+}
+
+// teamsSeverityColor 按告警等级映射卡片标题颜色, 已恢复的告警统一为绿色
+func teamsSeverityColor(alert models.AlertCurEvent) string {
+	if alert.IsRecovered {
+		return "Good"
+	}
+
+	switch alert.Severity {
+	case "P0":
+		return "Attention"
+	case "P1":
+		return "Warning"
+	case "P2":
+		return "Accent"
+	default:
+		return "Default"
+	}
+}
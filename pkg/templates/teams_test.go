@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"encoding/json"
+	"testing"
+	"watchAlert/config"
+	"watchAlert/internal/models"
+)
+
+const teamsTestTmpl = `{{ define "Title" }}[{{ .Severity }}] {{ .RuleName }}{{ end }}{{ define "Event" }}告警详情: {{ .Annotations }}{{ end }}`
+
+func TestTeamsTemplate(t *testing.T) {
+	config.Application.Server.ExternalUrl = "http://w8t.example.com/"
+
+	alert := models.AlertCurEvent{
+		RuleName:      "CPU 使用率过高",
+		Severity:      "P0",
+		Fingerprint:   "1234567890",
+		FaultCenterId: "fc-1",
+		Annotations:   "CPU 使用率 95%",
+	}
+
+	content := teamsTemplate(alert, models.NoticeTemplateExample{Template: teamsTestTmpl})
+
+	var msg map[string]any
+	if err := json.Unmarshal([]byte(content), &msg); err != nil {
+		t.Fatalf("card is not valid json: %v, content: %s", err, content)
+	}
+
+	if msg["type"] != "message" {
+		t.Fatalf("unexpected message type: %v", msg["type"])
+	}
+
+	attachments, ok := msg["attachments"].([]any)
+	if !ok || len(attachments) != 1 {
+		t.Fatalf("expected one attachment, got: %v", msg["attachments"])
+	}
+
+	attachment := attachments[0].(map[string]any)
+	if attachment["contentType"] != models.TeamsAdaptiveCardContentType {
+		t.Fatalf("unexpected contentType: %v", attachment["contentType"])
+	}
+
+	card, ok := attachment["content"].(map[string]any)
+	if !ok {
+		t.Fatalf("attachment content is not an object: %v", attachment["content"])
+	}
+	if card["type"] != "AdaptiveCard" || card["$schema"] != models.TeamsAdaptiveCardSchema || card["version"] != models.TeamsAdaptiveCardVersion {
+		t.Fatalf("invalid adaptive card header: %v", card)
+	}
+
+	body, ok := card["body"].([]any)
+	if !ok || len(body) == 0 {
+		t.Fatalf("adaptive card body is empty")
+	}
+	for i, e := range body {
+		element := e.(map[string]any)
+		switch element["type"] {
+		case "TextBlock":
+			if _, ok := element["text"].(string); !ok {
+				t.Fatalf("body[%d] TextBlock missing text", i)
+			}
+		case "FactSet":
+			facts, ok := element["facts"].([]any)
+			if !ok || len(facts) == 0 {
+				t.Fatalf("body[%d] FactSet missing facts", i)
+			}
+			for _, f := range facts {
+				fact := f.(map[string]any)
+				if _, ok := fact["title"].(string); !ok {
+					t.Fatalf("body[%d] fact missing title", i)
+				}
+				if _, ok := fact["value"].(string); !ok {
+					t.Fatalf("body[%d] fact missing value", i)
+				}
+			}
+		default:
+			t.Fatalf("body[%d] unexpected element type: %v", i, element["type"])
+		}
+	}
+
+	title := body[0].(map[string]any)
+	if title["text"] != "[P0] CPU 使用率过高" || title["color"] != "Attention" {
+		t.Fatalf("unexpected title block: %v", title)
+	}
+
+	actions, ok := card["actions"].([]any)
+	if !ok || len(actions) != 1 {
+		t.Fatalf("expected one action, got: %v", card["actions"])
+	}
+	action := actions[0].(map[string]any)
+	if action["type"] != "Action.OpenUrl" || action["url"] != "http://w8t.example.com/faultCenter/detail/fc-1?fingerprint=1234567890" {
+		t.Fatalf("unexpected action: %v", action)
+	}
+}
+
+func TestTeamsSeverityColor(t *testing.T) {
+	cases := []struct {
+		alert models.AlertCurEvent
+		color string
+	}{
+		{models.AlertCurEvent{Severity: "P0"}, "Attention"},
+		{models.AlertCurEvent{Severity: "P1"}, "Warning"},
+		{models.AlertCurEvent{Severity: "P2"}, "Accent"},
+		{models.AlertCurEvent{Severity: "P0", IsRecovered: true}, "Good"},
+	}
+
+	for _, c := range cases {
+		if got := teamsSeverityColor(c.alert); got != c.color {
+			t.Errorf("severity %s recovered %v: expected %s, got %s", c.alert.Severity, c.alert.IsRecovered, c.color, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package templates
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+func telegramTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	t := models.TelegramMsgTemplate{
+		Text: ParserTemplate("Event", alert, noticeTmpl.Template),
+	}
+
+	return tools.JsonMarshalToString(t)
+}
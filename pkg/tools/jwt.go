@@ -2,6 +2,8 @@ package tools
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 	"watchAlert/config"
 
@@ -22,6 +24,8 @@ const (
 	TokenType = "bearer"
 	// AppGuardName 颁发者
 	AppGuardName = "WatchAlert"
+	// ApiKeyTokenType API 密钥认证通过后写入 Authorization 的身份类型, 用于解析密钥的身份
+	ApiKeyTokenType = "apikey"
 )
 
 var StSignKey = []byte(viper.GetString("jwt.WatchAlert"))
@@ -65,6 +69,10 @@ func GetUser(tokenStr string) string {
 		return ""
 	}
 
+	if id, name, ok := parseApiKeyIdentity(tokenStr); ok {
+		return ApiKeyTokenType + ":" + name + "(" + id + ")"
+	}
+
 	tokenStr = tokenStr[len(TokenType)+1:]
 	token, err := ParseToken(tokenStr)
 	if err != nil {
@@ -78,6 +86,10 @@ func GetUserID(tokenStr string) string {
 		return ""
 	}
 
+	if id, _, ok := parseApiKeyIdentity(tokenStr); ok {
+		return ApiKeyTokenType + "-" + id
+	}
+
 	tokenStr = tokenStr[len(TokenType)+1:]
 	token, err := ParseToken(tokenStr)
 	if err != nil {
@@ -86,3 +98,19 @@ func GetUserID(tokenStr string) string {
 
 	return token.ID
 }
+
+// BuildApiKeyIdentity 构造 API 密钥的身份标识, 由认证中间件在密钥校验通过后写入 Authorization,
+// 客户端伪造的标识无法通过 Token 校验
+func BuildApiKeyIdentity(id int, name string) string {
+	return ApiKeyTokenType + " " + strconv.Itoa(id) + ":" + name
+}
+
+// parseApiKeyIdentity 解析 API 密钥的身份标识, 返回密钥 ID 及名称
+func parseApiKeyIdentity(tokenStr string) (string, string, bool) {
+	identity, ok := strings.CutPrefix(tokenStr, ApiKeyTokenType+" ")
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.Cut(identity, ":")
+}
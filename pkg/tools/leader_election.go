@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -23,22 +24,22 @@ const (
 
 // LeaderElector Leader 选举器
 type LeaderElector struct {
-	client         *redis.Client
-	ctx            context.Context
-	instanceID     string
-	isLeader       bool
+	client     redis.UniversalClient
+	ctx        context.Context
+	instanceID string
+	// 选举循环及续期协程均会修改, HTTP 请求中也会读取
+	isLeader       atomic.Bool
 	cancelRenew    context.CancelFunc
 	onBecomeLeader func()
 	onLoseLeader   func()
 }
 
 // NewLeaderElector 创建 Leader 选举器
-func NewLeaderElector(ctx context.Context, client *redis.Client, onBecomeLeader, onLoseLeader func()) *LeaderElector {
+func NewLeaderElector(ctx context.Context, client redis.UniversalClient, onBecomeLeader, onLoseLeader func()) *LeaderElector {
 	return &LeaderElector{
 		client:         client,
 		ctx:            ctx,
 		instanceID:     uuid.New().String(),
-		isLeader:       false,
 		onBecomeLeader: onBecomeLeader,
 		onLoseLeader:   onLoseLeader,
 	}
@@ -92,12 +93,12 @@ func (le *LeaderElector) tryBecomeLeader() {
 			logc.Errorf(le.ctx, "获取当前 Leader 失败: %v", err)
 		} else if currentLeader == le.instanceID {
 			// 自己已经是 Leader，但可能是从 Follower 恢复
-			if !le.isLeader {
+			if !le.isLeader.Load() {
 				le.promoteToLeader()
 			}
 		} else {
 			// 其他实例是 Leader
-			if le.isLeader {
+			if le.isLeader.Load() {
 				le.demoteToFollower()
 			}
 		}
@@ -106,12 +107,11 @@ func (le *LeaderElector) tryBecomeLeader() {
 
 // promoteToLeader 提升为 Leader
 func (le *LeaderElector) promoteToLeader() {
-	if le.isLeader {
+	if !le.isLeader.CompareAndSwap(false, true) {
 		return
 	}
 
 	logc.Infof(le.ctx, "当前实例成为 Leader，ID: %s", le.instanceID)
-	le.isLeader = true
 
 	// 启动心跳续期
 	le.startHeartbeat()
@@ -124,12 +124,11 @@ func (le *LeaderElector) promoteToLeader() {
 
 // demoteToFollower 降级为 Follower
 func (le *LeaderElector) demoteToFollower() {
-	if !le.isLeader {
+	if !le.isLeader.CompareAndSwap(true, false) {
 		return
 	}
 
 	logc.Infof(le.ctx, "当前实例降级为 Follower，ID: %s", le.instanceID)
-	le.isLeader = false
 
 	// 停止心跳续期
 	if le.cancelRenew != nil {
@@ -196,17 +195,17 @@ func (le *LeaderElector) checkLeaderStatus() {
 
 	if err == redis.Nil {
 		// Leader 已失效，尝试成为 Leader
-		if !le.isLeader {
+		if !le.isLeader.Load() {
 			logc.Infof(le.ctx, "检测到 Leader 缺失，尝试竞选...")
 			le.tryBecomeLeader()
 		}
 	} else if err != nil {
 		logc.Errorf(le.ctx, "检查 Leader 状态失败: %v", err)
-	} else if currentLeader != le.instanceID && le.isLeader {
+	} else if currentLeader != le.instanceID && le.isLeader.Load() {
 		// 自己以为是 Leader，但实际上不是
 		logc.Infof(le.ctx, "检测到 Leader 变更，主动降级")
 		le.demoteToFollower()
-	} else if currentLeader == le.instanceID && !le.isLeader {
+	} else if currentLeader == le.instanceID && !le.isLeader.Load() {
 		// 自己是 Leader 但状态未更新
 		logc.Infof(le.ctx, "重新确认 Leader 身份")
 		le.promoteToLeader()
@@ -215,7 +214,7 @@ func (le *LeaderElector) checkLeaderStatus() {
 
 // resign 主动辞去 Leader
 func (le *LeaderElector) resign() {
-	if !le.isLeader {
+	if !le.isLeader.Load() {
 		return
 	}
 
@@ -240,7 +239,7 @@ func (le *LeaderElector) resign() {
 
 // IsLeader 判断当前实例是否是 Leader
 func (le *LeaderElector) IsLeader() bool {
-	return le.isLeader
+	return le.isLeader.Load()
 }
 
 // GetLeaderID 获取当前 Leader 的实例 ID
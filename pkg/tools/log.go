@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 结构化日志的关联字段, 便于在日志系统中按字段过滤
+const (
+	LogFieldTenantId      = "tenant_id"
+	LogFieldRuleId        = "rule_id"
+	LogFieldRuleName      = "rule_name"
+	LogFieldFaultCenterId = "fault_center_id"
+	LogFieldDatasourceId  = "datasource_id"
+	LogFieldFingerprint   = "fingerprint"
+)
+
+// WithLogFields 在 ctx 中附加结构化日志字段, 之后通过 logc 使用该 ctx 输出的日志均携带这些字段, 值为空的字段不附加
+func WithLogFields(ctx context.Context, kvs ...string) context.Context {
+	fields := make([]logx.LogField, 0, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if kvs[i+1] == "" {
+			continue
+		}
+		fields = append(fields, logx.Field(kvs[i], kvs[i+1]))
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return logx.ContextWithFields(ctx, fields...)
+}
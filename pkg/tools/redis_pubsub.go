@@ -34,7 +34,7 @@ type ReloadMessage struct {
 }
 
 // PublishReloadMessage 发布重载消息
-func PublishReloadMessage(ctx context.Context, client *redis.Client, channel string, msg ReloadMessage) error {
+func PublishReloadMessage(ctx context.Context, client redis.UniversalClient, channel string, msg ReloadMessage) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal reload message: %v", err)
@@ -52,7 +52,7 @@ func PublishReloadMessage(ctx context.Context, client *redis.Client, channel str
 }
 
 // SubscribeReloadMessages 订阅重载消息
-func SubscribeReloadMessages(ctx context.Context, client *redis.Client, channel string, handler func(msg ReloadMessage)) {
+func SubscribeReloadMessages(ctx context.Context, client redis.UniversalClient, channel string, handler func(msg ReloadMessage)) {
 	pubsub := client.Subscribe(channel)
 	defer pubsub.Close()
 
@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	// ShardWorkersKey 存活实例注册表的 Redis Key, 成员为实例 ID, 分数为最后一次心跳时间
+	ShardWorkersKey = "w8t:shard:workers"
+	// ShardWorkerTTL 实例超过该时间（秒）未上报心跳视为已下线
+	ShardWorkerTTL = 10
+	// ShardHeartbeatInterval 心跳上报及刷新存活实例列表的间隔（秒）
+	ShardHeartbeatInterval = 3
+)
+
+// ShardWorker 分片实例, 通过 Redis 注册心跳发现存活实例, 按规则 ID 的最高随机权重哈希（rendezvous hashing）分配规则
+type ShardWorker struct {
+	client     redis.UniversalClient
+	ctx        context.Context
+	cancel     context.CancelFunc
+	instanceID string
+
+	mu sync.RWMutex
+	// 按实例 ID 排序的存活实例, 各实例排序一致才能得到相同的分片结果
+	workers []string
+	// 存活实例变化时调用, 用于重新分配规则
+	onRebalance func()
+}
+
+// NewShardWorker 创建分片实例
+func NewShardWorker(ctx context.Context, client redis.UniversalClient, onRebalance func()) *ShardWorker {
+	c, cancel := context.WithCancel(ctx)
+	return &ShardWorker{
+		client:      client,
+		ctx:         c,
+		cancel:      cancel,
+		instanceID:  uuid.New().String(),
+		onRebalance: onRebalance,
+	}
+}
+
+// Start 注册当前实例并开始上报心跳, 返回前完成首次注册, 之后可直接判断规则归属
+func (s *ShardWorker) Start() {
+	logc.Infof(s.ctx, "分片实例 ID: %s", s.instanceID)
+	s.heartbeat()
+
+	go func() {
+		ticker := time.NewTicker(ShardHeartbeatInterval * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if s.heartbeat() && s.onRebalance != nil {
+					s.onRebalance()
+				}
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止心跳并注销当前实例, 其他实例在下一次心跳时接管当前实例的规则
+func (s *ShardWorker) Stop() {
+	s.cancel()
+	if err := s.client.ZRem(ShardWorkersKey, s.instanceID).Err(); err != nil {
+		logc.Errorf(context.Background(), "注销分片实例失败: %v", err)
+	}
+}
+
+// heartbeat 上报心跳、清理已下线的实例并刷新存活实例列表, 返回存活实例是否发生变化
+func (s *ShardWorker) heartbeat() bool {
+	now := time.Now().Unix()
+	if err := s.client.ZAdd(ShardWorkersKey, redis.Z{Score: float64(now), Member: s.instanceID}).Err(); err != nil {
+		logc.Errorf(s.ctx, "分片实例心跳上报失败: %v", err)
+		return false
+	}
+	s.client.ZRemRangeByScore(ShardWorkersKey, "-inf", fmt.Sprintf("(%d", now-ShardWorkerTTL))
+
+	workers, err := s.client.ZRange(ShardWorkersKey, 0, -1).Result()
+	if err != nil {
+		logc.Errorf(s.ctx, "获取存活分片实例失败: %v", err)
+		return false
+	}
+	slices.Sort(workers)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.Equal(s.workers, workers) {
+		return false
+	}
+
+	logc.Infof(s.ctx, "存活分片实例变化, %d -> %d 个实例: %v", len(s.workers), len(workers), workers)
+	s.workers = workers
+	return true
+}
+
+// Owner 获取负责 key 的实例 ID 及分片序号, 没有存活实例时返回空
+func (s *ShardWorker) Owner(key string) (string, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.workers) == 0 {
+		return "", -1
+	}
+
+	shard := ShardIndex(key, s.workers)
+	return s.workers[shard], shard
+}
+
+// Owns 判断 key 是否由当前实例负责
+func (s *ShardWorker) Owns(key string) bool {
+	owner, _ := s.Owner(key)
+	return owner == s.instanceID
+}
+
+// Workers 获取存活实例列表
+func (s *ShardWorker) Workers() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.workers)
+}
+
+// GetInstanceID 获取当前实例 ID
+func (s *ShardWorker) GetInstanceID() string {
+	return s.instanceID
+}
+
+// ShardIndex 按最高随机权重哈希（rendezvous hashing）获取负责 key 的实例序号, 没有实例时返回 -1;
+// 实例增减时只有归属于变化实例的 key 会迁移, 其余 key 的归属保持不变
+func ShardIndex(key string, workers []string) int {
+	shard, best := -1, uint64(0)
+	for i, worker := range workers {
+		if w := shardWeight(worker, key); shard < 0 || w > best {
+			shard, best = i, w
+		}
+	}
+	return shard
+}
+
+// shardWeight 计算 key 在实例上的权重, FNV 结果经 splitmix64 打散, 避免相近的实例 ID 或 key 权重相近
+func shardWeight(worker, key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(worker))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
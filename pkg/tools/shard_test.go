@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShardIndexMovesOnlyAffectedKeys(t *testing.T) {
+	const rules = 10000
+	workers := []string{"worker-a", "worker-b", "worker-c", "worker-d"}
+
+	owners := func(workers []string) []string {
+		result := make([]string, rules)
+		for i := range result {
+			result[i] = workers[ShardIndex(fmt.Sprintf("rule-%d", i), workers)]
+		}
+		return result
+	}
+	moved := func(before, after []string) int {
+		var n int
+		for i := range before {
+			if before[i] != after[i] {
+				n++
+			}
+		}
+		return n
+	}
+
+	before := owners(workers)
+
+	// 新增实例后约 1/5 的规则迁移到新实例, 其余规则归属不变
+	joined := append(append([]string(nil), workers...), "worker-e")
+	after := owners(joined)
+	for i := range before {
+		if before[i] != after[i] && after[i] != "worker-e" {
+			t.Fatalf("rule-%d moved from %s to %s, want only moves to the new worker", i, before[i], after[i])
+		}
+	}
+	if n, want := moved(before, after), rules/len(joined); n < want*8/10 || n > want*12/10 {
+		t.Fatalf("moved %d rules after join, want about %d", n, want)
+	}
+
+	// 实例下线后只有该实例的规则迁移, 约 1/4
+	left := []string{"worker-a", "worker-b", "worker-d"}
+	after = owners(left)
+	for i := range before {
+		if before[i] != after[i] && before[i] != "worker-c" {
+			t.Fatalf("rule-%d moved from %s to %s, want only rules of the removed worker", i, before[i], after[i])
+		}
+	}
+	if n, want := moved(before, after), rules/len(workers); n < want*8/10 || n > want*12/10 {
+		t.Fatalf("moved %d rules after leave, want about %d", n, want)
+	}
+}
+
+func TestShardIndexNoWorkers(t *testing.T) {
+	if got := ShardIndex("rule-1", nil); got != -1 {
+		t.Fatalf("ShardIndex with no workers = %d, want -1", got)
+	}
+}
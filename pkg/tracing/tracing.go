@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"watchAlert/config"
+
+	"github.com/zeromicro/go-zero/core/logc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "watchAlert/alert/eval"
+
+	// 未配置时的服务名称
+	defaultServiceName = "watchalert"
+
+	AttrRuleId         = attribute.Key("rule.id")
+	AttrRuleName       = attribute.Key("rule.name")
+	AttrTenantId       = attribute.Key("tenant.id")
+	AttrDatasourceId   = attribute.Key("datasource.id")
+	AttrDatasourceType = attribute.Key("datasource.type")
+	AttrRedisOperation = attribute.Key("redis.operation")
+)
+
+// 启用后的 TracerProvider, 停机时用于上报剩余的 Span
+var provider *sdktrace.TracerProvider
+
+// Initialize 根据配置启用 OTLP 链路上报, 未启用时使用全局默认的空实现, Span 不产生开销
+func Initialize(ctx context.Context, c config.Tracing) {
+	if !c.Enabled {
+		return
+	}
+
+	if c.Endpoint == "" {
+		logc.Error(ctx, "链路追踪已启用, 但未配置上报地址")
+		return
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(c.Endpoint))
+	if err != nil {
+		logc.Errorf(ctx, "创建链路追踪上报失败, endpoint: %s, err: %s", c.Endpoint, err.Error())
+		return
+	}
+
+	serviceName := c.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if c.SampleRatio > 0 && c.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(c.SampleRatio)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", config.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	logc.Infof(ctx, "链路追踪已启用, endpoint: %s", c.Endpoint)
+}
+
+// Shutdown 上报缓冲中的 Span 并关闭 TracerProvider
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+
+	return provider.Shutdown(ctx)
+}
+
+// Start 创建 Span, 父 Span 从 ctx 中获取
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError 记录错误并将 Span 标记为失败
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}